@@ -0,0 +1,186 @@
+package integrationtests
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// ProcessorHarness runs a processor as a child process against a rendered
+// copy of a configurations/*.yaml config, for tests that need to talk to it
+// over HTTP. It replaces the copy-pasted build/start/poll/kill sequence that
+// used to live in each *_test.go file in this package.
+type ProcessorHarness struct {
+	t      *testing.T
+	cmd    *exec.Cmd
+	url    string
+	output *syncBuffer
+}
+
+// syncBuffer is an io.Writer safe for concurrent use by the child process's
+// stdout/stderr pipes and by WaitForLog reading the accumulated output.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Start renders configName with an ephemeral listen port, launches the
+// processor against it, and waits for it to become ready. The processor is
+// killed and its output flushed to t.Log (only on failure) via t.Cleanup.
+func Start(t *testing.T, configName string) *ProcessorHarness {
+	t.Helper()
+
+	configPath, env := setupProcessorEnv(t, configName)
+
+	port, err := allocateEphemeralPort()
+	if err != nil {
+		t.Fatalf("Failed to allocate an ephemeral port: %v", err)
+	}
+
+	renderedConfigPath := renderConfigWithPort(t, configPath, port)
+
+	output := &syncBuffer{}
+
+	cmd := exec.Command("go", "run", "../cmd/processor", "--config="+renderedConfigPath)
+	cmd.Env = env
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start processor: %v", err)
+	}
+
+	h := &ProcessorHarness{
+		t:      t,
+		cmd:    cmd,
+		url:    fmt.Sprintf("http://127.0.0.1:%d", port),
+		output: output,
+	}
+
+	t.Cleanup(func() {
+		if h.cmd.Process != nil {
+			syscall.Kill(-h.cmd.Process.Pid, syscall.SIGKILL) // nolint: errcheck
+			h.cmd.Wait()                                      // nolint: errcheck
+		}
+		if t.Failed() {
+			t.Logf("processor output:\n%s", h.output.String())
+		}
+	})
+
+	h.waitUntilReady(10 * time.Second)
+
+	return h
+}
+
+// URL is the base URL of the running processor.
+func (h *ProcessorHarness) URL() string {
+	return h.url
+}
+
+// Do sends req against the harness's processor, always rewriting req's URL
+// scheme and host to the allocated ephemeral address, whatever host req was
+// built with.
+func (h *ProcessorHarness) Do(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	req.URL.Host = strings.TrimPrefix(h.url, "http://")
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	return client.Do(req)
+}
+
+// WaitForLog polls the processor's accumulated stdout/stderr for a line
+// matching pattern, failing the test if it doesn't appear within timeout.
+func (h *ProcessorHarness) WaitForLog(pattern string, timeout time.Duration) {
+	h.t.Helper()
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		h.t.Fatalf("Invalid WaitForLog pattern %q: %v", pattern, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if re.MatchString(h.output.String()) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	h.t.Fatalf("Timed out waiting for log line matching %q", pattern)
+}
+
+// waitUntilReady polls /healthz (accepting any response as proof the
+// processor's HTTP listener is up, mirroring the loose "connection
+// succeeded" readiness check the tests used before this harness existed)
+// until timeout.
+func (h *ProcessorHarness) waitUntilReady(timeout time.Duration) {
+	h.t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(h.url + "/healthz")
+		if err == nil {
+			resp.Body.Close() // nolint: errcheck
+			return
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	h.t.Fatalf("Processor did not become ready within %v", timeout)
+}
+
+// allocateEphemeralPort grabs a free TCP port by binding to :0 and
+// immediately releasing it, so multiple harnesses can run with t.Parallel()
+// instead of all fighting over a fixed :8080.
+func allocateEphemeralPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close() // nolint: errcheck
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// renderConfigWithPort copies configPath into the test's temp directory,
+// substituting the fixed ":8080" listen port the checked-in configurations
+// use for the allocated ephemeral port.
+func renderConfigWithPort(t *testing.T, configPath string, port int) string {
+	t.Helper()
+
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config %q: %v", configPath, err)
+	}
+
+	rendered := bytes.ReplaceAll(contents, []byte(":8080"), []byte(fmt.Sprintf(":%d", port)))
+
+	renderedPath := fmt.Sprintf("%s/%s", t.TempDir(), "processor.yaml")
+	if err := os.WriteFile(renderedPath, rendered, 0o644); err != nil {
+		t.Fatalf("Failed to write rendered config: %v", err)
+	}
+
+	return renderedPath
+}
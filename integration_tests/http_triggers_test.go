@@ -6,50 +6,19 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
-	"os/exec"
 	"reflect"
 	"strings"
-	"syscall"
 	"testing"
-	"time"
 )
 
 func TestGetRequest(t *testing.T) {
-	configPath, env := setupProcessorEnv(t, "get.yaml")
-	cmd := exec.Command("go", "run", "../cmd/processor", "--config="+configPath)
-	cmd.Env = env
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	if err := cmd.Start(); err != nil {
-		t.Fatalf("Failed to start processor: %v", err)
-	}
-	defer func() {
-		if cmd.Process != nil {
-			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-			cmd.Wait()
-		}
-	}()
+	h := Start(t, "get.yaml")
 
-	url := "http://localhost:8080"
-	ready := false
-	for range 20 {
-		time.Sleep(500 * time.Millisecond)
-		resp, err := http.Get(url)
-		if err == nil {
-			resp.Body.Close()
-			ready = true
-			break
-		}
-	}
-	if !ready {
-		cmd.Process.Kill()
-		t.Fatalf("Processor did not start within timeout")
-	}
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, h.URL(), nil)
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := h.Do(req)
 	if err != nil {
 		t.Fatalf("Failed to send request: %v", err)
 	}
@@ -73,43 +42,14 @@ func TestGetRequest(t *testing.T) {
 }
 
 func TestPostTextRequest(t *testing.T) {
-	configPath, env := setupProcessorEnv(t, "post_text.yaml")
-	cmd := exec.Command("go", "run", "../cmd/processor", "--config="+configPath)
-	cmd.Env = env
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	if err := cmd.Start(); err != nil {
-		t.Fatalf("Failed to start processor: %v", err)
-	}
-	defer func() {
-		if cmd.Process != nil {
-			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-			cmd.Wait()
-		}
-	}()
-
-	url := "http://localhost:8080"
-	ready := false
-	for range 20 {
-		time.Sleep(500 * time.Millisecond)
-		resp, err := http.Get(url)
-		if err == nil {
-			resp.Body.Close()
-			ready = true
-			break
-		}
-	}
-	if !ready {
-		cmd.Process.Kill()
-		t.Fatalf("Processor did not start within timeout")
-	}
+	h := Start(t, "post_text.yaml")
 
 	postData := "world"
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(postData))
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, h.URL(), strings.NewReader(postData))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := h.Do(req)
 	if err != nil {
 		t.Fatalf("Failed to send request: %v", err)
 	}
@@ -131,12 +71,11 @@ func TestPostTextRequest(t *testing.T) {
 	t.Logf("HTTP trigger responded with status: %d", resp.StatusCode)
 
 	postData = "John"
-	req, err = http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(postData))
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodPost, h.URL(), strings.NewReader(postData))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
-	client = &http.Client{Timeout: 5 * time.Second}
-	resp, err = client.Do(req)
+	resp, err = h.Do(req)
 	if err != nil {
 		t.Fatalf("Failed to send request: %v", err)
 	}
@@ -160,46 +99,18 @@ func TestPostTextRequest(t *testing.T) {
 }
 
 func TestPostJSONRequest(t *testing.T) {
-	configPath, env := setupProcessorEnv(t, "post_json.yaml")
-	cmd := exec.Command("go", "run", "../cmd/processor", "--config="+configPath)
-	cmd.Env = env
-	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-	if err := cmd.Start(); err != nil {
-		t.Fatalf("Failed to start processor: %v", err)
-	}
-	defer func() {
-		if cmd.Process != nil {
-			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
-			cmd.Wait()
-		}
-	}()
+	h := Start(t, "post_json.yaml")
 
-	url := "http://localhost:8080"
-	ready := false
-	for range 20 {
-		time.Sleep(500 * time.Millisecond)
-		resp, err := http.Get(url)
-		if err == nil {
-			resp.Body.Close()
-			ready = true
-			break
-		}
-	}
-	if !ready {
-		cmd.Process.Kill()
-		t.Fatalf("Processor did not start within timeout")
-	}
 	postData := map[string]any{"id": 0, "country": "Italy"}
 	jsonData, err := json.Marshal(postData)
 	if err != nil {
 		t.Fatalf("Failed to marshal JSON: %v", err)
 	}
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, h.URL(), bytes.NewBuffer(jsonData))
 	if err != nil {
 		t.Fatalf("Failed to create request: %v", err)
 	}
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := h.Do(req)
 	if err != nil {
 		t.Fatalf("Failed to send request: %v", err)
 	}
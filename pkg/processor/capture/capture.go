@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package capture defines the common interface trigger code uses to pull
+// frames from a video/audio source, independent of how that source is
+// actually reached (a live HTTP multipart stream, an RTSP server, an
+// ffmpeg subprocess, ...). Concrete backends live in subpackages
+// (httpmjpeg, gortsplib, ffmpeg) so a trigger can swap between them
+// without changing its own orchestration loop.
+package capture
+
+import (
+	"context"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+)
+
+// Client captures frames from a single source. Implementations are not
+// expected to be safe for concurrent use.
+type Client interface {
+	// Connect establishes the capture session. NextFrame must be safe to
+	// call as soon as Connect returns without error.
+	Connect(ctx context.Context) error
+
+	// NextFrame blocks until a frame is available, ctx is done, or the
+	// capture session ends, in which case it returns an error.
+	NextFrame(ctx context.Context) (packets.Frame, error)
+
+	// Close tears down the capture session. It is safe to call even if
+	// Connect was never called or failed.
+	Close() error
+}
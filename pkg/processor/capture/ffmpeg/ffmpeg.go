@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ffmpeg implements capture.Client by spawning
+// `ffmpeg -i <url> -f mjpeg pipe:1` and scanning its stdout for JPEG
+// frames. Useful for sources neither a plain HTTP GET nor gortsplib can
+// reach directly (RTMP, HLS, exotic camera firmwares), since ffmpeg
+// already knows how to demux them.
+package ffmpeg
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+)
+
+const readBufferSize = 64 * 1024
+
+// Client captures JPEG frames from ffmpeg's raw mjpeg muxer output.
+type Client struct {
+	url string
+
+	cmd    *exec.Cmd
+	reader *bufio.Reader
+	start  time.Time
+}
+
+// NewClient creates a Client that will spawn ffmpeg against url on
+// Connect.
+func NewClient(url string) *Client {
+	return &Client{url: url}
+}
+
+// Connect spawns ffmpeg and wires up its stdout for reading.
+func (c *Client) Connect(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-i", c.url, "-f", "mjpeg", "pipe:1")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to open ffmpeg stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	c.cmd = cmd
+	c.reader = bufio.NewReaderSize(stdout, readBufferSize)
+	c.start = time.Now()
+
+	return nil
+}
+
+// NextFrame scans ffmpeg's stdout for the next complete JPEG image.
+func (c *Client) NextFrame(ctx context.Context) (packets.Frame, error) {
+	if c.reader == nil {
+		return packets.Frame{}, fmt.Errorf("ffmpeg: not connected")
+	}
+
+	data, err := readJPEGFrame(c.reader)
+	if err != nil {
+		return packets.Frame{}, fmt.Errorf("failed to read JPEG frame from ffmpeg: %w", err)
+	}
+
+	return packets.Frame{
+		Data: data,
+		PTS:  time.Since(c.start),
+		Kind: packets.KindVideo,
+	}, nil
+}
+
+// Close kills the ffmpeg subprocess and waits for it to exit.
+func (c *Client) Close() error {
+	if c.cmd == nil || c.cmd.Process == nil {
+		return nil
+	}
+	_ = c.cmd.Process.Kill()
+	_ = c.cmd.Wait()
+	return nil
+}
+
+// readJPEGFrame scans r for one JPEG image delimited by the SOI (0xFFD8)
+// and EOI (0xFFD9) markers ffmpeg's raw mjpeg muxer emits back-to-back,
+// with no multipart-style framing of its own.
+func readJPEGFrame(r *bufio.Reader) ([]byte, error) {
+	if err := skipToSOI(r); err != nil {
+		return nil, err
+	}
+
+	frame := []byte{0xff, 0xd8}
+	prev := byte(0)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		frame = append(frame, b)
+		if prev == 0xff && b == 0xd9 {
+			return frame, nil
+		}
+		prev = b
+	}
+}
+
+func skipToSOI(r *bufio.Reader) error {
+	prev := byte(0)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if prev == 0xff && b == 0xd8 {
+			return nil
+		}
+		prev = b
+	}
+}
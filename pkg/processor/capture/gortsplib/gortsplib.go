@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gortsplib implements capture.Client against an RTSP source's
+// MJPEG track via github.com/bluenviron/gortsplib. It only supports
+// MJPEG-format video; cameras that publish H264/H265 should use the
+// dedicated rtsp trigger instead, which carries the decode pipeline this
+// lightweight capture backend intentionally does not duplicate.
+package gortsplib
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	glib "github.com/bluenviron/gortsplib/v5"
+	"github.com/bluenviron/gortsplib/v5/pkg/base"
+	"github.com/bluenviron/gortsplib/v5/pkg/description"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/pion/rtp"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+)
+
+const frameQueueCapacity = 16
+
+// Client captures JPEG frames from an RTSP source's MJPEG track.
+type Client struct {
+	url string
+
+	client *glib.Client
+	queue  *packets.Queue
+}
+
+// NewClient creates a Client that will DESCRIBE/SETUP/PLAY url on Connect.
+func NewClient(url string) *Client {
+	return &Client{url: url, queue: packets.NewQueue(frameQueueCapacity)}
+}
+
+// Connect performs DESCRIBE/SETUP/PLAY against the configured RTSP URL and
+// starts decoding its MJPEG track into the frame queue.
+func (c *Client) Connect(ctx context.Context) error {
+	u, err := base.ParseURL(c.url)
+	if err != nil {
+		return fmt.Errorf("failed to parse RTSP URL: %w", err)
+	}
+
+	client := &glib.Client{}
+	if err := client.Start(u.Scheme, u.Host); err != nil {
+		return fmt.Errorf("failed to connect to RTSP server: %w", err)
+	}
+
+	desc, _, err := client.Describe(u)
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to DESCRIBE RTSP stream: %w", err)
+	}
+
+	mjpegFormat, media := findMJPEGTrack(desc.Medias)
+	if mjpegFormat == nil {
+		client.Close()
+		return fmt.Errorf("no MJPEG track advertised by %s", c.url)
+	}
+
+	dec, err := mjpegFormat.CreateDecoder()
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to create MJPEG decoder: %w", err)
+	}
+
+	if err := client.Setup(desc.BaseURL, media, 0, 0); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to SETUP RTSP track: %w", err)
+	}
+
+	start := time.Now()
+	client.OnPacketRTP(media, mjpegFormat, func(pkt *rtp.Packet) {
+		jpegData, err := dec.Decode(pkt)
+		if err != nil || len(jpegData) == 0 {
+			return
+		}
+		c.queue.Push(packets.Frame{
+			Data: jpegData,
+			PTS:  time.Since(start),
+			Kind: packets.KindVideo,
+		})
+	})
+
+	if _, err := client.Play(nil); err != nil {
+		client.Close()
+		return fmt.Errorf("failed to PLAY RTSP stream: %w", err)
+	}
+
+	c.client = client
+
+	return nil
+}
+
+// NextFrame pops the next decoded JPEG frame off the queue.
+func (c *Client) NextFrame(ctx context.Context) (packets.Frame, error) {
+	return c.queue.Pop(ctx)
+}
+
+// Close closes the RTSP connection and the frame queue.
+func (c *Client) Close() error {
+	c.queue.Close()
+	if c.client != nil {
+		return c.client.Close()
+	}
+	return nil
+}
+
+func findMJPEGTrack(medias []*description.Media) (*format.MJPEG, *description.Media) {
+	for _, media := range medias {
+		for _, forma := range media.Formats {
+			if mj, ok := forma.(*format.MJPEG); ok {
+				return mj, media
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpmjpeg implements capture.Client against an HTTP
+// multipart/x-mixed-replace MJPEG stream, the format most IP cameras and
+// mjpg-streamer expose.
+package httpmjpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+)
+
+const (
+	// DefaultMaxFrameSize rejects frames larger than this when a part
+	// carries no Content-Length, guarding against a misbehaving source
+	// that never sends a closing boundary.
+	DefaultMaxFrameSize = 10 * 1024 * 1024
+
+	// DefaultReadTimeout is how long NextFrame will wait for the next
+	// byte of a part before giving up and forcing a reconnect.
+	DefaultReadTimeout = 10 * time.Second
+)
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// Client captures JPEG frames from an HTTP multipart/x-mixed-replace
+// stream using the standard library's mime/multipart reader.
+type Client struct {
+	url          string
+	maxFrameSize int
+	readTimeout  time.Duration
+
+	resp   *http.Response
+	body   *deadlineReadCloser
+	reader *multipart.Reader
+	start  time.Time
+}
+
+// NewClient creates a Client that will GET url on Connect. maxFrameSize and
+// readTimeout fall back to DefaultMaxFrameSize/DefaultReadTimeout when <= 0.
+func NewClient(url string, maxFrameSize int, readTimeout time.Duration) *Client {
+	if maxFrameSize <= 0 {
+		maxFrameSize = DefaultMaxFrameSize
+	}
+	if readTimeout <= 0 {
+		readTimeout = DefaultReadTimeout
+	}
+	return &Client{url: url, maxFrameSize: maxFrameSize, readTimeout: readTimeout}
+}
+
+// Connect issues the GET request and parses the multipart boundary out of
+// the response's Content-Type header via mime.ParseMediaType, which
+// correctly honors quoted-string rules that a hand-rolled split on "=" does
+// not (e.g. boundary="my;boundary"; charset=utf-8).
+func (c *Client) Connect(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := (&http.Client{Timeout: 0}).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MJPEG stream: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" {
+		resp.Body.Close()
+		return fmt.Errorf("failed to parse multipart boundary from Content-Type %q: %w",
+			resp.Header.Get("Content-Type"), err)
+	}
+
+	body := newDeadlineReadCloser(resp.Body, c.readTimeout)
+
+	c.resp = resp
+	c.body = body
+	c.reader = multipart.NewReader(body, params["boundary"])
+	c.start = time.Now()
+
+	return nil
+}
+
+// NextFrame reads one multipart part and returns its body as a video
+// frame. A per-frame read timeout (reset on every successful read) closes
+// the connection if the source stalls, so NextFrame returns an error
+// instead of hanging forever.
+func (c *Client) NextFrame(ctx context.Context) (packets.Frame, error) {
+	if c.reader == nil {
+		return packets.Frame{}, fmt.Errorf("httpmjpeg: not connected")
+	}
+
+	part, err := c.reader.NextPart()
+	if err != nil {
+		return packets.Frame{}, fmt.Errorf("failed to read next part: %w", err)
+	}
+	defer part.Close()
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	if contentLength := part.Header.Get("Content-Length"); contentLength != "" {
+		length, convErr := strconv.Atoi(contentLength)
+		if convErr != nil || length <= 0 {
+			return packets.Frame{}, fmt.Errorf("invalid Content-Length %q", contentLength)
+		}
+		if length > c.maxFrameSize {
+			return packets.Frame{}, fmt.Errorf("frame size %d exceeds max frame size %d", length, c.maxFrameSize)
+		}
+		if _, err := io.CopyN(buf, part, int64(length)); err != nil {
+			return packets.Frame{}, fmt.Errorf("failed to read frame data: %w", err)
+		}
+	} else {
+		// No Content-Length: legal per RFC 2046 and common with
+		// axis/foscam cameras. Read until the part's own boundary (which
+		// multipart.Reader enforces as io.EOF), capped at maxFrameSize+1
+		// so a missing boundary can't grow the frame forever.
+		limited := io.LimitReader(part, int64(c.maxFrameSize)+1)
+		n, err := io.Copy(buf, limited)
+		if err != nil {
+			return packets.Frame{}, fmt.Errorf("failed to read frame data: %w", err)
+		}
+		if n > int64(c.maxFrameSize) {
+			return packets.Frame{}, fmt.Errorf("frame exceeds max frame size %d", c.maxFrameSize)
+		}
+	}
+
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+
+	return packets.Frame{
+		Data: data,
+		PTS:  time.Since(c.start),
+		Kind: packets.KindVideo,
+	}, nil
+}
+
+// Close closes the underlying HTTP response body.
+func (c *Client) Close() error {
+	if c.resp != nil {
+		return c.resp.Body.Close()
+	}
+	return nil
+}
+
+// deadlineReadCloser closes the wrapped ReadCloser if no read completes
+// within timeout of the previous one, turning a stalled connection into a
+// Read error instead of a hang. http.Response.Body doesn't expose a
+// net.Conn to set a read deadline on directly, so this achieves the same
+// effect by forcing Close on the timer firing.
+type deadlineReadCloser struct {
+	rc      io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+}
+
+func newDeadlineReadCloser(rc io.ReadCloser, timeout time.Duration) *deadlineReadCloser {
+	d := &deadlineReadCloser{rc: rc, timeout: timeout}
+	d.timer = time.AfterFunc(timeout, func() {
+		rc.Close()
+	})
+	return d
+}
+
+func (d *deadlineReadCloser) Read(p []byte) (int, error) {
+	n, err := d.rc.Read(p)
+	d.timer.Reset(d.timeout)
+	return n, err
+}
+
+func (d *deadlineReadCloser) Close() error {
+	d.timer.Stop()
+	return d.rc.Close()
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package httpmjpeg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMJPEGServer(t *testing.T, frames [][]byte) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", `multipart/x-mixed-replace; boundary="frame"; charset=utf-8`)
+		for _, frame := range frames {
+			fmt.Fprintf(w, "--frame\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(frame))
+			w.Write(frame)
+			fmt.Fprint(w, "\r\n")
+		}
+		fmt.Fprint(w, "--frame--\r\n")
+	}))
+}
+
+func TestClientNextFrame(t *testing.T) {
+	frames := [][]byte{[]byte("frame-one"), []byte("frame-two")}
+	server := newMJPEGServer(t, frames)
+	defer server.Close()
+
+	c := NewClient(server.URL, 0, 0)
+	require.NoError(t, c.Connect(context.Background()))
+	defer c.Close()
+
+	for _, expected := range frames {
+		frame, err := c.NextFrame(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, expected, frame.Data)
+	}
+
+	_, err := c.NextFrame(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClientRejectsOversizedFrame(t *testing.T) {
+	server := newMJPEGServer(t, [][]byte{[]byte("0123456789")})
+	defer server.Close()
+
+	c := NewClient(server.URL, 4, 0)
+	require.NoError(t, c.Connect(context.Background()))
+	defer c.Close()
+
+	_, err := c.NextFrame(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClientQuotedBoundaryWithParams(t *testing.T) {
+	// Regression test: a hand-rolled parser that splits on the first "="
+	// after "boundary" misparses this header; mime.ParseMediaType does not.
+	server := newMJPEGServer(t, [][]byte{[]byte("hello")})
+	defer server.Close()
+
+	c := NewClient(server.URL, 0, 0)
+	require.NoError(t, c.Connect(context.Background()))
+	defer c.Close()
+
+	frame, err := c.NextFrame(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), frame.Data)
+}
+
+func TestDeadlineReadCloserClosesOnStall(t *testing.T) {
+	pr, pw := io.Pipe()
+	d := newDeadlineReadCloser(pr, 20*time.Millisecond)
+	defer d.Close()
+
+	buf := make([]byte, 1)
+	time.Sleep(50 * time.Millisecond)
+
+	_, err := d.Read(buf)
+	assert.Error(t, err)
+
+	pw.Close()
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package packets holds the types every capture.Client backend shares: the
+// Frame a backend hands its caller, and a bounded Queue backends use to
+// decouple their own I/O goroutine from a possibly-slow consumer.
+package packets
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Kind identifies what track a Frame came from.
+type Kind string
+
+const (
+	KindVideo Kind = "video"
+	KindAudio Kind = "audio"
+)
+
+// Frame is one demuxed access unit produced by a capture.Client: a JPEG
+// image, a PCM chunk, etc.
+type Frame struct {
+	Data    []byte
+	PTS     time.Duration
+	Kind    Kind
+	TrackID int
+}
+
+// Queue is a bounded FIFO of Frames with drop-oldest semantics: Push never
+// blocks its caller, discarding the oldest buffered frame instead of
+// stalling a capture backend's I/O goroutine when the consumer falls
+// behind.
+type Queue struct {
+	ch chan Frame
+}
+
+// NewQueue creates a Queue holding at most capacity buffered frames.
+func NewQueue(capacity int) *Queue {
+	return &Queue{ch: make(chan Frame, capacity)}
+}
+
+// Push enqueues f, dropping the oldest buffered frame first if the queue
+// is full.
+func (q *Queue) Push(f Frame) {
+	for {
+		select {
+		case q.ch <- f:
+			return
+		default:
+		}
+
+		select {
+		case <-q.ch:
+		default:
+		}
+	}
+}
+
+// Pop blocks until a frame is available, ctx is done, or the queue has
+// been closed, in which case it returns io.EOF.
+func (q *Queue) Pop(ctx context.Context) (Frame, error) {
+	select {
+	case f, ok := <-q.ch:
+		if !ok {
+			return Frame{}, io.EOF
+		}
+		return f, nil
+	case <-ctx.Done():
+		return Frame{}, ctx.Err()
+	}
+}
+
+// Close marks the queue as done; a Pop already blocked, or any Pop after,
+// returns io.EOF once buffered frames are drained.
+func (q *Queue) Close() {
+	close(q.ch)
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+// Metadata keys a caller writing encoded video to a Sink (mjpeg, hls,
+// webrtc) should set on the metadata passed to Write, so a sink that needs
+// to know more than "here are some bytes" - muxing into MPEG-TS segments,
+// packetizing into RTP - doesn't need a second, codec-aware method on the
+// Sink interface. A sink that doesn't care about one of these (mjpeg, which
+// only ever carries MJPEG frames) is free to ignore it.
+const (
+	// MetadataCodec names the codec data is encoded with, e.g. "h264",
+	// "mjpeg". A sink that only supports one codec may ignore this key, or
+	// reject Write calls carrying an unexpected value.
+	MetadataCodec = "codec"
+
+	// MetadataTimestamp carries the frame's presentation timestamp as a
+	// time.Time, for sinks that need to pace or order frames (HLS segment
+	// boundaries, RTP timestamps) instead of simply forwarding bytes as they
+	// arrive.
+	MetadataTimestamp = "timestamp"
+
+	// MetadataKeyframe carries a bool: true if data is a keyframe (IDR
+	// frame), which muxers need to know to start a new segment or GOP
+	// cleanly instead of mid-stream.
+	MetadataKeyframe = "keyframe"
+)
+
+// FrameMetadata reads the MetadataCodec/MetadataKeyframe conventions above
+// out of a Write call's metadata map, defaulting codec to "" and keyframe to
+// false if either key is absent or holds an unexpected type.
+func FrameMetadata(metadata map[string]interface{}) (codec string, keyframe bool) {
+	if metadata == nil {
+		return "", false
+	}
+
+	codec, _ = metadata[MetadataCodec].(string)
+	keyframe, _ = metadata[MetadataKeyframe].(bool)
+	return codec, keyframe
+}
@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hls implements an HLS frame sink: frames pushed in via Write are
+// muxed into rolling MPEG-TS segments and served as a playlist over the
+// same embedded-http.Server pattern the mjpeg sink uses, via the shared
+// framehub package.
+package hls
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink/internal/framehub"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink/internal/mpegts"
+)
+
+// Configuration for the HLS sink.
+type Configuration struct {
+	Port           int    `json:"port,omitempty"`
+	Path           string `json:"path,omitempty"`
+	SegmentSeconds int    `json:"segmentSeconds,omitempty"`
+	SegmentCount   int    `json:"segmentCount,omitempty"`
+}
+
+// segment is one rolling segment of the playlist.
+type segment struct {
+	index    int
+	duration time.Duration
+	data     []byte
+}
+
+// Sink implements HLS HTTP streaming.
+type Sink struct {
+	logger        logger.Logger
+	configuration *Configuration
+	hub           *framehub.Hub
+
+	mu        sync.Mutex
+	segments  []segment
+	nextIndex int
+	cur       *mpegts.Muxer
+	curStart  time.Time
+	havePTS   bool
+}
+
+// factory implements sink.Factory
+type factory struct{}
+
+func (f *factory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
+	config := &Configuration{
+		Port:           8082,
+		Path:           "/hls",
+		SegmentSeconds: 2,
+		SegmentCount:   5,
+	}
+
+	if err := mapstructure.Decode(configuration, config); err != nil {
+		return nil, fmt.Errorf("failed to parse hls sink configuration: %w", err)
+	}
+
+	s := &Sink{
+		logger:        logger,
+		configuration: config,
+	}
+	s.hub = framehub.NewHub(logger, 32, 100*time.Millisecond, s.consumeFrame)
+
+	return s, nil
+}
+
+func (f *factory) GetKind() string {
+	return "hls"
+}
+
+// Start starts the HLS HTTP server.
+func (s *Sink) Start() error {
+	s.logger.InfoWith("Starting HLS sink", "port", s.configuration.Port, "path", s.configuration.Path)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.configuration.Path+"/", s.handleHTTP)
+
+	return s.hub.Start(fmt.Sprintf(":%d", s.configuration.Port), mux)
+}
+
+// Stop stops the HLS sink.
+func (s *Sink) Stop(force bool) error {
+	s.logger.InfoWith("Stopping HLS sink", "force", force)
+	return s.hub.Stop()
+}
+
+// Write accepts an encoded access unit, muxing it into the current segment;
+// see sink.MetadataCodec/sink.MetadataKeyframe for the metadata convention
+// producers should set. This sink does not yet branch on codec - like the
+// rtsp sink's own HLS muxer, every frame is wrapped in a private-data PES,
+// which is structurally valid MPEG-TS but not guaranteed to render in every
+// HLS player (see mpegts.streamTypePrivateData).
+func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	return s.hub.Write(ctx, data)
+}
+
+// GetKind returns the sink type.
+func (s *Sink) GetKind() string {
+	return "hls"
+}
+
+// GetConfig returns the sink configuration.
+func (s *Sink) GetConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"port":           s.configuration.Port,
+		"path":           s.configuration.Path,
+		"segmentSeconds": s.configuration.SegmentSeconds,
+		"segmentCount":   s.configuration.SegmentCount,
+	}
+}
+
+// consumeFrame appends frame to the current MPEG-TS segment, rotating to a
+// new segment once the configured segment duration has elapsed and evicting
+// the oldest segment past the configured rolling window.
+func (s *Sink) consumeFrame(frame []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cur == nil {
+		s.cur = mpegts.NewMuxer()
+		s.curStart = time.Now()
+		s.havePTS = false
+	}
+
+	pts := int64(0)
+	if s.havePTS {
+		pts = int64(time.Since(s.curStart)/time.Microsecond) * 90 / 1000
+	}
+	s.cur.WriteFrame(frame, pts)
+	s.havePTS = true
+
+	segmentDuration := time.Duration(s.configuration.SegmentSeconds) * time.Second
+	if time.Since(s.curStart) >= segmentDuration {
+		s.segments = append(s.segments, segment{
+			index:    s.nextIndex,
+			duration: time.Since(s.curStart),
+			data:     s.cur.Bytes(),
+		})
+		s.nextIndex++
+		if len(s.segments) > s.configuration.SegmentCount {
+			s.segments = s.segments[len(s.segments)-s.configuration.SegmentCount:]
+		}
+		s.cur = nil
+	}
+}
+
+// handleHTTP serves the rolling playlist at {path}/index.m3u8 and its
+// segments at {path}/{n}.ts.
+func (s *Sink) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, ".ts") {
+		s.serveSegment(w, r)
+		return
+	}
+	s.servePlaylist(w, r)
+}
+
+func (s *Sink) servePlaylist(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	segments := append([]segment{}, s.segments...)
+	s.mu.Unlock()
+
+	segmentDuration := time.Duration(s.configuration.SegmentSeconds) * time.Second
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(segmentDuration.Seconds())+1)
+	if len(segments) > 0 {
+		fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", segments[0].index)
+	}
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%d.ts\n", seg.duration.Seconds(), seg.index)
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Write([]byte(b.String()))
+}
+
+func (s *Sink) serveSegment(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	idx, err := strconv.Atoi(strings.TrimSuffix(name, ".ts"))
+	if err != nil {
+		http.Error(w, "bad segment name", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	var data []byte
+	for _, seg := range s.segments {
+		if seg.index == idx {
+			data = seg.data
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Write(data)
+}
+
+func init() {
+	sink.RegistrySingleton.Register("hls", &factory{})
+}
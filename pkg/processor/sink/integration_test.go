@@ -5,6 +5,7 @@ package sink_test
 
 import (
 	"context"
+	"encoding/pem"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -227,6 +228,219 @@ func (suite *SinkIntegrationTestSuite) TestConfigurationValidation() {
 	suite.Contains(err.Error(), "invalid rtsp type")
 }
 
+// Test WebSocket sink over WSS with server certificate pinning
+func (suite *SinkIntegrationTestSuite) TestWebSocketSinkWSSIntegration() {
+	logger, _ := nucliozap.NewNuclioZapTest("test")
+
+	upgrader := websocket.Upgrader{}
+	receivedData := make(chan []byte, 1)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		receivedData <- message
+	}))
+	defer server.Close()
+
+	caPEM := server.Certificate().Raw
+	pemBlock := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caPEM})
+
+	wsURL := "wss" + server.URL[5:]
+
+	config := map[string]interface{}{
+		"url":         wsURL,
+		"messageType": "binary",
+		"timeout":     5,
+		"tls": map[string]interface{}{
+			"ca": string(pemBlock),
+		},
+	}
+
+	wsSink, err := sink.RegistrySingleton.Create(logger, "websocket", config)
+	suite.NoError(err)
+
+	err = wsSink.Start()
+	suite.NoError(err)
+	defer wsSink.Stop(false)
+
+	time.Sleep(200 * time.Millisecond)
+
+	testData := []byte("test wss message")
+	err = wsSink.Write(context.Background(), testData, nil)
+	suite.NoError(err)
+
+	select {
+	case data := <-receivedData:
+		suite.Equal(testData, data)
+	case <-time.After(2 * time.Second):
+		suite.Fail("Timeout waiting for WSS message")
+	}
+}
+
+// Test that wss:// without TLS material or explicit skip-verify is rejected
+func (suite *SinkIntegrationTestSuite) TestWebSocketSinkWSSRequiresTLSMaterial() {
+	logger, _ := nucliozap.NewNuclioZapTest("test")
+
+	_, err := sink.RegistrySingleton.Create(logger, "websocket", map[string]interface{}{
+		"url": "wss://example.com",
+	})
+	suite.Error(err)
+	suite.Contains(err.Error(), "requires tls")
+}
+
+// Test that custom headers reach the handshake request
+func (suite *SinkIntegrationTestSuite) TestWebSocketSinkHeadersIntegration() {
+	logger, _ := nucliozap.NewNuclioZapTest("test")
+
+	upgrader := websocket.Upgrader{}
+	handshakeDone := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("Bearer test-token", r.Header.Get("Authorization"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handshakeDone <- struct{}{}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	config := map[string]interface{}{
+		"url":         wsURL,
+		"messageType": "binary",
+		"timeout":     5,
+		"headers": map[string]interface{}{
+			"Authorization": "Bearer test-token",
+		},
+	}
+
+	wsSink, err := sink.RegistrySingleton.Create(logger, "websocket", config)
+	suite.NoError(err)
+
+	err = wsSink.Start()
+	suite.NoError(err)
+	defer wsSink.Stop(false)
+
+	select {
+	case <-handshakeDone:
+	case <-time.After(2 * time.Second):
+		suite.Fail("Timeout waiting for handshake")
+	}
+}
+
+// Test that basic auth credentials reach the handshake request
+func (suite *SinkIntegrationTestSuite) TestWebSocketSinkBasicAuthIntegration() {
+	logger, _ := nucliozap.NewNuclioZapTest("test")
+
+	upgrader := websocket.Upgrader{}
+	handshakeDone := make(chan struct{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		suite.True(ok)
+		suite.Equal("alice", username)
+		suite.Equal("secret", password)
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handshakeDone <- struct{}{}
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	config := map[string]interface{}{
+		"url":         wsURL,
+		"messageType": "binary",
+		"timeout":     5,
+		"basicAuth": map[string]interface{}{
+			"username": "alice",
+			"password": "secret",
+		},
+	}
+
+	wsSink, err := sink.RegistrySingleton.Create(logger, "websocket", config)
+	suite.NoError(err)
+
+	err = wsSink.Start()
+	suite.NoError(err)
+	defer wsSink.Stop(false)
+
+	select {
+	case <-handshakeDone:
+	case <-time.After(2 * time.Second):
+		suite.Fail("Timeout waiting for handshake")
+	}
+}
+
+// Test WebSocket sink with permessage-deflate compression enabled
+func (suite *SinkIntegrationTestSuite) TestWebSocketSinkCompressionIntegration() {
+	logger, _ := nucliozap.NewNuclioZapTest("test")
+
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	receivedData := make(chan []byte, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		receivedData <- message
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	config := map[string]interface{}{
+		"url":              wsURL,
+		"messageType":      "binary",
+		"timeout":          5,
+		"compression":      true,
+		"compressionLevel": 6,
+	}
+
+	wsSink, err := sink.RegistrySingleton.Create(logger, "websocket", config)
+	suite.NoError(err)
+
+	err = wsSink.Start()
+	suite.NoError(err)
+	defer wsSink.Stop(false)
+
+	time.Sleep(200 * time.Millisecond)
+
+	testData := []byte("test compressed message")
+	err = wsSink.Write(context.Background(), testData, nil)
+	suite.NoError(err)
+
+	select {
+	case data := <-receivedData:
+		suite.Equal(testData, data)
+	case <-time.After(2 * time.Second):
+		suite.Fail("Timeout waiting for compressed WebSocket message")
+	}
+}
+
 func TestSinkIntegrationTestSuite(t *testing.T) {
 	suite.Run(t, new(SinkIntegrationTestSuite))
 }
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package framehub holds the client-registry, backpressure, and shutdown
+// bookkeeping shared by every HTTP-embedded frame sink (mjpeg, hls, webrtc):
+// a single producer pushes frames in via Write, each sink decides how those
+// frames reach whichever clients are currently connected, and Stop tears
+// everything down in the same order every implementation needs.
+package framehub
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nuclio/logger"
+)
+
+// Hub owns the inbound frame channel, the HTTP server a frame sink embeds,
+// and the goroutines/shutdown sequencing all three frame sinks repeat
+// verbatim today. A caller supplies a Broadcast func that fans a frame out
+// however that sink's wire format requires (MJPEG multipart chunks, HLS
+// segment buffering, WebRTC RTP packetization, ...); Hub only owns the
+// channel, the server lifecycle, and the drop-when-full backpressure policy.
+type Hub struct {
+	logger    logger.Logger
+	frameChan chan []byte
+	stopChan  chan struct{}
+	wg        sync.WaitGroup
+	server    *http.Server
+
+	// writeTimeout bounds how long Write blocks trying to hand a frame to
+	// the hub before dropping it; see NewHub.
+	writeTimeout time.Duration
+
+	// broadcast is called once per frame read off frameChan, from the single
+	// goroutine Start spawns for it; implementations needing their own
+	// locking around per-client state (see Registry below) do it here.
+	broadcast func(frame []byte)
+}
+
+// NewHub creates a Hub with the given inbound-frame buffer depth and
+// per-Write drop timeout, and registers broadcast as the function called
+// with each frame accepted via Write.
+func NewHub(log logger.Logger, bufferFrames int, writeTimeout time.Duration, broadcast func(frame []byte)) *Hub {
+	return &Hub{
+		logger:       log,
+		frameChan:    make(chan []byte, bufferFrames),
+		stopChan:     make(chan struct{}),
+		writeTimeout: writeTimeout,
+		broadcast:    broadcast,
+	}
+}
+
+// Start builds an *http.Server listening on addr with mux as its handler,
+// and spawns the broadcast loop and the server's own goroutine, both
+// tracked so Stop can wait for them to exit.
+func (h *Hub) Start(addr string, mux *http.ServeMux) error {
+	h.server = &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	h.wg.Add(1)
+	go h.loop()
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if err := h.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			h.logger.ErrorWith("frame sink server error", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// loop drains frameChan into broadcast until stopChan closes or frameChan is
+// closed (the latter only ever happens from within Stop, after stopChan has
+// already closed, so this always exits via the stopChan case first).
+func (h *Hub) loop() {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case frame, ok := <-h.frameChan:
+			if !ok {
+				return
+			}
+			h.broadcast(frame)
+		case <-h.stopChan:
+			return
+		}
+	}
+}
+
+// Stop signals the broadcast loop and any handlers selecting on Done to
+// exit, gracefully shuts down the HTTP server (bounded to 5s), waits for
+// both spawned goroutines, and closes the inbound frame channel.
+func (h *Hub) Stop() error {
+	close(h.stopChan)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var shutdownErr error
+	if h.server != nil {
+		shutdownErr = h.server.Shutdown(ctx)
+	}
+
+	h.wg.Wait()
+	close(h.frameChan)
+
+	return shutdownErr
+}
+
+// Write hands data to the broadcast loop, dropping it if the loop can't keep
+// up within the configured writeTimeout or ctx is cancelled first - the
+// backpressure policy every frame sink's Write method applies.
+func (h *Hub) Write(ctx context.Context, data []byte) error {
+	select {
+	case h.frameChan <- data:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(h.writeTimeout):
+		h.logger.DebugWith("dropping frame - sink channel full")
+		return nil
+	}
+}
+
+// Done returns the channel that closes once Stop has been called, for HTTP
+// handlers to select on alongside their own request context.
+func (h *Hub) Done() <-chan struct{} {
+	return h.stopChan
+}
+
+// Registry is the per-client fan-out registry shared by sinks (mjpeg,
+// webrtc) that push every frame to every currently connected client over
+// its own buffered channel, dropping a frame for any client whose channel is
+// currently full instead of blocking the broadcast loop on a slow reader.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[chan []byte]struct{}
+}
+
+// NewRegistry creates an empty client Registry.
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[chan []byte]struct{})}
+}
+
+// Register adds a new client channel, buffered to bufferFrames, and returns
+// it along with an unregister func the caller must call exactly once (by
+// convention, deferred right after Register) to remove and close it.
+func (r *Registry) Register(bufferFrames int) (ch chan []byte, unregister func()) {
+	clientChan := make(chan []byte, bufferFrames)
+
+	r.mu.Lock()
+	r.clients[clientChan] = struct{}{}
+	r.mu.Unlock()
+
+	return clientChan, func() {
+		r.mu.Lock()
+		delete(r.clients, clientChan)
+		r.mu.Unlock()
+		close(clientChan)
+	}
+}
+
+// Broadcast sends frame to every registered client's channel, dropping it
+// (and calling onDrop, if non-nil) for any client whose channel is full
+// rather than blocking on a slow reader.
+func (r *Registry) Broadcast(frame []byte, onDrop func()) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for clientChan := range r.clients {
+		select {
+		case clientChan <- frame:
+		default:
+			if onDrop != nil {
+				onDrop()
+			}
+		}
+	}
+}
+
+// CloseAll closes every currently registered client channel and clears the
+// registry; called from Stop so any handler still blocked reading its
+// client channel wakes up with ok == false.
+func (r *Registry) CloseAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for clientChan := range r.clients {
+		close(clientChan)
+	}
+	r.clients = make(map[chan []byte]struct{})
+}
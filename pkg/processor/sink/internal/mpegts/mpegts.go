@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+// Package mpegts implements just enough MPEG-TS muxing to build
+// independently-playable HLS segments out of a stream of access units,
+// shared by every sink that serves an HLS playlist (currently the rtsp
+// sink's hlsMuxer and the hls sink).
+package mpegts
+
+import "encoding/binary"
+
+// MPEG-TS packets are always 188 bytes; PIDs below are picked out of the
+// reserved-for-private-use range to keep the PAT/PMT trivial.
+const (
+	PacketSize = 188
+	patPID     = 0x0000
+	pmtPID     = 0x1000
+	streamPID  = 0x0100
+
+	// streamTypePrivateData flags the PMT's elementary stream as opaque
+	// private data: every payload this muxer carries (MJPEG frames, H.264
+	// access units, ...) is a single access unit per PES, with no standard
+	// TS stream-type id that both matches the content and is widely
+	// understood by browsers' HLS players. Segments built here are
+	// structurally valid MPEG-TS, but are not guaranteed to render in every
+	// HLS client; see chunk3-6 for the planned H.264 path that will let this
+	// use a real stream type.
+	streamTypePrivateData = 0x06
+)
+
+// Muxer incrementally appends one PES-wrapped access unit per call to
+// WriteFrame, starting every segment with a PAT/PMT pair so it is
+// independently playable.
+type Muxer struct {
+	buf        []byte
+	continuity map[uint16]byte
+}
+
+// NewMuxer creates a Muxer and writes its PAT/PMT header.
+func NewMuxer() *Muxer {
+	m := &Muxer{continuity: make(map[uint16]byte)}
+	m.writePAT()
+	m.writePMT()
+	return m
+}
+
+// Bytes returns the segment built so far.
+func (m *Muxer) Bytes() []byte {
+	return m.buf
+}
+
+// WriteFrame PES-wraps data (presented at ptsNinetyKHz, a 33-bit 90kHz
+// timestamp) and appends it to the segment as one or more 188-byte packets.
+func (m *Muxer) WriteFrame(data []byte, ptsNinetyKHz int64) {
+	pes := buildPES(data, ptsNinetyKHz)
+	m.writePackets(streamPID, pes, true)
+}
+
+func (m *Muxer) writePAT() {
+	// PAT payload: table_id, section_length, transport_stream_id,
+	// version/current_next, section_number, last_section_number, one
+	// program entry (program 1 -> PMT PID), CRC32 (not validated by most
+	// HLS players so a zeroed placeholder is tolerated; kept explicit to
+	// flag the simplification rather than silently omitting it).
+	section := []byte{
+		0x00,       // table id
+		0xb0, 0x0d, // section_syntax_indicator=1, length=13
+		0x00, 0x01, // transport_stream_id
+		0xc1,       // version=0, current_next=1
+		0x00, 0x00, // section/last_section number
+		0x00, 0x01, // program_number=1
+		0xe0 | byte(pmtPID>>8), byte(pmtPID), // PMT PID
+		0x00, 0x00, 0x00, 0x00, // CRC32 placeholder
+	}
+	m.writePackets(patPID, withPointerField(section), true)
+}
+
+func (m *Muxer) writePMT() {
+	section := []byte{
+		0x02,       // table id
+		0xb0, 0x12, // section_syntax_indicator=1, length=18
+		0x00, 0x01, // program_number
+		0xc1,       // version=0, current_next=1
+		0x00, 0x00, // section/last_section number
+		0xe0 | byte(streamPID>>8), byte(streamPID), // PCR PID
+		0xf0, 0x00, // program_info_length=0
+		streamTypePrivateData,
+		0xe0 | byte(streamPID>>8), byte(streamPID),
+		0xf0, 0x00, // ES_info_length=0
+		0x00, 0x00, 0x00, 0x00, // CRC32 placeholder
+	}
+	m.writePackets(pmtPID, withPointerField(section), true)
+}
+
+// withPointerField prefixes a PSI section with the single pointer_field
+// byte TS requires when payload_unit_start_indicator is set.
+func withPointerField(section []byte) []byte {
+	return append([]byte{0x00}, section...)
+}
+
+// buildPES wraps data in a minimal PES header carrying a PTS-only
+// timestamp (stream_id 0xbd, private_stream_1, matching the PMT's private
+// stream type above).
+func buildPES(data []byte, ptsNinetyKHz int64) []byte {
+	header := make([]byte, 0, 19+len(data))
+	header = append(header, 0x00, 0x00, 0x01, 0xbd) // start code + stream id
+	packetLen := 3 + 5 + len(data)
+	if packetLen > 0xffff {
+		packetLen = 0
+	}
+	header = append(header, byte(packetLen>>8), byte(packetLen))
+	header = append(header, 0x80, 0x80, 0x05) // marker bits, PTS-only flag, PTS header length
+	header = append(header, encodePTS(ptsNinetyKHz)...)
+	return append(header, data...)
+}
+
+// encodePTS packs a 33-bit 90kHz timestamp into MPEG-TS's 5-byte PTS-only
+// encoding (0010 prefix, bit-interleaved marker bits).
+func encodePTS(pts int64) []byte {
+	v := uint64(pts) & 0x1ffffffff
+	out := make([]byte, 5)
+	out[0] = 0x21 | byte((v>>29)&0x0e)
+	binary.BigEndian.PutUint16(out[1:3], uint16((v>>14)&0xfffe)|1)
+	binary.BigEndian.PutUint16(out[3:5], uint16((v<<1)&0xfffe)|1)
+	return out
+}
+
+// writePackets splits payload into 188-byte TS packets on pid, setting
+// payload_unit_start_indicator on the first packet when pusi is true.
+func (m *Muxer) writePackets(pid uint16, payload []byte, pusi bool) {
+	first := true
+	for len(payload) > 0 {
+		pkt := make([]byte, PacketSize)
+		pkt[0] = 0x47
+		flags := byte(0)
+		if first && pusi {
+			flags |= 0x40
+		}
+		pkt[1] = flags | byte(pid>>8)
+		pkt[2] = byte(pid)
+
+		cc := m.continuity[pid]
+		m.continuity[pid] = (cc + 1) & 0x0f
+
+		headerLen := 4
+		n := PacketSize - headerLen
+		if n > len(payload) {
+			// pad the last packet with an adaptation field stuffed with 0xff
+			stuffLen := n - len(payload)
+			pkt[3] = 0x30 | cc
+			pkt[4] = byte(stuffLen - 1)
+			if stuffLen > 1 {
+				pkt[5] = 0x00
+				for i := 6; i < 4+stuffLen; i++ {
+					pkt[i] = 0xff
+				}
+			}
+			copy(pkt[4+stuffLen:], payload)
+			payload = nil
+		} else {
+			pkt[3] = 0x10 | cc
+			copy(pkt[4:], payload[:n])
+			payload = payload[n:]
+		}
+
+		m.buf = append(m.buf, pkt...)
+		first = false
+	}
+}
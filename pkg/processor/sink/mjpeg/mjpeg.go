@@ -1,4 +1,4 @@
-// SPDX-FileCopyrightText: Â© 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
 // SPDX-License-Identifier: Apache-2.0
 
 package mjpeg
@@ -7,12 +7,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/nuclio/logger"
 	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink/internal/framehub"
 )
 
 // Configuration for MJPEG sink
@@ -26,12 +26,8 @@ type Configuration struct {
 type Sink struct {
 	logger        logger.Logger
 	configuration *Configuration
-	server        *http.Server
-	frameChan     chan []byte
-	clients       map[chan []byte]struct{}
-	clientsMux    sync.RWMutex
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
+	hub           *framehub.Hub
+	clients       *framehub.Registry
 }
 
 // factory implements sink.Factory
@@ -48,13 +44,14 @@ func (f *factory) Create(logger logger.Logger, configuration map[string]interfac
 		return nil, fmt.Errorf("failed to parse mjpeg sink configuration: %w", err)
 	}
 
-	return &Sink{
+	s := &Sink{
 		logger:        logger,
 		configuration: config,
-		frameChan:     make(chan []byte, 10),
-		clients:       make(map[chan []byte]struct{}),
-		stopChan:      make(chan struct{}),
-	}, nil
+		clients:       framehub.NewRegistry(),
+	}
+	s.hub = framehub.NewHub(logger, 10, 100*time.Millisecond, s.broadcastFrame)
+
+	return s, nil
 }
 
 func (f *factory) GetKind() string {
@@ -68,66 +65,21 @@ func (s *Sink) Start() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc(s.configuration.Path, s.handleStream)
 
-	s.server = &http.Server{
-		Addr:    fmt.Sprintf(":%d", s.configuration.Port),
-		Handler: mux,
-	}
-
-	// Start frame broadcaster
-	s.wg.Add(1)
-	go s.broadcastFrames()
-
-	// Start HTTP server
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			s.logger.ErrorWith("MJPEG server error", "error", err)
-		}
-	}()
-
-	return nil
+	return s.hub.Start(fmt.Sprintf(":%d", s.configuration.Port), mux)
 }
 
 // Stop stops the MJPEG sink
 func (s *Sink) Stop(force bool) error {
 	s.logger.InfoWith("Stopping MJPEG sink", "force", force)
 
-	close(s.stopChan)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := s.server.Shutdown(ctx); err != nil {
-		s.logger.WarnWith("MJPEG server shutdown error", "error", err)
-	}
-
-	s.wg.Wait()
-
-	close(s.frameChan)
-
-	s.clientsMux.Lock()
-	for clientChan := range s.clients {
-		close(clientChan)
-	}
-	s.clients = make(map[chan []byte]struct{})
-	s.clientsMux.Unlock()
-
-	return nil
+	err := s.hub.Stop()
+	s.clients.CloseAll()
+	return err
 }
 
 // Write sends a frame to the MJPEG stream
 func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
-	select {
-	case s.frameChan <- data:
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(100 * time.Millisecond):
-		// Drop frame if channel is full
-		s.logger.DebugWith("Dropping frame - channel full")
-		return nil
-	}
+	return s.hub.Write(ctx, data)
 }
 
 // GetKind returns the sink type
@@ -152,17 +104,9 @@ func (s *Sink) handleStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "close")
 
-	clientChan := make(chan []byte, 5)
-
-	s.clientsMux.Lock()
-	s.clients[clientChan] = struct{}{}
-	s.clientsMux.Unlock()
-
+	clientChan, unregister := s.clients.Register(5)
 	defer func() {
-		s.clientsMux.Lock()
-		delete(s.clients, clientChan)
-		s.clientsMux.Unlock()
-		close(clientChan)
+		unregister()
 		s.logger.DebugWith("MJPEG client disconnected", "remote", r.RemoteAddr)
 	}()
 
@@ -208,39 +152,18 @@ func (s *Sink) handleStream(w http.ResponseWriter, r *http.Request) {
 		case <-r.Context().Done():
 			return
 
-		case <-s.stopChan:
+		case <-s.hub.Done():
 			return
 		}
 	}
 }
 
-// broadcastFrames broadcasts frames to all connected clients
-func (s *Sink) broadcastFrames() {
-	defer s.wg.Done()
-
-	for {
-		select {
-		case frame, ok := <-s.frameChan:
-			if !ok {
-				return
-			}
-
-			s.clientsMux.RLock()
-			for clientChan := range s.clients {
-				select {
-				case clientChan <- frame:
-					// Frame sent successfully
-				default:
-					// Client channel full - drop frame for this client
-					s.logger.DebugWith("Dropping frame for slow client")
-				}
-			}
-			s.clientsMux.RUnlock()
-
-		case <-s.stopChan:
-			return
-		}
-	}
+// broadcastFrame fans frame out to every connected client, dropping it for
+// any client whose channel is currently full.
+func (s *Sink) broadcastFrame(frame []byte) {
+	s.clients.Broadcast(frame, func() {
+		s.logger.DebugWith("Dropping frame for slow client")
+	})
 }
 
 func init() {
@@ -5,53 +5,242 @@ package sink
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/nuclio/logger"
 )
 
+// unversioned is the version key Register stores a plain (non-versioned)
+// factory under, keeping Get/Create backward compatible for callers that
+// never heard of versions.
+const unversioned = ""
+
+// versionedFactory pairs a registered Factory with the JSON-schema-like
+// description of the configuration it accepts, used by Create to validate
+// incoming configuration before invoking factory.Create.
+type versionedFactory struct {
+	factory Factory
+	schema  map[string]interface{}
+}
+
+// FactoryInfo describes everything a registered kind currently offers, as
+// returned by Registry.Describe.
+type FactoryInfo struct {
+	Kind     string
+	Versions []string
+
+	// Schema is the newest version's accepted-configuration schema, or nil
+	// if that version was registered without one.
+	Schema map[string]interface{}
+}
+
 // Registry manages sink factories
 type Registry struct {
 	lock      sync.RWMutex
-	factories map[string]Factory
+	factories map[string]map[string]*versionedFactory // kind -> version -> factory
 }
 
 // NewRegistry creates a new registry
 func NewRegistry() *Registry {
 	return &Registry{
-		factories: make(map[string]Factory),
+		factories: make(map[string]map[string]*versionedFactory),
 	}
 }
 
-// Register registers a sink factory
+// Register registers a sink factory under its single, unversioned slot,
+// overwriting whatever was registered there before. Equivalent to
+// RegisterVersioned(kind, "", factory, nil).
 func (r *Registry) Register(kind string, factory Factory) {
+	r.RegisterVersioned(kind, unversioned, factory, nil)
+}
+
+// RegisterVersioned registers factory as kind's implementation for version,
+// alongside the JSON-schema-like description (see Create) of the
+// configuration it accepts. Registering the same kind/version pair again
+// overwrites the previous entry, so two versions of a sink can be deployed
+// side by side and traffic shifted by changing the version callers pass to
+// Create.
+func (r *Registry) RegisterVersioned(kind, version string, factory Factory, schema map[string]interface{}) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	r.factories[kind] = factory
+	if r.factories[kind] == nil {
+		r.factories[kind] = make(map[string]*versionedFactory)
+	}
+
+	r.factories[kind][version] = &versionedFactory{
+		factory: factory,
+		schema:  schema,
+	}
 }
 
-// Get retrieves a factory by kind
+// Unregister removes every version of kind from the registry.
+func (r *Registry) Unregister(kind string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.factories[kind]; !ok {
+		return fmt.Errorf("sink factory not found: %s", kind)
+	}
+
+	delete(r.factories, kind)
+	return nil
+}
+
+// Get retrieves the newest registered factory for kind.
 func (r *Registry) Get(kind string) (Factory, error) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
-	factory, ok := r.factories[kind]
+	entry, err := r.resolve(kind, unversioned)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.factory, nil
+}
+
+// resolve returns kind's factory entry for version, falling back to the
+// newest registered version when version is empty. Callers must hold lock.
+func (r *Registry) resolve(kind, version string) (*versionedFactory, error) {
+	versions, ok := r.factories[kind]
 	if !ok {
 		return nil, fmt.Errorf("sink factory not found: %s", kind)
 	}
 
-	return factory, nil
+	if version != "" {
+		entry, ok := versions[version]
+		if !ok {
+			return nil, fmt.Errorf("sink factory %q has no version %q", kind, version)
+		}
+		return entry, nil
+	}
+
+	return versions[newestVersion(versions)], nil
+}
+
+// newestVersion returns the highest version key in versions, comparing
+// dot-separated numeric segments where possible and falling back to a plain
+// string comparison otherwise. Callers must hold lock.
+func newestVersion(versions map[string]*versionedFactory) string {
+	var newest string
+	first := true
+
+	for version := range versions {
+		if first || compareVersions(version, newest) > 0 {
+			newest = version
+			first = false
+		}
+	}
+
+	return newest
 }
 
-// Create creates a new sink instance
-func (r *Registry) Create(logger logger.Logger, kind string, configuration map[string]interface{}) (Sink, error) {
-	factory, err := r.Get(kind)
+// compareVersions compares two dot-separated version strings numerically
+// segment by segment (e.g. "2.10" > "2.9"), falling back to a lexical
+// comparison of the raw strings if either side doesn't parse as numeric
+// segments (e.g. the unversioned "" slot always sorts lowest).
+func compareVersions(a, b string) int {
+	aParts, aOK := parseVersion(a)
+	bParts, bOK := parseVersion(b)
+
+	if !aOK || !bOK {
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aSeg, bSeg int
+		if i < len(aParts) {
+			aSeg = aParts[i]
+		}
+		if i < len(bParts) {
+			bSeg = bParts[i]
+		}
+		if aSeg != bSeg {
+			return aSeg - bSeg
+		}
+	}
+
+	return 0
+}
+
+func parseVersion(version string) ([]int, bool) {
+	if version == "" {
+		return nil, false
+	}
+
+	segments := strings.Split(version, ".")
+	parts := make([]int, len(segments))
+	for i, segment := range segments {
+		n, err := strconv.Atoi(segment)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = n
+	}
+
+	return parts, true
+}
+
+// Create creates a new sink instance of kind. version selects which
+// registered implementation to use, falling back to the newest registered
+// version when omitted or empty; passing more than one version is an error.
+// If that version was registered with a schema (see RegisterVersioned),
+// configuration is validated against it first, returning a *ValidationError
+// listing every offending field instead of invoking factory.Create.
+func (r *Registry) Create(logger logger.Logger, kind string, configuration map[string]interface{}, version ...string) (Sink, error) {
+	if len(version) > 1 {
+		return nil, fmt.Errorf("sink %q: at most one version may be specified, got %d", kind, len(version))
+	}
+
+	var requestedVersion string
+	if len(version) == 1 {
+		requestedVersion = version[0]
+	}
+
+	r.lock.RLock()
+	entry, err := r.resolve(kind, requestedVersion)
+	r.lock.RUnlock()
 	if err != nil {
 		return nil, err
 	}
 
-	return factory.Create(logger, configuration)
+	if entry.schema != nil {
+		if fields := validateConfiguration(entry.schema, configuration); len(fields) > 0 {
+			return nil, &ValidationError{Kind: kind, Fields: fields}
+		}
+	}
+
+	return entry.factory.Create(logger, configuration)
+}
+
+// Describe returns kind's registered versions (newest last) and the newest
+// version's configuration schema.
+func (r *Registry) Describe(kind string) (FactoryInfo, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	versions, ok := r.factories[kind]
+	if !ok {
+		return FactoryInfo{}, fmt.Errorf("sink factory not found: %s", kind)
+	}
+
+	sortedVersions := make([]string, 0, len(versions))
+	for version := range versions {
+		sortedVersions = append(sortedVersions, version)
+	}
+	sort.Slice(sortedVersions, func(i, j int) bool {
+		return compareVersions(sortedVersions[i], sortedVersions[j]) < 0
+	})
+
+	return FactoryInfo{
+		Kind:     kind,
+		Versions: sortedVersions,
+		Schema:   versions[newestVersion(versions)].schema,
+	}, nil
 }
 
 // GetRegisteredKinds returns a list of all registered sink kinds
@@ -59,6 +59,73 @@ func (suite *RegistryTestSuite) TestGetRegisteredKinds() {
 	suite.Contains(kinds, "test2")
 }
 
+func (suite *RegistryTestSuite) TestUnregister() {
+	suite.registry.Register("test", &mockFactory{kind: "test"})
+
+	suite.NoError(suite.registry.Unregister("test"))
+
+	_, err := suite.registry.Get("test")
+	suite.Error(err)
+}
+
+func (suite *RegistryTestSuite) TestUnregisterNotFound() {
+	err := suite.registry.Unregister("nonexistent")
+	suite.Error(err)
+	suite.Contains(err.Error(), "sink factory not found")
+}
+
+func (suite *RegistryTestSuite) TestRegisterVersionedCreateFallsBackToNewest() {
+	suite.registry.RegisterVersioned("test", "1.0", &mockFactory{kind: "test"}, nil)
+	suite.registry.RegisterVersioned("test", "2.0", &mockFactory{kind: "test"}, nil)
+
+	sink, err := suite.registry.Create(nil, "test", map[string]interface{}{})
+	suite.NoError(err)
+	suite.NotNil(sink)
+
+	_, err = suite.registry.Create(nil, "test", map[string]interface{}{}, "1.0")
+	suite.NoError(err)
+
+	_, err = suite.registry.Create(nil, "test", map[string]interface{}{}, "9.9")
+	suite.Error(err)
+}
+
+func (suite *RegistryTestSuite) TestDescribe() {
+	schemaV1 := map[string]interface{}{"required": []string{"url"}}
+	suite.registry.RegisterVersioned("test", "1.0", &mockFactory{kind: "test"}, schemaV1)
+	suite.registry.RegisterVersioned("test", "2.0", &mockFactory{kind: "test"}, nil)
+
+	info, err := suite.registry.Describe("test")
+	suite.NoError(err)
+	suite.Equal([]string{"1.0", "2.0"}, info.Versions)
+	suite.Nil(info.Schema)
+}
+
+func (suite *RegistryTestSuite) TestDescribeNotFound() {
+	_, err := suite.registry.Describe("nonexistent")
+	suite.Error(err)
+}
+
+func (suite *RegistryTestSuite) TestCreateValidatesSchema() {
+	schema := map[string]interface{}{
+		"required": []string{"url"},
+		"properties": map[string]interface{}{
+			"url":  map[string]interface{}{"type": "string"},
+			"port": map[string]interface{}{"type": "number"},
+		},
+	}
+	suite.registry.RegisterVersioned("test", "1.0", &mockFactory{kind: "test"}, schema)
+
+	_, err := suite.registry.Create(nil, "test", map[string]interface{}{"url": "http://x", "port": 8080})
+	suite.NoError(err)
+
+	_, err = suite.registry.Create(nil, "test", map[string]interface{}{"port": "not-a-number"})
+	suite.Error(err)
+
+	validationErr, ok := err.(*ValidationError)
+	suite.True(ok)
+	suite.Len(validationErr.Fields, 2) // missing "url", wrong type for "port"
+}
+
 func TestRegistryTestSuite(t *testing.T) {
 	suite.Run(t, new(RegistryTestSuite))
 }
@@ -0,0 +1,367 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v5"
+	"github.com/bluenviron/gortsplib/v5/pkg/base"
+	"github.com/bluenviron/gortsplib/v5/pkg/description"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/nuclio/logger"
+)
+
+const (
+	broadcastBackoffInitial = time.Second
+	broadcastBackoffMax     = 30 * time.Second
+)
+
+// BroadcastManager republishes every frame the sink writes to a remote
+// rtsp:// or rtmp:// endpoint, reconnecting with exponential backoff when
+// the remote end goes away. It subscribes to the same RingBuffer the
+// HLS/RTMP egress pipelines use, so a broadcast is just another reader and
+// does not require its own encode path.
+//
+// Unlike the HLS/RTMP Path readers, a broadcast is outbound and has no
+// viewer to gate it: it runs whenever the sink decides to, via Start/Stop,
+// independent of whether anyone is pulling from the sink locally.
+type BroadcastManager struct {
+	logger logger.Logger
+	sink   *Sink
+	url    string
+
+	mu     sync.Mutex
+	active bool
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newBroadcastManager creates a BroadcastManager that, once started,
+// forwards frames to url.
+func newBroadcastManager(log logger.Logger, s *Sink, url string) *BroadcastManager {
+	return &BroadcastManager{
+		logger: log,
+		sink:   s,
+		url:    url,
+	}
+}
+
+// IsActive reports whether the broadcast loop is currently running.
+func (m *BroadcastManager) IsActive() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active
+}
+
+// Start begins forwarding frames to m.url. It is a no-op if already active.
+func (m *BroadcastManager) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active {
+		return nil
+	}
+
+	m.active = true
+	m.stop = make(chan struct{})
+
+	m.wg.Add(1)
+	go m.run(m.stop)
+
+	return nil
+}
+
+// Stop halts the broadcast loop. It is a no-op if not active.
+func (m *BroadcastManager) Stop() {
+	m.mu.Lock()
+	if !m.active {
+		m.mu.Unlock()
+		return
+	}
+	m.active = false
+	close(m.stop)
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// run reconnects to m.url with exponential backoff, forwarding ring buffer
+// entries for as long as each connection stays up.
+func (m *BroadcastManager) run(stop <-chan struct{}) {
+	defer m.wg.Done()
+
+	sub, unsubscribe := m.sink.ringBuffer.Subscribe()
+	defer unsubscribe()
+
+	backoff := broadcastBackoffInitial
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		err := m.publish(stop, sub)
+		if err == nil {
+			return // stop was closed mid-publish
+		}
+
+		m.logger.WarnWith("Broadcast connection failed, retrying", "url", m.url, "error", err, "backoff", backoff)
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > broadcastBackoffMax {
+			backoff = broadcastBackoffMax
+		}
+	}
+}
+
+// publish opens one connection to m.url and forwards ring entries to it
+// until the connection errors out or stop is closed. A nil return means
+// stop was closed; any other return is a connection error worth retrying.
+func (m *BroadcastManager) publish(stop <-chan struct{}, sub <-chan ringEntry) error {
+	if strings.HasPrefix(m.url, "rtmp://") {
+		return m.publishRTMP(stop, sub)
+	}
+	return m.publishRTSP(stop, sub)
+}
+
+// publishRTSP connects to an rtsp:// target as a client and forwards each
+// already-encoded RTP packet via ANNOUNCE/SETUP/RECORD.
+func (m *BroadcastManager) publishRTSP(stop <-chan struct{}, sub <-chan ringEntry) error {
+	u, err := base.ParseURL(m.url)
+	if err != nil {
+		return fmt.Errorf("failed to parse broadcast URL: %w", err)
+	}
+
+	desc, media, err := m.sink.outgoingDescription()
+	if err != nil {
+		return err
+	}
+
+	client := &gortsplib.Client{}
+	if err := client.Start(u.Scheme, u.Host); err != nil {
+		return fmt.Errorf("failed to connect to broadcast target: %w", err)
+	}
+	defer client.Close()
+
+	desc.BaseURL = u
+	if err := client.Announce(u, desc); err != nil {
+		return fmt.Errorf("failed to ANNOUNCE to broadcast target: %w", err)
+	}
+
+	if err := client.Setup(desc.BaseURL, media, 0, 0); err != nil {
+		return fmt.Errorf("failed to SETUP broadcast target: %w", err)
+	}
+
+	if _, err := client.Record(); err != nil {
+		return fmt.Errorf("failed to RECORD to broadcast target: %w", err)
+	}
+
+	m.logger.InfoWith("Broadcasting to RTSP target", "url", m.url)
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case entry, ok := <-sub:
+			if !ok {
+				return fmt.Errorf("ring buffer closed")
+			}
+			for _, pkt := range entry.packets {
+				if err := client.WritePacketRTP(media, pkt); err != nil {
+					return fmt.Errorf("failed to write RTP packet to broadcast target: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// publishRTMP connects to an rtmp:// target, performs the handshake and a
+// minimal publish announcement, then forwards frames as FLV video tags.
+func (m *BroadcastManager) publishRTMP(stop <-chan struct{}, sub <-chan ringEntry) error {
+	addr, streamPath := splitRTMPURL(m.url)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to broadcast target: %w", err)
+	}
+	defer conn.Close()
+
+	if err := rtmpClientHandshake(conn); err != nil {
+		return fmt.Errorf("broadcast RTMP handshake failed: %w", err)
+	}
+
+	if err := rtmpSendPublish(conn, streamPath); err != nil {
+		return fmt.Errorf("failed to send RTMP publish command: %w", err)
+	}
+
+	m.logger.InfoWith("Broadcasting to RTMP target", "url", m.url)
+
+	start := time.Now()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case entry, ok := <-sub:
+			if !ok {
+				return fmt.Errorf("ring buffer closed")
+			}
+			if err := writeFLVVideoTag(conn, entry.raw, time.Since(start)); err != nil {
+				return fmt.Errorf("failed to write FLV tag to broadcast target: %w", err)
+			}
+		}
+	}
+}
+
+// outgoingDescription builds the description.Session/Media pair describing
+// this sink's own stream, for use as the ANNOUNCE body when broadcasting
+// out over RTSP.
+func (s *Sink) outgoingDescription() (*description.Session, *description.Media, error) {
+	if s.configuration.Type == "video" {
+		if s.mjpegFormat == nil {
+			return nil, nil, fmt.Errorf("sink has no video format to broadcast")
+		}
+		m := &description.Media{
+			Type:    description.MediaTypeVideo,
+			Formats: []format.Format{s.mjpegFormat},
+		}
+		return &description.Session{Medias: []*description.Media{m}}, m, nil
+	}
+
+	if s.configuration.Type == "h264" || s.configuration.Type == "h265" {
+		var videoFormat format.Format
+		if s.configuration.Type == "h264" && s.h264Format != nil {
+			videoFormat = s.h264Format
+		} else if s.configuration.Type == "h265" && s.h265Format != nil {
+			videoFormat = s.h265Format
+		} else {
+			return nil, nil, fmt.Errorf("sink has no video format to broadcast")
+		}
+		m := &description.Media{
+			Type:    description.MediaTypeVideo,
+			Formats: []format.Format{videoFormat},
+		}
+		return &description.Session{Medias: []*description.Media{m}}, m, nil
+	}
+
+	if s.lpcmFormat == nil {
+		return nil, nil, fmt.Errorf("sink has no audio format to broadcast")
+	}
+	m := &description.Media{
+		Type:    description.MediaTypeAudio,
+		Formats: []format.Format{s.lpcmFormat},
+	}
+	return &description.Session{Medias: []*description.Media{m}}, m, nil
+}
+
+// splitRTMPURL splits an rtmp://host:port/app/stream URL into its TCP
+// address (defaulting the port to 1935) and the app/stream path to publish
+// to.
+func splitRTMPURL(url string) (addr string, streamPath string) {
+	rest := strings.TrimPrefix(url, "rtmp://")
+
+	slash := strings.IndexByte(rest, '/')
+	if slash == -1 {
+		return ensurePort(rest), ""
+	}
+
+	host := rest[:slash]
+	return ensurePort(host), rest[slash+1:]
+}
+
+func ensurePort(host string) string {
+	if strings.Contains(host, ":") {
+		return host
+	}
+	return host + ":1935"
+}
+
+// rtmpClientHandshake performs the RTMP handshake as the connecting client:
+// send C0/C1, read S0/S1/S2, send C2. As in the server-side handshake in
+// rtmp.go, the random handshake payload is echoed back without validation.
+func rtmpClientHandshake(conn net.Conn) error {
+	c0c1 := make([]byte, 1+1536)
+	c0c1[0] = 3 // RTMP version 3
+	if _, err := conn.Write(c0c1); err != nil {
+		return err
+	}
+
+	s0s1s2 := make([]byte, 1+1536+1536)
+	if _, err := readFull(conn, s0s1s2); err != nil {
+		return err
+	}
+
+	c2 := s0s1s2[1 : 1+1536]
+	_, err := conn.Write(c2)
+	return err
+}
+
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// rtmpSendPublish sends a minimal AMF0 "publish" command on chunk stream 3,
+// enough for servers (mediamtx, nginx-rtmp) to start accepting our video
+// tags for streamPath. It does not wait for or parse the server's
+// onStatus reply.
+func rtmpSendPublish(conn net.Conn, streamPath string) error {
+	body := encodeAMF0String("publish")
+	body = append(body, encodeAMF0Number(0)...) // transaction id
+	body = append(body, 0x05)                   // AMF0 null (command object)
+	body = append(body, encodeAMF0String(streamPath)...)
+	body = append(body, encodeAMF0String("live")...)
+
+	header := []byte{
+		0x03,    // chunk basic header: fmt=0, chunk stream id=3
+		0, 0, 0, // timestamp (0)
+		byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body)), // message length
+		0x14,       // message type id: AMF0 command
+		0, 0, 0, 0, // message stream id
+	}
+
+	w := bufio.NewWriter(conn)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func encodeAMF0String(s string) []byte {
+	out := []byte{0x02, byte(len(s) >> 8), byte(len(s))}
+	return append(out, []byte(s)...)
+}
+
+func encodeAMF0Number(n float64) []byte {
+	bits := make([]byte, 9)
+	bits[0] = 0x00
+	u := math.Float64bits(n)
+	for i := 0; i < 8; i++ {
+		bits[1+i] = byte(u >> (56 - 8*i))
+	}
+	return bits
+}
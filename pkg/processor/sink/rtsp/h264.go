@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// h264NALUType / h265NALUType are the NAL unit type values carrying
+// parameter sets, used by splitParameterSets to pull SPS/PPS/VPS out of an
+// Annex-B access unit before the rest is handed to the RTP encoder.
+const (
+	h264NALUTypeSPS = 7
+	h264NALUTypePPS = 8
+
+	h265NALUTypeVPS = 32
+	h265NALUTypeSPS = 33
+	h265NALUTypePPS = 34
+)
+
+// annexBStartCode is the longer of the two Annex-B start codes; splitAnnexB
+// also accepts the 3-byte form.
+var annexBStartCode = []byte{0x00, 0x00, 0x00, 0x01}
+
+// splitAnnexB splits an Annex-B bitstream (as produced by ffmpeg's raw
+// h264/hevc muxers) into its constituent NAL units, stripping start codes.
+func splitAnnexB(data []byte) [][]byte {
+	var nalus [][]byte
+
+	start := -1
+	i := 0
+	for i < len(data) {
+		code, codeLen := matchStartCode(data[i:])
+		if code {
+			if start >= 0 {
+				nalus = append(nalus, data[start:i])
+			}
+			i += codeLen
+			start = i
+			continue
+		}
+		i++
+	}
+	if start >= 0 && start < len(data) {
+		nalus = append(nalus, data[start:])
+	}
+	return nalus
+}
+
+func matchStartCode(b []byte) (bool, int) {
+	if bytes.HasPrefix(b, annexBStartCode) {
+		return true, 4
+	}
+	if len(b) >= 3 && b[0] == 0 && b[1] == 0 && b[2] == 1 {
+		return true, 3
+	}
+	return false, 0
+}
+
+// splitH264ParameterSets separates SPS/PPS NAL units out of nalus, returning
+// them alongside the remaining (non-parameter-set) NAL units in order.
+func splitH264ParameterSets(nalus [][]byte) (sps, pps []byte, rest [][]byte) {
+	for _, nalu := range nalus {
+		if len(nalu) == 0 {
+			continue
+		}
+		switch nalu[0] & 0x1f {
+		case h264NALUTypeSPS:
+			sps = nalu
+		case h264NALUTypePPS:
+			pps = nalu
+		default:
+			rest = append(rest, nalu)
+		}
+	}
+	return
+}
+
+// splitH265ParameterSets is the HEVC equivalent of splitH264ParameterSets;
+// HEVC NAL headers are two bytes with the type in bits 1-6 of the first.
+func splitH265ParameterSets(nalus [][]byte) (vps, sps, pps []byte, rest [][]byte) {
+	for _, nalu := range nalus {
+		if len(nalu) == 0 {
+			continue
+		}
+		switch (nalu[0] >> 1) & 0x3f {
+		case h265NALUTypeVPS:
+			vps = nalu
+		case h265NALUTypeSPS:
+			sps = nalu
+		case h265NALUTypePPS:
+			pps = nalu
+		default:
+			rest = append(rest, nalu)
+		}
+	}
+	return
+}
+
+// videoTranscoder spawns a persistent ffmpeg process that transcodes a raw
+// MJPEG stream fed to its stdin into an Annex-B H.264/H.265 bitstream on
+// its stdout. Frames go in and NAL units come out asynchronously (ffmpeg's
+// own buffering and B-frame reordering mean there is no 1:1 relationship
+// between a call to writeJPEG and any particular readNALUs result).
+type videoTranscoder struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	buf    []byte
+}
+
+// newVideoTranscoder spawns ffmpeg to transcode incoming MJPEG frames to
+// codec ("h264" or "h265"), using encoder as the ffmpeg -c:v value (e.g.
+// "libx264"/"libx265").
+func newVideoTranscoder(ctx context.Context, codec, encoder string) (*videoTranscoder, error) {
+	outputFormat := "h264"
+	if codec == "h265" {
+		outputFormat = "hevc"
+	}
+
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-f", "mjpeg", "-i", "pipe:0",
+		"-c:v", encoder,
+		"-preset", "veryfast",
+		"-tune", "zerolatency",
+		"-f", outputFormat, "pipe:1")
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ffmpeg stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ffmpeg stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	return &videoTranscoder{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: bufio.NewReaderSize(stdout, 256*1024),
+	}, nil
+}
+
+// writeJPEG feeds one raw JPEG frame to ffmpeg's stdin.
+func (t *videoTranscoder) writeJPEG(data []byte) error {
+	_, err := t.stdin.Write(data)
+	return err
+}
+
+// readNALUs blocks until at least one more NAL unit can be extracted from
+// ffmpeg's stdout, growing an internal buffer across reads as needed.
+func (t *videoTranscoder) readNALUs() ([][]byte, error) {
+	chunk := make([]byte, 64*1024)
+	for {
+		nalus := splitAnnexB(t.buf)
+		// Keep the last NAL unit (which may still be incomplete) buffered
+		// until the next start code confirms it ended.
+		if len(nalus) > 1 {
+			complete := nalus[:len(nalus)-1]
+			last := nalus[len(nalus)-1]
+			t.buf = append([]byte{}, last...)
+			return complete, nil
+		}
+
+		n, err := t.stdout.Read(chunk)
+		if n > 0 {
+			t.buf = append(t.buf, chunk[:n]...)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// close kills the ffmpeg subprocess and releases its pipes.
+func (t *videoTranscoder) close() error {
+	t.stdin.Close()
+	if t.cmd.Process != nil {
+		_ = t.cmd.Process.Kill()
+		_ = t.cmd.Wait()
+	}
+	return nil
+}
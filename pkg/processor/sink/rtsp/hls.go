@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink/internal/mpegts"
+)
+
+// hlsSegment is one rolling segment of the playlist.
+type hlsSegment struct {
+	index    int
+	duration time.Duration
+	data     []byte
+}
+
+// hlsMuxer re-packages the frames a Sink pushes into its RingBuffer as a
+// rolling window of MPEG-TS segments, served over HTTP as an HLS playlist.
+// The segmenter goroutine only runs while the path has readers: ServeHTTP
+// marks the path as touched on every request, and an idle timer removes
+// that reader once no request has arrived for closeAfter, since plain HTTP
+// carries no persistent session to hook a disconnect callback into.
+type hlsMuxer struct {
+	logger logger.Logger
+	path   *path
+
+	segmentDuration time.Duration
+	segmentCount    int
+
+	mu        sync.Mutex
+	segments  []hlsSegment
+	nextIndex int
+	cur       *mpegts.Muxer
+	curStart  time.Time
+	curFrames int
+	havePTS   bool
+
+	active      atomic.Bool
+	lastRequest atomic.Int64 // unix nanos
+}
+
+// newHLSMuxer creates an hlsMuxer bound to path, which must already gate
+// start/stop on readers added via ServeHTTP.
+func newHLSMuxer(log logger.Logger, p *path, segmentDuration time.Duration, segmentCount int) *hlsMuxer {
+	return &hlsMuxer{
+		logger:          log,
+		path:            p,
+		segmentDuration: segmentDuration,
+		segmentCount:    segmentCount,
+	}
+}
+
+// run consumes the ring buffer until stop is closed, building a new TS
+// segment every segmentDuration and keeping only the last segmentCount of
+// them, the same rolling-window eviction the RTSP audio DataProcessorStream
+// uses for its own buffer.
+func (m *hlsMuxer) run(buffer *RingBuffer, stop <-chan struct{}) {
+	entries, unsubscribe := buffer.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			m.consume(entry)
+		}
+	}
+}
+
+func (m *hlsMuxer) consume(entry ringEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cur == nil {
+		m.cur = mpegts.NewMuxer()
+		m.curStart = time.Now()
+		m.havePTS = false
+	}
+
+	pts := int64(0)
+	if m.havePTS {
+		pts = int64(time.Since(m.curStart)/time.Microsecond) * 90 / 1000
+	}
+	m.cur.WriteFrame(entry.raw, pts)
+	m.havePTS = true
+	m.curFrames++
+
+	if time.Since(m.curStart) >= m.segmentDuration {
+		m.segments = append(m.segments, hlsSegment{
+			index:    m.nextIndex,
+			duration: time.Since(m.curStart),
+			data:     m.cur.Bytes(),
+		})
+		m.nextIndex++
+		if len(m.segments) > m.segmentCount {
+			m.segments = m.segments[len(m.segments)-m.segmentCount:]
+		}
+		m.cur = nil
+		m.curFrames = 0
+	}
+}
+
+// ServeHTTP serves the rolling playlist at /hls/{path}/index.m3u8 and its
+// segments at /hls/{path}/{n}.ts.
+func (m *hlsMuxer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lastRequest.Store(time.Now().UnixNano())
+
+	if m.active.CompareAndSwap(false, true) {
+		if err := m.path.addReader(); err != nil {
+			m.active.Store(false)
+			http.Error(w, "stream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	if strings.HasSuffix(r.URL.Path, ".ts") {
+		m.serveSegment(w, r)
+		return
+	}
+	m.servePlaylist(w, r)
+}
+
+func (m *hlsMuxer) servePlaylist(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	segments := append([]hlsSegment{}, m.segments...)
+	m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	b.WriteString("#EXT-X-VERSION:3\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", int(m.segmentDuration.Seconds())+1)
+	if len(segments) > 0 {
+		fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", segments[0].index)
+	}
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n%d.ts\n", seg.duration.Seconds(), seg.index)
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Write([]byte(b.String()))
+}
+
+func (m *hlsMuxer) serveSegment(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	idx, err := strconv.Atoi(strings.TrimSuffix(name, ".ts"))
+	if err != nil {
+		http.Error(w, "bad segment name", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	var data []byte
+	for _, seg := range m.segments {
+		if seg.index == idx {
+			data = seg.data
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Write(data)
+}
+
+// watchIdle releases the path's reader once no HTTP request has arrived for
+// closeAfter, letting the segmenter stop; the next request re-acquires it
+// via ServeHTTP.
+func (m *hlsMuxer) watchIdle(closeAfter time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(closeAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !m.active.Load() {
+				continue
+			}
+			idleFor := time.Since(time.Unix(0, m.lastRequest.Load()))
+			if idleFor >= closeAfter && m.active.CompareAndSwap(true, false) {
+				m.path.removeReader()
+			}
+		}
+	}
+}
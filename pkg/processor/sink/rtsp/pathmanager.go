@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"sync"
+	"time"
+)
+
+// path is a single named media source shared by every egress protocol
+// (RTSP/HLS/RTMP) bound to it. Its encoder pipeline - the HLS segmenter and
+// the RTMP publisher, both of which spend CPU re-muxing the ring buffer -
+// only runs while at least one reader is attached, and lingers for
+// closeAfter once the last reader disconnects so a quick reconnect doesn't
+// pay the startup cost again. RTSP itself needs no gating: its server
+// socket is always listening and sessions already come and go on their own.
+type path struct {
+	name       string
+	buffer     *RingBuffer
+	closeAfter time.Duration
+
+	start func() error
+	stop  func()
+
+	mu         sync.Mutex
+	readers    int
+	started    bool
+	closeTimer *time.Timer
+}
+
+// pathManager owns every path known to a Sink, keyed by Configuration.Path.
+type pathManager struct {
+	mu    sync.Mutex
+	paths map[string]*path
+}
+
+// newPathManager creates an empty pathManager.
+func newPathManager() *pathManager {
+	return &pathManager{paths: make(map[string]*path)}
+}
+
+// getOrCreatePath registers (or returns the already-registered) path for
+// name. start is invoked the first time a reader attaches (the 0->1 reader
+// transition); stop is invoked closeAfter after the last reader detaches,
+// unless a new reader arrives first.
+func (pm *pathManager) getOrCreatePath(name string, buffer *RingBuffer, closeAfter time.Duration, start func() error, stop func()) *path {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if p, ok := pm.paths[name]; ok {
+		return p
+	}
+
+	p := &path{
+		name:       name,
+		buffer:     buffer,
+		closeAfter: closeAfter,
+		start:      start,
+		stop:       stop,
+	}
+	pm.paths[name] = p
+
+	return p
+}
+
+// addReader increments the reader count, starting the pipeline on the
+// 0->1 transition and cancelling any pending on-demand shutdown.
+func (p *path) addReader() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closeTimer != nil {
+		p.closeTimer.Stop()
+		p.closeTimer = nil
+	}
+
+	p.readers++
+
+	if !p.started {
+		if err := p.start(); err != nil {
+			p.readers--
+			return err
+		}
+		p.started = true
+	}
+
+	return nil
+}
+
+// removeReader decrements the reader count, scheduling the pipeline to stop
+// after closeAfter once the last reader has gone.
+func (p *path) removeReader() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.readers > 0 {
+		p.readers--
+	}
+
+	if p.readers > 0 || !p.started {
+		return
+	}
+
+	p.closeTimer = time.AfterFunc(p.closeAfter, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.readers > 0 {
+			return
+		}
+
+		p.stop()
+		p.started = false
+		p.closeTimer = nil
+	})
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"sync"
+
+	"github.com/pion/rtp"
+)
+
+// ringEntry is one media unit produced by Sink.Write: the raw bytes passed
+// in (a JPEG frame or a PCM chunk) alongside the RTP packets gortsplib
+// encoded them into. Every egress protocol re-wraps raw in its own
+// container (HLS segments, RTMP FLV tags) except RTSP, which forwards
+// packets as-is.
+type ringEntry struct {
+	raw     []byte
+	packets []*rtp.Packet
+}
+
+// RingBuffer is a bounded fan-out buffer: Sink.Write is its only producer,
+// and every egress protocol (RTSP/HLS/RTMP) reads the same sequence of
+// entries through its own Subscribe channel instead of Sink calling each
+// protocol directly.
+type RingBuffer struct {
+	mu      sync.Mutex
+	cap     int
+	subs    map[int]chan ringEntry
+	nextSub int
+}
+
+// NewRingBuffer creates a RingBuffer whose per-subscriber channel holds at
+// most capacity buffered entries before new pushes are dropped for that
+// subscriber.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{
+		cap:  capacity,
+		subs: make(map[int]chan ringEntry),
+	}
+}
+
+// Push fans entry out to every current subscriber, dropping it for any
+// subscriber whose channel is full rather than blocking the producer.
+func (rb *RingBuffer) Push(entry ringEntry) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	for _, ch := range rb.subs {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new reader and returns its channel and an
+// unsubscribe function that must be called exactly once when the reader is
+// done.
+func (rb *RingBuffer) Subscribe() (<-chan ringEntry, func()) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	id := rb.nextSub
+	rb.nextSub++
+
+	ch := make(chan ringEntry, rb.cap)
+	rb.subs[id] = ch
+
+	return ch, func() {
+		rb.mu.Lock()
+		defer rb.mu.Unlock()
+		if _, ok := rb.subs[id]; ok {
+			delete(rb.subs, id)
+			close(ch)
+		}
+	}
+}
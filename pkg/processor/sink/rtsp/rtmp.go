@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package rtsp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/nuclio/logger"
+)
+
+// rtmpServer accepts RTMP play connections (OBS and most RTMP-capable
+// players can tune into a live stream this way, not just publish one) and
+// republishes a path's RingBuffer as bare FLV video tags. It performs just
+// enough of the handshake and chunk-stream protocol to get a client into
+// the playing state; it does not implement publishing, sends no AMF
+// command replies at all (it only waits for a message containing "play"),
+// and does not handle chunk stream ids other than the ones this server
+// itself uses.
+type rtmpServer struct {
+	logger   logger.Logger
+	listener net.Listener
+	path     *path
+
+	wg   sync.WaitGroup
+	stop chan struct{}
+}
+
+func newRTMPServer(log logger.Logger, p *path) *rtmpServer {
+	return &rtmpServer{
+		logger: log,
+		path:   p,
+		stop:   make(chan struct{}),
+	}
+}
+
+func (s *rtmpServer) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for RTMP: %w", err)
+	}
+	s.listener = ln
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+
+	return nil
+}
+
+func (s *rtmpServer) Stop() {
+	close(s.stop)
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	s.wg.Wait()
+}
+
+func (s *rtmpServer) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.stop:
+				return
+			default:
+				s.logger.WarnWith("RTMP accept failed", "error", err)
+				return
+			}
+		}
+
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+func (s *rtmpServer) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer conn.Close()
+
+	if err := rtmpHandshake(conn); err != nil {
+		s.logger.WarnWith("RTMP handshake failed", "error", err)
+		return
+	}
+
+	if err := rtmpAwaitPlay(conn); err != nil {
+		s.logger.WarnWith("RTMP client did not reach play state", "error", err)
+		return
+	}
+
+	if err := s.path.addReader(); err != nil {
+		s.logger.WarnWith("RTMP stream unavailable", "error", err)
+		return
+	}
+	defer s.path.removeReader()
+
+	entries, unsubscribe := s.path.buffer.Subscribe()
+	defer unsubscribe()
+
+	start := time.Now()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			if err := writeFLVVideoTag(conn, entry.raw, time.Since(start)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// rtmpHandshake performs the uninspected version of the RTMP handshake:
+// C0+C1 are read and echoed back as S0+S1+S2, and C2 is drained without
+// validating the random payload both sides exchange, since this server has
+// no publish-time need to authenticate the handshake content itself.
+func rtmpHandshake(conn net.Conn) error {
+	c0c1 := make([]byte, 1+1536)
+	if _, err := io.ReadFull(conn, c0c1); err != nil {
+		return fmt.Errorf("failed to read C0/C1: %w", err)
+	}
+
+	s0s1s2 := make([]byte, 1+1536+1536)
+	s0s1s2[0] = 0x03 // RTMP version 3
+	copy(s0s1s2[1+8:1+1536], c0c1[1+8:1+1536])
+	copy(s0s1s2[1+1536:], c0c1[1:1+1536])
+	if _, err := conn.Write(s0s1s2); err != nil {
+		return fmt.Errorf("failed to write S0/S1/S2: %w", err)
+	}
+
+	c2 := make([]byte, 1536)
+	if _, err := io.ReadFull(conn, c2); err != nil {
+		return fmt.Errorf("failed to read C2: %w", err)
+	}
+
+	return nil
+}
+
+// rtmpAwaitPlay drains chunk stream messages from the client until a
+// "play" AMF0 command arrives, ignoring connect/createStream and anything
+// else this minimal server doesn't need to act on.
+func rtmpAwaitPlay(conn net.Conn) error {
+	header := make([]byte, 12)
+	deadline := time.Now().Add(10 * time.Second)
+	conn.SetReadDeadline(deadline)
+	defer conn.SetReadDeadline(time.Time{})
+
+	for i := 0; i < 32; i++ {
+		if _, err := io.ReadFull(conn, header[:1]); err != nil {
+			return fmt.Errorf("failed to read chunk basic header: %w", err)
+		}
+
+		fmtType := header[0] >> 6
+		headerLen := map[byte]int{0: 11, 1: 7, 2: 3, 3: 0}[fmtType]
+		if headerLen > 0 {
+			if _, err := io.ReadFull(conn, header[1:1+headerLen]); err != nil {
+				return fmt.Errorf("failed to read chunk message header: %w", err)
+			}
+		}
+
+		length := 0
+		if headerLen >= 7 {
+			length = int(header[4])<<16 | int(header[5])<<8 | int(header[6])
+		}
+		if length == 0 || length > 65536 {
+			continue
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return fmt.Errorf("failed to read chunk payload: %w", err)
+		}
+
+		if containsASCII(payload, "play") {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("client did not send play within handshake window")
+}
+
+func containsASCII(payload []byte, needle string) bool {
+	n := []byte(needle)
+	for i := 0; i+len(n) <= len(payload); i++ {
+		match := true
+		for j := range n {
+			if payload[i+j] != n[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFLVVideoTag writes data to w as a single bare FLV video tag (tag
+// header, body, previous-tag-size), timestamped since stream start, with no
+// RTMP chunk header around it. FLV has no standard codec id for bare JPEG
+// frames, so every tag carries the VP6 codec id as a transport placeholder;
+// this holds for h264/h265 sinks too, whose Annex-B data is not sent with
+// codec id 7 (AVC).
+func writeFLVVideoTag(w io.Writer, data []byte, ts time.Duration) error {
+	const placeholderCodecID = 0x04 // VP6, used only as a transport placeholder
+
+	body := make([]byte, 1+len(data))
+	body[0] = 0x10 | placeholderCodecID // frame type 1 (keyframe)
+	copy(body[1:], data)
+
+	tag := make([]byte, 11+len(body)+4)
+	tag[0] = 0x09 // video tag
+	tag[1] = byte(len(body) >> 16)
+	tag[2] = byte(len(body) >> 8)
+	tag[3] = byte(len(body))
+
+	msTs := uint32(ts.Milliseconds())
+	tag[4] = byte(msTs >> 16)
+	tag[5] = byte(msTs >> 8)
+	tag[6] = byte(msTs)
+	tag[7] = byte(msTs >> 24)
+
+	copy(tag[11:], body)
+	binary.BigEndian.PutUint32(tag[11+len(body):], uint32(11+len(body)))
+
+	_, err := w.Write(tag)
+	return err
+}
@@ -9,29 +9,89 @@ import (
 	"fmt"
 	"image"
 	"image/jpeg"
+	"net"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/bluenviron/gortsplib/v5"
 	"github.com/bluenviron/gortsplib/v5/pkg/base"
 	"github.com/bluenviron/gortsplib/v5/pkg/description"
 	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/bluenviron/gortsplib/v5/pkg/format/rtph264"
+	"github.com/bluenviron/gortsplib/v5/pkg/format/rtph265"
 	"github.com/bluenviron/gortsplib/v5/pkg/format/rtplpcm"
 	"github.com/bluenviron/gortsplib/v5/pkg/format/rtpmjpeg"
 	"github.com/mitchellh/mapstructure"
 	"github.com/nuclio/logger"
+	"github.com/pion/rtp"
 	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
 )
 
+const (
+	defaultOnDemandCloseAfterSeconds = 10
+	defaultHLSSegmentSeconds         = 2
+	defaultHLSSegmentCount           = 5
+	defaultRingBufferCapacity        = 32
+
+	defaultMulticastIPRange  = "224.1.0.0/16"
+	defaultMulticastRTPPort  = 8002
+	defaultMulticastRTCPPort = 8003
+
+	defaultBroadcastAdminPort = 9001
+)
+
 // Configuration for RTSP sink
 type Configuration struct {
-	Port       int    `json:"port,omitempty"`
-	Path       string `json:"path,omitempty"`
-	Type       string `json:"type,omitempty"` // "video" or "audio"
+	Port int    `json:"port,omitempty"`
+	Path string `json:"path,omitempty"`
+	// Type selects the media carried by the stream: "video" (MJPEG,
+	// the default), "h264", "h265", or "audio".
+	Type       string `json:"type,omitempty"`
 	SampleRate int    `json:"sample_rate,omitempty"`
 	Channels   int    `json:"channels,omitempty"`
+
+	// Codec overrides the ffmpeg encoder used to transcode incoming JPEG
+	// frames when Type is "h264" or "h265" (default "libx264"/"libx265").
+	// Has no effect on frames already H.264/H.265-encoded and signaled via
+	// metadata["codec"] on Write.
+	Codec string `json:"codec,omitempty"`
+
+	// OnDemandCloseAfterSeconds is how long the HLS/RTMP pipelines linger
+	// after their last reader disconnects before being torn down.
+	OnDemandCloseAfterSeconds int `json:"on_demand_close_after_seconds,omitempty"`
+
+	EnableHLS         bool `json:"enable_hls,omitempty"`
+	HLSPort           int  `json:"hls_port,omitempty"`
+	HLSSegmentSeconds int  `json:"hls_segment_seconds,omitempty"`
+	HLSSegmentCount   int  `json:"hls_segment_count,omitempty"`
+
+	EnableRTMP bool `json:"enable_rtmp,omitempty"`
+	RTMPPort   int  `json:"rtmp_port,omitempty"`
+
+	// Multicast lets every viewer of a busy path share one copy of each RTP
+	// packet instead of the server fanning out one copy per unicast session.
+	Multicast         bool   `json:"multicast,omitempty"`
+	MulticastIPRange  string `json:"multicast_ip_range,omitempty"`
+	MulticastRTPPort  int    `json:"multicast_rtp_port,omitempty"`
+	MulticastRTCPPort int    `json:"multicast_rtcp_port,omitempty"`
+
+	// BroadcastURL, when set, is an external rtsp:// or rtmp:// endpoint
+	// that every frame written to this sink is also republished to (e.g.
+	// a shared mediamtx cluster or a CDN ingest URL).
+	BroadcastURL string `json:"broadcast_url,omitempty"`
+
+	// BroadcastAdminPort serves a small HTTP API for toggling the
+	// broadcast on and off at runtime without restarting the function.
+	BroadcastAdminPort int `json:"broadcast_admin_port,omitempty"`
 }
 
-// Sink implements RTSP streaming using gortsplib
+// Sink implements RTSP streaming using gortsplib, and optionally fans the
+// same encoded frames out over HLS and RTMP so one function's output is
+// playable from a browser, OBS, or ffplay/VLC without extra infrastructure.
+// All three protocols share one producer: Write pushes into a per-sink
+// RingBuffer, and the on-demand Path built around it starts the HLS/RTMP
+// pipelines only once a reader shows up for them.
 type Sink struct {
 	logger        logger.Logger
 	configuration *Configuration
@@ -39,11 +99,31 @@ type Sink struct {
 	stream        *gortsplib.ServerStream
 	mjpegFormat   *format.MJPEG
 	mjpegEncoder  *rtpmjpeg.Encoder
+	h264Format    *format.H264
+	h264Encoder   *rtph264.Encoder
+	h265Format    *format.H265
+	h265Encoder   *rtph265.Encoder
 	lpcmFormat    *format.LPCM
 	lpcmEncoder   *rtplpcm.Encoder
 	mutex         sync.RWMutex
 	stopChan      chan struct{}
 	wg            sync.WaitGroup
+
+	transcoder     *videoTranscoder
+	transcoderStop chan struct{}
+
+	ringBuffer *RingBuffer
+	path       *path
+
+	hlsMuxer   *hlsMuxer
+	hlsServer  *http.Server
+	hlsStop    chan struct{}
+	rtmpServer *rtmpServer
+
+	broadcastManager     *BroadcastManager
+	broadcastAdminServer *http.Server
+
+	playingSessions sync.Map // *gortsplib.ServerSession -> struct{}, tracks which RTSP sessions reached PLAY so OnSessionClose knows whether to release a Path reader
 }
 
 // factory implements sink.Factory
@@ -51,25 +131,67 @@ type factory struct{}
 
 func (f *factory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
 	config := &Configuration{
-		Port:       8554,
-		Path:       "/stream",
-		Type:       "video",
-		SampleRate: 16000,
-		Channels:   1,
+		Port:                      8554,
+		Path:                      "/stream",
+		Type:                      "video",
+		SampleRate:                16000,
+		Channels:                  1,
+		OnDemandCloseAfterSeconds: defaultOnDemandCloseAfterSeconds,
+		HLSPort:                   8888,
+		HLSSegmentSeconds:         defaultHLSSegmentSeconds,
+		HLSSegmentCount:           defaultHLSSegmentCount,
+		RTMPPort:                  1935,
+		MulticastIPRange:          defaultMulticastIPRange,
+		MulticastRTPPort:          defaultMulticastRTPPort,
+		MulticastRTCPPort:         defaultMulticastRTCPPort,
+		BroadcastAdminPort:        defaultBroadcastAdminPort,
 	}
 
 	if err := mapstructure.Decode(configuration, config); err != nil {
 		return nil, fmt.Errorf("failed to parse rtsp sink configuration: %w", err)
 	}
 
-	if config.Type != "video" && config.Type != "audio" {
-		return nil, fmt.Errorf("invalid rtsp type: %s (must be 'video' or 'audio')", config.Type)
+	switch config.Type {
+	case "video", "h264", "h265", "audio":
+	default:
+		return nil, fmt.Errorf("invalid rtsp type: %s (must be 'video', 'h264', 'h265' or 'audio')", config.Type)
+	}
+
+	if config.OnDemandCloseAfterSeconds <= 0 {
+		config.OnDemandCloseAfterSeconds = defaultOnDemandCloseAfterSeconds
+	}
+	if config.HLSSegmentSeconds <= 0 {
+		config.HLSSegmentSeconds = defaultHLSSegmentSeconds
+	}
+	if config.HLSSegmentCount <= 0 {
+		config.HLSSegmentCount = defaultHLSSegmentCount
+	}
+	if config.MulticastIPRange == "" {
+		config.MulticastIPRange = defaultMulticastIPRange
+	}
+	if config.MulticastRTPPort <= 0 {
+		config.MulticastRTPPort = defaultMulticastRTPPort
+	}
+	if config.MulticastRTCPPort <= 0 {
+		config.MulticastRTCPPort = defaultMulticastRTCPPort
+	}
+	if config.BroadcastAdminPort <= 0 {
+		config.BroadcastAdminPort = defaultBroadcastAdminPort
+	}
+	if config.Codec == "" {
+		switch config.Type {
+		case "h264":
+			config.Codec = "libx264"
+		case "h265":
+			config.Codec = "libx265"
+		}
 	}
 
 	return &Sink{
 		logger:        logger,
 		configuration: config,
 		stopChan:      make(chan struct{}),
+		ringBuffer:    NewRingBuffer(defaultRingBufferCapacity),
 	}, nil
 }
 
@@ -104,6 +226,49 @@ func (s *Sink) Start() error {
 		if err != nil {
 			return fmt.Errorf("failed to create MJPEG encoder: %w", err)
 		}
+	} else if s.configuration.Type == "h264" {
+		s.h264Format = &format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+		}
+		desc = description.Session{
+			Title: "DigitalHub H.264 Stream",
+			Medias: []*description.Media{{
+				Type:    description.MediaTypeVideo,
+				Formats: []format.Format{s.h264Format},
+			}},
+		}
+
+		var err error
+		s.h264Encoder, err = s.h264Format.CreateEncoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H.264 encoder: %w", err)
+		}
+
+		if err := s.startVideoTranscoder(); err != nil {
+			return err
+		}
+	} else if s.configuration.Type == "h265" {
+		s.h265Format = &format.H265{
+			PayloadTyp: 96,
+		}
+		desc = description.Session{
+			Title: "DigitalHub H.265 Stream",
+			Medias: []*description.Media{{
+				Type:    description.MediaTypeVideo,
+				Formats: []format.Format{s.h265Format},
+			}},
+		}
+
+		var err error
+		s.h265Encoder, err = s.h265Format.CreateEncoder()
+		if err != nil {
+			return fmt.Errorf("failed to create H.265 encoder: %w", err)
+		}
+
+		if err := s.startVideoTranscoder(); err != nil {
+			return err
+		}
 	} else {
 		// LPCM audio format (little-endian PCM)
 		s.lpcmFormat = &format.LPCM{
@@ -139,6 +304,12 @@ func (s *Sink) Start() error {
 		UDPRTCPAddress: ":8001", // RTCP port for UDP transport
 	}
 
+	if s.configuration.Multicast {
+		s.server.MulticastIPRange = s.configuration.MulticastIPRange
+		s.server.MulticastRTPPort = s.configuration.MulticastRTPPort
+		s.server.MulticastRTCPPort = s.configuration.MulticastRTCPPort
+	}
+
 	// Start the server first to initialize it
 	if err := s.server.Start(); err != nil {
 		return fmt.Errorf("failed to start RTSP server: %w", err)
@@ -161,6 +332,192 @@ func (s *Sink) Start() error {
 		s.server.Wait()
 	}()
 
+	if s.transcoder != nil {
+		s.transcoderStop = make(chan struct{})
+		s.wg.Add(1)
+		go s.runTranscoderLoop(s.transcoderStop)
+	}
+
+	closeAfter := time.Duration(s.configuration.OnDemandCloseAfterSeconds) * time.Second
+	s.path = newPathManager().getOrCreatePath(
+		s.configuration.Path,
+		s.ringBuffer,
+		closeAfter,
+		s.startEgressPipelines,
+		s.stopEgressPipelines,
+	)
+
+	if s.configuration.EnableHLS {
+		if err := s.startHLSServer(closeAfter); err != nil {
+			return err
+		}
+	}
+
+	if s.configuration.EnableRTMP {
+		s.rtmpServer = newRTMPServer(s.logger, s.path)
+		if err := s.rtmpServer.Start(fmt.Sprintf(":%d", s.configuration.RTMPPort)); err != nil {
+			return err
+		}
+	}
+
+	if s.configuration.BroadcastURL != "" {
+		s.broadcastManager = newBroadcastManager(s.logger, s, s.configuration.BroadcastURL)
+		if err := s.broadcastManager.Start(); err != nil {
+			return fmt.Errorf("failed to start broadcast: %w", err)
+		}
+
+		if err := s.startBroadcastAdminServer(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startBroadcastAdminServer serves a tiny HTTP API for toggling the
+// broadcast on and off at runtime: GET reports status, POST start/stop
+// (re)starts or halts it, without restarting the function.
+func (s *Sink) startBroadcastAdminServer() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/broadcast", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"active":%t,"url":%q}`, s.broadcastManager.IsActive(), s.configuration.BroadcastURL)
+		case http.MethodPost:
+			if err := s.broadcastManager.Start(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			s.broadcastManager.Stop()
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	s.broadcastAdminServer = &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.configuration.BroadcastAdminPort),
+		Handler: mux,
+	}
+
+	ln, err := net.Listen("tcp", s.broadcastAdminServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for broadcast admin API: %w", err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.broadcastAdminServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.WarnWith("Broadcast admin server stopped", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// startVideoTranscoder spawns the ffmpeg subprocess that converts incoming
+// JPEG frames to Annex-B H.264/H.265. It only spawns the process; NAL units
+// are not read and forwarded to the stream until runTranscoderLoop starts,
+// once the ServerStream exists.
+func (s *Sink) startVideoTranscoder() error {
+	t, err := newVideoTranscoder(context.Background(), s.configuration.Type, s.configuration.Codec)
+	if err != nil {
+		return fmt.Errorf("failed to start video transcoder: %w", err)
+	}
+	s.transcoder = t
+	return nil
+}
+
+// runTranscoderLoop reads NAL units produced by the ffmpeg transcoder as
+// they become available and turns them into RTP packets on the stream.
+// This runs independently of Write, since encoded frames arrive on their
+// own schedule relative to the JPEG frames fed in.
+func (s *Sink) runTranscoderLoop(stop <-chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		nalus, err := s.transcoder.readNALUs()
+		if err != nil {
+			select {
+			case <-stop:
+				return
+			default:
+				s.logger.WarnWith("Video transcoder stopped producing NAL units", "error", err)
+				return
+			}
+		}
+		if len(nalus) == 0 {
+			continue
+		}
+
+		if err := s.writeEncodedNALUs(nalus); err != nil {
+			s.logger.WarnWith("Failed to write transcoded frame", "error", err)
+		}
+	}
+}
+
+// startEgressPipelines is the Path's start callback: it runs the HLS
+// segmenter against the ring buffer. RTMP has no shared pipeline to start
+// here since each RTMP connection subscribes to the ring buffer directly;
+// it still goes through the same Path reader count so HLS and RTMP agree
+// on when the stream is "live".
+func (s *Sink) startEgressPipelines() error {
+	if s.hlsMuxer != nil {
+		s.hlsStop = make(chan struct{})
+		go s.hlsMuxer.run(s.ringBuffer, s.hlsStop)
+	}
+	return nil
+}
+
+// stopEgressPipelines is the Path's stop callback, undoing startEgressPipelines.
+func (s *Sink) stopEgressPipelines() {
+	if s.hlsStop != nil {
+		close(s.hlsStop)
+		s.hlsStop = nil
+	}
+}
+
+// startHLSServer builds the hlsMuxer and serves it over HTTP at
+// /hls{path}/, where {path} is the sink's configured RTSP path.
+func (s *Sink) startHLSServer(closeAfter time.Duration) error {
+	segmentDuration := time.Duration(s.configuration.HLSSegmentSeconds) * time.Second
+	s.hlsMuxer = newHLSMuxer(s.logger, s.path, segmentDuration, s.configuration.HLSSegmentCount)
+
+	idleStop := make(chan struct{})
+	go s.hlsMuxer.watchIdle(closeAfter, idleStop)
+
+	mux := http.NewServeMux()
+	mux.Handle(fmt.Sprintf("/hls%s/", s.configuration.Path), s.hlsMuxer)
+
+	s.hlsServer = &http.Server{
+		Addr:    fmt.Sprintf(":%d", s.configuration.HLSPort),
+		Handler: mux,
+	}
+
+	ln, err := net.Listen("tcp", s.hlsServer.Addr)
+	if err != nil {
+		close(idleStop)
+		return fmt.Errorf("failed to listen for HLS: %w", err)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(idleStop)
+		if err := s.hlsServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.WarnWith("HLS server stopped", "error", err)
+		}
+	}()
+
 	return nil
 }
 
@@ -170,6 +527,31 @@ func (s *Sink) Stop(force bool) error {
 
 	close(s.stopChan)
 
+	if s.rtmpServer != nil {
+		s.rtmpServer.Stop()
+	}
+
+	if s.broadcastManager != nil {
+		s.broadcastManager.Stop()
+	}
+
+	if s.broadcastAdminServer != nil {
+		s.broadcastAdminServer.Close()
+	}
+
+	if s.hlsServer != nil {
+		s.hlsServer.Close()
+	}
+
+	if s.transcoder != nil {
+		close(s.transcoderStop)
+		// Killing the ffmpeg process unblocks runTranscoderLoop's pending
+		// stdout read, which is otherwise not ctx/channel-aware.
+		s.transcoder.close()
+	}
+
+	s.stopEgressPipelines()
+
 	if s.stream != nil {
 		s.stream.Close()
 	}
@@ -183,7 +565,9 @@ func (s *Sink) Stop(force bool) error {
 	return nil
 }
 
-// Write sends data to the RTSP stream
+// Write sends data to the RTSP stream and, once Start has wired them up,
+// fans the same raw frame/chunk out to the HLS and RTMP pipelines through
+// the ring buffer.
 func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -192,10 +576,90 @@ func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]inter
 		return fmt.Errorf("rtsp sink not started")
 	}
 
-	if s.configuration.Type == "video" {
+	switch s.configuration.Type {
+	case "video":
 		return s.writeVideoFrame(data)
+	case "h264", "h265":
+		return s.writeEncodedVideoFrame(data, metadata)
+	default:
+		return s.writeAudioFrame(data)
+	}
+}
+
+// writeEncodedVideoFrame handles a Write call while the sink is configured
+// for "h264"/"h265". If metadata["codec"] names the sink's own codec, data
+// is already an Annex-B buffer and is sent straight to the RTP encoder;
+// otherwise data is treated as a JPEG frame and handed to the ffmpeg
+// transcoder, whose output reaches the stream asynchronously via
+// runTranscoderLoop.
+func (s *Sink) writeEncodedVideoFrame(data []byte, metadata map[string]interface{}) error {
+	if codec, _ := metadata["codec"].(string); codec == s.configuration.Type {
+		return s.writeEncodedNALUs(splitAnnexB(data))
+	}
+
+	if s.transcoder == nil {
+		return fmt.Errorf("no video transcoder configured for type %q", s.configuration.Type)
+	}
+	return s.transcoder.writeJPEG(data)
+}
+
+// writeEncodedNALUs RTP-packetizes nalus with the sink's H.264/H.265
+// encoder and writes them to the stream with an NTP-stamped timestamp,
+// pulling SPS/PPS/VPS out along the way so late-joining players get them.
+func (s *Sink) writeEncodedNALUs(nalus [][]byte) error {
+	if s.stream == nil {
+		return fmt.Errorf("stream not initialized")
+	}
+
+	media := s.stream.Desc.Medias[0]
+	now := time.Now()
+
+	var rtpPackets []*rtp.Packet
+	var err error
+
+	if s.configuration.Type == "h264" {
+		sps, pps, rest := splitH264ParameterSets(nalus)
+		if sps != nil {
+			s.h264Format.SPS = sps
+		}
+		if pps != nil {
+			s.h264Format.PPS = pps
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		rtpPackets, err = s.h264Encoder.Encode(rest)
+	} else {
+		vps, sps, pps, rest := splitH265ParameterSets(nalus)
+		if vps != nil {
+			s.h265Format.VPS = vps
+		}
+		if sps != nil {
+			s.h265Format.SPS = sps
+		}
+		if pps != nil {
+			s.h265Format.PPS = pps
+		}
+		if len(rest) == 0 {
+			return nil
+		}
+		rtpPackets, err = s.h265Encoder.Encode(rest)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode video frame: %w", err)
+	}
+
+	for _, pkt := range rtpPackets {
+		if err := s.stream.WritePacketRTPWithNTP(media, pkt, now); err != nil {
+			return fmt.Errorf("failed to write RTP packet: %w", err)
+		}
 	}
-	return s.writeAudioFrame(data)
+
+	if s.ringBuffer != nil {
+		s.ringBuffer.Push(ringEntry{raw: bytes.Join(nalus, annexBStartCode), packets: rtpPackets})
+	}
+
+	return nil
 }
 
 // ensureJPEGDimensionsValid ensures JPEG dimensions are multiples of 8
@@ -267,6 +731,10 @@ func (s *Sink) writeVideoFrame(jpegData []byte) error {
 		}
 	}
 
+	if s.ringBuffer != nil {
+		s.ringBuffer.Push(ringEntry{raw: validJPEG, packets: packets})
+	}
+
 	return nil
 }
 
@@ -292,6 +760,10 @@ func (s *Sink) writeAudioFrame(pcmData []byte) error {
 		}
 	}
 
+	if s.ringBuffer != nil {
+		s.ringBuffer.Push(ringEntry{raw: pcmData, packets: packets})
+	}
+
 	return nil
 }
 
@@ -303,14 +775,36 @@ func (s *Sink) GetKind() string {
 // GetConfig returns the sink configuration
 func (s *Sink) GetConfig() map[string]interface{} {
 	config := map[string]interface{}{
-		"port": s.configuration.Port,
-		"path": s.configuration.Path,
-		"type": s.configuration.Type,
+		"port":                          s.configuration.Port,
+		"path":                          s.configuration.Path,
+		"type":                          s.configuration.Type,
+		"on_demand_close_after_seconds": s.configuration.OnDemandCloseAfterSeconds,
+		"enable_hls":                    s.configuration.EnableHLS,
+		"enable_rtmp":                   s.configuration.EnableRTMP,
+		"multicast":                     s.configuration.Multicast,
 	}
 	if s.configuration.Type == "audio" {
 		config["sample_rate"] = s.configuration.SampleRate
 		config["channels"] = s.configuration.Channels
 	}
+	if s.configuration.Type == "h264" || s.configuration.Type == "h265" {
+		config["codec"] = s.configuration.Codec
+	}
+	if s.configuration.EnableHLS {
+		config["hls_port"] = s.configuration.HLSPort
+	}
+	if s.configuration.EnableRTMP {
+		config["rtmp_port"] = s.configuration.RTMPPort
+	}
+	if s.configuration.Multicast {
+		config["multicast_ip_range"] = s.configuration.MulticastIPRange
+		config["multicast_rtp_port"] = s.configuration.MulticastRTPPort
+		config["multicast_rtcp_port"] = s.configuration.MulticastRTCPPort
+	}
+	if s.configuration.BroadcastURL != "" {
+		config["broadcast_url"] = s.configuration.BroadcastURL
+		config["broadcast_admin_port"] = s.configuration.BroadcastAdminPort
+	}
 	return config
 }
 
@@ -334,6 +828,10 @@ func (h *rtspHandler) OnSessionOpen(ctx *gortsplib.ServerHandlerOnSessionOpenCtx
 
 func (h *rtspHandler) OnSessionClose(ctx *gortsplib.ServerHandlerOnSessionCloseCtx) {
 	h.logger.InfoWith("RTSP session closed")
+
+	if _, wasPlaying := h.sink.playingSessions.LoadAndDelete(ctx.Session); wasPlaying && h.sink.path != nil {
+		h.sink.path.removeReader()
+	}
 }
 
 func (h *rtspHandler) OnDescribe(ctx *gortsplib.ServerHandlerOnDescribeCtx) (*base.Response, *gortsplib.ServerStream, error) {
@@ -361,6 +859,16 @@ func (h *rtspHandler) OnSetup(ctx *gortsplib.ServerHandlerOnSetupCtx) (*base.Res
 		}, nil, fmt.Errorf("path not found: %s", ctx.Path)
 	}
 
+	if ctx.Transport != nil && *ctx.Transport == gortsplib.TransportUDPMulticast && !h.sink.configuration.Multicast {
+		return &base.Response{
+			StatusCode: base.StatusUnsupportedTransport,
+		}, nil, fmt.Errorf("multicast is not enabled on this sink")
+	}
+
+	// gortsplib negotiates the multicast group address and answers the
+	// SETUP response itself; returning the same ServerStream for both
+	// unicast and multicast readers lets WritePacketRTP fan a single copy
+	// of each packet out to every reader sharing the multicast group.
 	return &base.Response{
 		StatusCode: base.StatusOK,
 	}, h.sink.stream, nil
@@ -368,6 +876,16 @@ func (h *rtspHandler) OnSetup(ctx *gortsplib.ServerHandlerOnSetupCtx) (*base.Res
 
 func (h *rtspHandler) OnPlay(ctx *gortsplib.ServerHandlerOnPlayCtx) (*base.Response, error) {
 	h.logger.InfoWith("RTSP PLAY request")
+
+	if h.sink.path != nil {
+		if err := h.sink.path.addReader(); err != nil {
+			return &base.Response{
+				StatusCode: base.StatusServiceUnavailable,
+			}, err
+		}
+		h.sink.playingSessions.Store(ctx.Session, struct{}{})
+	}
+
 	return &base.Response{
 		StatusCode: base.StatusOK,
 	}, nil
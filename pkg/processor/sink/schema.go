@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError reports every configuration field that failed schema
+// validation in Registry.Create, instead of bailing out on the first one.
+type ValidationError struct {
+	Kind   string
+	Fields []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("sink %q configuration invalid: %s", e.Kind, strings.Join(e.Fields, "; "))
+}
+
+// validateConfiguration checks configuration against a JSON-schema-like
+// description and returns one human-readable message per offending field
+// (an empty slice means configuration is valid). schema supports two
+// top-level keys: "required" ([]string of field names that must be
+// present) and "properties" (map[string]interface{} of field name to a
+// sub-schema with a "type" key, one of "string", "number", "bool",
+// "object", or "array"). Fields not listed in "properties" are left
+// unvalidated.
+func validateConfiguration(schema map[string]interface{}, configuration map[string]interface{}) []string {
+	var fields []string
+
+	if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			if _, present := configuration[name]; !present {
+				fields = append(fields, fmt.Sprintf("%q is required", name))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, rawPropertySchema := range properties {
+		value, present := configuration[name]
+		if !present {
+			continue
+		}
+
+		propertySchema, ok := rawPropertySchema.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		wantType, ok := propertySchema["type"].(string)
+		if !ok {
+			continue
+		}
+
+		if !valueMatchesType(value, wantType) {
+			fields = append(fields, fmt.Sprintf("%q must be of type %q", name, wantType))
+		}
+	}
+
+	return fields
+}
+
+func valueMatchesType(value interface{}, wantType string) bool {
+	switch wantType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		switch value.(type) {
+		case int, int64, float32, float64:
+			return true
+		default:
+			return false
+		}
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	default:
+		// Unrecognized declared type: nothing to check against.
+		return true
+	}
+}
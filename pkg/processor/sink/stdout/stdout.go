@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package stdout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+)
+
+// Configuration for the stdout sink
+type Configuration struct {
+	// Prefix is written before each JSON line, e.g. to tag the stream the
+	// record came from. Empty by default.
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// Sink writes each Write call as a single JSON line to writer (os.Stdout in
+// production), one line per call: {"timestamp":..., "prefix":..., "data":...,
+// "metadata":...}. Intended for low-volume, human-inspectable output such as
+// audit trails, not high-throughput media.
+type Sink struct {
+	logger        logger.Logger
+	configuration *Configuration
+	writer        io.Writer
+
+	mu sync.Mutex
+}
+
+// factory implements sink.Factory
+type factory struct{}
+
+func (f *factory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
+	config := &Configuration{}
+
+	if err := mapstructure.Decode(configuration, config); err != nil {
+		return nil, fmt.Errorf("failed to parse stdout sink configuration: %w", err)
+	}
+
+	return &Sink{
+		logger:        logger,
+		configuration: config,
+		writer:        os.Stdout,
+	}, nil
+}
+
+func (f *factory) GetKind() string {
+	return "stdout"
+}
+
+// Start starts the stdout sink (no-op for stdout)
+func (s *Sink) Start() error {
+	s.logger.InfoWith("Starting stdout sink")
+	return nil
+}
+
+// Stop stops the stdout sink (no-op for stdout)
+func (s *Sink) Stop(force bool) error {
+	s.logger.InfoWith("Stopping stdout sink", "force", force)
+	return nil
+}
+
+// Write marshals data and metadata as a single JSON line and writes it.
+func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"timestamp": time.Now().UTC(),
+		"prefix":    s.configuration.Prefix,
+		"data":      string(data),
+		"metadata":  metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal stdout sink record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.writer.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write stdout sink record: %w", err)
+	}
+	return nil
+}
+
+// GetKind returns the sink type
+func (s *Sink) GetKind() string {
+	return "stdout"
+}
+
+// GetConfig returns the sink configuration
+func (s *Sink) GetConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"prefix": s.configuration.Prefix,
+	}
+}
+
+func init() {
+	sink.RegistrySingleton.Register("stdout", &factory{})
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// decorrelatedJitterBackoff implements AWS's "decorrelated jitter" backoff
+// (https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/):
+// each attempt's delay is a random value between base and 3x the previous
+// delay, capped at max. Called with prev == 0 for the first retry.
+func decorrelatedJitterBackoff(prev, base, max time.Duration) time.Duration {
+	if prev < base {
+		prev = base
+	}
+
+	upper := prev * 3
+	if upper > max {
+		upper = max
+	}
+	if upper <= base {
+		return base
+	}
+
+	return base + time.Duration(rand.Int63n(int64(upper-base)))
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value, which is either a
+// number of seconds or an HTTP-date, returning the remaining wait duration.
+// Returns ok == false if header is empty or unparseable.
+func parseRetryAfter(header string) (delay time.Duration, ok bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			seconds = 0
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		delay = time.Until(when)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+// isRetryableStatus reports whether an HTTP response status code warrants a
+// retry: 5xx (server trouble), 408 (client should retry the request), and
+// 429 (rate limited, typically paired with Retry-After). Any other 4xx is
+// the caller's fault and won't succeed by retrying.
+func isRetryableStatus(statusCode int) bool {
+	if statusCode >= 500 {
+		return true
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDecorrelatedJitterBackoff_CapsAtMax(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := time.Second
+
+	delay := time.Duration(0)
+	for i := 0; i < 20; i++ {
+		delay = decorrelatedJitterBackoff(delay, base, max)
+		if delay < base {
+			t.Fatalf("iteration %d: delay %v below base %v", i, delay, base)
+		}
+		if delay > max {
+			t.Fatalf("iteration %d: delay %v exceeds max %v", i, delay, max)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoff_FirstCallUsesBase(t *testing.T) {
+	base := 50 * time.Millisecond
+	max := 5 * time.Second
+
+	delay := decorrelatedJitterBackoff(0, base, max)
+	if delay < base || delay > base*3 {
+		t.Fatalf("expected first delay within [base, 3*base], got %v", delay)
+	}
+}
+
+func TestParseRetryAfter_Seconds(t *testing.T) {
+	delay, ok := parseRetryAfter("5")
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if delay != 5*time.Second {
+		t.Fatalf("expected 5s, got %v", delay)
+	}
+}
+
+func TestParseRetryAfter_HTTPDate(t *testing.T) {
+	when := time.Now().Add(10 * time.Second).UTC()
+	delay, ok := parseRetryAfter(when.Format(http.TimeFormat))
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if delay <= 0 || delay > 11*time.Second {
+		t.Fatalf("expected delay close to 10s, got %v", delay)
+	}
+}
+
+func TestParseRetryAfter_Empty(t *testing.T) {
+	if _, ok := parseRetryAfter(""); ok {
+		t.Fatal("expected not ok for empty header")
+	}
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		500: true,
+		503: true,
+		408: true,
+		429: true,
+		400: false,
+		404: false,
+		200: false,
+	}
+	for status, want := range cases {
+		if got := isRetryableStatus(status); got != want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
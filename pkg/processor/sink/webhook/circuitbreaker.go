@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker protects a single webhook URL from being hammered with
+// retries once it's clearly down: after Threshold consecutive failures seen
+// within Window of each other it opens, short-circuiting every further
+// Write until Cooldown has passed, at which point exactly one probe request
+// is let through to decide whether to close again or stay open for another
+// Cooldown. One Sink has exactly one URL, so one breaker per Sink instance
+// is sufficient - there's no need to key it by URL.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	state            circuitState
+	consecutiveFails int
+	lastFailure      time.Time
+	openedAt         time.Time
+}
+
+// newCircuitBreaker creates a breaker, or nil if threshold disables it.
+func newCircuitBreaker(threshold int, window, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, window: window, cooldown: cooldown}
+}
+
+// Allow reports whether a request may proceed right now, and if so whether
+// it is the single probe attempt a half-open breaker lets through (whose
+// outcome decides the breaker's next state).
+func (c *circuitBreaker) Allow(now time.Time) (allowed, isProbe bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case circuitOpen:
+		if now.Sub(c.openedAt) < c.cooldown {
+			return false, false
+		}
+		c.state = circuitHalfOpen
+		return true, true
+	case circuitHalfOpen:
+		return false, false // a probe is already outstanding
+	default: // circuitClosed
+		return true, false
+	}
+}
+
+// RecordSuccess closes the breaker and resets its failure streak.
+func (c *circuitBreaker) RecordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = circuitClosed
+	c.consecutiveFails = 0
+}
+
+// RecordFailure counts a failed attempt against the breaker, opening it once
+// Threshold consecutive failures land within Window of each other, or
+// re-opening it immediately if the failure was the half-open probe.
+func (c *circuitBreaker) RecordFailure(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == circuitHalfOpen {
+		c.state = circuitOpen
+		c.openedAt = now
+		return
+	}
+
+	if c.window > 0 && !c.lastFailure.IsZero() && now.Sub(c.lastFailure) > c.window {
+		c.consecutiveFails = 0
+	}
+	c.lastFailure = now
+	c.consecutiveFails++
+
+	if c.consecutiveFails >= c.threshold {
+		c.state = circuitOpen
+		c.openedAt = now
+	}
+}
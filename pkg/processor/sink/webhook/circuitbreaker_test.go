@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCircuitBreaker_DisabledByDefault(t *testing.T) {
+	if b := newCircuitBreaker(0, time.Second, time.Second); b != nil {
+		t.Fatalf("expected nil breaker for threshold <= 0, got %+v", b)
+	}
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b := newCircuitBreaker(3, time.Minute, time.Second)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		b.RecordFailure(now)
+	}
+	if allowed, _ := b.Allow(now); !allowed {
+		t.Fatal("expected breaker still closed before threshold reached")
+	}
+
+	b.RecordFailure(now)
+	if allowed, _ := b.Allow(now); allowed {
+		t.Fatal("expected breaker open after threshold consecutive failures")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute, 10*time.Millisecond)
+	now := time.Now()
+
+	b.RecordFailure(now)
+	if allowed, _ := b.Allow(now); allowed {
+		t.Fatal("expected breaker open immediately after threshold failure")
+	}
+
+	later := now.Add(20 * time.Millisecond)
+	allowed, isProbe := b.Allow(later)
+	if !allowed || !isProbe {
+		t.Fatalf("expected a single probe to be allowed after cooldown, got allowed=%v isProbe=%v", allowed, isProbe)
+	}
+
+	if allowed, _ := b.Allow(later); allowed {
+		t.Fatal("expected no second concurrent probe while one is outstanding")
+	}
+}
+
+func TestCircuitBreaker_SuccessRecoversToClosedState(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute, time.Millisecond)
+	now := time.Now()
+
+	b.RecordFailure(now)
+	b.Allow(now.Add(2 * time.Millisecond)) // transition to half-open
+	b.RecordSuccess()
+
+	if allowed, isProbe := b.Allow(now.Add(3 * time.Millisecond)); !allowed || isProbe {
+		t.Fatalf("expected breaker closed after success, got allowed=%v isProbe=%v", allowed, isProbe)
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute, time.Millisecond)
+	now := time.Now()
+
+	b.RecordFailure(now)
+	b.Allow(now.Add(2 * time.Millisecond)) // transition to half-open
+	b.RecordFailure(now.Add(2 * time.Millisecond))
+
+	if allowed, _ := b.Allow(now.Add(2 * time.Millisecond)); allowed {
+		t.Fatal("expected breaker to stay open right after a failed probe")
+	}
+}
+
+func TestCircuitBreaker_WindowResetsStaleFailures(t *testing.T) {
+	b := newCircuitBreaker(2, 10*time.Millisecond, time.Second)
+	now := time.Now()
+
+	b.RecordFailure(now)
+	// A failure long after the window should not combine with the earlier one.
+	later := now.Add(time.Second)
+	b.RecordFailure(later)
+
+	if allowed, _ := b.Allow(later); !allowed {
+		t.Fatal("expected breaker to stay closed when failures are outside the window")
+	}
+}
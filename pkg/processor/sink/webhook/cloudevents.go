@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// cloudEventsContentType is the Content-Type a CloudEvents 1.0 structured
+// JSON-mode envelope is sent with, overriding whatever Configuration.Headers
+// sets for Content-Type.
+const cloudEventsContentType = "application/cloudevents+json"
+
+// cloudEventSeq makes each envelope's id unique within a process without
+// pulling in a UUID dependency this no-vendor repo can't add.
+var cloudEventSeq atomic.Uint64
+
+// cloudEvent is a CloudEvents 1.0 structured-mode JSON envelope:
+// https://github.com/cloudevents/spec/blob/main/cloudevents/spec.md
+//
+// Metadata is carried as a plain "metadata" field rather than individual
+// CloudEvents extension attributes, since Write's metadata map may hold
+// values extension attributes can't (only primitive types are allowed
+// there); a Knative-style consumer that wants specific fields promoted to
+// real extension attributes can still read them out of "metadata".
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            string          `json:"data_base64"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+}
+
+// wrapCloudEvent encodes data/metadata as a CloudEvents 1.0 structured JSON
+// envelope, base64-carrying data (the sink has no way to know whether the
+// payload is itself JSON) per the spec's data_base64 convention.
+func wrapCloudEvent(source, eventType string, data []byte, metadata map[string]interface{}) ([]byte, error) {
+	var metadataJSON json.RawMessage
+	if len(metadata) > 0 {
+		encoded, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataJSON = encoded
+	}
+
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              fmt.Sprintf("%d-%d", time.Now().UnixNano(), cloudEventSeq.Add(1)),
+		Source:          source,
+		Type:            eventType,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/octet-stream",
+		Data:            base64.StdEncoding.EncodeToString(data),
+		Metadata:        metadataJSON,
+	}
+
+	return json.Marshal(event)
+}
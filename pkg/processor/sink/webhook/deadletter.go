@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+)
+
+// DeadLetter durably records a payload Write gave up on after exhausting
+// Configuration.MaxRetries (or hitting a non-retryable response), so it
+// isn't silently dropped.
+type DeadLetter interface {
+	Deliver(ctx context.Context, data []byte, metadata map[string]interface{}, deliverErr error) error
+}
+
+// deadLetterRecord is the envelope every DeadLetter implementation here
+// writes or forwards, bundling the original payload with why delivery
+// failed. DataBase64 rather than Data because the payload isn't necessarily
+// JSON or even UTF-8.
+type deadLetterRecord struct {
+	DataBase64 string                 `json:"data_base64"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Error      string                 `json:"error"`
+	URL        string                 `json:"url"`
+	Time       time.Time              `json:"time"`
+}
+
+// newDeadLetter builds the DeadLetter configuration.DeadLetterKind names, or
+// nil if DeadLetterKind is empty (the default: failed deliveries are simply
+// dropped, as before this request).
+func newDeadLetter(logger logger.Logger, config *Configuration) (DeadLetter, error) {
+	switch config.DeadLetterKind {
+	case "":
+		return nil, nil
+
+	case "file":
+		if config.DeadLetterFilePath == "" {
+			return nil, fmt.Errorf("deadLetterFilePath is required when deadLetterKind is \"file\"")
+		}
+		return newFileDeadLetter(config.DeadLetterFilePath, config.URL), nil
+
+	case "sink":
+		if config.DeadLetterSinkKind == "" {
+			return nil, fmt.Errorf("deadLetterSinkKind is required when deadLetterKind is \"sink\"")
+		}
+		return newSinkDeadLetter(logger, config.DeadLetterSinkKind, config.DeadLetterSinkConfig)
+
+	default:
+		// "s3" (named in the original request) needs an AWS SDK this
+		// dependency-less, no-go.mod repo has no way to vendor; "file" and
+		// "sink" (which can itself point at a durable store, e.g. another
+		// webhook) cover the same need without a new dependency.
+		return nil, fmt.Errorf("unsupported deadLetterKind: %s (supported: \"file\", \"sink\")", config.DeadLetterKind)
+	}
+}
+
+// fileDeadLetter appends one JSON line per failed delivery to a file.
+type fileDeadLetter struct {
+	mu   sync.Mutex
+	path string
+	url  string
+}
+
+func newFileDeadLetter(path, url string) *fileDeadLetter {
+	return &fileDeadLetter{path: path, url: url}
+}
+
+func (d *fileDeadLetter) Deliver(ctx context.Context, data []byte, metadata map[string]interface{}, deliverErr error) error {
+	encoded, err := json.Marshal(deadLetterRecord{
+		DataBase64: base64.StdEncoding.EncodeToString(data),
+		Metadata:   metadata,
+		Error:      deliverErr.Error(),
+		URL:        d.url,
+		Time:       time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter record: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead letter file %q: %w", d.path, err)
+	}
+	defer f.Close() // nolint: errcheck
+
+	_, err = f.Write(append(encoded, '\n'))
+	return err
+}
+
+// sinkDeadLetter forwards a failed delivery's payload to another
+// sink.RegistrySingleton-registered sink kind (e.g. "stdout" for local
+// debugging, or another "webhook" pointed at a durable collector).
+type sinkDeadLetter struct {
+	target sink.Sink
+}
+
+func newSinkDeadLetter(logger logger.Logger, kind string, configuration map[string]interface{}) (*sinkDeadLetter, error) {
+	target, err := sink.RegistrySingleton.Create(logger, kind, configuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter sink %q: %w", kind, err)
+	}
+	if err := target.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start dead letter sink %q: %w", kind, err)
+	}
+	return &sinkDeadLetter{target: target}, nil
+}
+
+func (d *sinkDeadLetter) Deliver(ctx context.Context, data []byte, metadata map[string]interface{}, deliverErr error) error {
+	forwarded := make(map[string]interface{}, len(metadata)+1)
+	for k, v := range metadata {
+		forwarded[k] = v
+	}
+	forwarded["dead_letter_error"] = deliverErr.Error()
+
+	return d.target.Write(ctx, data, forwarded)
+}
@@ -22,7 +22,38 @@ type Configuration struct {
 	Headers    map[string]string `json:"headers,omitempty"`
 	Timeout    int               `json:"timeout,omitempty"`    // seconds
 	MaxRetries int               `json:"maxRetries,omitempty"` // max retry attempts
-	RetryDelay int               `json:"retryDelay,omitempty"` // seconds between retries
+
+	// RetryDelay is the base delay (seconds) decorrelatedJitterBackoff
+	// starts from; MaxRetryDelay caps how large a single backoff can grow
+	// to. A 429/503 response's Retry-After header, when present, overrides
+	// the computed backoff for that one retry.
+	RetryDelay    int `json:"retryDelay,omitempty"`
+	MaxRetryDelay int `json:"maxRetryDelay,omitempty"`
+
+	// CircuitBreakerThreshold is how many consecutive failures (within
+	// CircuitBreakerWindowSeconds of each other) open the breaker,
+	// short-circuiting further Write calls until CircuitBreakerCooldownSeconds
+	// has passed. <= 0 (the default) disables the breaker entirely.
+	CircuitBreakerThreshold       int `json:"circuitBreakerThreshold,omitempty"`
+	CircuitBreakerWindowSeconds   int `json:"circuitBreakerWindowSeconds,omitempty"`
+	CircuitBreakerCooldownSeconds int `json:"circuitBreakerCooldownSeconds,omitempty"`
+
+	// CloudEvents wraps data/metadata in a CloudEvents 1.0 structured JSON
+	// envelope (Content-Type: application/cloudevents+json) instead of
+	// sending data as the raw request body.
+	CloudEvents       bool   `json:"cloudEvents,omitempty"`
+	CloudEventsSource string `json:"cloudEventsSource,omitempty"`
+	CloudEventsType   string `json:"cloudEventsType,omitempty"`
+
+	// DeadLetterKind selects where a delivery gives up on goes after
+	// MaxRetries is exhausted (or a non-retryable response is received):
+	// "" (the default) drops it, as before this field existed; "file"
+	// appends it to DeadLetterFilePath; "sink" forwards it to another
+	// sink.RegistrySingleton kind named by DeadLetterSinkKind.
+	DeadLetterKind       string                 `json:"deadLetterKind,omitempty"`
+	DeadLetterFilePath   string                 `json:"deadLetterFilePath,omitempty"`
+	DeadLetterSinkKind   string                 `json:"deadLetterSinkKind,omitempty"`
+	DeadLetterSinkConfig map[string]interface{} `json:"deadLetterSinkConfig,omitempty"`
 }
 
 // Sink implements HTTP webhook client
@@ -30,6 +61,8 @@ type Sink struct {
 	logger        logger.Logger
 	configuration *Configuration
 	client        *http.Client
+	breaker       *circuitBreaker // nil when CircuitBreakerThreshold <= 0
+	deadLetter    DeadLetter      // nil when DeadLetterKind == ""
 }
 
 // factory implements sink.Factory
@@ -37,11 +70,12 @@ type factory struct{}
 
 func (f *factory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
 	config := &Configuration{
-		Method:     "POST",
-		Timeout:    10,
-		MaxRetries: 3,
-		RetryDelay: 1,
-		Headers:    make(map[string]string),
+		Method:        "POST",
+		Timeout:       10,
+		MaxRetries:    3,
+		RetryDelay:    1,
+		MaxRetryDelay: 30,
+		Headers:       make(map[string]string),
 	}
 
 	if err := mapstructure.Decode(configuration, config); err != nil {
@@ -52,6 +86,11 @@ func (f *factory) Create(logger logger.Logger, configuration map[string]interfac
 		return nil, fmt.Errorf("webhook url is required")
 	}
 
+	deadLetter, err := newDeadLetter(logger, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook dead letter: %w", err)
+	}
+
 	client := &http.Client{
 		Timeout: time.Duration(config.Timeout) * time.Second,
 	}
@@ -60,6 +99,12 @@ func (f *factory) Create(logger logger.Logger, configuration map[string]interfac
 		logger:        logger,
 		configuration: config,
 		client:        client,
+		breaker: newCircuitBreaker(
+			config.CircuitBreakerThreshold,
+			time.Duration(config.CircuitBreakerWindowSeconds)*time.Second,
+			time.Duration(config.CircuitBreakerCooldownSeconds)*time.Second,
+		),
+		deadLetter: deadLetter,
 	}, nil
 }
 
@@ -79,50 +124,130 @@ func (s *Sink) Stop(force bool) error {
 	return nil
 }
 
-// Write sends data to the webhook
+// Write sends data to the webhook, retrying retryable failures with
+// exponential backoff and jitter, short-circuiting via the circuit breaker
+// once the endpoint looks dead, and handing the payload to DeadLetter (if
+// configured) once it gives up.
 func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	body := data
+	if s.configuration.CloudEvents {
+		wrapped, err := wrapCloudEvent(s.configuration.CloudEventsSource, s.configuration.CloudEventsType, data, metadata)
+		if err != nil {
+			return fmt.Errorf("failed to wrap data as CloudEvents envelope: %w", err)
+		}
+		body = wrapped
+	}
+
+	deliverErr := s.deliver(ctx, body)
+	if deliverErr == nil {
+		return nil
+	}
+
+	if s.deadLetter != nil {
+		if err := s.deadLetter.Deliver(ctx, data, metadata, deliverErr); err != nil {
+			s.logger.WarnWith("Failed to hand payload to dead letter", "url", s.configuration.URL, "error", err)
+			return fmt.Errorf("%w (dead letter also failed: %s)", deliverErr, err)
+		}
+		s.logger.InfoWith("Delivery failed, payload handed to dead letter", "url", s.configuration.URL)
+	}
+
+	return deliverErr
+}
+
+// deliver runs the retry loop for a single (already CloudEvents-wrapped, if
+// applicable) request body.
+func (s *Sink) deliver(ctx context.Context, body []byte) error {
 	var lastErr error
+	var delay time.Duration
 
 	for attempt := 0; attempt <= s.configuration.MaxRetries; attempt++ {
-		if attempt > 0 {
-			s.logger.DebugWith("Retrying webhook request", "attempt", attempt)
-			time.Sleep(time.Duration(s.configuration.RetryDelay) * time.Second)
+		if s.breaker != nil {
+			allowed, _ := s.breaker.Allow(time.Now())
+			if !allowed {
+				return fmt.Errorf("webhook circuit breaker open for %s", s.configuration.URL)
+			}
 		}
 
-		req, err := http.NewRequestWithContext(ctx, s.configuration.Method, s.configuration.URL, bytes.NewReader(data))
-		if err != nil {
-			lastErr = fmt.Errorf("failed to create request: %w", err)
-			continue
+		if attempt > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
 		}
 
-		// Set headers
-		for key, value := range s.configuration.Headers {
-			req.Header.Set(key, value)
+		statusCode, retryAfter, err := s.attempt(ctx, body)
+		if err == nil {
+			if s.breaker != nil {
+				s.breaker.RecordSuccess()
+			}
+			return nil
 		}
+		lastErr = err
 
-		// Default content type if not specified
-		if req.Header.Get("Content-Type") == "" {
-			req.Header.Set("Content-Type", "application/octet-stream")
+		if s.breaker != nil {
+			s.breaker.RecordFailure(time.Now())
 		}
 
-		resp, err := s.client.Do(req)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to send request: %w", err)
-			continue
+		// statusCode == 0 means the request never got a response at all
+		// (DNS/connect/timeout failure), which is always worth retrying.
+		if statusCode != 0 && !isRetryableStatus(statusCode) {
+			return fmt.Errorf("webhook failed with non-retryable status: %w", lastErr)
 		}
 
-		resp.Body.Close()
-
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			return nil
+		if retryAfter > 0 {
+			delay = retryAfter
+		} else {
+			base := time.Duration(s.configuration.RetryDelay) * time.Second
+			max := time.Duration(s.configuration.MaxRetryDelay) * time.Second
+			delay = decorrelatedJitterBackoff(delay, base, max)
 		}
 
-		lastErr = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		s.logger.DebugWith("Retrying webhook request", "attempt", attempt+1, "delay", delay, "error", lastErr)
 	}
 
 	return fmt.Errorf("webhook failed after %d attempts: %w", s.configuration.MaxRetries+1, lastErr)
 }
 
+// attempt sends body once, returning the response status code (0 if the
+// request itself failed before getting a response) and any Retry-After
+// delay the server asked for.
+func (s *Sink) attempt(ctx context.Context, body []byte) (statusCode int, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, s.configuration.Method, s.configuration.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range s.configuration.Headers {
+		req.Header.Set(key, value)
+	}
+
+	switch {
+	case s.configuration.CloudEvents:
+		req.Header.Set("Content-Type", cloudEventsContentType)
+	case req.Header.Get("Content-Type") == "":
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, 0, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			retryAfter = delay
+		}
+	}
+
+	return resp.StatusCode, retryAfter, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
 // GetKind returns the sink type
 func (s *Sink) GetKind() string {
 	return "webhook"
@@ -131,12 +256,15 @@ func (s *Sink) GetKind() string {
 // GetConfig returns the sink configuration
 func (s *Sink) GetConfig() map[string]interface{} {
 	return map[string]interface{}{
-		"url":        s.configuration.URL,
-		"method":     s.configuration.Method,
-		"headers":    s.configuration.Headers,
-		"timeout":    s.configuration.Timeout,
-		"maxRetries": s.configuration.MaxRetries,
-		"retryDelay": s.configuration.RetryDelay,
+		"url":            s.configuration.URL,
+		"method":         s.configuration.Method,
+		"headers":        s.configuration.Headers,
+		"timeout":        s.configuration.Timeout,
+		"maxRetries":     s.configuration.MaxRetries,
+		"retryDelay":     s.configuration.RetryDelay,
+		"maxRetryDelay":  s.configuration.MaxRetryDelay,
+		"cloudEvents":    s.configuration.CloudEvents,
+		"deadLetterKind": s.configuration.DeadLetterKind,
 	}
 }
 
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/nuclio/zap"
+)
+
+func newTestLogger(t *testing.T) *nucliozap.NuclioZap {
+	logger, err := nucliozap.NewNuclioZapTest("test")
+	if err != nil {
+		t.Fatalf("failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestWrite_NonRetryableStatusStopsImmediately(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	f := &factory{}
+	s, err := f.Create(newTestLogger(t), map[string]interface{}{
+		"url":        server.URL,
+		"maxRetries": 3,
+		"retryDelay": 0,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	if err := s.Write(context.Background(), []byte("payload"), nil); err == nil {
+		t.Fatal("expected error for non-retryable status")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected exactly 1 attempt for a non-retryable 400, got %d", attempts)
+	}
+}
+
+func TestWrite_RetriesOnRetryableStatusThenSucceeds(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	f := &factory{}
+	s, err := f.Create(newTestLogger(t), map[string]interface{}{
+		"url":           server.URL,
+		"maxRetries":    5,
+		"retryDelay":    0,
+		"maxRetryDelay": 0,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	if err := s.Write(context.Background(), []byte("payload"), nil); err != nil {
+		t.Fatalf("expected eventual success, got error: %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWrite_ExhaustedRetriesGoToFileDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	deadLetterFile, err := os.CreateTemp("", "webhook-dead-letter-*.jsonl")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(deadLetterFile.Name()) // nolint: errcheck
+	deadLetterFile.Close()                 // nolint: errcheck
+
+	f := &factory{}
+	s, err := f.Create(newTestLogger(t), map[string]interface{}{
+		"url":                server.URL,
+		"maxRetries":         1,
+		"retryDelay":         0,
+		"deadLetterKind":     "file",
+		"deadLetterFilePath": deadLetterFile.Name(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	writeErr := s.Write(context.Background(), []byte("payload"), map[string]interface{}{"k": "v"})
+	if writeErr == nil {
+		t.Fatal("expected the original delivery error to still be returned")
+	}
+
+	contents, err := os.ReadFile(deadLetterFile.Name())
+	if err != nil {
+		t.Fatalf("failed to read dead letter file: %v", err)
+	}
+	if len(contents) == 0 {
+		t.Fatal("expected a dead letter record to have been written")
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(contents[:len(contents)-1], &record); err != nil {
+		t.Fatalf("failed to parse dead letter record: %v", err)
+	}
+	if record["url"] != server.URL {
+		t.Fatalf("expected dead letter record to carry the webhook url, got %v", record["url"])
+	}
+}
+
+func TestWrite_CircuitBreakerShortCircuitsAfterThreshold(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	f := &factory{}
+	s, err := f.Create(newTestLogger(t), map[string]interface{}{
+		"url":                           server.URL,
+		"maxRetries":                    0,
+		"retryDelay":                    0,
+		"circuitBreakerThreshold":       2,
+		"circuitBreakerWindowSeconds":   60,
+		"circuitBreakerCooldownSeconds": 60,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	// Two calls trip the breaker (each call is a single attempt since maxRetries is 0).
+	_ = s.Write(context.Background(), []byte("payload"), nil)
+	_ = s.Write(context.Background(), []byte("payload"), nil)
+
+	attemptsBeforeShortCircuit := attempts
+	if err := s.Write(context.Background(), []byte("payload"), nil); err == nil {
+		t.Fatal("expected short-circuit error once breaker is open")
+	}
+	if attempts != attemptsBeforeShortCircuit {
+		t.Fatalf("expected no new request once breaker opened, attempts went from %d to %d", attemptsBeforeShortCircuit, attempts)
+	}
+}
+
+func TestWrite_CloudEventsEnvelope(t *testing.T) {
+	var receivedContentType string
+	var receivedBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedContentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&receivedBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	f := &factory{}
+	s, err := f.Create(newTestLogger(t), map[string]interface{}{
+		"url":               server.URL,
+		"maxRetries":        0,
+		"cloudEvents":       true,
+		"cloudEventsSource": "test-source",
+		"cloudEventsType":   "test.event",
+	})
+	if err != nil {
+		t.Fatalf("failed to create sink: %v", err)
+	}
+
+	if err := s.Write(context.Background(), []byte("payload"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if receivedContentType != cloudEventsContentType {
+		t.Fatalf("expected Content-Type %q, got %q", cloudEventsContentType, receivedContentType)
+	}
+	if receivedBody["source"] != "test-source" || receivedBody["type"] != "test.event" {
+		t.Fatalf("unexpected CloudEvents envelope: %+v", receivedBody)
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoff_CapsAtMax(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := time.Second
+
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := reconnectBackoff(attempt, base, max)
+		if delay < 0 {
+			t.Fatalf("attempt %d: delay went negative: %v", attempt, delay)
+		}
+		if delay > max+max/2 {
+			t.Fatalf("attempt %d: delay %v exceeds max+jitter %v", attempt, delay, max+max/2)
+		}
+	}
+}
+
+func TestReconnectBackoff_GrowsWithAttempt(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := 10 * time.Second
+
+	// with jitter up to delay/2, attempt N's minimum (no jitter) should
+	// still exceed attempt N-1's maximum (full jitter) once far enough
+	// apart that doubling dominates the jitter range.
+	lo := reconnectBackoff(0, base, max)
+	hi := reconnectBackoff(4, base, max)
+	if hi <= lo {
+		t.Fatalf("expected backoff to grow with attempt count: attempt 0 = %v, attempt 4 = %v", lo, hi)
+	}
+}
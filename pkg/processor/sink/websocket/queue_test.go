@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+// SPDX-License-Identifier: Apache-2.0
+
+package websocket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nuclio/zap"
+)
+
+func newTestSink(t *testing.T, policy string, queueSize int) *Sink {
+	logger, err := nucliozap.NewNuclioZapTest("test")
+	if err != nil {
+		t.Fatalf("failed to create test logger: %v", err)
+	}
+
+	return &Sink{
+		logger: logger,
+		configuration: &Configuration{
+			QueueSize:      queueSize,
+			OverflowPolicy: policy,
+		},
+		stopChan: make(chan struct{}),
+		queue:    make(chan []byte, queueSize),
+	}
+}
+
+func TestWrite_DropNewestDiscardsWhenFull(t *testing.T) {
+	s := newTestSink(t, OverflowPolicyDropNewest, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := s.Write(ctx, []byte("data"), nil); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+
+	stats := s.GetStats()
+	if stats.Enqueued != 2 {
+		t.Fatalf("expected 2 enqueued, got %d", stats.Enqueued)
+	}
+	if stats.Dropped != 1 {
+		t.Fatalf("expected 1 dropped, got %d", stats.Dropped)
+	}
+}
+
+func TestWrite_DropOldestEvictsToMakeRoom(t *testing.T) {
+	s := newTestSink(t, OverflowPolicyDropOldest, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := s.Write(ctx, []byte("data"), nil); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+
+	stats := s.GetStats()
+	if stats.Enqueued != 3 {
+		t.Fatalf("expected 3 enqueued, got %d", stats.Enqueued)
+	}
+	if stats.Dropped != 1 {
+		t.Fatalf("expected 1 dropped, got %d", stats.Dropped)
+	}
+	if len(s.queue) != 2 {
+		t.Fatalf("expected queue to hold 2 items, got %d", len(s.queue))
+	}
+}
+
+func TestWrite_BlockRespectsContextCancellation(t *testing.T) {
+	s := newTestSink(t, OverflowPolicyBlock, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := s.Write(ctx, []byte("data"), nil); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	cancel()
+	if err := s.Write(ctx, []byte("data"), nil); err == nil {
+		t.Fatalf("expected Write to return an error once ctx is done and queue is full")
+	}
+}
@@ -4,9 +4,17 @@
 package websocket
 
 import (
+	"compress/flate"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -15,11 +23,84 @@ import (
 	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
 )
 
+// TLSConfiguration holds the WSS client TLS material for a WebSocket sink.
+type TLSConfiguration struct {
+	// CAFile/CA are mutually exclusive sources for a custom CA pool used to
+	// verify the server certificate: a path to a PEM bundle, or the PEM
+	// contents inline. If neither is set, the system pool is used.
+	CAFile string `json:"caFile,omitempty"`
+	CA     string `json:"ca,omitempty"`
+
+	// CertFile/KeyFile, when both set, present a client certificate during
+	// the handshake (mutual TLS).
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	ServerName         string `json:"serverName,omitempty"`
+}
+
+// BasicAuthConfiguration holds HTTP basic auth credentials sent with the
+// WebSocket handshake request.
+type BasicAuthConfiguration struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
 // Configuration for WebSocket sink
 type Configuration struct {
 	URL         string `json:"url"`
 	MessageType string `json:"messageType,omitempty"` // "text" or "binary"
 	Timeout     int    `json:"timeout,omitempty"`     // seconds
+
+	TLS          *TLSConfiguration       `json:"tls,omitempty"`
+	Subprotocols []string                `json:"subprotocols,omitempty"`
+	Headers      map[string]string       `json:"headers,omitempty"` // e.g. bearer/API-key auth
+	BasicAuth    *BasicAuthConfiguration `json:"basicAuth,omitempty"`
+
+	// Compression enables RFC 7692 permessage-deflate negotiation on the
+	// dial, and, once negotiated, write-side compression at CompressionLevel
+	// (flate.BestSpeed..flate.BestCompression; 0 falls back to the gorilla
+	// default).
+	Compression      bool `json:"compression,omitempty"`
+	CompressionLevel int  `json:"compressionLevel,omitempty"`
+
+	// PingIntervalSeconds/PongTimeoutSeconds drive a background health check:
+	// a ping is sent every PingIntervalSeconds, and the connection is
+	// considered dead (triggering reconnect) if no pong is seen within
+	// PongTimeoutSeconds.
+	PingIntervalSeconds int `json:"pingIntervalSeconds,omitempty"`
+	PongTimeoutSeconds  int `json:"pongTimeoutSeconds,omitempty"`
+
+	// ReconnectBaseDelayMilliseconds/ReconnectMaxDelayMilliseconds bound the
+	// exponential backoff (min(base*2^attempt, max), plus uniform jitter in
+	// [0, delay/2]) applied between reconnect attempts. MaxReconnectAttempts,
+	// when nonzero, gives up reconnecting after that many consecutive
+	// failures; zero means retry forever.
+	ReconnectBaseDelayMilliseconds int `json:"reconnectBaseDelayMilliseconds,omitempty"`
+	ReconnectMaxDelayMilliseconds  int `json:"reconnectMaxDelayMilliseconds,omitempty"`
+	MaxReconnectAttempts           int `json:"maxReconnectAttempts,omitempty"`
+
+	// QueueSize bounds the write queue a dedicated writer goroutine drains,
+	// so Write() enqueues and returns instead of blocking on the conn
+	// directly. OverflowPolicy governs what happens once the queue is full:
+	// OverflowPolicyBlock, OverflowPolicyDropOldest or OverflowPolicyDropNewest.
+	QueueSize      int    `json:"queueSize,omitempty"`
+	OverflowPolicy string `json:"overflowPolicy,omitempty"`
+}
+
+// Overflow policies for a full write queue.
+const (
+	OverflowPolicyBlock      = "block"
+	OverflowPolicyDropOldest = "drop_oldest"
+	OverflowPolicyDropNewest = "drop_newest"
+)
+
+// Stats holds write-queue counters for a WebSocket sink.
+type Stats struct {
+	Enqueued    uint64
+	Dropped     uint64
+	WriteErrors uint64
 }
 
 // Sink implements WebSocket client
@@ -28,8 +109,15 @@ type Sink struct {
 	configuration *Configuration
 	conn          *websocket.Conn
 	connMux       sync.RWMutex
+	writeMux      sync.Mutex
 	stopChan      chan struct{}
 	wg            sync.WaitGroup
+
+	queue       chan []byte
+	queueMux    sync.Mutex // serializes drop_oldest's peek-then-send against concurrent Write calls
+	enqueued    atomic.Uint64
+	dropped     atomic.Uint64
+	writeErrors atomic.Uint64
 }
 
 // factory implements sink.Factory
@@ -37,8 +125,14 @@ type factory struct{}
 
 func (f *factory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
 	config := &Configuration{
-		MessageType: "binary",
-		Timeout:     10,
+		MessageType:                    "binary",
+		Timeout:                        10,
+		PingIntervalSeconds:            30,
+		PongTimeoutSeconds:             60,
+		ReconnectBaseDelayMilliseconds: 500,
+		ReconnectMaxDelayMilliseconds:  30000,
+		QueueSize:                      256,
+		OverflowPolicy:                 OverflowPolicyBlock,
 	}
 
 	if err := mapstructure.Decode(configuration, config); err != nil {
@@ -53,10 +147,46 @@ func (f *factory) Create(logger logger.Logger, configuration map[string]interfac
 		return nil, fmt.Errorf("invalid message type: %s (must be 'text' or 'binary')", config.MessageType)
 	}
 
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url: %w", err)
+	}
+
+	if config.TLS != nil && (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return nil, fmt.Errorf("tls.certFile and tls.keyFile must be set together")
+	}
+
+	if config.TLS != nil && config.TLS.CAFile != "" && config.TLS.CA != "" {
+		return nil, fmt.Errorf("tls.caFile and tls.ca are mutually exclusive")
+	}
+
+	if config.CompressionLevel != 0 && (config.CompressionLevel < flate.BestSpeed || config.CompressionLevel > flate.BestCompression) {
+		return nil, fmt.Errorf("compressionLevel must be between %d and %d", flate.BestSpeed, flate.BestCompression)
+	}
+
+	if config.QueueSize <= 0 {
+		return nil, fmt.Errorf("queueSize must be > 0")
+	}
+
+	switch config.OverflowPolicy {
+	case OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest:
+	default:
+		return nil, fmt.Errorf("invalid overflow policy: %s", config.OverflowPolicy)
+	}
+
+	if parsedURL.Scheme == "wss" {
+		hasTLSMaterial := config.TLS != nil &&
+			(config.TLS.CAFile != "" || config.TLS.CA != "" || config.TLS.CertFile != "" || config.TLS.InsecureSkipVerify)
+		if !hasTLSMaterial {
+			return nil, fmt.Errorf("wss:// url requires tls CA/cert material or explicit tls.insecureSkipVerify")
+		}
+	}
+
 	return &Sink{
 		logger:        logger,
 		configuration: config,
 		stopChan:      make(chan struct{}),
+		queue:         make(chan []byte, config.QueueSize),
 	}, nil
 }
 
@@ -72,9 +202,11 @@ func (s *Sink) Start() error {
 		return err
 	}
 
-	// Start connection manager
-	s.wg.Add(1)
+	// Start connection manager and the dedicated writer goroutine that owns
+	// the conn exclusively on the data-write path.
+	s.wg.Add(2)
 	go s.manageConnection()
+	go s.writeLoop()
 
 	return nil
 }
@@ -97,14 +229,74 @@ func (s *Sink) Stop(force bool) error {
 	return nil
 }
 
-// Write sends data to the WebSocket
+// Write enqueues data onto the sink's bounded write queue, returning
+// quickly; a dedicated writer goroutine owns the connection and drains the
+// queue onto it. Once the queue is full, behavior depends on
+// Configuration.OverflowPolicy: OverflowPolicyBlock waits for room (or ctx
+// to be done), OverflowPolicyDropOldest discards the oldest queued item to
+// make room, and OverflowPolicyDropNewest discards data itself.
 func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	switch s.configuration.OverflowPolicy {
+	case OverflowPolicyDropNewest:
+		select {
+		case s.queue <- data:
+			s.enqueued.Add(1)
+		default:
+			s.dropped.Add(1)
+		}
+		return nil
+
+	case OverflowPolicyDropOldest:
+		s.queueMux.Lock()
+		defer s.queueMux.Unlock()
+		for {
+			select {
+			case s.queue <- data:
+				s.enqueued.Add(1)
+				return nil
+			default:
+			}
+			select {
+			case <-s.queue:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+
+	default: // OverflowPolicyBlock
+		select {
+		case s.queue <- data:
+			s.enqueued.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// writeLoop drains the write queue onto whatever connection is currently
+// active, for as long as the sink is running.
+func (s *Sink) writeLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case data := <-s.queue:
+			s.writeItem(data)
+		}
+	}
+}
+
+func (s *Sink) writeItem(data []byte) {
 	s.connMux.RLock()
 	conn := s.conn
 	s.connMux.RUnlock()
 
 	if conn == nil {
-		return fmt.Errorf("websocket not connected")
+		s.dropped.Add(1)
+		return
 	}
 
 	messageType := websocket.BinaryMessage
@@ -112,19 +304,40 @@ func (s *Sink) Write(ctx context.Context, data []byte, metadata map[string]inter
 		messageType = websocket.TextMessage
 	}
 
-	if err := conn.WriteMessage(messageType, data); err != nil {
+	s.writeMux.Lock()
+	err := conn.WriteMessage(messageType, data)
+	s.writeMux.Unlock()
+
+	if err != nil {
 		s.logger.WarnWith("Failed to write to websocket", "error", err)
-		// Trigger reconnection
-		s.connMux.Lock()
-		if s.conn != nil {
-			s.conn.Close()
-			s.conn = nil
-		}
-		s.connMux.Unlock()
-		return err
+		s.writeErrors.Add(1)
+		s.dropConnection(conn)
 	}
+}
 
-	return nil
+// GetStats returns the sink's write-queue counters.
+func (s *Sink) GetStats() Stats {
+	return Stats{
+		Enqueued:    s.enqueued.Load(),
+		Dropped:     s.dropped.Load(),
+		WriteErrors: s.writeErrors.Load(),
+	}
+}
+
+// dropConnection closes conn and clears it from the sink if it is still the
+// active connection, letting manageConnection pick up a reconnect. A no-op
+// if conn has already been replaced or cleared (e.g. by a concurrent caller,
+// or by Stop).
+func (s *Sink) dropConnection(conn *websocket.Conn) {
+	s.connMux.Lock()
+	defer s.connMux.Unlock()
+
+	if s.conn != conn {
+		return
+	}
+
+	s.conn.Close()
+	s.conn = nil
 }
 
 // GetKind returns the sink type
@@ -134,58 +347,244 @@ func (s *Sink) GetKind() string {
 
 // GetConfig returns the sink configuration
 func (s *Sink) GetConfig() map[string]interface{} {
-	return map[string]interface{}{
-		"url":         s.configuration.URL,
-		"messageType": s.configuration.MessageType,
-		"timeout":     s.configuration.Timeout,
+	config := map[string]interface{}{
+		"url":                  s.configuration.URL,
+		"messageType":          s.configuration.MessageType,
+		"timeout":              s.configuration.Timeout,
+		"subprotocols":         s.configuration.Subprotocols,
+		"compression":          s.configuration.Compression,
+		"compressionLevel":     s.configuration.CompressionLevel,
+		"pingIntervalSeconds":  s.configuration.PingIntervalSeconds,
+		"pongTimeoutSeconds":   s.configuration.PongTimeoutSeconds,
+		"maxReconnectAttempts": s.configuration.MaxReconnectAttempts,
+		"queueSize":            s.configuration.QueueSize,
+		"overflowPolicy":       s.configuration.OverflowPolicy,
+	}
+	if s.configuration.TLS != nil {
+		config["tlsCertFile"] = s.configuration.TLS.CertFile
+		config["tlsCaFile"] = s.configuration.TLS.CAFile
+		config["tlsInsecureSkipVerify"] = s.configuration.TLS.InsecureSkipVerify
 	}
+	return config
 }
 
 // connect establishes a WebSocket connection
 func (s *Sink) connect() error {
 	dialer := websocket.Dialer{
-		HandshakeTimeout: time.Duration(s.configuration.Timeout) * time.Second,
+		HandshakeTimeout:  time.Duration(s.configuration.Timeout) * time.Second,
+		Subprotocols:      s.configuration.Subprotocols,
+		EnableCompression: s.configuration.Compression,
+	}
+
+	tlsConfig, err := s.buildTLSConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build TLS configuration: %w", err)
 	}
+	dialer.TLSClientConfig = tlsConfig
 
-	conn, _, err := dialer.Dial(s.configuration.URL, nil)
+	conn, _, err := dialer.Dial(s.configuration.URL, s.buildHeaders())
 	if err != nil {
 		return fmt.Errorf("failed to connect to websocket: %w", err)
 	}
 
+	if s.configuration.Compression {
+		conn.EnableWriteCompression(true)
+		if s.configuration.CompressionLevel != 0 {
+			if err := conn.SetCompressionLevel(s.configuration.CompressionLevel); err != nil {
+				s.logger.WarnWith("Failed to set compression level", "error", err)
+			}
+		}
+	}
+
+	pongTimeout := time.Duration(s.configuration.PongTimeoutSeconds) * time.Second
+	_ = conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+
 	s.connMux.Lock()
 	s.conn = conn
 	s.connMux.Unlock()
 
+	s.wg.Add(2)
+	go s.readPump(conn)
+	go s.pingLoop(conn)
+
 	return nil
 }
 
-// manageConnection monitors and reconnects the WebSocket
-func (s *Sink) manageConnection() {
+// readPump pumps control frames (pongs) off conn so SetPongHandler fires,
+// discarding any data frames since a sink only ever writes. It returns, and
+// drops the connection, as soon as the read errors (closed conn, or the
+// read deadline elapsing without a pong).
+func (s *Sink) readPump(conn *websocket.Conn) {
 	defer s.wg.Done()
+	defer s.dropConnection(conn)
 
-	ticker := time.NewTicker(5 * time.Second)
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// pingLoop sends a control ping every PingIntervalSeconds so a live peer
+// keeps refreshing conn's read deadline via the pong handler; a dead peer's
+// connection is reaped once that deadline elapses in readPump.
+func (s *Sink) pingLoop(conn *websocket.Conn) {
+	defer s.wg.Done()
+
+	interval := time.Duration(s.configuration.PingIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-s.stopChan:
+			return
 		case <-ticker.C:
-			s.connMux.RLock()
-			connected := s.conn != nil
-			s.connMux.RUnlock()
-
-			if !connected {
-				s.logger.DebugWith("Attempting to reconnect WebSocket")
-				if err := s.connect(); err != nil {
-					s.logger.WarnWith("Failed to reconnect", "error", err)
-				} else {
-					s.logger.InfoWith("WebSocket reconnected")
-				}
+			s.writeMux.Lock()
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval))
+			s.writeMux.Unlock()
+			if err != nil {
+				s.dropConnection(conn)
+				return
 			}
+		}
+	}
+}
 
+// buildHeaders assembles the handshake request headers from the sink's
+// configured custom headers and basic auth credentials.
+func (s *Sink) buildHeaders() http.Header {
+	if len(s.configuration.Headers) == 0 && s.configuration.BasicAuth == nil {
+		return nil
+	}
+
+	headers := http.Header{}
+	for key, value := range s.configuration.Headers {
+		headers.Set(key, value)
+	}
+
+	if s.configuration.BasicAuth != nil {
+		req := &http.Request{Header: headers}
+		req.SetBasicAuth(s.configuration.BasicAuth.Username, s.configuration.BasicAuth.Password)
+	}
+
+	return headers
+}
+
+// buildTLSConfig constructs client TLS config for a wss:// URL from the
+// sink's configuration, or returns (nil, nil) if TLS is not configured (the
+// dialer then uses the system defaults for a plain ws:// URL).
+func (s *Sink) buildTLSConfig() (*tls.Config, error) {
+	if s.configuration.TLS == nil {
+		return nil, nil
+	}
+
+	tlsCfg := s.configuration.TLS
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tlsCfg.InsecureSkipVerify,
+		ServerName:         tlsCfg.ServerName,
+	}
+
+	if tlsCfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tlsCfg.CertFile, tlsCfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	caPEM := tlsCfg.CA
+	if tlsCfg.CAFile != "" {
+		caBytes, err := os.ReadFile(tlsCfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		caPEM = string(caBytes)
+	}
+	if caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, fmt.Errorf("failed to parse TLS CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// manageConnection monitors the WebSocket and reconnects it when dropped
+// (by Write, or by the ping/pong health check), applying exponential
+// backoff with jitter between attempts.
+func (s *Sink) manageConnection() {
+	defer s.wg.Done()
+
+	checkTicker := time.NewTicker(time.Second)
+	defer checkTicker.Stop()
+
+	attempt := 0
+
+	for {
+		select {
 		case <-s.stopChan:
 			return
+		case <-checkTicker.C:
+		}
+
+		s.connMux.RLock()
+		connected := s.conn != nil
+		s.connMux.RUnlock()
+		if connected {
+			attempt = 0
+			continue
+		}
+
+		if s.configuration.MaxReconnectAttempts > 0 && attempt >= s.configuration.MaxReconnectAttempts {
+			s.logger.WarnWith("Giving up on WebSocket reconnection", "attempts", attempt)
+			return
 		}
+
+		delay := reconnectBackoff(attempt,
+			time.Duration(s.configuration.ReconnectBaseDelayMilliseconds)*time.Millisecond,
+			time.Duration(s.configuration.ReconnectMaxDelayMilliseconds)*time.Millisecond)
+
+		select {
+		case <-s.stopChan:
+			return
+		case <-time.After(delay):
+		}
+
+		s.logger.DebugWith("Attempting to reconnect WebSocket", "attempt", attempt+1, "delay", delay)
+		if err := s.connect(); err != nil {
+			attempt++
+			s.logger.WarnWith("Failed to reconnect", "attempt", attempt, "error", err)
+			continue
+		}
+
+		attempt = 0
+		s.logger.InfoWith("WebSocket reconnected")
+	}
+}
+
+// reconnectBackoff computes min(base*2^attempt, max) plus uniform jitter in
+// [0, delay/2].
+func reconnectBackoff(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
 	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay + jitter
 }
 
 func init() {
@@ -0,0 +1,107 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvAdvertiseEncodings lets operators disable the Accept-Encoding rewrite
+// below, e.g. for a strict compatibility test against an upstream that
+// behaves oddly when offered a trimmed header. Mirrors the ergonomics of
+// gRPC-Go's GRPC_GO_ADVERTISE_COMPRESSORS switch: set to "false" to opt
+// out, anything else (including unset) leaves the rewrite enabled.
+const EnvAdvertiseEncodings = "EXTPROC_ADVERTISE_ENCODINGS"
+
+// advertiseEncodingsEnabled reports whether the Accept-Encoding rewrite is
+// enabled, per EnvAdvertiseEncodings.
+func advertiseEncodingsEnabled() bool {
+	return os.Getenv(EnvAdvertiseEncodings) != "false"
+}
+
+// registeredDecoderNames returns the Content-Encoding names with a
+// registered decoder (see RegisterDecoder), sorted for deterministic
+// output.
+func registeredDecoderNames() []string {
+	decoderRegistryMu.RLock()
+	defer decoderRegistryMu.RUnlock()
+	names := make([]string, 0, len(decoderRegistry))
+	for name := range decoderRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// advertisedAcceptEncoding rewrites an outbound Accept-Encoding header
+// value to the intersection of what the peer listed and the encodings this
+// package can actually decode (see RegisterDecoder), so an upstream that
+// would otherwise pick an encoding nothing here can decompress - e.g. "br"
+// when only a gzip decoder happens to be registered - doesn't force body
+// buffering to bail out later. A bare "*" is treated as accepting
+// everything, so it becomes the full registered set. ok is false, and
+// value should be left untouched, when the peer sent no Accept-Encoding,
+// or every one of its entries is already registered (nothing to narrow).
+func advertisedAcceptEncoding(value string) (rewritten string, ok bool) {
+	tokens := parseAcceptEncoding(value)
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	wantsWildcard := false
+	kept := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if t.q <= 0 {
+			continue
+		}
+		if t.name == "*" {
+			wantsWildcard = true
+			continue
+		}
+		if _, registered := decoderFor(t.name); registered {
+			kept = append(kept, t.name)
+		}
+	}
+
+	if wantsWildcard {
+		kept = registeredDecoderNames()
+	}
+
+	if len(kept) == 0 {
+		return "", false
+	}
+	if !wantsWildcard && len(kept) == len(tokens) {
+		return "", false
+	}
+
+	return strings.Join(kept, ", "), true
+}
+
+// advertiseAcceptEncoding narrows the outbound Accept-Encoding header (see
+// advertisedAcceptEncoding) when opts enables body decompression for
+// handler inspection - DecompressBodies is what actually causes a body to
+// be buffered and decoded before reaching a Handler - and the rewrite
+// hasn't been disabled via EnvAdvertiseEncodings. A no-op otherwise, or if
+// there is no Accept-Encoding header to rewrite.
+func advertiseAcceptEncoding(ctx *RequestContext, opts *ProcessingOptions, headers *AllHeaders) {
+	if opts == nil || !opts.DecompressBodies || !advertiseEncodingsEnabled() {
+		return
+	}
+
+	acceptEncoding, err := headers.GetHeaderValueAsString("accept-encoding")
+	if err != nil || acceptEncoding == "" {
+		return
+	}
+
+	rewritten, ok := advertisedAcceptEncoding(acceptEncoding)
+	if !ok {
+		return
+	}
+
+	ctx.OverwriteHeader("accept-encoding", HeaderValue{Value: rewritten})
+}
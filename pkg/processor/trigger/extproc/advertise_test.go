@@ -0,0 +1,159 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvertisedAcceptEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+		expectedOK     bool
+	}{
+		{
+			name:           "gzip only is already registered, no rewrite",
+			acceptEncoding: "gzip",
+			expectedOK:     false,
+		},
+		{
+			name:           "br only is already registered, no rewrite",
+			acceptEncoding: "br",
+			expectedOK:     false,
+		},
+		{
+			name:           "zstd only is already registered, no rewrite",
+			acceptEncoding: "zstd",
+			expectedOK:     false,
+		},
+		{
+			name:           "identity only has nothing to narrow",
+			acceptEncoding: "identity",
+			expectedOK:     false,
+		},
+		{
+			name:           "unregistered codec is dropped",
+			acceptEncoding: "gzip, x-unknown",
+			expected:       "gzip",
+			expectedOK:     true,
+		},
+		{
+			name:           "wildcard expands to the full registered set",
+			acceptEncoding: "*",
+			expected:       "br, deflate, gzip, zstd",
+			expectedOK:     true,
+		},
+		{
+			name:           "q=0 entries are excluded from consideration",
+			acceptEncoding: "gzip;q=0, br",
+			expected:       "br",
+			expectedOK:     true,
+		},
+		{
+			name:           "empty header is a no-op",
+			acceptEncoding: "",
+			expectedOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewritten, ok := advertisedAcceptEncoding(tt.acceptEncoding)
+			assert.Equal(t, tt.expectedOK, ok)
+			if tt.expectedOK {
+				assert.Equal(t, tt.expected, rewritten)
+			}
+		})
+	}
+}
+
+func TestAdvertiseEncodingsEnabled(t *testing.T) {
+	defer os.Unsetenv(EnvAdvertiseEncodings)
+
+	require.NoError(t, os.Unsetenv(EnvAdvertiseEncodings))
+	assert.True(t, advertiseEncodingsEnabled())
+
+	require.NoError(t, os.Setenv(EnvAdvertiseEncodings, "false"))
+	assert.False(t, advertiseEncodingsEnabled())
+
+	require.NoError(t, os.Setenv(EnvAdvertiseEncodings, "anything-else"))
+	assert.True(t, advertiseEncodingsEnabled())
+}
+
+func TestAdvertiseAcceptEncodingRewritesHeaderOnRequestHeaders(t *testing.T) {
+	defer os.Unsetenv(EnvAdvertiseEncodings)
+	require.NoError(t, os.Unsetenv(EnvAdvertiseEncodings))
+
+	mockHandler := new(MockEventHandler)
+	processor := &ObserveProcessor{AbstractProcessor: AbstractProcessor{Handler: mockHandler}}
+	processor.Init(&ProcessingOptions{DecompressBodies: true}, nil, mockHandler)
+
+	headers := &AllHeaders{
+		Headers:    map[string]string{"accept-encoding": "gzip, br, x-unknown"},
+		RawHeaders: map[string][]byte{},
+	}
+	ctx := &RequestContext{AllHeaders: headers}
+	ctx.ResetPhase()
+
+	require.NoError(t, processor.ProcessRequestHeaders(ctx, *headers))
+
+	require.NotNil(t, ctx.response.headerMutation)
+	require.Len(t, ctx.response.headerMutation.SetHeaders, 1)
+	assert.Equal(t, "accept-encoding", ctx.response.headerMutation.SetHeaders[0].Header.Key)
+	assert.Equal(t, "gzip, br", ctx.response.headerMutation.SetHeaders[0].Header.Value)
+}
+
+func TestAdvertiseAcceptEncodingDisabledByEnvVar(t *testing.T) {
+	defer os.Unsetenv(EnvAdvertiseEncodings)
+	require.NoError(t, os.Setenv(EnvAdvertiseEncodings, "false"))
+
+	mockHandler := new(MockEventHandler)
+	processor := &PreProcessor{AbstractProcessor: AbstractProcessor{Handler: mockHandler}}
+	processor.Init(&ProcessingOptions{DecompressBodies: true}, nil, mockHandler)
+
+	headers := &AllHeaders{
+		Headers:    map[string]string{"accept-encoding": "gzip, x-unknown"},
+		RawHeaders: map[string][]byte{},
+	}
+	ctx := &RequestContext{AllHeaders: headers, bodybuffer: &EncodedBody{Type: BodyType{}, Complete: true}}
+	ctx.ResetPhase()
+
+	mockHandler.On("HandleEvent", ctx, []byte(nil)).Return(&EventResponse{}, nil).Maybe()
+
+	require.NoError(t, processor.ProcessRequestHeaders(ctx, *headers))
+
+	if ctx.response.headerMutation != nil {
+		assert.Empty(t, ctx.response.headerMutation.SetHeaders)
+	}
+}
+
+func TestAdvertiseAcceptEncodingSkippedWhenDecompressBodiesDisabled(t *testing.T) {
+	defer os.Unsetenv(EnvAdvertiseEncodings)
+	require.NoError(t, os.Unsetenv(EnvAdvertiseEncodings))
+
+	mockHandler := new(MockEventHandler)
+	processor := &ObserveProcessor{AbstractProcessor: AbstractProcessor{Handler: mockHandler}}
+	processor.Init(&ProcessingOptions{DecompressBodies: false}, nil, mockHandler)
+
+	headers := &AllHeaders{
+		Headers:    map[string]string{"accept-encoding": "gzip, x-unknown"},
+		RawHeaders: map[string][]byte{},
+	}
+	ctx := &RequestContext{AllHeaders: headers}
+	ctx.ResetPhase()
+
+	require.NoError(t, processor.ProcessRequestHeaders(ctx, *headers))
+
+	if ctx.response.headerMutation != nil {
+		assert.Empty(t, ctx.response.headerMutation.SetHeaders)
+	}
+}
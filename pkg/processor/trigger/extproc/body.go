@@ -8,12 +8,96 @@ package extproc
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// decoderFactory wraps r with a decompressing io.ReadCloser for one
+// Content-Encoding token (e.g. "gzip", "br").
+type decoderFactory func(io.Reader) (io.ReadCloser, error)
+
+var (
+	decoderRegistryMu sync.RWMutex
+	decoderRegistry   = map[string]decoderFactory{}
 )
 
+func init() {
+	RegisterDecoder("gzip", func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	})
+	RegisterDecoder("deflate", func(r io.Reader) (io.ReadCloser, error) {
+		return flate.NewReader(r), nil
+	})
+	RegisterDecoder("br", func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(brotli.NewReader(r)), nil
+	})
+	RegisterDecoder("zstd", func(r io.Reader) (io.ReadCloser, error) {
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &zstdReadCloser{decoder}, nil
+	})
+}
+
+// zstdReadCloser adapts *zstd.Decoder, whose Close method takes no error, to
+// io.ReadCloser.
+type zstdReadCloser struct {
+	decoder *zstd.Decoder
+}
+
+func (z *zstdReadCloser) Read(p []byte) (int, error) {
+	return z.decoder.Read(p)
+}
+
+func (z *zstdReadCloser) Close() error {
+	z.decoder.Close()
+	return nil
+}
+
+// RegisterDecoder registers factory to decode bodies whose Content-Encoding
+// (or Transfer-Encoding) names name, e.g. "gzip" or "br". Registering an
+// already-registered name replaces it. Safe for concurrent use; intended
+// both for this package's built-in codecs and for SDK users adding custom
+// ones at runtime.
+func RegisterDecoder(name string, factory decoderFactory) {
+	decoderRegistryMu.Lock()
+	defer decoderRegistryMu.Unlock()
+	decoderRegistry[name] = factory
+}
+
+func decoderFor(name string) (decoderFactory, bool) {
+	decoderRegistryMu.RLock()
+	defer decoderRegistryMu.RUnlock()
+	factory, ok := decoderRegistry[name]
+	return factory, ok
+}
+
+// splitEncodingList splits a comma-separated Content-Encoding value (e.g.
+// "gzip, br") into its individual tokens, trimmed of surrounding whitespace.
+func splitEncodingList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
 type BodyType struct {
 	// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Type
 	ContentType string // the body content type, if applicable, but almost always present
@@ -38,13 +122,22 @@ func NewBodyTypeFromHeaders(headers *AllHeaders) BodyType {
 }
 
 // Reply true if the body bytes should be interpretted as compressed
-// data with strategies defined by the type's stored headers.
+// data with strategies defined by the type's stored headers. Only
+// encodings with a registered decoder (see RegisterDecoder) count; an
+// encoding nothing can decode is treated as opaque, uncompressed data.
 func (b *BodyType) IsCompressed() bool {
-	if len(b.ContentEncoding) > 0 {
-		return true
+	for _, encoding := range splitEncodingList(b.ContentEncoding) {
+		if encoding == "identity" {
+			continue
+		}
+		if _, ok := decoderFor(encoding); ok {
+			return true
+		}
 	}
 	if len(b.TransferEncoding) > 0 && !b.IsChunked() {
-		return true
+		if _, ok := decoderFor(b.TransferEncoding); ok {
+			return true
+		}
 	}
 	return false
 }
@@ -75,6 +168,12 @@ type EncodedBody struct {
 	MaxSize      int64    // maximum allowable size of a buffer; -1 for no limit
 	Complete     bool     // flag to identify if the body is complete
 	Decompressed bool     // flag to identify if decompression was successful
+	Truncated    bool     // set once OnBodyOverflowTruncate stopped accepting further chunks
+
+	// passThrough is set once OnBodyOverflowPassThrough has reverted this
+	// body's phase to per-chunk delivery; Value is dropped and no longer
+	// accumulated for the remainder of the phase.
+	passThrough bool
 }
 
 // Initializer for an `EncodedBody` when headers are known, and thus
@@ -156,22 +255,81 @@ func (b *EncodedBody) DecompressBody() error {
 		return errors.New("cannot decompress an incomplete body")
 	}
 
-	// TODO: declare and check supported encoding/compression strategies
-	encoding := b.Encoding()
-	if encoding == "gzip" {
-		unzipped, err := gUnzipData(b.Value)
+	// Content-Encoding may list multiple codings applied in order (e.g.
+	// "gzip, br" means gzip-then-brotli was applied to the original data),
+	// so they must be undone in reverse order.
+	encodings := splitEncodingList(b.Encoding())
+	value := b.Value
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := encodings[i]
+		if encoding == "identity" {
+			continue
+		}
+
+		factory, ok := decoderFor(encoding)
+		if !ok {
+			log.Printf("Decompression for \"%s\" not yet implemented\n", encoding)
+			b.Decompressed = false
+			return errors.New("unsupported encoding/compression strategy")
+		}
+
+		decoded, err := decodeWith(factory, value)
 		if err != nil {
 			b.Decompressed = false
-			log.Printf("gzip decompression failed: %v\n", err)
-			return errors.New("gzip decompression failed")
+			log.Printf("%s decompression failed: %v\n", encoding, err)
+			return fmt.Errorf("%s decompression failed: %w", encoding, err)
 		}
-		b.Value = unzipped
+		value = decoded
+	}
+
+	b.Value = value
+	b.Decompressed = true
+	return nil
+}
+
+// CompressBody re-encodes b.Value with the codec registered for encoding
+// (see RegisterEncoder), so a PreProcessor/PostProcessor that mutated a
+// decompressed body can restore the client's original Content-Encoding
+// before calling RequestContext.ReplaceBodyChunk. "" and "identity" are
+// no-ops. On success, b.Value holds the re-encoded bytes, b.Type's
+// ContentEncoding is updated to encoding, and Decompressed is cleared
+// since the stored bytes are compressed again; b is left unchanged on
+// error.
+func (b *EncodedBody) CompressBody(encoding string) error {
+	if encoding == "" || encoding == "identity" {
 		return nil
 	}
 
-	log.Printf("Decompression for \"%s\" not yet implemented\n", encoding)
+	factory, ok := encoderFor(encoding)
+	if !ok {
+		return fmt.Errorf("unsupported encoding/compression strategy: %s", encoding)
+	}
+
+	encoded, err := encodeWith(factory, b.Value)
+	if err != nil {
+		return fmt.Errorf("%s compression failed: %w", encoding, err)
+	}
+
+	b.Value = encoded
+	b.Type.ContentEncoding = encoding
 	b.Decompressed = false
-	return errors.New("unsupported encoding/compression strategy")
+	return nil
+}
+
+// decodeWith runs data through a decoder built by factory and returns the
+// fully decoded bytes.
+func decodeWith(factory decoderFactory, data []byte) ([]byte, error) {
+	reader, err := factory(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var result bytes.Buffer
+	if _, err := result.ReadFrom(reader); err != nil {
+		return nil, err
+	}
+	return result.Bytes(), nil
 }
 
 // https://gist.github.com/alex-ant/aeaaf497055590dacba760af24839b8d
@@ -2,9 +2,13 @@ package extproc
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
+	"io"
 	"testing"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -154,7 +158,7 @@ func TestEncodedBody_AppendChunk(t *testing.T) {
 }
 
 func TestEncodedBody_DecompressBody(t *testing.T) {
-	// Helper function to create gzipped data
+	// Helper functions to create compressed data per codec
 	createGzippedData := func(data []byte) []byte {
 		var buf bytes.Buffer
 		writer := gzip.NewWriter(&buf)
@@ -162,6 +166,27 @@ func TestEncodedBody_DecompressBody(t *testing.T) {
 		writer.Close()
 		return buf.Bytes()
 	}
+	createBrotliData := func(data []byte) []byte {
+		var buf bytes.Buffer
+		writer := brotli.NewWriter(&buf)
+		writer.Write(data)
+		writer.Close()
+		return buf.Bytes()
+	}
+	createDeflateData := func(data []byte) []byte {
+		var buf bytes.Buffer
+		writer, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+		writer.Write(data)
+		writer.Close()
+		return buf.Bytes()
+	}
+	createZstdData := func(data []byte) []byte {
+		var buf bytes.Buffer
+		writer, _ := zstd.NewWriter(&buf)
+		writer.Write(data)
+		writer.Close()
+		return buf.Bytes()
+	}
 
 	tests := []struct {
 		name        string
@@ -211,16 +236,71 @@ func TestEncodedBody_DecompressBody(t *testing.T) {
 			expectError: true,
 		},
 		{
-			name: "unsupported encoding",
+			name: "invalid brotli data",
 			body: &EncodedBody{
 				Type: BodyType{
-					ContentEncoding: "br", // brotli compression
+					ContentEncoding: "br",
 				},
-				Value:    []byte("test"),
+				Value:    []byte("not brotli"),
 				Complete: true,
 			},
 			expectError: true,
 		},
+		{
+			name: "unregistered encoding is treated as uncompressed",
+			body: &EncodedBody{
+				Type: BodyType{
+					ContentEncoding: "compress",
+				},
+				Value:    []byte("test"),
+				Complete: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "valid brotli compression",
+			body: &EncodedBody{
+				Type: BodyType{
+					ContentEncoding: "br",
+				},
+				Value:    createBrotliData([]byte("test")),
+				Complete: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "valid deflate compression",
+			body: &EncodedBody{
+				Type: BodyType{
+					ContentEncoding: "deflate",
+				},
+				Value:    createDeflateData([]byte("test")),
+				Complete: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "valid zstd compression",
+			body: &EncodedBody{
+				Type: BodyType{
+					ContentEncoding: "zstd",
+				},
+				Value:    createZstdData([]byte("test")),
+				Complete: true,
+			},
+			expectError: false,
+		},
+		{
+			name: "comma-separated encodings applied in reverse order",
+			body: &EncodedBody{
+				Type: BodyType{
+					ContentEncoding: "gzip, br",
+				},
+				Value:    createBrotliData(createGzippedData([]byte("test"))),
+				Complete: true,
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -232,11 +312,73 @@ func TestEncodedBody_DecompressBody(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.True(t, tt.body.Decompressed)
+				assert.Equal(t, []byte("test"), tt.body.Value)
 			}
 		})
 	}
 }
 
+func TestRegisterDecoder(t *testing.T) {
+	calls := 0
+	RegisterDecoder("x-test-codec", func(r io.Reader) (io.ReadCloser, error) {
+		calls++
+		return io.NopCloser(r), nil
+	})
+
+	body := &EncodedBody{
+		Type:     BodyType{ContentEncoding: "x-test-codec"},
+		Value:    []byte("passthrough"),
+		Complete: true,
+	}
+
+	assert.True(t, body.IsCompressed())
+	err := body.DecompressBody()
+	assert.NoError(t, err)
+	assert.True(t, body.Decompressed)
+	assert.Equal(t, []byte("passthrough"), body.Value)
+	assert.Equal(t, 1, calls)
+}
+
+func TestEncodedBody_CompressBody(t *testing.T) {
+	t.Run("identity is a no-op", func(t *testing.T) {
+		body := &EncodedBody{Value: []byte("test"), Decompressed: true}
+		require.NoError(t, body.CompressBody("identity"))
+		assert.Equal(t, []byte("test"), body.Value)
+		assert.True(t, body.Decompressed)
+	})
+
+	t.Run("empty encoding is a no-op", func(t *testing.T) {
+		body := &EncodedBody{Value: []byte("test"), Decompressed: true}
+		require.NoError(t, body.CompressBody(""))
+		assert.Equal(t, []byte("test"), body.Value)
+	})
+
+	t.Run("unregistered encoding errors", func(t *testing.T) {
+		body := &EncodedBody{Value: []byte("test")}
+		err := body.CompressBody("compress")
+		assert.Error(t, err)
+		assert.Equal(t, []byte("test"), body.Value, "body should be left unchanged on error")
+	})
+
+	t.Run("gzip round-trips through DecompressBody", func(t *testing.T) {
+		body := &EncodedBody{
+			Type:         BodyType{ContentEncoding: "gzip"},
+			Value:        []byte("hello world"),
+			Complete:     true,
+			Decompressed: true,
+		}
+
+		require.NoError(t, body.CompressBody("gzip"))
+		assert.NotEqual(t, []byte("hello world"), body.Value)
+		assert.Equal(t, "gzip", body.Type.ContentEncoding)
+		assert.False(t, body.Decompressed)
+
+		require.NoError(t, body.DecompressBody())
+		assert.Equal(t, []byte("hello world"), body.Value)
+		assert.True(t, body.Decompressed)
+	})
+}
+
 func TestNewEncodedBodyFromHeaders(t *testing.T) {
 	tests := []struct {
 		name    string
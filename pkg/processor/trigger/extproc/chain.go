@@ -0,0 +1,428 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import (
+	"log"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/nuclio/errors"
+)
+
+// ChainProcessor composes Configuration.Pipeline's stages into a single
+// RequestProcessor: request-phase body/trailer processing runs the stages
+// in Pipeline order, response-phase processing runs them in reverse,
+// mirroring how a chain of HTTP middlewares unwinds. Each stage is built
+// from one of PreProcessor/PostProcessor/WrapProcessor/ObserveProcessor,
+// reusing their existing AbstractProcessor.Handler pattern; ChainProcessor
+// itself calls their unexported processRequest/processResponse/
+// wrapRequest/wrapResponse/observeRequest/observeResponse helpers
+// directly so it can see the Handler error a stage produced (those
+// methods' public Process*Body wrappers log and swallow it) and act on
+// ContinueOnError/ErrorStatus accordingly.
+type ChainProcessor struct {
+	name   string
+	opts   *ProcessingOptions
+	stages []*chainStage
+}
+
+// chainStage pairs a StageConfig with the processor instance built for it.
+type chainStage struct {
+	config    StageConfig
+	processor RequestProcessor
+}
+
+// NewChainProcessor builds a ChainProcessor from stageConfigs, constructing
+// and Init-ing one stage processor per entry with the given opts/handler -
+// the same (opts, handler) every standalone extproc trigger type is
+// Init-ed with, since a pipeline still runs as a single trigger backed by
+// one nuclio function.
+func NewChainProcessor(stageConfigs []StageConfig, opts *ProcessingOptions, handler EventHandler) (*ChainProcessor, error) {
+	stages := make([]*chainStage, 0, len(stageConfigs))
+
+	for _, config := range stageConfigs {
+		processor, err := newStageProcessor(config.Type)
+		if err != nil {
+			return nil, err
+		}
+		if err := processor.Init(opts, nil, handler); err != nil {
+			return nil, err
+		}
+		stages = append(stages, &chainStage{config: config, processor: processor})
+	}
+
+	return &ChainProcessor{name: "chainprocessor", opts: opts, stages: stages}, nil
+}
+
+// newStageProcessor builds the bare AbstractProcessor-embedding type for
+// operatorType; callers are responsible for Init-ing it.
+func newStageProcessor(operatorType OperatorType) (RequestProcessor, error) {
+	switch operatorType {
+	case OperatorTypePre:
+		return &PreProcessor{}, nil
+	case OperatorTypePost:
+		return &PostProcessor{}, nil
+	case OperatorTypeWrap:
+		return &WrapProcessor{}, nil
+	case OperatorTypeObserve:
+		return &ObserveProcessor{}, nil
+	default:
+		return nil, errors.Errorf("unsupported pipeline stage type: %s", operatorType)
+	}
+}
+
+func (c *ChainProcessor) GetName() string {
+	return c.name
+}
+
+func (c *ChainProcessor) GetOptions() *ProcessingOptions {
+	return c.opts
+}
+
+func (c *ChainProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	for _, stage := range c.stages {
+		if err := stage.processor.ProcessRequestHeaders(ctx, headers); err != nil {
+			return err
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ChainProcessor) ProcessRequestTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	for _, stage := range c.stages {
+		if err := stage.processor.ProcessRequestTrailers(ctx, trailers); err != nil {
+			return err
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ChainProcessor) ProcessResponseHeaders(ctx *RequestContext, headers AllHeaders) error {
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		if err := c.stages[i].processor.ProcessResponseHeaders(ctx, headers); err != nil {
+			return err
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ChainProcessor) ProcessResponseTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		if err := c.stages[i].processor.ProcessResponseTrailers(ctx, trailers); err != nil {
+			return err
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+// ProcessRequestBody runs every stage's request-body behavior in Pipeline
+// order, threading each stage's (possibly mutated) body into the next.
+// EncodedBody decompression happens exactly once, before this method is
+// ever called (see RequestContext.handleBodyChunk), regardless of how
+// many stages the pipeline has.
+func (c *ChainProcessor) ProcessRequestBody(ctx *RequestContext, body []byte) error {
+	out := body
+
+	for _, stage := range c.stages {
+		next, immediate, err := stage.runRequestBody(ctx, out)
+		if err != nil {
+			log.Printf("Pipeline stage %q failed processing request body: %v", stage.config.Name, err)
+			if !stage.config.ContinueOnError {
+				return ctx.CancelRequest(int32(stage.config.ErrorStatus), nil, nil)
+			}
+			continue
+		}
+		if immediate != nil {
+			return ctx.CancelRequest(immediate.Status, immediate.Headers, immediate.Body)
+		}
+		out = next
+	}
+
+	ctx.ReplaceBodyChunk(out)
+	return ctx.ContinueRequest()
+}
+
+// ProcessResponseBody mirrors ProcessRequestBody but runs stages in
+// reverse Pipeline order, as a chain of middlewares unwinds.
+func (c *ChainProcessor) ProcessResponseBody(ctx *RequestContext, body []byte) error {
+	out := body
+
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		stage := c.stages[i]
+		next, err := stage.runResponseBody(ctx, out)
+		if err != nil {
+			log.Printf("Pipeline stage %q failed processing response body: %v", stage.config.Name, err)
+			if !stage.config.ContinueOnError {
+				return ctx.CancelRequest(int32(stage.config.ErrorStatus), nil, nil)
+			}
+			continue
+		}
+		out = next
+	}
+
+	ctx.ReplaceBodyChunk(out)
+	return ctx.ContinueRequest()
+}
+
+// runRequestBody drives this stage's request-body behavior directly via
+// its underlying processor's unexported helper, so ChainProcessor sees
+// the Handler's error (if any) rather than the logged-and-swallowed one
+// the stage's own ProcessRequestBody would return. ObserveProcessor stages
+// are read-only taps: they're handed a copy of body and their result is
+// always discarded, regardless of what their Handler returns.
+func (s *chainStage) runRequestBody(ctx *RequestContext, body []byte) (out []byte, immediate *ImmediateResponse, err error) {
+	switch processor := s.processor.(type) {
+	case *PreProcessor:
+		out, _, err = processor.processRequest(ctx, body)
+		return out, nil, err
+	case *WrapProcessor:
+		out, immediate, err = processor.wrapRequest(ctx, body)
+		if immediate != nil {
+			return body, immediate, nil
+		}
+		return out, nil, err
+	case *ObserveProcessor:
+		err = processor.observeRequest(ctx, append([]byte(nil), body...))
+		return body, nil, err
+	default:
+		// PostProcessor and any unrecognized stage type don't act on the
+		// request body; pass it through unchanged.
+		return body, nil, nil
+	}
+}
+
+// runResponseBody is runRequestBody's response-phase counterpart.
+func (s *chainStage) runResponseBody(ctx *RequestContext, body []byte) ([]byte, error) {
+	switch processor := s.processor.(type) {
+	case *PostProcessor:
+		out, _, err := processor.processResponse(ctx, body)
+		return out, err
+	case *WrapProcessor:
+		out, err := processor.wrapResponse(ctx, body)
+		return out, err
+	case *ObserveProcessor:
+		err := processor.observeResponse(ctx, append([]byte(nil), body...))
+		return body, err
+	default:
+		// PreProcessor and any unrecognized stage type don't act on the
+		// response body; pass it through unchanged.
+		return body, nil
+	}
+}
+
+// ChainEntry pairs an arbitrary RequestProcessor with an optional predicate
+// scoping when it participates in a ProcessorChain. Unlike ChainProcessor's
+// stages, a ChainEntry's Processor isn't restricted to the four built-in
+// Pre/Post/Wrap/ObserveProcessor stage types: it can be any hand-written
+// RequestProcessor (e.g. a logging, auth, or metrics cross-cutting
+// processor), mixed freely with the built-in ones.
+type ChainEntry struct {
+	Processor RequestProcessor
+
+	// Predicate, if non-nil, is evaluated once against the request headers
+	// (e.g. to check a content-type) and decides whether Processor
+	// participates in every phase of this stream; nil means always active.
+	Predicate func(headers AllHeaders) bool
+}
+
+// ProcessorChain composes entries' RequestProcessors into a single
+// RequestProcessor, running each phase through them in Entries order for
+// the request path and in reverse for the response path, the same
+// middleware-unwind convention ChainProcessor uses. It implements
+// RequestProcessor itself, so GenericExtProcServer (via
+// Serve/ServeWithOptions/Attach) accepts a ProcessorChain transparently in
+// place of any single processor.
+//
+// A cancelled request (CancelRequest/an ImmediateResponse staged by any
+// entry, detected via RequestContext.MutationSummary) stops the chain
+// immediately: later entries, including ones still scoped in by their
+// Predicate, don't run. Header and body mutations staged by earlier entries
+// are visible to later ones, since they're all staged on the same
+// RequestContext.
+type ProcessorChain struct {
+	name    string
+	opts    *ProcessingOptions
+	entries []ChainEntry
+}
+
+// NewProcessorChain builds a ProcessorChain from entries; name identifies it
+// to GetName() and as a namespace for the per-stream predicate results it
+// stores on RequestContext.
+func NewProcessorChain(name string, opts *ProcessingOptions, entries []ChainEntry) *ProcessorChain {
+	return &ProcessorChain{name: name, opts: opts, entries: entries}
+}
+
+func (c *ProcessorChain) GetName() string {
+	return c.name
+}
+
+func (c *ProcessorChain) GetOptions() *ProcessingOptions {
+	return c.opts
+}
+
+// activeStoreKey namespaces this chain's per-stream "which entries are
+// active" flags on RequestContext's named value store, so nesting two
+// ProcessorChains (unusual, but not forbidden) under distinct names doesn't
+// collide.
+func (c *ProcessorChain) activeStoreKey() string {
+	return "extproc.chain." + c.name + ".active"
+}
+
+// activeEntries returns, per entry, whether it's active for this stream:
+// computed from the request headers by ProcessRequestHeaders and stashed on
+// ctx so every later phase sees the same decision. A stream that somehow
+// reaches a later phase without going through ProcessRequestHeaders first
+// (not possible via the normal ext_proc phase order, but defensive here)
+// gets every entry active.
+func (c *ProcessorChain) activeEntries(ctx *RequestContext) []bool {
+	if val, err := ctx.GetStoredValue(c.activeStoreKey()); err == nil {
+		if active, ok := val.([]bool); ok {
+			return active
+		}
+	}
+
+	active := make([]bool, len(c.entries))
+	for i := range active {
+		active[i] = true
+	}
+	return active
+}
+
+func (c *ProcessorChain) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	active := make([]bool, len(c.entries))
+	for i, entry := range c.entries {
+		active[i] = entry.Predicate == nil || entry.Predicate(headers)
+	}
+	ctx.SetStoredValue(c.activeStoreKey(), active)
+
+	for i, entry := range c.entries {
+		if !active[i] {
+			continue
+		}
+		if err := entry.Processor.ProcessRequestHeaders(ctx, headers); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ProcessorChain) ProcessRequestTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	active := c.activeEntries(ctx)
+	for i, entry := range c.entries {
+		if !active[i] {
+			continue
+		}
+		if err := entry.Processor.ProcessRequestTrailers(ctx, trailers); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ProcessorChain) ProcessResponseHeaders(ctx *RequestContext, headers AllHeaders) error {
+	active := c.activeEntries(ctx)
+	for i := len(c.entries) - 1; i >= 0; i-- {
+		if !active[i] {
+			continue
+		}
+		if err := c.entries[i].Processor.ProcessResponseHeaders(ctx, headers); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+func (c *ProcessorChain) ProcessResponseTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	active := c.activeEntries(ctx)
+	for i := len(c.entries) - 1; i >= 0; i-- {
+		if !active[i] {
+			continue
+		}
+		if err := c.entries[i].Processor.ProcessResponseTrailers(ctx, trailers); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+// ProcessRequestBody runs every active entry's ProcessRequestBody in order,
+// threading each entry's (possibly replaced) body into the next by reading
+// back whatever RequestContext.ReplaceBodyChunk staged, since entries mutate
+// the body through ctx rather than a return value.
+func (c *ProcessorChain) ProcessRequestBody(ctx *RequestContext, body []byte) error {
+	active := c.activeEntries(ctx)
+	out := body
+
+	for i, entry := range c.entries {
+		if !active[i] {
+			continue
+		}
+		if err := entry.Processor.ProcessRequestBody(ctx, out); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+		if mutated, ok := currentBodyMutationBytes(ctx); ok {
+			out = mutated
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+// ProcessResponseBody mirrors ProcessRequestBody but runs entries in
+// reverse order, as a chain of middlewares unwinds.
+func (c *ProcessorChain) ProcessResponseBody(ctx *RequestContext, body []byte) error {
+	active := c.activeEntries(ctx)
+	out := body
+
+	for i := len(c.entries) - 1; i >= 0; i-- {
+		if !active[i] {
+			continue
+		}
+		if err := c.entries[i].Processor.ProcessResponseBody(ctx, out); err != nil {
+			return err
+		}
+		if ctx.MutationSummary().Cancelled {
+			return nil
+		}
+		if mutated, ok := currentBodyMutationBytes(ctx); ok {
+			out = mutated
+		}
+	}
+	return ctx.ContinueRequest()
+}
+
+// currentBodyMutationBytes returns the body bytes currently staged by
+// RequestContext.ReplaceBodyChunk, if any entry has staged one via this
+// RequestContext's body mutation (a ClearBodyChunk is reported as staged
+// with an empty body, matching what envoy would end up receiving).
+func currentBodyMutationBytes(ctx *RequestContext) ([]byte, bool) {
+	bm := ctx.response.bodyMutation
+	if bm == nil {
+		return nil, false
+	}
+	switch m := bm.Mutation.(type) {
+	case *extprocv3.BodyMutation_Body:
+		return m.Body, true
+	case *extprocv3.BodyMutation_ClearBody:
+		return []byte{}, true
+	default:
+		return nil, false
+	}
+}
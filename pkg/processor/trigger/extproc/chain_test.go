@@ -0,0 +1,335 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainProcessor_GetName(t *testing.T) {
+	c := &ChainProcessor{name: "chainprocessor"}
+	assert.Equal(t, "chainprocessor", c.GetName())
+}
+
+// newChainStage builds a stage backed by a fresh MockEventHandler, already
+// Init-ed the same way NewChainProcessor would.
+func newChainStage(t *testing.T, name string, operatorType OperatorType, handler *MockEventHandler) *chainStage {
+	t.Helper()
+	processor, err := newStageProcessor(operatorType)
+	require.NoError(t, err)
+	require.NoError(t, processor.Init(NewDefaultOptions(), nil, handler))
+	return &chainStage{
+		config:    StageConfig{Name: name, Type: operatorType},
+		processor: processor,
+	}
+}
+
+func TestChainProcessor_ProcessRequestBody_Ordering(t *testing.T) {
+	// Pre mutates the request body, Observe taps it read-only, Post doesn't
+	// act on the request at all: ChainProcessor should run Pre first, feed
+	// its output to Observe, and forward Pre's output unchanged to envoy.
+	preHandler := new(MockEventHandler)
+	observeHandler := new(MockEventHandler)
+	postHandler := new(MockEventHandler)
+
+	pre := newChainStage(t, "pre", OperatorTypePre, preHandler)
+	observe := newChainStage(t, "observe", OperatorTypeObserve, observeHandler)
+	post := newChainStage(t, "post", OperatorTypePost, postHandler)
+	chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{pre, observe, post}}
+
+	ctx := &RequestContext{AllHeaders: &AllHeaders{}, bodybuffer: &EncodedBody{}}
+	ctx.ResetPhase()
+	body := []byte("original")
+
+	preHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Body: []byte("from-pre")}, nil).Once()
+	observeHandler.On("HandleEvent", ctx, []byte("from-pre")).Return(&EventResponse{Body: []byte("ignored")}, nil).Once()
+
+	err := chain.ProcessRequestBody(ctx, body)
+	require.NoError(t, err)
+	preHandler.AssertExpectations(t)
+	observeHandler.AssertExpectations(t)
+	postHandler.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+
+	assert.Equal(t, []byte("from-pre"), ctx.response.bodyMutation.GetBody())
+}
+
+func TestChainProcessor_ProcessResponseBody_ReverseOrdering(t *testing.T) {
+	// Response-phase processing runs in reverse Pipeline order: Post mutates
+	// first, Observe taps Post's output, Pre doesn't act on the response.
+	preHandler := new(MockEventHandler)
+	observeHandler := new(MockEventHandler)
+	postHandler := new(MockEventHandler)
+
+	pre := newChainStage(t, "pre", OperatorTypePre, preHandler)
+	observe := newChainStage(t, "observe", OperatorTypeObserve, observeHandler)
+	post := newChainStage(t, "post", OperatorTypePost, postHandler)
+	chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{pre, observe, post}}
+
+	ctx := &RequestContext{bodybuffer: &EncodedBody{}}
+	ctx.ResetPhase()
+	body := []byte("upstream response")
+
+	postHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Body: []byte("from-post")}, nil).Once()
+	observeHandler.On("HandleEvent", ctx, []byte("from-post")).Return(&EventResponse{Body: []byte("ignored")}, nil).Once()
+
+	err := chain.ProcessResponseBody(ctx, body)
+	require.NoError(t, err)
+	postHandler.AssertExpectations(t)
+	observeHandler.AssertExpectations(t)
+	preHandler.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+
+	assert.Equal(t, []byte("from-post"), ctx.response.bodyMutation.GetBody())
+}
+
+func TestChainProcessor_ProcessRequestBody_ErrorPropagation(t *testing.T) {
+	t.Run("ContinueOnError false short-circuits with the stage's ErrorStatus", func(t *testing.T) {
+		failingHandler := new(MockEventHandler)
+		nextHandler := new(MockEventHandler)
+
+		failing := newChainStage(t, "failing", OperatorTypePre, failingHandler)
+		failing.config.ErrorStatus = 502
+		next := newChainStage(t, "next", OperatorTypePre, nextHandler)
+		chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{failing, next}}
+
+		ctx := &RequestContext{bodybuffer: &EncodedBody{}}
+		body := []byte("original")
+
+		failingHandler.On("HandleEvent", ctx, body).Return(nil, assert.AnError).Once()
+
+		err := chain.ProcessRequestBody(ctx, body)
+		require.NoError(t, err)
+		failingHandler.AssertExpectations(t)
+		nextHandler.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+
+		require.NotNil(t, ctx.response.immediateResponse)
+		assert.EqualValues(t, 502, ctx.response.immediateResponse.Status.Code)
+	})
+
+	t.Run("ContinueOnError true logs and moves on with the body unchanged", func(t *testing.T) {
+		failingHandler := new(MockEventHandler)
+		nextHandler := new(MockEventHandler)
+
+		failing := newChainStage(t, "failing", OperatorTypePre, failingHandler)
+		failing.config.ContinueOnError = true
+		next := newChainStage(t, "next", OperatorTypePre, nextHandler)
+		chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{failing, next}}
+
+		ctx := &RequestContext{AllHeaders: &AllHeaders{}, bodybuffer: &EncodedBody{}}
+		ctx.ResetPhase()
+		body := []byte("original")
+
+		failingHandler.On("HandleEvent", ctx, body).Return(nil, assert.AnError).Once()
+		nextHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Body: []byte("from-next")}, nil).Once()
+
+		err := chain.ProcessRequestBody(ctx, body)
+		require.NoError(t, err)
+		failingHandler.AssertExpectations(t)
+		nextHandler.AssertExpectations(t)
+		assert.Nil(t, ctx.response.immediateResponse)
+		assert.Equal(t, []byte("from-next"), ctx.response.bodyMutation.GetBody())
+	})
+}
+
+func TestChainProcessor_ProcessRequestBody_WrapImmediateResponseShortCircuits(t *testing.T) {
+	// Once a stage returns an ImmediateResponse, later stages must not run:
+	// calling their ProcessRequestBody would call ctx.ContinueRequest(),
+	// which unconditionally clears response.immediateResponse.
+	wrapHandler := new(MockEventHandler)
+	nextHandler := new(MockEventHandler)
+
+	wrap := newChainStage(t, "wrap", OperatorTypeWrap, wrapHandler)
+	next := newChainStage(t, "next", OperatorTypePre, nextHandler)
+	chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{wrap, next}}
+
+	ctx := &RequestContext{bodybuffer: &EncodedBody{}}
+	body := []byte("original")
+
+	wrapHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Status: 401, Body: []byte("denied")}, nil).Once()
+
+	err := chain.ProcessRequestBody(ctx, body)
+	require.NoError(t, err)
+	wrapHandler.AssertExpectations(t)
+	nextHandler.AssertNotCalled(t, "HandleEvent", mock.Anything, mock.Anything)
+
+	require.NotNil(t, ctx.response.immediateResponse)
+	assert.EqualValues(t, 401, ctx.response.immediateResponse.Status.Code)
+	assert.Equal(t, []byte("denied"), ctx.response.immediateResponse.Body)
+}
+
+// chainFakeProcessor is a minimal RequestProcessor for ProcessorChain tests,
+// covering the case ChainProcessor's stage types don't: an arbitrary
+// hand-written processor (e.g. a logging or auth cross-cutting concern)
+// rather than one of Pre/Post/Wrap/ObserveProcessor. Each on* hook, if set,
+// replaces the AbstractProcessor default (a plain ctx.ContinueRequest());
+// nil leaves that phase a pass-through.
+type chainFakeProcessor struct {
+	AbstractProcessor
+	name string
+
+	onRequestHeaders func(ctx *RequestContext, headers AllHeaders) error
+	onRequestBody    func(ctx *RequestContext, body []byte) error
+}
+
+func (p *chainFakeProcessor) GetName() string { return p.name }
+
+func (p *chainFakeProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	if p.onRequestHeaders != nil {
+		return p.onRequestHeaders(ctx, headers)
+	}
+	return ctx.ContinueRequest()
+}
+
+func (p *chainFakeProcessor) ProcessRequestBody(ctx *RequestContext, body []byte) error {
+	if p.onRequestBody != nil {
+		return p.onRequestBody(ctx, body)
+	}
+	return ctx.ContinueRequest()
+}
+
+func TestProcessorChain_GetName(t *testing.T) {
+	c := NewProcessorChain("my-chain", NewDefaultOptions(), nil)
+	assert.Equal(t, "my-chain", c.GetName())
+}
+
+func TestProcessorChain_ProcessRequestBody_ThreadsMutationsInOrder(t *testing.T) {
+	// first appends "-a", second appends "-b" to whatever body it's handed;
+	// second must see first's output, and the final body sent upstream must
+	// be second's output.
+	first := &chainFakeProcessor{
+		name: "first",
+		onRequestBody: func(ctx *RequestContext, body []byte) error {
+			ctx.ReplaceBodyChunk(append(append([]byte(nil), body...), []byte("-a")...))
+			return ctx.ContinueRequest()
+		},
+	}
+	second := &chainFakeProcessor{
+		name: "second",
+		onRequestBody: func(ctx *RequestContext, body []byte) error {
+			assert.Equal(t, []byte("original-a"), body)
+			ctx.ReplaceBodyChunk(append(append([]byte(nil), body...), []byte("-b")...))
+			return ctx.ContinueRequest()
+		},
+	}
+	chain := NewProcessorChain("chain", NewDefaultOptions(), []ChainEntry{
+		{Processor: first}, {Processor: second},
+	})
+
+	ctx := &RequestContext{bodybuffer: &EncodedBody{}, data: map[string]any{}}
+	ctx.ResetPhase()
+	err := chain.ProcessRequestBody(ctx, []byte("original"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("original-a-b"), ctx.response.bodyMutation.GetBody())
+}
+
+func TestProcessorChain_Predicate_SkipsInactiveEntry(t *testing.T) {
+	var jsonOnlyCalled bool
+	jsonOnly := &chainFakeProcessor{
+		name: "json-only",
+		onRequestHeaders: func(ctx *RequestContext, headers AllHeaders) error {
+			jsonOnlyCalled = true
+			return ctx.ContinueRequest()
+		},
+	}
+	chain := NewProcessorChain("chain", NewDefaultOptions(), []ChainEntry{
+		{
+			Processor: jsonOnly,
+			Predicate: func(headers AllHeaders) bool {
+				value, _, exists := headers.GetHeaderValue("content-type")
+				return exists && value != nil && *value == "application/json"
+			},
+		},
+	})
+
+	ctx := &RequestContext{bodybuffer: &EncodedBody{}, data: map[string]any{}}
+	headers := AllHeaders{Headers: map[string]string{"content-type": "text/plain"}, RawHeaders: map[string][]byte{}}
+
+	err := chain.ProcessRequestHeaders(ctx, headers)
+	require.NoError(t, err)
+	assert.False(t, jsonOnlyCalled, "predicate should have skipped the entry for a non-matching content-type")
+}
+
+func TestProcessorChain_Cancel_ShortCircuitsLaterEntries(t *testing.T) {
+	denying := &chainFakeProcessor{
+		name: "auth",
+		onRequestHeaders: func(ctx *RequestContext, headers AllHeaders) error {
+			return ctx.CancelRequest(401, nil, []byte("denied"))
+		},
+	}
+	var laterCalled bool
+	later := &chainFakeProcessor{
+		name: "later",
+		onRequestHeaders: func(ctx *RequestContext, headers AllHeaders) error {
+			laterCalled = true
+			return ctx.ContinueRequest()
+		},
+	}
+	chain := NewProcessorChain("chain", NewDefaultOptions(), []ChainEntry{
+		{Processor: denying}, {Processor: later},
+	})
+
+	ctx := &RequestContext{bodybuffer: &EncodedBody{}, data: map[string]any{}}
+	headers := AllHeaders{Headers: map[string]string{}, RawHeaders: map[string][]byte{}}
+
+	err := chain.ProcessRequestHeaders(ctx, headers)
+	require.NoError(t, err)
+	assert.False(t, laterCalled, "a cancelled request must stop the chain before later entries run")
+	require.NotNil(t, ctx.response.immediateResponse)
+	assert.EqualValues(t, 401, ctx.response.immediateResponse.Status.Code)
+}
+
+func TestChainProcessor_ProcessRequestBody_DecompressesOnlyOnceAtHeadOfChain(t *testing.T) {
+	// Build a gzip-compressed request body and drive it through
+	// RequestContext.handleBodyChunk the same way GenericExtProcServer does:
+	// decompression happens there, once, before ChainProcessor ever sees the
+	// body. Both pipeline stages must then observe the same decompressed
+	// bytes, and neither stage (nor ChainProcessor itself) decompresses
+	// again.
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte("plaintext body"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	compressed := buf.Bytes()
+
+	headers := &AllHeaders{
+		Headers:    map[string]string{"content-encoding": "gzip"},
+		RawHeaders: map[string][]byte{},
+	}
+
+	firstHandler := new(MockEventHandler)
+	secondHandler := new(MockEventHandler)
+	first := newChainStage(t, "first", OperatorTypePre, firstHandler)
+	second := newChainStage(t, "second", OperatorTypePre, secondHandler)
+	chain := &ChainProcessor{name: "chainprocessor", stages: []*chainStage{first, second}}
+
+	opts := &ProcessingOptions{
+		BufferStreamedBodies: true,
+		DecompressBodies:     true,
+	}
+	ctx := &RequestContext{
+		AllHeaders:     headers,
+		extProcOptions: opts,
+		bodybuffer:     NewEncodedBodyFromHeaders(headers),
+	}
+	ctx.ResetPhase()
+	ctx.EndOfStream = true
+
+	firstHandler.On("HandleEvent", ctx, []byte("plaintext body")).Return(&EventResponse{Body: []byte("plaintext body")}, nil).Once()
+	secondHandler.On("HandleEvent", ctx, []byte("plaintext body")).Return(&EventResponse{Body: []byte("plaintext body")}, nil).Once()
+
+	err = ctx.handleBodyChunk(chain.ProcessRequestBody, opts, compressed, false)
+	require.NoError(t, err)
+
+	assert.True(t, ctx.HasDecompressedBody())
+	firstHandler.AssertExpectations(t)
+	secondHandler.AssertExpectations(t)
+}
@@ -22,6 +22,7 @@ type PhaseResponse struct {
 	bodyMutation      *extprocv3.BodyMutation      // body responses
 	continueRequest   *extprocv3.CommonResponse    // headers/body responses
 	immediateResponse *extprocv3.ImmediateResponse // headers/body responses
+	clearRouteCache   bool                         // set by RequestContext.ClearRouteCache; cleared by ResetPhase
 }
 
 // RequestContext helps manage and pass data related to a given request
@@ -45,10 +46,38 @@ type RequestContext struct {
 
 	EndOfStream bool // flag declaring when request/response processing is complete
 
+	// StreamScratch is scratch state a StreamingHandler can use to carry
+	// data across successive OnResponseChunk calls for the same stream
+	// (e.g. a partial frame split across chunk boundaries). It is never
+	// touched by the SDK itself and is nil until a handler sets it.
+	StreamScratch any
+
 	extProcOptions *ProcessingOptions // external processing options
 	data           map[string]any     // named data store for clients passing values
 	response       PhaseResponse      // internal response helper object
 	bodybuffer     *EncodedBody       // reset on request headers, response headers
+	held           []byte             // bytes accumulated by HoldBodyChunk, pending YieldBodyChunk
+	mutationStack  []mutationSnapshot // open BeginMutations scopes, innermost last
+
+	// requestAcceptEncoding snapshots the request's `accept-encoding` header
+	// at request-headers time, since AllHeaders is overwritten with the
+	// response's own headers by the time PostProcessor processes the
+	// response body; see recompressBody.
+	requestAcceptEncoding string
+
+	// requestGRPCFramer and responseGRPCFramer hold the incremental
+	// gRPC message framing state (see GRPCFramer) for each direction of a
+	// gRPC-framed body, lazily created by grpcFramerFor.
+	requestGRPCFramer  *GRPCFramer
+	responseGRPCFramer *GRPCFramer
+
+	// GRPCStatus and GRPCStatusMessage surface a gRPC call's outcome from
+	// its "grpc-status"/"grpc-message" trailers (see
+	// https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md#responses),
+	// captured by AbstractProcessor's trailer hooks. Empty until trailers
+	// carrying them have been processed.
+	GRPCStatus        string
+	GRPCStatusMessage string
 }
 
 // Initialize a request context with parsed headers
@@ -98,6 +127,8 @@ func initReqCtx(rc *RequestContext, headers *AllHeaders) error {
 		rc.RequestID = rc.extProcOptions.RequestIdFallback
 	}
 
+	rc.requestAcceptEncoding, _ = rc.AllHeaders.GetHeaderValueAsString("accept-encoding")
+
 	// remove "envoy" headers from (copied) headers, so clients don't need to parse
 	rc.AllHeaders.DropHeadersNamedStartingWith(":")
 
@@ -118,10 +149,36 @@ func (rc *RequestContext) appendBodyChunk(chunk []byte) error {
 }
 
 // Internal handler for each "chunk" (complete or not) for a request or response
-// body. This is repeated in request and response body handling.
-func (rc *RequestContext) handleBodyChunk(handler BodyHandler, opts *ProcessingOptions, chunk []byte) (err error) {
+// body. This is repeated in request and response body handling. isResponse
+// selects which of MaxBufferedRequestBodyBytes/MaxBufferedResponseBodyBytes
+// applies.
+func (rc *RequestContext) handleBodyChunk(handler BodyHandler, opts *ProcessingOptions, chunk []byte, isResponse bool) (err error) {
 	if opts.BufferStreamedBodies {
-		err = rc.appendBodyChunk(chunk)
+		if rc.bodybuffer.passThrough {
+			return handler(rc, chunk)
+		}
+
+		switch rc.checkBodyOverflow(opts, chunk, isResponse) {
+		case bodyOverflowPassThrough:
+			return handler(rc, chunk)
+		case bodyOverflowImmediateResponse:
+			status := opts.OverflowResponseStatus
+			if status == 0 {
+				status = 413
+			}
+			if err := rc.CancelRequest(status, nil, nil); err != nil {
+				return err
+			}
+			return handler(rc, rc.CurrentBodyBytes())
+		case bodyOverflowError:
+			return errors.New("buffered body exceeds configured limit")
+		case bodyOverflowTruncated:
+			// stop appending further chunks, but still call the handler
+			// with whatever was collected so far
+		default: // bodyOverflowNone
+			err = rc.appendBodyChunk(chunk)
+		}
+
 		if err == nil && rc.EndOfStream {
 			rc.bodybuffer.Complete = true // EndOfStream, no (size) error
 			if opts.DecompressBodies {
@@ -140,6 +197,173 @@ func (rc *RequestContext) handleBodyChunk(handler BodyHandler, opts *ProcessingO
 	return handler(rc, chunk)
 }
 
+// bodyOverflowOutcome classifies what, if anything, handleBodyChunk should
+// do about the current chunk once checkBodyOverflow has evaluated the
+// configured limits against it.
+type bodyOverflowOutcome int
+
+const (
+	bodyOverflowNone bodyOverflowOutcome = iota // under every limit, append as usual
+	bodyOverflowTruncated
+	bodyOverflowPassThrough
+	bodyOverflowImmediateResponse
+	bodyOverflowError
+)
+
+// checkBodyOverflow enforces the per-phase (MaxBufferedRequestBodyBytes /
+// MaxBufferedResponseBodyBytes) and global (MaxConcurrentBufferedBodyBytes)
+// buffered-body limits against appending chunk, applying opts.OnBodyOverflow
+// once either one would be exceeded. Mutates rc.bodybuffer/releases global
+// accounting as needed for the PassThrough/Truncate policies; the caller
+// (handleBodyChunk) still has to act on the returned outcome.
+func (rc *RequestContext) checkBodyOverflow(opts *ProcessingOptions, chunk []byte, isResponse bool) bodyOverflowOutcome {
+	limit := opts.MaxBufferedRequestBodyBytes
+	if isResponse {
+		limit = opts.MaxBufferedResponseBodyBytes
+	}
+
+	overflow := limit > 0 && int64(len(rc.bodybuffer.Value)+len(chunk)) > limit
+
+	if !overflow && opts.MaxConcurrentBufferedBodyBytes > 0 {
+		if opts.globalBufferedBytes.Add(int64(len(chunk))) > opts.MaxConcurrentBufferedBodyBytes {
+			opts.globalBufferedBytes.Add(-int64(len(chunk))) // don't count bytes we won't keep
+			overflow = true
+		}
+	}
+
+	if !overflow {
+		return bodyOverflowNone
+	}
+
+	switch opts.OnBodyOverflow {
+	case OnBodyOverflowPassThrough:
+		rc.bodybuffer.passThrough = true
+		rc.releaseBufferedBytes()
+		rc.bodybuffer.Value = nil
+		return bodyOverflowPassThrough
+	case OnBodyOverflowImmediateResponse:
+		return bodyOverflowImmediateResponse
+	case OnBodyOverflowError:
+		return bodyOverflowError
+	default: // OnBodyOverflowTruncate
+		rc.bodybuffer.Truncated = true
+		return bodyOverflowTruncated
+	}
+}
+
+// releaseBufferedBytes returns this context's currently buffered body bytes
+// to extProcOptions.globalBufferedBytes, e.g. when a buffer is dropped
+// (OnBodyOverflowPassThrough) or replaced by a fresh one for a new phase.
+func (rc *RequestContext) releaseBufferedBytes() {
+	if rc.extProcOptions == nil || rc.bodybuffer == nil {
+		return
+	}
+	rc.extProcOptions.globalBufferedBytes.Add(-int64(len(rc.bodybuffer.Value)))
+}
+
+// BodyTruncated reports whether the current request/response body buffer
+// stopped accepting chunks early due to OnBodyOverflowTruncate.
+func (rc *RequestContext) BodyTruncated() bool {
+	return rc.bodybuffer.Truncated
+}
+
+// BytesBuffered returns how many body bytes are currently buffered for the
+// request/response in progress.
+func (rc *RequestContext) BytesBuffered() int64 {
+	return int64(len(rc.bodybuffer.Value))
+}
+
+// RegisterHealthDependency registers a named upstream dependency check
+// (e.g. a database ping or a kafka broker lookup) with the gRPC health
+// service wired up by ServeWithOptions/Attach. The check is polled in the
+// background for the lifetime of the server; while it returns a non-nil
+// error, ExternalProcessorServiceName reports NOT_SERVING over
+// grpc.health.v1.Health, so envoy and kubernetes gRPC probes can drain
+// traffic away from this processor without relying on TCP-level checks.
+// Returns an error if no health service has been registered (e.g. a
+// processor driving GenericExtProcServer directly, bypassing Serve/
+// ServeWithOptions/Attach).
+func (rc *RequestContext) RegisterHealthDependency(name string, check func() error) error {
+	if rc.extProcOptions == nil || rc.extProcOptions.healthServer == nil {
+		return errors.New("no gRPC health service is registered for this processor")
+	}
+	rc.extProcOptions.healthServer.RegisterDependency(name, check, 0)
+	return nil
+}
+
+// BodyChunkAction is the action a StreamParseHandler requests for a chunk
+// it was given, via HandleBodyStreamParse: see BodyChunkForward,
+// BodyChunkReplace, and BodyChunkHold.
+type BodyChunkAction int
+
+const (
+	BodyChunkForward BodyChunkAction = iota // forward the chunk unchanged
+	BodyChunkReplace                        // forward the handler's returned bytes instead of the chunk
+	BodyChunkHold                           // buffer the chunk (see HoldBodyChunk) instead of forwarding it now
+)
+
+// StreamParseHandler processes a request/response body one chunk at a time
+// as envoy delivers it, for uses like line-delimited transformation, SSE
+// re-tagging, or redaction on large bodies without ever buffering the whole
+// body. isFinal marks the last chunk of the body. The action return value
+// selects what HandleBodyStreamParse does with the (possibly nil) returned
+// bytes; see BodyChunkAction.
+type StreamParseHandler func(rc *RequestContext, chunk []byte, isFinal bool) (BodyChunkAction, []byte, error)
+
+// HandleBodyStreamParse drives handler for a single body chunk and stages
+// the resulting body mutation accordingly: BodyChunkForward/BodyChunkReplace
+// stage the chunk (or its replacement) immediately via
+// ReplaceStreamedBodyChunk, while BodyChunkHold accumulates it (see
+// HoldBodyChunk) and stages nothing until a later call yields it (see
+// YieldBodyChunk) or the final chunk forces a flush. Intended to be called
+// from a RequestProcessor's own ProcessRequestBody/ProcessResponseBody.
+func (rc *RequestContext) HandleBodyStreamParse(handler StreamParseHandler, chunk []byte) error {
+	rc.bodybuffer.Complete = rc.EndOfStream
+
+	action, replacement, err := handler(rc, chunk, rc.EndOfStream)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case BodyChunkForward:
+		return rc.ReplaceStreamedBodyChunk(chunk)
+	case BodyChunkReplace:
+		return rc.ReplaceStreamedBodyChunk(replacement)
+	case BodyChunkHold:
+		if err := rc.HoldBodyChunk(chunk); err != nil {
+			return err
+		}
+		if rc.EndOfStream {
+			return rc.YieldBodyChunk(nil)
+		}
+		return rc.ReplaceStreamedBodyChunk(nil)
+	default:
+		return fmt.Errorf("unknown BodyChunkAction: %d", action)
+	}
+}
+
+// HoldBodyChunk appends chunk to the context's internal hold buffer instead
+// of forwarding it immediately, bounded by
+// extProcOptions.StreamHoldWatermark (no limit if <= 0).
+func (rc *RequestContext) HoldBodyChunk(chunk []byte) error {
+	if rc.extProcOptions != nil && rc.extProcOptions.StreamHoldWatermark > 0 {
+		if int64(len(rc.held)+len(chunk)) > rc.extProcOptions.StreamHoldWatermark {
+			return errors.New("held body bytes would exceed StreamHoldWatermark")
+		}
+	}
+	rc.held = append(rc.held, chunk...)
+	return nil
+}
+
+// YieldBodyChunk flushes any bytes previously accumulated via
+// HoldBodyChunk, plus extra, as the next body mutation.
+func (rc *RequestContext) YieldBodyChunk(extra []byte) error {
+	out := append(rc.held, extra...)
+	rc.held = nil
+	return rc.ReplaceStreamedBodyChunk(out)
+}
+
 // @deprecate: migrate to clearer name "HasStoredValue"
 func (rc *RequestContext) HasValue(name string) bool {
 	_, exists := rc.data[name]
@@ -193,6 +417,18 @@ func (rc *RequestContext) ResetPhase() error {
 	rc.response.bodyMutation = nil
 	rc.response.continueRequest = nil
 	rc.response.immediateResponse = nil
+	rc.response.clearRouteCache = false
+	rc.mutationStack = nil
+	return nil
+}
+
+// ClearRouteCache marks the current phase's response to ask envoy to
+// recompute routing (e.g. after a handler rewrites `:authority` or `:path`
+// headers in a way that should pick a different route/cluster). It only
+// has an effect on a headers response, and is cleared by ResetPhase at the
+// start of the next phase.
+func (rc *RequestContext) ClearRouteCache() error {
+	rc.response.clearRouteCache = true
 	return nil
 }
 
@@ -207,8 +443,9 @@ func (rc *RequestContext) ContinueRequest() error {
 
 	rc.response.continueRequest = &extprocv3.CommonResponse{
 		// status? (ie response phase status)
-		HeaderMutation: rc.response.headerMutation,
-		BodyMutation:   rc.response.bodyMutation,
+		HeaderMutation:  rc.response.headerMutation,
+		BodyMutation:    rc.response.bodyMutation,
+		ClearRouteCache: rc.response.clearRouteCache,
 		// trailers?
 	}
 
@@ -447,6 +684,25 @@ func (rc *RequestContext) ReplaceBodyChunk(body []byte) error {
 	return nil
 }
 
+// Method to call to replace a single streamed response body chunk (see
+// ProcessingModeStreamed). Unlike ReplaceBodyChunk, an empty body is not a
+// no-op (it drops the chunk instead of forwarding it), and content-length
+// is left untouched since it is meaningless per chunk once a body is
+// streamed rather than buffered in full.
+func (rc *RequestContext) ReplaceStreamedBodyChunk(body []byte) error {
+	if body == nil {
+		body = []byte{}
+	}
+
+	rc.response.bodyMutation = &extprocv3.BodyMutation{
+		Mutation: &extprocv3.BodyMutation_Body{
+			Body: body,
+		},
+	}
+
+	return nil
+}
+
 // Method to call to clear an entire request/response body chunk
 func (rc *RequestContext) ClearBodyChunk() error {
 	rc.response.bodyMutation = &extprocv3.BodyMutation{
@@ -457,6 +713,140 @@ func (rc *RequestContext) ClearBodyChunk() error {
 	return nil
 }
 
+// mutationSnapshot captures a PhaseResponse as of a BeginMutations call, so
+// RollbackMutations can restore it. Header mutation slices are cloned since
+// further appends after Begin can grow the original slice's backing array
+// in place, which would otherwise corrupt the "before" snapshot.
+type mutationSnapshot struct {
+	name              string
+	setHeaders        []*corev3.HeaderValueOption
+	removeHeaders     []string
+	bodyMutation      *extprocv3.BodyMutation
+	continueRequest   *extprocv3.CommonResponse
+	immediateResponse *extprocv3.ImmediateResponse
+}
+
+// BeginMutations opens a named, speculative mutation scope: header/body
+// mutations staged via AppendHeader, ReplaceBodyChunk, CancelRequest, etc.
+// after this call can be discarded in bulk with RollbackMutations(name), or
+// finalized in place with CommitMutations(name). Scopes nest (e.g. auth,
+// then rate-limit, then transform); Commit/RollbackMutations must name the
+// innermost scope still open.
+func (rc *RequestContext) BeginMutations(name string) error {
+	var setHeaders []*corev3.HeaderValueOption
+	var removeHeaders []string
+	if hm := rc.response.headerMutation; hm != nil {
+		setHeaders = slices.Clone(hm.SetHeaders)
+		removeHeaders = slices.Clone(hm.RemoveHeaders)
+	}
+
+	rc.mutationStack = append(rc.mutationStack, mutationSnapshot{
+		name:              name,
+		setHeaders:        setHeaders,
+		removeHeaders:     removeHeaders,
+		bodyMutation:      rc.response.bodyMutation,
+		continueRequest:   rc.response.continueRequest,
+		immediateResponse: rc.response.immediateResponse,
+	})
+	return nil
+}
+
+// CommitMutations closes the named scope opened by BeginMutations, keeping
+// every mutation staged since then.
+func (rc *RequestContext) CommitMutations(name string) error {
+	return rc.popMutationScope(name)
+}
+
+// RollbackMutations closes the named scope opened by BeginMutations,
+// discarding every header/body mutation staged since then.
+func (rc *RequestContext) RollbackMutations(name string) error {
+	snap, err := rc.peekMutationScope(name)
+	if err != nil {
+		return err
+	}
+
+	if rc.response.headerMutation == nil {
+		rc.response.headerMutation = &extprocv3.HeaderMutation{}
+	}
+	rc.response.headerMutation.SetHeaders = snap.setHeaders
+	rc.response.headerMutation.RemoveHeaders = snap.removeHeaders
+	rc.response.bodyMutation = snap.bodyMutation
+	rc.response.continueRequest = snap.continueRequest
+	rc.response.immediateResponse = snap.immediateResponse
+
+	return rc.popMutationScope(name)
+}
+
+// peekMutationScope returns the innermost open mutation scope, erroring if
+// there is none or if it isn't named name.
+func (rc *RequestContext) peekMutationScope(name string) (mutationSnapshot, error) {
+	if len(rc.mutationStack) == 0 {
+		return mutationSnapshot{}, fmt.Errorf("no open mutation scope named %q", name)
+	}
+	top := rc.mutationStack[len(rc.mutationStack)-1]
+	if top.name != name {
+		return mutationSnapshot{}, fmt.Errorf("mutation scope %q is not the innermost open scope (innermost is %q)", name, top.name)
+	}
+	return top, nil
+}
+
+// popMutationScope removes the innermost open mutation scope, erroring (and
+// leaving the stack untouched) if it isn't named name.
+func (rc *RequestContext) popMutationScope(name string) error {
+	if _, err := rc.peekMutationScope(name); err != nil {
+		return err
+	}
+	rc.mutationStack = rc.mutationStack[:len(rc.mutationStack)-1]
+	return nil
+}
+
+// BodyMutationKind classifies the kind of body mutation staged in a
+// RequestContext's current phase response; see MutationSummary.
+type BodyMutationKind int
+
+const (
+	BodyMutationNone    BodyMutationKind = iota // no body mutation staged
+	BodyMutationReplace                         // ReplaceBodyChunk/ReplaceStreamedBodyChunk staged a replacement body
+	BodyMutationClear                           // ClearBodyChunk staged a cleared body
+)
+
+// MutationSummary describes the header/body mutations already staged into a
+// RequestContext's current phase response, so middleware composed from
+// several RequestProcessors (auth, then rate-limit, then transform, ...)
+// can inspect what earlier ones queued before deciding whether to add their
+// own. See RequestContext.MutationSummary.
+type MutationSummary struct {
+	SetHeaders    []string         // names staged via Add/Append/OverwriteHeader(s)
+	RemoveHeaders []string         // names staged via RemoveHeader(s)/RemoveHeadersVariadic
+	BodyMutation  BodyMutationKind // kind of body mutation staged, if any
+	Cancelled     bool             // true if CancelRequest has staged an ImmediateResponse
+}
+
+// MutationSummary reports the header/body mutations currently staged on rc,
+// across every open BeginMutations scope (mutations apply immediately; see
+// BeginMutations).
+func (rc *RequestContext) MutationSummary() MutationSummary {
+	summary := MutationSummary{Cancelled: rc.response.immediateResponse != nil}
+
+	if hm := rc.response.headerMutation; hm != nil {
+		for _, h := range hm.SetHeaders {
+			summary.SetHeaders = append(summary.SetHeaders, h.Header.GetKey())
+		}
+		summary.RemoveHeaders = append(summary.RemoveHeaders, hm.RemoveHeaders...)
+	}
+
+	if bm := rc.response.bodyMutation; bm != nil {
+		switch bm.Mutation.(type) {
+		case *extprocv3.BodyMutation_Body:
+			summary.BodyMutation = BodyMutationReplace
+		case *extprocv3.BodyMutation_ClearBody:
+			summary.BodyMutation = BodyMutationClear
+		}
+	}
+
+	return summary
+}
+
 // Return a body's declared content type, encoding, and transfer style.
 func (rc *RequestContext) GetBodyType() BodyType {
 	return rc.bodybuffer.Type
@@ -477,6 +867,34 @@ func (rc *RequestContext) CurrentBodyBytes() []byte {
 	return rc.bodybuffer.Value
 }
 
+// captureGRPCTrailers records a gRPC call's "grpc-status"/"grpc-message"
+// trailers onto rc, so an EventHandler can inspect the outcome of a
+// streamed gRPC call without re-parsing trailers itself. No-op for either
+// trailer that isn't present.
+func (rc *RequestContext) captureGRPCTrailers(trailers AllHeaders) {
+	if status, err := trailers.GetHeaderValueAsString("grpc-status"); err == nil {
+		rc.GRPCStatus = status
+	}
+	if message, err := trailers.GetHeaderValueAsString("grpc-message"); err == nil {
+		rc.GRPCStatusMessage = message
+	}
+}
+
+// FeedGRPCRequestMessages feeds chunk through this context's request-side
+// GRPCFramer and returns any complete messages it now has available. Only
+// meaningful when GetBodyType().ContentType is gRPC-framed (see
+// IsGRPCContentType); intended for a PreProcessor/ObserveProcessor's
+// ProcessRequestBody to drive per-message calls to ProcessGRPCMessage.
+func (rc *RequestContext) FeedGRPCRequestMessages(chunk []byte) []GRPCMessage {
+	return grpcFramerFor(rc, false).Feed(chunk)
+}
+
+// FeedGRPCResponseMessages is FeedGRPCRequestMessages's response-body
+// counterpart.
+func (rc *RequestContext) FeedGRPCResponseMessages(chunk []byte) []GRPCMessage {
+	return grpcFramerFor(rc, true).Feed(chunk)
+}
+
 // Internal method to recover request's response status from envoy `:status` header.
 func (rc *RequestContext) parseStatusFromResponseHeaders(headers AllHeaders) error {
 
@@ -1,10 +1,14 @@
 package extproc
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestContext(t *testing.T) {
@@ -121,6 +125,259 @@ func TestContext(t *testing.T) {
 		assert.Equal(t, int32(403), int32(ctx.response.immediateResponse.Status.Code))
 	})
 
+	t.Run("ClearRouteCache surfaces on the common response", func(t *testing.T) {
+		ctx := &RequestContext{
+			AllHeaders: headers,
+		}
+		ctx.ResetPhase()
+
+		err := ctx.ClearRouteCache()
+		require.NoError(t, err)
+
+		err = ctx.ContinueRequest()
+		require.NoError(t, err)
+		assert.True(t, ctx.response.continueRequest.ClearRouteCache)
+
+		// ResetPhase (the next phase starting) clears it again
+		ctx.ResetPhase()
+		err = ctx.ContinueRequest()
+		require.NoError(t, err)
+		assert.False(t, ctx.response.continueRequest.ClearRouteCache)
+	})
+
+	t.Run("mutation staging and rollback", func(t *testing.T) {
+		newCtx := func() *RequestContext {
+			ctx := &RequestContext{AllHeaders: headers}
+			ctx.ResetPhase()
+			return ctx
+		}
+
+		t.Run("commit keeps staged mutations", func(t *testing.T) {
+			ctx := newCtx()
+			require.NoError(t, ctx.AddHeader("x-auth", HeaderValue{Value: "ok"}))
+
+			require.NoError(t, ctx.BeginMutations("transform"))
+			require.NoError(t, ctx.AddHeader("x-transform", HeaderValue{Value: "1"}))
+			require.NoError(t, ctx.CommitMutations("transform"))
+
+			summary := ctx.MutationSummary()
+			assert.ElementsMatch(t, []string{"x-auth", "x-transform"}, summary.SetHeaders)
+		})
+
+		t.Run("rollback discards only the staged scope's mutations", func(t *testing.T) {
+			ctx := newCtx()
+			require.NoError(t, ctx.AddHeader("x-auth", HeaderValue{Value: "ok"}))
+
+			require.NoError(t, ctx.BeginMutations("rate-limit"))
+			require.NoError(t, ctx.AddHeader("x-rate-limit", HeaderValue{Value: "1"}))
+			require.NoError(t, ctx.RollbackMutations("rate-limit"))
+
+			summary := ctx.MutationSummary()
+			assert.Equal(t, []string{"x-auth"}, summary.SetHeaders)
+		})
+
+		t.Run("nested scopes roll back independently", func(t *testing.T) {
+			ctx := newCtx()
+
+			require.NoError(t, ctx.BeginMutations("outer"))
+			require.NoError(t, ctx.AddHeader("x-outer", HeaderValue{Value: "1"}))
+
+			require.NoError(t, ctx.BeginMutations("inner"))
+			require.NoError(t, ctx.AddHeader("x-inner", HeaderValue{Value: "1"}))
+			require.NoError(t, ctx.RollbackMutations("inner"))
+
+			require.NoError(t, ctx.CommitMutations("outer"))
+
+			summary := ctx.MutationSummary()
+			assert.Equal(t, []string{"x-outer"}, summary.SetHeaders)
+		})
+
+		t.Run("rollback restores a cancelled request", func(t *testing.T) {
+			ctx := newCtx()
+			require.NoError(t, ctx.BeginMutations("auth"))
+			require.NoError(t, ctx.CancelRequest(403, nil, nil))
+			assert.True(t, ctx.MutationSummary().Cancelled)
+
+			require.NoError(t, ctx.RollbackMutations("auth"))
+			assert.False(t, ctx.MutationSummary().Cancelled)
+		})
+
+		t.Run("errors naming a scope that isn't innermost", func(t *testing.T) {
+			ctx := newCtx()
+			require.NoError(t, ctx.BeginMutations("outer"))
+			require.NoError(t, ctx.BeginMutations("inner"))
+
+			assert.Error(t, ctx.CommitMutations("outer"))
+			assert.Error(t, ctx.RollbackMutations("nonexistent"))
+		})
+	})
+
+	t.Run("health dependency registration", func(t *testing.T) {
+		t.Run("errors without a registered health service", func(t *testing.T) {
+			ctx := &RequestContext{extProcOptions: &ProcessingOptions{}}
+			err := ctx.RegisterHealthDependency("fake-upstream", func() error { return nil })
+			assert.Error(t, err)
+		})
+
+		t.Run("forwards to the shared HealthServer", func(t *testing.T) {
+			health := NewHealthServer()
+			opts := &ProcessingOptions{healthServer: health}
+			ctx := &RequestContext{extProcOptions: opts}
+
+			err := ctx.RegisterHealthDependency("fake-upstream", func() error { return errors.New("down") })
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				resp, _ := health.Check(context.Background(), &pb.HealthCheckRequest{Service: ExternalProcessorServiceName})
+				return resp.Status == pb.HealthCheckResponse_NOT_SERVING
+			}, time.Second, 10*time.Millisecond)
+		})
+	})
+
+	t.Run("stream parse handling", func(t *testing.T) {
+		newCtx := func() *RequestContext {
+			ctx := &RequestContext{
+				AllHeaders:     headers,
+				bodybuffer:     &EncodedBody{Value: make([]byte, 0)},
+				extProcOptions: &ProcessingOptions{},
+			}
+			ctx.ResetPhase()
+			return ctx
+		}
+
+		t.Run("forward", func(t *testing.T) {
+			ctx := newCtx()
+			err := ctx.HandleBodyStreamParse(func(rc *RequestContext, chunk []byte, isFinal bool) (BodyChunkAction, []byte, error) {
+				return BodyChunkForward, nil, nil
+			}, []byte("chunk-1"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("chunk-1"), ctx.response.bodyMutation.GetBody())
+			assert.False(t, ctx.HasCompleteBody())
+		})
+
+		t.Run("replace", func(t *testing.T) {
+			ctx := newCtx()
+			err := ctx.HandleBodyStreamParse(func(rc *RequestContext, chunk []byte, isFinal bool) (BodyChunkAction, []byte, error) {
+				return BodyChunkReplace, []byte("rewritten"), nil
+			}, []byte("chunk-1"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("rewritten"), ctx.response.bodyMutation.GetBody())
+		})
+
+		t.Run("hold then yield on final chunk", func(t *testing.T) {
+			ctx := newCtx()
+
+			err := ctx.HandleBodyStreamParse(func(rc *RequestContext, chunk []byte, isFinal bool) (BodyChunkAction, []byte, error) {
+				return BodyChunkHold, nil, nil
+			}, []byte("part-1"))
+			require.NoError(t, err)
+			assert.Empty(t, ctx.response.bodyMutation.GetBody())
+			assert.False(t, ctx.HasCompleteBody())
+
+			ctx.ResetPhase()
+			ctx.EndOfStream = true
+			err = ctx.HandleBodyStreamParse(func(rc *RequestContext, chunk []byte, isFinal bool) (BodyChunkAction, []byte, error) {
+				return BodyChunkHold, nil, nil
+			}, []byte("part-2"))
+			require.NoError(t, err)
+			assert.Equal(t, []byte("part-1part-2"), ctx.response.bodyMutation.GetBody())
+			assert.True(t, ctx.HasCompleteBody())
+		})
+
+		t.Run("hold respects StreamHoldWatermark", func(t *testing.T) {
+			ctx := newCtx()
+			ctx.extProcOptions.StreamHoldWatermark = 4
+			err := ctx.HoldBodyChunk([]byte("too long"))
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("body overflow handling", func(t *testing.T) {
+		newCtx := func(opts *ProcessingOptions) *RequestContext {
+			ctx := &RequestContext{
+				AllHeaders:     headers,
+				bodybuffer:     &EncodedBody{Value: make([]byte, 0)},
+				extProcOptions: opts,
+			}
+			ctx.ResetPhase()
+			return ctx
+		}
+		handler := func(rc *RequestContext, body []byte) error {
+			return rc.ContinueRequest()
+		}
+
+		t.Run("truncate stops appending but still calls handler", func(t *testing.T) {
+			ctx := newCtx(&ProcessingOptions{
+				BufferStreamedBodies:        true,
+				MaxBufferedRequestBodyBytes: 4,
+				OnBodyOverflow:              OnBodyOverflowTruncate,
+			})
+
+			require.NoError(t, ctx.handleBodyChunk(handler, ctx.extProcOptions, []byte("toolong"), false))
+			assert.True(t, ctx.BodyTruncated())
+			assert.Equal(t, int64(0), ctx.BytesBuffered())
+		})
+
+		t.Run("passThrough drops the buffer and forwards subsequent chunks raw", func(t *testing.T) {
+			ctx := newCtx(&ProcessingOptions{
+				BufferStreamedBodies:        true,
+				MaxBufferedRequestBodyBytes: 4,
+				OnBodyOverflow:              OnBodyOverflowPassThrough,
+			})
+
+			var forwarded []byte
+			capture := func(rc *RequestContext, body []byte) error {
+				forwarded = body
+				return rc.ContinueRequest()
+			}
+
+			require.NoError(t, ctx.handleBodyChunk(capture, ctx.extProcOptions, []byte("toolong"), false))
+			assert.Equal(t, []byte("toolong"), forwarded)
+
+			require.NoError(t, ctx.handleBodyChunk(capture, ctx.extProcOptions, []byte("more"), false))
+			assert.Equal(t, []byte("more"), forwarded)
+			assert.Equal(t, int64(0), ctx.BytesBuffered())
+		})
+
+		t.Run("immediateResponse cancels with the configured status", func(t *testing.T) {
+			ctx := newCtx(&ProcessingOptions{
+				BufferStreamedBodies:         true,
+				MaxBufferedResponseBodyBytes: 4,
+				OnBodyOverflow:               OnBodyOverflowImmediateResponse,
+				OverflowResponseStatus:       413,
+			})
+
+			require.NoError(t, ctx.handleBodyChunk(handler, ctx.extProcOptions, []byte("toolong"), true))
+			require.NotNil(t, ctx.response.immediateResponse)
+			assert.Equal(t, int32(413), int32(ctx.response.immediateResponse.Status.Code))
+		})
+
+		t.Run("error policy fails the phase", func(t *testing.T) {
+			ctx := newCtx(&ProcessingOptions{
+				BufferStreamedBodies:        true,
+				MaxBufferedRequestBodyBytes: 4,
+				OnBodyOverflow:              OnBodyOverflowError,
+			})
+
+			err := ctx.handleBodyChunk(handler, ctx.extProcOptions, []byte("toolong"), false)
+			assert.Error(t, err)
+		})
+
+		t.Run("global limit is enforced across contexts sharing options", func(t *testing.T) {
+			sharedOpts := &ProcessingOptions{
+				BufferStreamedBodies:           true,
+				MaxConcurrentBufferedBodyBytes: 6,
+				OnBodyOverflow:                 OnBodyOverflowError,
+			}
+			first := newCtx(sharedOpts)
+			second := newCtx(sharedOpts)
+
+			require.NoError(t, first.handleBodyChunk(handler, sharedOpts, []byte("abcd"), false))
+			err := second.handleBodyChunk(handler, sharedOpts, []byte("efgh"), false)
+			assert.Error(t, err)
+		})
+	})
+
 	t.Run("response phase handling", func(t *testing.T) {
 		ctx := &RequestContext{
 			AllHeaders: headers,
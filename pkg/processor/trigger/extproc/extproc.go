@@ -9,6 +9,7 @@ import (
 	"errors"
 	"io"
 	"log"
+	"math/rand"
 	"strconv"
 	"time"
 
@@ -25,8 +26,10 @@ const (
 // Primary interface for supported request processing that SDK users must
 // implement, passing a complying type to `GenericExtProcServer` or `Serve`.
 //
-// TODO: Passing through health check calls would help support better reasoning
-// about dependencies for external processing (e.g., DB or kafka availability)
+// A RequestProcessor that also wants its dependencies (e.g. DB or Kafka
+// availability) reflected in gRPC health checking can additionally implement
+// HealthReporter; ServeWithOptions and Attach detect it with a type
+// assertion and register it automatically.
 type RequestProcessor interface {
 	GetName() string
 	GetOptions() *ProcessingOptions
@@ -50,6 +53,33 @@ type EventHandler interface {
 	HandleEvent(ctx *RequestContext, body []byte) (*EventResponse, error)
 }
 
+// StreamingHandler is an addition to EventHandler for handlers willing to
+// process a response body one chunk at a time (see BodyProcessingMode)
+// instead of waiting for it to buffer in full. endOfStream marks the final
+// chunk of the body. The returned bytes replace chunk before it's forwarded
+// to envoy; a nil return drops the chunk (forwards nothing for it). A
+// handler assigned to AbstractProcessor.Handler must still implement
+// EventHandler to be accepted there; StreamingHandler is detected with a
+// type assertion on top of it.
+type StreamingHandler interface {
+	OnResponseChunk(ctx *RequestContext, chunk []byte, endOfStream bool) ([]byte, error)
+}
+
+// GRPCMessageHandler is an addition to EventHandler for handlers wanting to
+// observe or transform individual gRPC messages framed within a
+// gRPC-content-typed request/response body (see GRPCFramer/
+// IsGRPCContentType), instead of only the whole, possibly multi-message
+// bytes HandleEvent receives. A handler assigned to
+// AbstractProcessor.Handler must still implement EventHandler to be
+// accepted there; GRPCMessageHandler is detected with a type assertion on
+// top of it, the same way StreamingHandler augments EventHandler for
+// response chunk streaming. PreProcessor and ObserveProcessor call this
+// once per complete message; the whole-body ProcessRequestBody/
+// ProcessResponseBody hooks, and HandleEvent itself, still run as usual.
+type GRPCMessageHandler interface {
+	ProcessGRPCMessage(ctx *RequestContext, msg GRPCMessage) error
+}
+
 type AbstractProcessor struct {
 	opts *ProcessingOptions
 
@@ -71,15 +101,20 @@ func (s *AbstractProcessor) GetOptions() *ProcessingOptions {
 }
 
 func (s *AbstractProcessor) ProcessResponseTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	ctx.captureGRPCTrailers(trailers)
 	return ctx.ContinueRequest()
 }
 func (s *AbstractProcessor) ProcessRequestTrailers(ctx *RequestContext, trailers AllHeaders) error {
+	ctx.captureGRPCTrailers(trailers)
 	return ctx.ContinueRequest()
 }
+
 func (s *AbstractProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	advertiseAcceptEncoding(ctx, s.opts, &headers)
 	return ctx.ContinueRequest()
 }
 func (s *AbstractProcessor) ProcessResponseHeaders(ctx *RequestContext, headers AllHeaders) error {
+	advertiseAcceptEncoding(ctx, s.opts, &headers)
 	return ctx.ContinueRequest()
 }
 func (s *AbstractProcessor) ProcessResponseBody(ctx *RequestContext, body []byte) error {
@@ -98,6 +133,43 @@ type GenericExtProcServer struct {
 	name      string
 	processor RequestProcessor
 	options   *ProcessingOptions
+	metrics   *streamMetrics // lazily initialized, see Process
+}
+
+// HealthServer returns the gRPC health service registered alongside this
+// ExternalProcessor by ServeWithOptions/Attach, or nil if GetOptions()
+// returns a *ProcessingOptions that was never passed through one of those
+// (e.g. GenericExtProcServer constructed and used directly).
+func (s *GenericExtProcServer) HealthServer() *HealthServer {
+	if s.options == nil {
+		return nil
+	}
+	return s.options.healthServer
+}
+
+// recvResult carries the outcome of one srv.Recv() call back to Process's
+// select loop, so a pending Recv() can race against stream-lifetime/context
+// cancellation without blocking them.
+type recvResult struct {
+	req *extprocv3.ProcessingRequest
+	err error
+}
+
+// streamLifetimeDeadline returns the jittered point in time at which a
+// stream started at streamStart should be proactively ended, or the zero
+// Time if MaxStreamLifetime is disabled. Jitter (from Backoff, if any)
+// spreads reconnects out so replicas don't all cycle connections at once.
+func (s *GenericExtProcServer) streamLifetimeDeadline(streamStart time.Time) time.Time {
+	if s.options.MaxStreamLifetime <= 0 {
+		return time.Time{}
+	}
+
+	lifetime := s.options.MaxStreamLifetime
+	if s.options.Backoff != nil && s.options.Backoff.Jitter > 0 {
+		jitter := s.options.Backoff.Jitter
+		lifetime = time.Duration(float64(lifetime) * (1 - rand.Float64()*jitter))
+	}
+	return streamStart.Add(lifetime)
 }
 
 // Implementation of the bidi stream `Process` in an external processor. Given the
@@ -113,59 +185,127 @@ func (s *GenericExtProcServer) Process(srv extprocv3.ExternalProcessor_ProcessSe
 		s.options = NewDefaultOptions()
 	}
 
+	if s.metrics == nil {
+		s.metrics = newStreamMetrics()
+	}
+
 	if s.options.LogStream {
 		log.Printf("Starting request stream in \"%s\"", s.name)
 	}
 
+	if s.options.Observer != nil {
+		s.options.Observer.StreamStarted(s.name)
+		defer s.options.Observer.StreamEnded(s.name)
+	}
+
 	rc := &RequestContext{
 		extProcOptions: s.options,
 	}
 	ctx := srv.Context()
 
+	streamStart := time.Now()
+	deadline := s.streamLifetimeDeadline(streamStart)
+	var lifetimeTimer *time.Timer
+	if !deadline.IsZero() {
+		lifetimeTimer = time.NewTimer(time.Until(deadline))
+		defer lifetimeTimer.Stop()
+	}
+
+	consecutiveFailures := 0
+
+	recvCh := make(chan recvResult, 1)
+	recv := func() {
+		req, err := srv.Recv()
+		recvCh <- recvResult{req, err}
+	}
+	go recv()
+
 	for {
+		var lifetimeCh <-chan time.Time
+		if lifetimeTimer != nil {
+			lifetimeCh = lifetimeTimer.C
+		}
+
 		select {
 		case <-ctx.Done():
 			if s.options.LogStream {
 				log.Printf("Request stream terminated in \"%s\"", s.name)
 			}
+			rc.releaseBufferedBytes()
 			return ctx.Err()
 
-		default:
-		}
-
-		req, err := srv.Recv()
-		if err == io.EOF {
+		case <-lifetimeCh:
 			if s.options.LogStream {
-				log.Printf("Request stream terminated in \"%s\"", s.name)
+				log.Printf("Proactively ending long-lived stream in \"%s\" (MaxStreamLifetime reached)", s.name)
 			}
+			s.metrics.recordReconnect()
+			rc.releaseBufferedBytes()
 			return nil
-		}
-		if err != nil {
-			return status.Errorf(codes.Unknown, "cannot receive stream request: %v", err)
-		}
 
-		// clear response in the context if defined, this is not
-		// carried across request phases because each one has an
-		// idiosyncratic response. rc gets "initialized" during
-		// RequestHeaders phase processing.
-		_ = rc.ResetPhase()
-
-		resp, err := s.processPhase(req, s.processor, rc)
-		if err != nil {
-			log.Printf("Phase processing error %v\n", err)
-		} else if resp == nil {
-			log.Printf("Phase processing did not define a response\n")
-		} else {
-			if s.options.LogPhases {
-				log.Printf("Sending ProcessingResponse: %v \n", resp)
+		case res := <-recvCh:
+			if res.err == io.EOF {
+				if s.options.LogStream {
+					log.Printf("Request stream terminated in \"%s\"", s.name)
+				}
+				rc.releaseBufferedBytes()
+				return nil
 			}
-			if err := srv.Send(resp); err != nil {
-				log.Printf("Send error %v", err)
+			if res.err != nil {
+				return status.Errorf(codes.Unknown, "cannot receive stream request: %v", res.err)
 			}
-		}
+
+			// clear response in the context if defined, this is not
+			// carried across request phases because each one has an
+			// idiosyncratic response. rc gets "initialized" during
+			// RequestHeaders phase processing.
+			_ = rc.ResetPhase()
+
+			resp, err := s.processPhase(res.req, s.processor, rc)
+			if err != nil {
+				consecutiveFailures++
+				delay := s.options.Backoff.delay(consecutiveFailures - 1)
+				s.metrics.setBackoffCurrent(delay)
+				log.Printf("Phase processing error %v\n", err)
+				if delay > 0 {
+					time.Sleep(delay)
+				}
+			} else {
+				if isFullCycleComplete(res.req, rc) {
+					consecutiveFailures = 0
+					s.metrics.setBackoffCurrent(0)
+				}
+				if resp == nil {
+					log.Printf("Phase processing did not define a response\n")
+				} else {
+					if s.options.LogPhases {
+						log.Printf("Sending ProcessingResponse: %v \n", resp)
+					}
+					if err := srv.Send(resp); err != nil {
+						log.Printf("Send error %v", err)
+					}
+				}
+			}
+
+			go recv()
+		} // end select
 	} // end for over stream messages
 }
 
+// isFullCycleComplete reports whether req was the last message of a full
+// request/response cycle (a response body's final chunk, or response
+// trailers), the point at which GenericExtProcServer.Process resets its
+// consecutive-failure count for backoff purposes.
+func isFullCycleComplete(req *extprocv3.ProcessingRequest, rc *RequestContext) bool {
+	switch req.Request.(type) {
+	case *extprocv3.ProcessingRequest_ResponseBody:
+		return rc.EndOfStream
+	case *extprocv3.ProcessingRequest_ResponseTrailers:
+		return true
+	default:
+		return false
+	}
+}
+
 // Internal per-phase processing logic, with a defined `RequestContext` and `RequestProcessor`
 func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest, processor RequestProcessor, rc *RequestContext) (*extprocv3.ProcessingResponse, error) {
 	if rc == nil {
@@ -178,6 +318,7 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 	)
 
 	phase := REQUEST_PHASE_UNDETERMINED
+	durationBefore := rc.Duration
 
 	switch req := procReq.Request.(type) {
 	case *extprocv3.ProcessingRequest_RequestHeaders:
@@ -195,6 +336,7 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 		rc.EndOfStream = h.EndOfStream
 
 		// set content-type, content-encoding, and/or transfer-encoding as available
+		rc.releaseBufferedBytes()
 		rc.bodybuffer = NewEncodedBodyFromHeaders(rc.AllHeaders)
 
 		ps = time.Now()
@@ -211,7 +353,7 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 		rc.AllHeaders.Headers[ProcessingPhaseHeader] = strconv.Itoa(phase)
 
 		ps = time.Now()
-		err = rc.handleBodyChunk(processor.ProcessRequestBody, s.options, b.Body)
+		err = rc.handleBodyChunk(processor.ProcessRequestBody, s.options, b.Body, false)
 		rc.Duration += time.Since(ps)
 
 	case *extprocv3.ProcessingRequest_RequestTrailers:
@@ -250,6 +392,7 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 		rc.AllHeaders = &headers
 
 		// set content-type, content-encoding, and/or transfer-encoding as available
+		rc.releaseBufferedBytes()
 		rc.bodybuffer = NewEncodedBodyFromHeaders(&headers)
 
 		ps = time.Now()
@@ -273,7 +416,7 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 		rc.AllHeaders.Headers[ProcessingPhaseHeader] = strconv.Itoa(phase)
 
 		ps = time.Now()
-		err = rc.handleBodyChunk(processor.ProcessResponseBody, s.options, b.Body)
+		err = rc.handleBodyChunk(processor.ProcessResponseBody, s.options, b.Body, true)
 		rc.Duration += time.Since(ps)
 
 		if rc.EndOfStream && s.options.UpdateDurationHeader {
@@ -299,6 +442,9 @@ func (s *GenericExtProcServer) processPhase(procReq *extprocv3.ProcessingRequest
 		}
 		err = errors.New("unknown request type")
 	}
+
+	observePhase(s.options, s.name, phase, rc.Duration-durationBefore, err)
+
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,149 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extproc
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// grpcFrameHeaderSize is the size, in bytes, of a gRPC length-prefixed
+// message frame's header: a 1-byte compressed flag followed by a 4-byte
+// big-endian message length.
+const grpcFrameHeaderSize = 5
+
+// GRPCMessage is one length-prefixed message from a gRPC-framed request or
+// response body. See
+// https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md#length-prefixed-message.
+type GRPCMessage struct {
+	Compressed bool   // the frame's compressed flag; true if Payload was compressed per grpc-encoding
+	Payload    []byte // the message bytes, still compressed if Compressed is set
+}
+
+// IsGRPCContentType reports whether contentType (as found on
+// BodyType.ContentType) identifies a gRPC-framed body, e.g.
+// "application/grpc" or "application/grpc+proto".
+func IsGRPCContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/grpc")
+}
+
+// GRPCFramer incrementally parses gRPC's length-prefixed message framing out
+// of a byte stream that may arrive in arbitrary chunk boundaries: a single
+// call to Feed may see several whole messages at once, or only part of one,
+// with the remainder completed by a later call. It does not itself decide
+// when the underlying body is complete, the way EncodedBody.Complete does.
+//
+// A GRPCFramer is not safe for concurrent use and carries state across
+// calls, so one must be kept per request/response body being parsed; see
+// RequestContext.FeedGRPCRequestMessages/FeedGRPCResponseMessages, which
+// keep one for each direction of a context's current request.
+type GRPCFramer struct {
+	pending []byte
+}
+
+// Feed appends chunk to any bytes held back from a previous call and
+// extracts every complete message now available, in order. Bytes belonging
+// to a frame that hasn't fully arrived yet are retained internally, to be
+// completed by a future Feed call, rather than returned.
+func (f *GRPCFramer) Feed(chunk []byte) []GRPCMessage {
+	if len(chunk) > 0 {
+		f.pending = append(f.pending, chunk...)
+	}
+
+	var messages []GRPCMessage
+	for {
+		if len(f.pending) < grpcFrameHeaderSize {
+			return messages
+		}
+
+		length := binary.BigEndian.Uint32(f.pending[1:grpcFrameHeaderSize])
+		frameSize := grpcFrameHeaderSize + int(length)
+		if len(f.pending) < frameSize {
+			return messages
+		}
+
+		payload := make([]byte, length)
+		copy(payload, f.pending[grpcFrameHeaderSize:frameSize])
+		messages = append(messages, GRPCMessage{
+			Compressed: f.pending[0] != 0,
+			Payload:    payload,
+		})
+		f.pending = f.pending[frameSize:]
+	}
+}
+
+// Pending returns the number of bytes currently held back awaiting the rest
+// of an incomplete frame.
+func (f *GRPCFramer) Pending() int {
+	return len(f.pending)
+}
+
+// EncodeGRPCMessage re-serializes msg into gRPC's length-prefixed wire
+// format, the inverse of what GRPCFramer.Feed parses out.
+func EncodeGRPCMessage(msg GRPCMessage) []byte {
+	out := make([]byte, grpcFrameHeaderSize+len(msg.Payload))
+	if msg.Compressed {
+		out[0] = 1
+	}
+	binary.BigEndian.PutUint32(out[1:grpcFrameHeaderSize], uint32(len(msg.Payload)))
+	copy(out[grpcFrameHeaderSize:], msg.Payload)
+	return out
+}
+
+// EncodeGRPCMessages re-serializes messages back-to-back, e.g. to rebuild a
+// body for RequestContext.ReplaceBodyChunk after a ProcessGRPCMessage hook
+// mutated one or more payloads.
+func EncodeGRPCMessages(messages []GRPCMessage) []byte {
+	var out []byte
+	for _, msg := range messages {
+		out = append(out, EncodeGRPCMessage(msg)...)
+	}
+	return out
+}
+
+// grpcMessagesFromBodyCall extracts whatever complete gRPC messages are now
+// available from a ProcessRequestBody/ProcessResponseBody call, accounting
+// for ProcessingOptions.BufferStreamedBodies: when bodies are streamed
+// per-chunk, body is a delta, so it's fed through ctx's persistent,
+// per-direction GRPCFramer (see FeedGRPCRequestMessages/
+// FeedGRPCResponseMessages) and partial frames carry over to the next
+// chunk. When bodies are buffered whole, body is instead the cumulative
+// buffer handed on every call as it grows, so messages are only extracted
+// once, with a throwaway framer, once the body is complete - otherwise
+// messages that completed early would be re-dispatched on every later call.
+func grpcMessagesFromBodyCall(ctx *RequestContext, body []byte, isResponse bool) []GRPCMessage {
+	if ctx.extProcOptions != nil && ctx.extProcOptions.BufferStreamedBodies {
+		if !ctx.HasCompleteBody() {
+			return nil
+		}
+		return (&GRPCFramer{}).Feed(body)
+	}
+	if isResponse {
+		return ctx.FeedGRPCResponseMessages(body)
+	}
+	return ctx.FeedGRPCRequestMessages(body)
+}
+
+// grpcFramerFor returns the GRPCFramer parsing the request or response body
+// (isResponse selects which) of ctx, creating one on first use so
+// subsequent chunks of the same request/response resume from where the last
+// one left off. Request and response bodies get independent framers, since
+// in a bidirectional streaming gRPC call both can be mid-flight at once;
+// ctx.StreamScratch is left alone for this, as it's documented to be
+// reserved for StreamingHandler/EventHandler use, not internal SDK state.
+func grpcFramerFor(ctx *RequestContext, isResponse bool) *GRPCFramer {
+	if isResponse {
+		if ctx.responseGRPCFramer == nil {
+			ctx.responseGRPCFramer = &GRPCFramer{}
+		}
+		return ctx.responseGRPCFramer
+	}
+	if ctx.requestGRPCFramer == nil {
+		ctx.requestGRPCFramer = &GRPCFramer{}
+	}
+	return ctx.requestGRPCFramer
+}
@@ -0,0 +1,124 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGRPCContentType(t *testing.T) {
+	assert.True(t, IsGRPCContentType("application/grpc"))
+	assert.True(t, IsGRPCContentType("application/grpc+proto"))
+	assert.False(t, IsGRPCContentType("application/json"))
+	assert.False(t, IsGRPCContentType(""))
+}
+
+func TestGRPCFramer_Feed(t *testing.T) {
+	t.Run("single message in one chunk", func(t *testing.T) {
+		framer := &GRPCFramer{}
+		frame := EncodeGRPCMessage(GRPCMessage{Payload: []byte("hello")})
+
+		messages := framer.Feed(frame)
+		require.Len(t, messages, 1)
+		assert.False(t, messages[0].Compressed)
+		assert.Equal(t, []byte("hello"), messages[0].Payload)
+		assert.Equal(t, 0, framer.Pending())
+	})
+
+	t.Run("compressed flag is preserved", func(t *testing.T) {
+		framer := &GRPCFramer{}
+		frame := EncodeGRPCMessage(GRPCMessage{Compressed: true, Payload: []byte("squeezed")})
+
+		messages := framer.Feed(frame)
+		require.Len(t, messages, 1)
+		assert.True(t, messages[0].Compressed)
+	})
+
+	t.Run("multiple messages in one chunk", func(t *testing.T) {
+		framer := &GRPCFramer{}
+		chunk := append(
+			EncodeGRPCMessage(GRPCMessage{Payload: []byte("first")}),
+			EncodeGRPCMessage(GRPCMessage{Payload: []byte("second")})...,
+		)
+
+		messages := framer.Feed(chunk)
+		require.Len(t, messages, 2)
+		assert.Equal(t, []byte("first"), messages[0].Payload)
+		assert.Equal(t, []byte("second"), messages[1].Payload)
+	})
+
+	t.Run("message split across chunk boundary", func(t *testing.T) {
+		framer := &GRPCFramer{}
+		frame := EncodeGRPCMessage(GRPCMessage{Payload: []byte("split message")})
+
+		messages := framer.Feed(frame[:3])
+		assert.Empty(t, messages)
+		assert.Equal(t, 3, framer.Pending())
+
+		messages = framer.Feed(frame[3:])
+		require.Len(t, messages, 1)
+		assert.Equal(t, []byte("split message"), messages[0].Payload)
+		assert.Equal(t, 0, framer.Pending())
+	})
+
+	t.Run("empty feed is a no-op", func(t *testing.T) {
+		framer := &GRPCFramer{}
+		assert.Empty(t, framer.Feed(nil))
+		assert.Equal(t, 0, framer.Pending())
+	})
+}
+
+func TestEncodeGRPCMessages(t *testing.T) {
+	messages := []GRPCMessage{
+		{Payload: []byte("one")},
+		{Compressed: true, Payload: []byte("two")},
+	}
+
+	encoded := EncodeGRPCMessages(messages)
+
+	framer := &GRPCFramer{}
+	decoded := framer.Feed(encoded)
+	require.Len(t, decoded, 2)
+	assert.Equal(t, messages, decoded)
+}
+
+func TestRequestContext_FeedGRPCMessages(t *testing.T) {
+	ctx := &RequestContext{}
+
+	frame := EncodeGRPCMessage(GRPCMessage{Payload: []byte("request")})
+	requestMessages := ctx.FeedGRPCRequestMessages(frame)
+	require.Len(t, requestMessages, 1)
+	assert.Equal(t, []byte("request"), requestMessages[0].Payload)
+
+	frame = EncodeGRPCMessage(GRPCMessage{Payload: []byte("response")})
+	responseMessages := ctx.FeedGRPCResponseMessages(frame)
+	require.Len(t, responseMessages, 1)
+	assert.Equal(t, []byte("response"), responseMessages[0].Payload)
+
+	// request/response directions don't share framing state.
+	assert.Equal(t, 0, ctx.requestGRPCFramer.Pending())
+	assert.Equal(t, 0, ctx.responseGRPCFramer.Pending())
+}
+
+func TestRequestContext_CaptureGRPCTrailers(t *testing.T) {
+	ctx := &RequestContext{}
+
+	ctx.captureGRPCTrailers(AllHeaders{
+		Headers: map[string]string{
+			"grpc-status":  "0",
+			"grpc-message": "",
+		},
+	})
+	assert.Equal(t, "0", ctx.GRPCStatus)
+	assert.Equal(t, "", ctx.GRPCStatusMessage)
+
+	ctx.captureGRPCTrailers(AllHeaders{
+		Headers: map[string]string{
+			"grpc-status":  "2",
+			"grpc-message": "unknown error",
+		},
+	})
+	assert.Equal(t, "2", ctx.GRPCStatus)
+	assert.Equal(t, "unknown error", ctx.GRPCStatusMessage)
+}
@@ -7,6 +7,7 @@ import (
 	"unicode/utf8"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 )
 
 type HeaderValue struct {
@@ -19,21 +20,46 @@ type HeaderValue struct {
 type AllHeaders struct {
 	Headers    map[string]string
 	RawHeaders map[string][]byte
+
+	// HeaderValues and RawHeaderValues hold every value recorded against a
+	// header name, in the order they were received/added, for headers that
+	// are legitimately repeated (Set-Cookie, Via, Forwarded, Cache-Control,
+	// Envoy's own x-envoy-* lists, ...). Headers/RawHeaders above still hold
+	// one collapsed value per name - the most recently recorded one - for
+	// callers that only ever cared about a single value; these are the
+	// order-preserving, no-value-dropped view. Nil unless NewAllHeadersFromEnvoyHeaderMap,
+	// AddHeader, or AddRawHeader populated them, so an AllHeaders built by
+	// hand (as most of this package's tests do) that never touches
+	// multi-value headers behaves exactly as it did before these fields
+	// existed.
+	HeaderValues    map[string][]string
+	RawHeaderValues map[string][][]byte
 }
 
 // The type required of a method to filter headers in-place
 type HeaderNameFilter func(string) bool
 
-// Create an `AllHeaders` struct from envoy-formatted headers.
+// Create an `AllHeaders` struct from envoy-formatted headers. A name
+// appearing more than once in headerMap (legitimate for headers like
+// Set-Cookie or Via) has every instance recorded, in order, in
+// HeaderValues/RawHeaderValues; Headers/RawHeaders still collapse to the
+// last instance received, for callers that only want one value.
 func NewAllHeadersFromEnvoyHeaderMap(headerMap *corev3.HeaderMap) (headers AllHeaders, err error) {
-	headers = AllHeaders{map[string]string{}, map[string][]byte{}}
+	headers = AllHeaders{
+		Headers:         map[string]string{},
+		RawHeaders:      map[string][]byte{},
+		HeaderValues:    map[string][]string{},
+		RawHeaderValues: map[string][][]byte{},
+	}
 
 	for _, h := range headerMap.GetHeaders() {
 
 		if len(h.Value) > 0 {
 			headers.Headers[h.Key] = h.Value
+			headers.HeaderValues[h.Key] = append(headers.HeaderValues[h.Key], h.Value)
 		} else {
 			headers.RawHeaders[h.Key] = h.RawValue
+			headers.RawHeaderValues[h.Key] = append(headers.RawHeaderValues[h.Key], h.RawValue)
 		}
 	}
 	return headers, nil
@@ -54,9 +80,38 @@ func (h *AllHeaders) Stringify() map[string]string {
 			headers[name] = b64.StdEncoding.EncodeToString(val)
 		}
 	}
+
+	// Where every instance of a header was recorded, join them CSV-style
+	// instead of leaving only the last-received value the loops above
+	// produce from Headers/RawHeaders alone.
+	for name, vals := range h.HeaderValues {
+		if len(vals) > 1 {
+			headers[name] = strings.Join(vals, ", ")
+		}
+	}
+	for name, vals := range h.RawHeaderValues {
+		if len(vals) > 1 {
+			headers[name] = joinRawHeaderValues(vals)
+		}
+	}
+
 	return headers
 }
 
+// joinRawHeaderValues CSV-joins vals the same way Stringify encodes a
+// single raw value: UTF-8 as-is, otherwise base64.
+func joinRawHeaderValues(vals [][]byte) string {
+	parts := make([]string, len(vals))
+	for i, val := range vals {
+		if utf8.Valid(val) {
+			parts[i] = string(val)
+		} else {
+			parts[i] = b64.StdEncoding.EncodeToString(val)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 // Get header values by name as either list of strings or raw bytes
 func (h *AllHeaders) GetHeaderValue(name string) (*string, []byte, bool) {
 	if value, exists := h.Headers[name]; exists {
@@ -68,6 +123,96 @@ func (h *AllHeaders) GetHeaderValue(name string) (*string, []byte, bool) {
 	return nil, nil, false
 }
 
+// GetHeaderValues returns every instance recorded for name, in the order
+// received/added, as either a list of strings or a list of raw byte values
+// - whichever kind name was recorded as. A name with only ever one instance
+// still returns a one-element slice, so a caller that always wants the full
+// list doesn't need a separate single-value code path. Returns ok=false if
+// name isn't present at all.
+func (h *AllHeaders) GetHeaderValues(name string) (values []string, rawValues [][]byte, ok bool) {
+	if vs, exists := h.HeaderValues[name]; exists {
+		return vs, nil, true
+	}
+	if vs, exists := h.RawHeaderValues[name]; exists {
+		return nil, vs, true
+	}
+
+	// Fall back to the single-value view, for an AllHeaders that was never
+	// routed through NewAllHeadersFromEnvoyHeaderMap/AddHeader (e.g. built
+	// by hand, or mutated by writing h.Headers[name] directly).
+	if v, exists := h.Headers[name]; exists {
+		return []string{v}, nil, true
+	}
+	if v, exists := h.RawHeaders[name]; exists {
+		return nil, [][]byte{v}, true
+	}
+	return nil, nil, false
+}
+
+// AddHeader appends value as a new instance of header name, preserving any
+// existing instances - the opposite of SetHeader, which replaces them.
+// Use this for headers that are legitimately repeated, such as Set-Cookie.
+// Headers (the single-value legacy view) is updated to value, so a caller
+// that doesn't know about HeaderValues still sees the most recently added
+// instance.
+func (h *AllHeaders) AddHeader(name, value string) {
+	if h.HeaderValues == nil {
+		h.HeaderValues = map[string][]string{}
+	}
+	h.HeaderValues[name] = append(h.HeaderValues[name], value)
+
+	if h.Headers == nil {
+		h.Headers = map[string]string{}
+	}
+	h.Headers[name] = value
+}
+
+// AddRawHeader is AddHeader for a raw-byte-valued instance.
+func (h *AllHeaders) AddRawHeader(name string, value []byte) {
+	if h.RawHeaderValues == nil {
+		h.RawHeaderValues = map[string][][]byte{}
+	}
+	h.RawHeaderValues[name] = append(h.RawHeaderValues[name], value)
+
+	if h.RawHeaders == nil {
+		h.RawHeaders = map[string][]byte{}
+	}
+	h.RawHeaders[name] = value
+}
+
+// SetHeader replaces every existing instance of name, string- or
+// raw-byte-valued, with a single string-valued instance - the opposite of
+// AddHeader's "add another instance" semantics.
+func (h *AllHeaders) SetHeader(name, value string) {
+	delete(h.RawHeaders, name)
+	delete(h.RawHeaderValues, name)
+
+	if h.Headers == nil {
+		h.Headers = map[string]string{}
+	}
+	h.Headers[name] = value
+
+	if h.HeaderValues == nil {
+		h.HeaderValues = map[string][]string{}
+	}
+	h.HeaderValues[name] = []string{value}
+}
+
+// AppendHeader folds value into name's existing instance by comma-joining
+// it onto whatever value (if any) is already there - the RFC 7230 section
+// 3.2.2 rule for combining repeated header fields into one field-value -
+// rather than adding a separate instance the way AddHeader does. Useful for
+// headers like Cache-Control, where "no-cache, no-store" and two separate
+// "Cache-Control: no-cache" / "Cache-Control: no-store" instances are
+// equivalent.
+func (h *AllHeaders) AppendHeader(name, value string) {
+	if existing, ok := h.Headers[name]; ok && existing != "" {
+		h.SetHeader(name, existing+", "+value)
+		return
+	}
+	h.SetHeader(name, value)
+}
+
 // Get header values by name, if it exists, as a single string joining multivalues
 // if they exist for the name
 func (h *AllHeaders) GetHeaderValueAsString(name string) (string, error) {
@@ -93,10 +238,12 @@ func (h *AllHeaders) GetHeaderValueAsString(name string) (string, error) {
 func (h *AllHeaders) DropHeaderNamed(name string) bool {
 	if _, exists := h.Headers[name]; exists {
 		delete(h.Headers, name)
+		delete(h.HeaderValues, name)
 		return true
 	}
 	if _, exists := h.RawHeaders[name]; exists {
 		delete(h.RawHeaders, name)
+		delete(h.RawHeaderValues, name)
 		return true
 	}
 	return false
@@ -107,6 +254,8 @@ func (h *AllHeaders) DropHeadersNamed(names []string) {
 	for _, name := range names {
 		delete(h.Headers, name)
 		delete(h.RawHeaders, name)
+		delete(h.HeaderValues, name)
+		delete(h.RawHeaderValues, name)
 	}
 }
 
@@ -118,11 +267,13 @@ func (h *AllHeaders) FilterHeaders(exclude HeaderNameFilter) {
 	for name := range h.Headers {
 		if exclude(name) {
 			delete(h.Headers, name)
+			delete(h.HeaderValues, name)
 		}
 	}
 	for name := range h.RawHeaders {
 		if exclude(name) {
 			delete(h.RawHeaders, name)
+			delete(h.RawHeaderValues, name)
 		}
 	}
 }
@@ -144,12 +295,179 @@ func (h *AllHeaders) DropHeadersNamedEndingWith(suffix string) {
 // Clone a set of headers, convenience for copying in case in-place
 // methods above are too destructive for use in a given implementation.
 func (h *AllHeaders) Clone() *AllHeaders {
-	copy := AllHeaders{map[string]string{}, map[string][]byte{}}
+	cloned := AllHeaders{Headers: map[string]string{}, RawHeaders: map[string][]byte{}}
+	for name, val := range h.Headers {
+		cloned.Headers[name] = val
+	}
+	for name, val := range h.RawHeaders {
+		cloned.RawHeaders[name] = val
+	}
+
+	if h.HeaderValues != nil {
+		cloned.HeaderValues = map[string][]string{}
+		for name, vals := range h.HeaderValues {
+			cloned.HeaderValues[name] = append([]string{}, vals...)
+		}
+	}
+	if h.RawHeaderValues != nil {
+		cloned.RawHeaderValues = map[string][][]byte{}
+		for name, vals := range h.RawHeaderValues {
+			copied := make([][]byte, len(vals))
+			copy(copied, vals)
+			cloned.RawHeaderValues[name] = copied
+		}
+	}
+
+	return &cloned
+}
+
+// Get header values by name, case-insensitively, as HTTP header names
+// require (RFC 7230 section 3.2): "Content-Type" and "content-type" name the
+// same header. GetHeaderValue only matches the exact key envoy sent it
+// under, which is fine when the caller controls the casing it looks up, but
+// not when the header came from a client that may have sent any casing.
+func (h *AllHeaders) GetHeaderValueFold(name string) (*string, []byte, bool) {
+	for key, value := range h.Headers {
+		if strings.EqualFold(key, name) {
+			return &value, nil, true
+		}
+	}
+	for key, value := range h.RawHeaders {
+		if strings.EqualFold(key, name) {
+			return nil, value, true
+		}
+	}
+	return nil, nil, false
+}
+
+// Drop, in-place, the headers with given names if they exist, matching
+// names case-insensitively.
+func (h *AllHeaders) DropHeadersNamedFold(names []string) {
+	h.FilterHeaders(func(name string) bool {
+		for _, n := range names {
+			if strings.EqualFold(name, n) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Filter headers by value rather than by name alone: exclude is called with
+// each header's name and value (Headers entries as []byte, so one filter
+// works over both maps), and the header is dropped in place if it returns
+// true. Useful for stripping Authorization tokens, masking cookies, or
+// dropping headers by a prefix/value combination DropHeadersNamedStartingWith
+// can't express on its own.
+func (h *AllHeaders) FilterHeadersWithValue(exclude func(name string, value []byte) bool) {
+	for name, val := range h.Headers {
+		if exclude(name, []byte(val)) {
+			delete(h.Headers, name)
+			delete(h.HeaderValues, name)
+		}
+	}
+	for name, val := range h.RawHeaders {
+		if exclude(name, val) {
+			delete(h.RawHeaders, name)
+			delete(h.RawHeaderValues, name)
+		}
+	}
+}
+
+// Redact, in-place, the headers with given names (matched exactly, the same
+// as DropHeadersNamed) by overwriting their value with replacement instead
+// of removing them - useful for logging/forwarding paths that want a
+// sensitive header's presence to stay visible without its value.
+func (h *AllHeaders) RedactHeaders(names []string, replacement string) {
+	for _, name := range names {
+		if _, exists := h.Headers[name]; exists {
+			h.Headers[name] = replacement
+			if _, ok := h.HeaderValues[name]; ok {
+				h.HeaderValues[name] = []string{replacement}
+			}
+			continue
+		}
+		if _, exists := h.RawHeaders[name]; exists {
+			h.RawHeaders[name] = []byte(replacement)
+			if _, ok := h.RawHeaderValues[name]; ok {
+				h.RawHeaderValues[name] = [][]byte{[]byte(replacement)}
+			}
+		}
+	}
+}
+
+// ToEnvoyHeaderMutation builds the HeaderMutation{SetHeaders, RemoveHeaders}
+// message ext_proc expects to apply this AllHeaders' current Headers as an
+// overwrite-or-add set, so a filter that mutated an AllHeaders directly
+// (rather than through RequestContext's UpdateHeader/RemoveHeader helpers)
+// can still hand its result back to Envoy. A name recorded in
+// HeaderValues/RawHeaderValues with more than one instance emits one
+// HeaderValueOption per instance (the first as an overwrite, the rest as
+// appends) instead of the single last-added value Headers/RawHeaders hold
+// for it; a name only ever written directly to
+// Headers/RawHeaders (bypassing AddHeader/SetHeader) still gets the single
+// overwrite entry it always has. No RemoveHeaders are produced since a
+// dropped header is simply absent from this AllHeaders, not a name to
+// explicitly remove - callers that need that also call RemoveHeaders on the
+// corresponding HeaderMutation name list themselves.
+func (h *AllHeaders) ToEnvoyHeaderMutation() *extprocv3.HeaderMutation {
+	hm := &extprocv3.HeaderMutation{}
+	overwrite := corev3.HeaderValueOption_HeaderAppendAction(
+		corev3.HeaderValueOption_HeaderAppendAction_value["OVERWRITE_IF_EXISTS_OR_ADD"],
+	)
+	appendAction := corev3.HeaderValueOption_HeaderAppendAction(
+		corev3.HeaderValueOption_HeaderAppendAction_value["APPEND_IF_EXISTS_OR_ADD"],
+	)
+
+	emitted := map[string]bool{}
+
+	for name, vals := range h.HeaderValues {
+		for i, val := range vals {
+			action := appendAction
+			if i == 0 {
+				action = overwrite
+			}
+			hm.SetHeaders = append(hm.SetHeaders, &corev3.HeaderValueOption{
+				Header:       &corev3.HeaderValue{Key: name, Value: val},
+				AppendAction: action,
+			})
+		}
+		emitted[name] = true
+	}
 	for name, val := range h.Headers {
-		copy.Headers[name] = val
+		if emitted[name] {
+			continue
+		}
+		hm.SetHeaders = append(hm.SetHeaders, &corev3.HeaderValueOption{
+			Header:       &corev3.HeaderValue{Key: name, Value: val},
+			AppendAction: overwrite,
+		})
+	}
+
+	emittedRaw := map[string]bool{}
+
+	for name, vals := range h.RawHeaderValues {
+		for i, val := range vals {
+			action := appendAction
+			if i == 0 {
+				action = overwrite
+			}
+			hm.SetHeaders = append(hm.SetHeaders, &corev3.HeaderValueOption{
+				Header:       &corev3.HeaderValue{Key: name, RawValue: val},
+				AppendAction: action,
+			})
+		}
+		emittedRaw[name] = true
 	}
 	for name, val := range h.RawHeaders {
-		copy.RawHeaders[name] = val
+		if emittedRaw[name] {
+			continue
+		}
+		hm.SetHeaders = append(hm.SetHeaders, &corev3.HeaderValueOption{
+			Header:       &corev3.HeaderValue{Key: name, RawValue: val},
+			AppendAction: overwrite,
+		})
 	}
-	return &copy
+
+	return hm
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package extproc
 
 import (
+	"strings"
 	"testing"
 
 	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -27,8 +28,10 @@ func TestNewAllHeadersFromEnvoyHeaderMap(t *testing.T) {
 				Headers: []*corev3.HeaderValue{},
 			},
 			want: AllHeaders{
-				Headers:    map[string]string{},
-				RawHeaders: map[string][]byte{},
+				Headers:         map[string]string{},
+				RawHeaders:      map[string][]byte{},
+				HeaderValues:    map[string][]string{},
+				RawHeaderValues: map[string][][]byte{},
 			},
 		},
 		{
@@ -45,6 +48,11 @@ func TestNewAllHeadersFromEnvoyHeaderMap(t *testing.T) {
 					"x-request-id": "123",
 				},
 				RawHeaders: map[string][]byte{},
+				HeaderValues: map[string][]string{
+					"content-type": {"application/json"},
+					"x-request-id": {"123"},
+				},
+				RawHeaderValues: map[string][][]byte{},
 			},
 		},
 		{
@@ -61,6 +69,11 @@ func TestNewAllHeadersFromEnvoyHeaderMap(t *testing.T) {
 					"content-type": []byte("application/json"),
 					"binary-data":  {0xFF, 0xFE, 0xFD},
 				},
+				HeaderValues: map[string][]string{},
+				RawHeaderValues: map[string][][]byte{
+					"content-type": {[]byte("application/json")},
+					"binary-data":  {{0xFF, 0xFE, 0xFD}},
+				},
 			},
 		},
 		{
@@ -78,6 +91,35 @@ func TestNewAllHeadersFromEnvoyHeaderMap(t *testing.T) {
 				RawHeaders: map[string][]byte{
 					"binary-data": {0xFF, 0xFE, 0xFD},
 				},
+				HeaderValues: map[string][]string{
+					"content-type": {"application/json"},
+				},
+				RawHeaderValues: map[string][][]byte{
+					"binary-data": {{0xFF, 0xFE, 0xFD}},
+				},
+			},
+		},
+		{
+			name: "repeated header with different values preserves every instance",
+			headerMap: &corev3.HeaderMap{
+				Headers: []*corev3.HeaderValue{
+					{Key: "set-cookie", Value: "a=1"},
+					{Key: "set-cookie", Value: "b=2"},
+					{Key: "x-request-id", Value: "123"},
+				},
+			},
+			want: AllHeaders{
+				Headers: map[string]string{
+					// collapsed, single-value view: last instance wins
+					"set-cookie":   "b=2",
+					"x-request-id": "123",
+				},
+				RawHeaders: map[string][]byte{},
+				HeaderValues: map[string][]string{
+					"set-cookie":   {"a=1", "b=2"},
+					"x-request-id": {"123"},
+				},
+				RawHeaderValues: map[string][][]byte{},
 			},
 		},
 	}
@@ -482,6 +524,232 @@ func TestAllHeaders_Clone(t *testing.T) {
 	assert.NotContains(t, original.RawHeaders, "new-raw")
 }
 
+func TestAllHeaders_GetHeaderValueFold(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		RawHeaders: map[string][]byte{
+			"X-Binary": []byte("raw-value"),
+		},
+	}
+
+	str, raw, exists := headers.GetHeaderValueFold("content-type")
+	require.True(t, exists)
+	require.NotNil(t, str)
+	assert.Equal(t, "application/json", *str)
+	assert.Nil(t, raw)
+
+	str, raw, exists = headers.GetHeaderValueFold("x-binary")
+	require.True(t, exists)
+	assert.Nil(t, str)
+	assert.Equal(t, []byte("raw-value"), raw)
+
+	_, _, exists = headers.GetHeaderValueFold("missing")
+	assert.False(t, exists)
+}
+
+func TestAllHeaders_DropHeadersNamedFold(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{
+			"Authorization": "value1",
+			"Keep":          "value2",
+		},
+		RawHeaders: map[string][]byte{
+			"X-Trace-Id": []byte("value3"),
+		},
+	}
+
+	expected := AllHeaders{
+		Headers: map[string]string{
+			"Keep": "value2",
+		},
+		RawHeaders: map[string][]byte{},
+	}
+
+	headers.DropHeadersNamedFold([]string{"authorization", "x-trace-id"})
+	assert.Equal(t, expected, headers)
+}
+
+func TestAllHeaders_FilterHeadersWithValue(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{
+			"authorization": "Bearer secret-token",
+			"x-keep":        "value",
+		},
+		RawHeaders: map[string][]byte{
+			"cookie": []byte("session=abc123"),
+		},
+	}
+
+	expected := AllHeaders{
+		Headers: map[string]string{
+			"x-keep": "value",
+		},
+		RawHeaders: map[string][]byte{},
+	}
+
+	headers.FilterHeadersWithValue(func(name string, value []byte) bool {
+		return strings.HasPrefix(string(value), "Bearer ") || name == "cookie"
+	})
+	assert.Equal(t, expected, headers)
+}
+
+func TestAllHeaders_RedactHeaders(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{
+			"authorization": "Bearer secret-token",
+			"keep":          "value",
+		},
+		RawHeaders: map[string][]byte{
+			"cookie": []byte("session=abc123"),
+		},
+	}
+
+	expected := AllHeaders{
+		Headers: map[string]string{
+			"authorization": "[REDACTED]",
+			"keep":          "value",
+		},
+		RawHeaders: map[string][]byte{
+			"cookie": []byte("[REDACTED]"),
+		},
+	}
+
+	headers.RedactHeaders([]string{"authorization", "cookie", "non-existent"}, "[REDACTED]")
+	assert.Equal(t, expected, headers)
+}
+
+func TestAllHeaders_ToEnvoyHeaderMutation(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{
+			"content-type": "application/json",
+		},
+		RawHeaders: map[string][]byte{
+			"binary-data": {0xFF, 0xFE, 0xFD},
+		},
+	}
+
+	hm := headers.ToEnvoyHeaderMutation()
+	require.Len(t, hm.SetHeaders, 2)
+	assert.Empty(t, hm.RemoveHeaders)
+
+	got := map[string]string{}
+	for _, h := range hm.SetHeaders {
+		if h.Header.Value != "" {
+			got[h.Header.Key] = h.Header.Value
+		} else {
+			got[h.Header.Key] = string(h.Header.RawValue)
+		}
+	}
+	assert.Equal(t, "application/json", got["content-type"])
+	assert.Equal(t, string([]byte{0xFF, 0xFE, 0xFD}), got["binary-data"])
+}
+
+func TestAllHeaders_Stringify_JoinsRepeatedHeader(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{"set-cookie": "b=2"},
+		HeaderValues: map[string][]string{
+			"set-cookie": {"a=1", "b=2"},
+		},
+		RawHeaders:      map[string][]byte{},
+		RawHeaderValues: map[string][][]byte{},
+	}
+
+	got := headers.Stringify()
+	assert.Equal(t, "a=1, b=2", got["set-cookie"])
+}
+
+func TestAllHeaders_AddHeader_PreservesEveryInstance(t *testing.T) {
+	headers := AllHeaders{}
+
+	headers.AddHeader("set-cookie", "a=1")
+	headers.AddHeader("set-cookie", "b=2")
+
+	assert.Equal(t, "b=2", headers.Headers["set-cookie"])
+
+	values, rawValues, ok := headers.GetHeaderValues("set-cookie")
+	require.True(t, ok)
+	assert.Nil(t, rawValues)
+	assert.Equal(t, []string{"a=1", "b=2"}, values)
+}
+
+func TestAllHeaders_AddRawHeader_PreservesEveryInstance(t *testing.T) {
+	headers := AllHeaders{}
+
+	headers.AddRawHeader("x-binary", []byte{0x01})
+	headers.AddRawHeader("x-binary", []byte{0x02})
+
+	values, rawValues, ok := headers.GetHeaderValues("x-binary")
+	require.True(t, ok)
+	assert.Nil(t, values)
+	assert.Equal(t, [][]byte{{0x01}, {0x02}}, rawValues)
+}
+
+func TestAllHeaders_SetHeader_ReplacesEveryInstance(t *testing.T) {
+	headers := AllHeaders{}
+	headers.AddHeader("set-cookie", "a=1")
+	headers.AddHeader("set-cookie", "b=2")
+
+	headers.SetHeader("set-cookie", "c=3")
+
+	values, _, ok := headers.GetHeaderValues("set-cookie")
+	require.True(t, ok)
+	assert.Equal(t, []string{"c=3"}, values)
+	assert.Equal(t, "c=3", headers.Headers["set-cookie"])
+}
+
+func TestAllHeaders_AppendHeader_FoldsIntoSingleInstance(t *testing.T) {
+	headers := AllHeaders{}
+	headers.SetHeader("cache-control", "no-cache")
+	headers.AppendHeader("cache-control", "no-store")
+
+	assert.Equal(t, "no-cache, no-store", headers.Headers["cache-control"])
+
+	values, _, ok := headers.GetHeaderValues("cache-control")
+	require.True(t, ok)
+	assert.Equal(t, []string{"no-cache, no-store"}, values)
+}
+
+func TestAllHeaders_GetHeaderValues_FallsBackToSingleValueView(t *testing.T) {
+	headers := AllHeaders{
+		Headers: map[string]string{"content-type": "application/json"},
+	}
+
+	values, rawValues, ok := headers.GetHeaderValues("content-type")
+	require.True(t, ok)
+	assert.Nil(t, rawValues)
+	assert.Equal(t, []string{"application/json"}, values)
+
+	_, _, ok = headers.GetHeaderValues("missing")
+	assert.False(t, ok)
+}
+
+func TestAllHeaders_ToEnvoyHeaderMutation_RepeatedHeaderEmitsOneEntryPerInstance(t *testing.T) {
+	headers := AllHeaders{}
+	headers.AddHeader("set-cookie", "a=1")
+	headers.AddHeader("set-cookie", "b=2")
+	headers.SetHeader("content-type", "application/json")
+
+	hm := headers.ToEnvoyHeaderMutation()
+
+	var cookieValues []string
+	var cookieActions []corev3.HeaderValueOption_HeaderAppendAction
+	for _, h := range hm.SetHeaders {
+		if h.Header.Key == "set-cookie" {
+			cookieValues = append(cookieValues, h.Header.Value)
+			cookieActions = append(cookieActions, h.AppendAction)
+		}
+	}
+
+	require.Len(t, cookieValues, 2)
+	assert.Equal(t, []string{"a=1", "b=2"}, cookieValues)
+	assert.Equal(t, corev3.HeaderValueOption_HeaderAppendAction(
+		corev3.HeaderValueOption_HeaderAppendAction_value["OVERWRITE_IF_EXISTS_OR_ADD"]), cookieActions[0])
+	assert.Equal(t, corev3.HeaderValueOption_HeaderAppendAction(
+		corev3.HeaderValueOption_HeaderAppendAction_value["APPEND_IF_EXISTS_OR_ADD"]), cookieActions[1])
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
@@ -7,6 +7,10 @@ package extproc
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -14,16 +18,265 @@ import (
 	pb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-type HealthServer struct{}
+// ExternalProcessorServiceName is the gRPC health service name under which
+// HealthServer reports the ExternalProcessor service's status independently
+// of overall server status (the "" service name, which envoy and most
+// probes check by default).
+const ExternalProcessorServiceName = "envoy.service.ext_proc.v3.ExternalProcessor"
 
-func (s *HealthServer) Check(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
-	return &pb.HealthCheckResponse{Status: pb.HealthCheckResponse_SERVING}, nil
+// LivenessServiceName and ReadinessServiceName are the gRPC health service
+// names HealthServer reports a registered HealthReporter's CheckLiveness and
+// CheckReadiness results under, kept separate from ExternalProcessorServiceName
+// so a Kubernetes-style probe setup can tell "restart this process"
+// (liveness) apart from "don't route it traffic yet" (readiness).
+const (
+	LivenessServiceName  = "liveness"
+	ReadinessServiceName = "readiness"
+)
+
+// HealthReporter is an optional interface a RequestProcessor can implement to
+// have its own liveness/readiness reasoning folded into gRPC health checking,
+// rather than relying solely on RegisterDependency's simple up/down checks.
+// CheckLiveness should fail only when the process is broken beyond recovery
+// (a probe failing it typically triggers a restart); CheckReadiness should
+// fail whenever the processor isn't ready to accept traffic yet (e.g. a
+// dependency like a DB or Kafka connection isn't established). Both are
+// polled on ExtProcServerOptions.HealthCheckInterval by
+// HealthServer.RegisterHealthReporter.
+type HealthReporter interface {
+	CheckLiveness(ctx context.Context) error
+	CheckReadiness(ctx context.Context) error
+}
+
+// defaultDependencyPollInterval is used by RegisterDependency when no
+// interval is given (RequestContext.RegisterHealthDependency never does).
+const defaultDependencyPollInterval = 5 * time.Second
+
+// healthDependency is one named check registered via
+// HealthServer.RegisterDependency (normally reached through
+// RequestContext.RegisterHealthDependency), polled periodically to decide
+// whether ExternalProcessorServiceName should report NOT_SERVING.
+type healthDependency struct {
+	name  string
+	check func() error
+}
+
+// HealthServer implements grpc_health_v1.HealthServer, tracking serving
+// status per gRPC service name instead of a single server-wide flag, so
+// ExternalProcessorServiceName can go NOT_SERVING (a saturated dependency,
+// or a shutdown in progress) while other services registered on the same
+// *grpc.Server keep reporting SERVING. Unset services default to SERVING.
+// Use SetServingStatus, Shutdown, or RegisterDependency to change that.
+// Construct it with NewHealthServer; the zero value's maps are nil, so
+// SetServingStatus on it panics.
+type HealthServer struct {
+	mu           sync.Mutex
+	status       map[string]pb.HealthCheckResponse_ServingStatus
+	watchers     map[string]map[chan pb.HealthCheckResponse_ServingStatus]struct{}
+	dependencies []healthDependency
+	shuttingDown bool
+	pollStarted  bool
+}
+
+// NewHealthServer returns a HealthServer with every service defaulting to
+// SERVING until changed.
+func NewHealthServer() *HealthServer {
+	return &HealthServer{
+		status:   map[string]pb.HealthCheckResponse_ServingStatus{},
+		watchers: map[string]map[chan pb.HealthCheckResponse_ServingStatus]struct{}{},
+	}
+}
+
+func (h *HealthServer) getStatus(service string) pb.HealthCheckResponse_ServingStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.status[service]; ok {
+		return s
+	}
+	return pb.HealthCheckResponse_SERVING
 }
 
-func (s *HealthServer) Watch(req *pb.HealthCheckRequest, srv pb.Health_WatchServer) error {
-	return status.Error(codes.Unimplemented, "Watch is not implemented")
+// SetServingStatus records the status for service and notifies any open
+// Watch streams for it. service "" is conventionally overall server status.
+func (h *HealthServer) SetServingStatus(service string, status pb.HealthCheckResponse_ServingStatus) {
+	h.mu.Lock()
+	h.status[service] = status
+	subs := make([]chan pb.HealthCheckResponse_ServingStatus, 0, len(h.watchers[service]))
+	for ch := range h.watchers[service] {
+		subs = append(subs, ch)
+	}
+	h.mu.Unlock()
+
+	for _, ch := range subs {
+		select {
+		case ch <- status:
+		default:
+			// a slow watcher already has an undelivered update queued; it
+			// will see this status (or a later one) on its next send.
+		}
+	}
+}
+
+// TriggerPortServiceName returns the gRPC health service name ServeWithOptions
+// and Attach register for the trigger listening on port, so List/Watch can
+// report readiness per configured trigger port rather than only overall ("")
+// and ExternalProcessorServiceName status.
+func TriggerPortServiceName(port int) string {
+	return fmt.Sprintf("trigger-port-%d", port)
+}
+
+// Shutdown marks overall server status ("") and ExternalProcessorServiceName
+// NOT_SERVING, for use during graceful shutdown: call it before
+// grpc.Server.GracefulStop so health probes stop routing new traffic while
+// in-flight streams finish draining on their own.
+func (h *HealthServer) Shutdown() {
+	h.mu.Lock()
+	h.shuttingDown = true
+	h.mu.Unlock()
+
+	h.SetServingStatus("", pb.HealthCheckResponse_NOT_SERVING)
+	h.SetServingStatus(ExternalProcessorServiceName, pb.HealthCheckResponse_NOT_SERVING)
+}
+
+func (h *HealthServer) Check(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	return &pb.HealthCheckResponse{Status: h.getStatus(req.Service)}, nil
+}
+
+func (h *HealthServer) Watch(req *pb.HealthCheckRequest, srv pb.Health_WatchServer) error {
+	ch := make(chan pb.HealthCheckResponse_ServingStatus, 1)
+	ch <- h.getStatus(req.Service)
+
+	h.mu.Lock()
+	if h.watchers[req.Service] == nil {
+		h.watchers[req.Service] = map[chan pb.HealthCheckResponse_ServingStatus]struct{}{}
+	}
+	h.watchers[req.Service][ch] = struct{}{}
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.watchers[req.Service], ch)
+		h.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case s := <-ch:
+			if err := srv.Send(&pb.HealthCheckResponse{Status: s}); err != nil {
+				return status.Errorf(codes.Unavailable, "cannot send health status: %v", err)
+			}
+		}
+	}
 }
 
 func (h *HealthServer) List(ctx context.Context, req *pb.HealthListRequest) (*pb.HealthListResponse, error) {
-	return &pb.HealthListResponse{}, nil
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	statuses := make(map[string]*pb.HealthCheckResponse, len(h.status))
+	for service, s := range h.status {
+		statuses[service] = &pb.HealthCheckResponse{Status: s}
+	}
+	return &pb.HealthListResponse{Statuses: statuses}, nil
+}
+
+// RegisterDependency adds a named upstream dependency check to the polled
+// set. The first call starts the poll loop with its pollInterval
+// (defaultDependencyPollInterval if <= 0); later calls only add their check
+// to that loop, and their pollInterval is ignored. Once started, the poll
+// loop runs for the lifetime of the process. A non-nil
+// return from check fails the whole poll, reporting
+// ExternalProcessorServiceName NOT_SERVING until every registered
+// dependency subsequently succeeds in the same poll.
+func (h *HealthServer) RegisterDependency(name string, check func() error, pollInterval time.Duration) {
+	h.mu.Lock()
+	h.dependencies = append(h.dependencies, healthDependency{name: name, check: check})
+	started := h.pollStarted
+	h.pollStarted = true
+	h.mu.Unlock()
+
+	if !started {
+		if pollInterval <= 0 {
+			pollInterval = defaultDependencyPollInterval
+		}
+		go h.pollDependencies(pollInterval)
+	}
+}
+
+// RegisterHealthReporter starts polling reporter's CheckLiveness and
+// CheckReadiness every interval (defaultDependencyPollInterval if <= 0),
+// reporting their results under LivenessServiceName/ReadinessServiceName
+// respectively; like checkDependencies, polling stops updating once
+// Shutdown has been called, and both keep the last status they reported
+// (Shutdown itself does not change them). Each poll's context is
+// scoped to interval, so a reporter that hangs doesn't stall subsequent polls
+// indefinitely.
+func (h *HealthServer) RegisterHealthReporter(reporter HealthReporter, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultDependencyPollInterval
+	}
+	go h.pollHealthReporter(reporter, interval)
+}
+
+func (h *HealthServer) pollHealthReporter(reporter HealthReporter, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		h.checkHealthReporter(reporter, interval)
+	}
+}
+
+func (h *HealthServer) checkHealthReporter(reporter HealthReporter, timeout time.Duration) {
+	h.mu.Lock()
+	shuttingDown := h.shuttingDown
+	h.mu.Unlock()
+	if shuttingDown {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	livenessStatus := pb.HealthCheckResponse_SERVING
+	if err := reporter.CheckLiveness(ctx); err != nil {
+		livenessStatus = pb.HealthCheckResponse_NOT_SERVING
+	}
+	h.SetServingStatus(LivenessServiceName, livenessStatus)
+
+	readinessStatus := pb.HealthCheckResponse_SERVING
+	if err := reporter.CheckReadiness(ctx); err != nil {
+		readinessStatus = pb.HealthCheckResponse_NOT_SERVING
+	}
+	h.SetServingStatus(ReadinessServiceName, readinessStatus)
+}
+
+func (h *HealthServer) pollDependencies(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		h.checkDependencies()
+	}
+}
+
+func (h *HealthServer) checkDependencies() {
+	h.mu.Lock()
+	deps := slices.Clone(h.dependencies)
+	shuttingDown := h.shuttingDown
+	h.mu.Unlock()
+
+	if shuttingDown {
+		// a shutdown in progress already forced NOT_SERVING; don't let a
+		// since-recovered dependency flip it back to SERVING.
+		return
+	}
+
+	for _, dep := range deps {
+		if err := dep.check(); err != nil {
+			h.SetServingStatus(ExternalProcessorServiceName, pb.HealthCheckResponse_NOT_SERVING)
+			return
+		}
+	}
+	h.SetServingStatus(ExternalProcessorServiceName, pb.HealthCheckResponse_SERVING)
 }
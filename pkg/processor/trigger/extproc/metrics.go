@@ -0,0 +1,41 @@
+package extproc
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// streamMetrics tracks ext_proc stream reconnect activity in memory,
+// standing in for extproc_stream_reconnects_total/extproc_backoff_current_seconds
+// until a real metrics registry is wired into this trigger (see the same
+// pattern in pkg/processor/trigger/openinference/errors.go's errorMetrics).
+type streamMetrics struct {
+	reconnectsTotal     atomic.Int64
+	backoffCurrentNanos atomic.Int64
+}
+
+func newStreamMetrics() *streamMetrics {
+	return &streamMetrics{}
+}
+
+// recordReconnect increments extproc_stream_reconnects_total, called once
+// per proactively-ended stream (see ProcessingOptions.MaxStreamLifetime).
+func (m *streamMetrics) recordReconnect() {
+	m.reconnectsTotal.Add(1)
+}
+
+// setBackoffCurrent records the delay currently being applied to a stream
+// recovering from consecutive phase-processing failures (extproc_backoff_current_seconds).
+func (m *streamMetrics) setBackoffCurrent(d time.Duration) {
+	m.backoffCurrentNanos.Store(int64(d))
+}
+
+// ReconnectsTotal returns the current extproc_stream_reconnects_total value.
+func (m *streamMetrics) ReconnectsTotal() int64 {
+	return m.reconnectsTotal.Load()
+}
+
+// BackoffCurrentSeconds returns the current extproc_backoff_current_seconds value.
+func (m *streamMetrics) BackoffCurrentSeconds() float64 {
+	return time.Duration(m.backoffCurrentNanos.Load()).Seconds()
+}
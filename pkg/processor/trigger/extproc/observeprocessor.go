@@ -28,6 +28,8 @@ func (s *ObserveProcessor) GetName() string {
 }
 
 func (s *ObserveProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	advertiseAcceptEncoding(ctx, s.GetOptions(), &headers)
+
 	// TODO: not needed if ProcessRequestBody always called
 	// err := observeRequest(ctx, nil)
 	// if err != nil {
@@ -36,7 +38,25 @@ func (s *ObserveProcessor) ProcessRequestHeaders(ctx *RequestContext, headers Al
 	return ctx.ContinueRequest()
 }
 
+// dispatchGRPCMessages hands any complete gRPC messages available in this
+// body call (see grpcMessagesFromBodyCall) to s.Handler, when it implements
+// GRPCMessageHandler and ctx's body is gRPC-framed (see IsGRPCContentType).
+// A no-op otherwise.
+func (s *ObserveProcessor) dispatchGRPCMessages(ctx *RequestContext, body []byte, isResponse bool) {
+	grpcHandler, ok := s.Handler.(GRPCMessageHandler)
+	if !ok || ctx.bodybuffer == nil || !IsGRPCContentType(ctx.bodybuffer.Type.ContentType) {
+		return
+	}
+	for _, msg := range grpcMessagesFromBodyCall(ctx, body, isResponse) {
+		if err := grpcHandler.ProcessGRPCMessage(ctx, msg); err != nil {
+			log.Printf("Error processing gRPC message: %v", err)
+		}
+	}
+}
+
 func (s *ObserveProcessor) ProcessRequestBody(ctx *RequestContext, body []byte) error {
+	s.dispatchGRPCMessages(ctx, body, false)
+
 	err := s.observeRequest(ctx, body)
 	if err != nil {
 		log.Printf("Error: %v", err)
@@ -45,6 +65,8 @@ func (s *ObserveProcessor) ProcessRequestBody(ctx *RequestContext, body []byte)
 }
 
 func (s *ObserveProcessor) ProcessResponseHeaders(ctx *RequestContext, headers AllHeaders) error {
+	advertiseAcceptEncoding(ctx, s.GetOptions(), &headers)
+
 	// TODO: not needed if ProcessResponseBody always called
 	// _, err := observeResponse(ctx, nil)
 	// if err != nil {
@@ -54,6 +76,8 @@ func (s *ObserveProcessor) ProcessResponseHeaders(ctx *RequestContext, headers A
 }
 
 func (s *ObserveProcessor) ProcessResponseBody(ctx *RequestContext, body []byte) error {
+	s.dispatchGRPCMessages(ctx, body, true)
+
 	err := s.observeResponse(ctx, body)
 	if err != nil {
 		log.Printf("Error: %v", err)
@@ -0,0 +1,71 @@
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveProcessor_GetName(t *testing.T) {
+	processor := &ObserveProcessor{}
+	assert.Equal(t, "observeprocessor", processor.GetName())
+}
+
+func TestObserveProcessor_ProcessRequestBody_DispatchesGRPCMessages(t *testing.T) {
+	mockHandler := &grpcEventHandler{}
+	processor := &ObserveProcessor{
+		AbstractProcessor: AbstractProcessor{Handler: mockHandler},
+	}
+
+	body := EncodeGRPCMessage(GRPCMessage{Payload: []byte("ping")})
+	ctx := &RequestContext{
+		bodybuffer: &EncodedBody{Type: BodyType{ContentType: "application/grpc"}},
+	}
+
+	mockHandler.On("HandleEvent", ctx, body).Return(&EventResponse{}, nil).Once()
+
+	err := processor.ProcessRequestBody(ctx, body)
+	assert.NoError(t, err)
+	require.Len(t, mockHandler.messages, 1)
+	assert.Equal(t, []byte("ping"), mockHandler.messages[0].Payload)
+}
+
+func TestObserveProcessor_ProcessResponseBody_DispatchesGRPCMessages(t *testing.T) {
+	mockHandler := &grpcEventHandler{}
+	processor := &ObserveProcessor{
+		AbstractProcessor: AbstractProcessor{Handler: mockHandler},
+	}
+
+	body := EncodeGRPCMessage(GRPCMessage{Payload: []byte("pong")})
+	ctx := &RequestContext{
+		bodybuffer: &EncodedBody{Type: BodyType{ContentType: "application/grpc+proto"}},
+	}
+
+	mockHandler.On("HandleEvent", ctx, body).Return(&EventResponse{}, nil).Once()
+
+	err := processor.ProcessResponseBody(ctx, body)
+	assert.NoError(t, err)
+	require.Len(t, mockHandler.messages, 1)
+	assert.Equal(t, []byte("pong"), mockHandler.messages[0].Payload)
+}
+
+func TestObserveProcessor_ProcessBody_HandlerWithoutGRPCSupportIsSkipped(t *testing.T) {
+	mockHandler := new(MockEventHandler)
+	processor := &ObserveProcessor{
+		AbstractProcessor: AbstractProcessor{Handler: mockHandler},
+	}
+
+	body := EncodeGRPCMessage(GRPCMessage{Payload: []byte("ping")})
+	ctx := &RequestContext{
+		bodybuffer: &EncodedBody{Type: BodyType{ContentType: "application/grpc"}},
+	}
+
+	mockHandler.On("HandleEvent", ctx, body).Return(&EventResponse{}, nil).Once()
+
+	assert.NotPanics(t, func() {
+		err := processor.ProcessRequestBody(ctx, body)
+		assert.NoError(t, err)
+	})
+	mockHandler.AssertExpectations(t)
+}
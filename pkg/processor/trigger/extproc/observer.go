@@ -0,0 +1,37 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import "time"
+
+// PhaseObserver is an optional, pluggable hook ProcessingOptions.Observer can
+// be set to, so GenericExtProcServer reports per-phase latency and outcome,
+// and active-stream counts, to an external metrics/tracing system (a
+// Prometheus registry, an OpenTelemetry tracer, or anything else). Nothing in
+// this package implements PhaseObserver itself, and a nil Observer (the
+// default) disables reporting entirely: streamMetrics' in-memory counters
+// remain the only always-on instrumentation until a caller wires one in.
+type PhaseObserver interface {
+	// ObservePhase reports how long processing phase took for processorName,
+	// and the error processing it returned (nil on success). phase is one of
+	// the REQUEST_PHASE_* constants.
+	ObservePhase(processorName string, phase int, duration time.Duration, err error)
+
+	// StreamStarted and StreamEnded bracket one ext_proc gRPC stream (one
+	// Process call) for processorName, so an Observer can maintain an
+	// active-streams gauge; every StreamStarted is followed by exactly one
+	// StreamEnded, even if the stream ends on error.
+	StreamStarted(processorName string)
+	StreamEnded(processorName string)
+}
+
+// observePhase reports duration/err for phase to options.Observer, if set.
+func observePhase(options *ProcessingOptions, processorName string, phase int, duration time.Duration, err error) {
+	if options == nil || options.Observer == nil {
+		return
+	}
+	options.Observer.ObservePhase(processorName, phase, duration, err)
+}
@@ -0,0 +1,65 @@
+package extproc
+
+import (
+	"testing"
+	"time"
+
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakePhaseObserver records PhaseObserver calls for assertions.
+type fakePhaseObserver struct {
+	phases        []int
+	errs          []error
+	streamStarts  int
+	streamEnds    int
+	lastProcessor string
+}
+
+func (f *fakePhaseObserver) ObservePhase(processorName string, phase int, duration time.Duration, err error) {
+	f.lastProcessor = processorName
+	f.phases = append(f.phases, phase)
+	f.errs = append(f.errs, err)
+}
+
+func (f *fakePhaseObserver) StreamStarted(string) { f.streamStarts++ }
+func (f *fakePhaseObserver) StreamEnded(string)   { f.streamEnds++ }
+
+func TestProcessPhase_ReportsToObserver(t *testing.T) {
+	observer := &fakePhaseObserver{}
+
+	mockProcessor := new(MockRequestProcessor)
+	mockProcessor.On("ProcessRequestHeaders", mock.Anything, mock.Anything).Return(nil)
+
+	opts := NewDefaultOptions()
+	opts.Observer = observer
+
+	server := &GenericExtProcServer{
+		name:      "test-processor",
+		processor: mockProcessor,
+		options:   opts,
+	}
+
+	rc := &RequestContext{extProcOptions: opts}
+
+	_, err := server.processPhase(&extprocv3.ProcessingRequest{
+		Request: &extprocv3.ProcessingRequest_RequestHeaders{
+			RequestHeaders: &extprocv3.HttpHeaders{},
+		},
+	}, mockProcessor, rc)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{REQUEST_PHASE_REQUEST_HEADERS}, observer.phases)
+	assert.Equal(t, "test-processor", observer.lastProcessor)
+	assert.Equal(t, []error{nil}, observer.errs)
+	mockProcessor.AssertExpectations(t)
+}
+
+func TestObservePhase_NilObserverIsNoop(t *testing.T) {
+	assert.NotPanics(t, func() {
+		observePhase(NewDefaultOptions(), "test-processor", REQUEST_PHASE_REQUEST_HEADERS, time.Millisecond, nil)
+		observePhase(nil, "test-processor", REQUEST_PHASE_REQUEST_HEADERS, time.Millisecond, nil)
+	})
+}
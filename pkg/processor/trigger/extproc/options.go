@@ -1,25 +1,157 @@
 package extproc
 
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// BodyProcessingMode selects how a processor that supports both whole-body
+// and chunk-wise handling (currently PostProcessor) treats response body
+// chunks: see ProcessingModeBuffered and ProcessingModeStreamed.
+type BodyProcessingMode string
+
+const (
+	// ProcessingModeBuffered calls the handler once with the whole response
+	// body (the historical, and still default, behavior).
+	ProcessingModeBuffered BodyProcessingMode = "buffered"
+
+	// ProcessingModeStreamed calls a StreamingHandler once per response body
+	// chunk as envoy delivers it, instead of waiting for the full body.
+	// Ignored, falling back to ProcessingModeBuffered, if the configured
+	// Handler does not implement StreamingHandler.
+	ProcessingModeStreamed BodyProcessingMode = "streamed"
+
+	// ProcessingModeAuto behaves like ProcessingModeBuffered, except that if
+	// the configured Handler implements StreamingHandler and the response's
+	// Content-Type looks like a streaming protocol (SSE's
+	// text/event-stream, or application/grpc and its +proto/+json
+	// variants), PostProcessor streams chunks through OnResponseChunk
+	// instead of buffering, the same as ProcessingModeStreamed. A response
+	// whose Content-Type doesn't match falls back to buffered handling,
+	// even with a StreamingHandler configured.
+	ProcessingModeAuto BodyProcessingMode = "auto"
+)
+
+// BodyOverflowPolicy selects what a RequestContext buffering a request or
+// response body (see ProcessingOptions.BufferStreamedBodies) does once that
+// buffer would grow past its configured limit: see OnBodyOverflowTruncate,
+// OnBodyOverflowPassThrough, OnBodyOverflowImmediateResponse, and
+// OnBodyOverflowError.
+type BodyOverflowPolicy int
+
+const (
+	// OnBodyOverflowTruncate stops appending further chunks, but still calls
+	// the handler with the bytes collected so far; EncodedBody.Truncated is
+	// set so the handler (via RequestContext.BodyTruncated) can tell.
+	OnBodyOverflowTruncate BodyOverflowPolicy = iota
+
+	// OnBodyOverflowPassThrough drops the buffer collected so far and, for
+	// the remainder of the phase, forwards each chunk to the handler as it
+	// arrives instead of buffering it.
+	OnBodyOverflowPassThrough
+
+	// OnBodyOverflowImmediateResponse cancels the request with
+	// ProcessingOptions.OverflowResponseStatus (defaulting to 413 if unset).
+	OnBodyOverflowImmediateResponse
+
+	// OnBodyOverflowError fails phase processing, returning an error on the
+	// ext_proc stream.
+	OnBodyOverflowError
+)
+
+// BackoffConfig controls the delay GenericExtProcServer applies before
+// resuming a stream after consecutive phase-processing failures (e.g. a
+// downstream handler panicking mid-stream), computed as:
+//
+//	delay = min(MaxDelay, BaseDelay * Multiplier^retries) * (1 ± rand*Jitter)
+//
+// A nil *BackoffConfig, or a zero BaseDelay, disables backoff entirely.
+type BackoffConfig struct {
+	BaseDelay  time.Duration // delay applied after the first consecutive failure
+	MaxDelay   time.Duration // upper bound on the computed delay; <= 0 for no cap
+	Multiplier float64       // exponential growth factor per retry (e.g. 1.6); <= 0 treated as 1 (constant delay)
+	Jitter     float64       // randomization applied to the computed delay, 0.0-1.0 (e.g. 0.2)
+}
+
+// delay returns the backoff delay for the given (zero-based) number of
+// consecutive failures seen so far.
+func (b *BackoffConfig) delay(retries int) time.Duration {
+	if b == nil || b.BaseDelay <= 0 {
+		return 0
+	}
+
+	multiplier := b.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+
+	d := float64(b.BaseDelay) * math.Pow(multiplier, float64(retries))
+	if b.MaxDelay > 0 && d > float64(b.MaxDelay) {
+		d = float64(b.MaxDelay)
+	}
+
+	if b.Jitter > 0 {
+		d *= 1 + (rand.Float64()*2-1)*b.Jitter
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	return time.Duration(d)
+}
+
 // Processing options specific to the external processor.
 type ProcessingOptions struct {
-	LogStream                 bool   // Log "stream" events, i.e. Process calls
-	LogPhases                 bool   // Log "phase" events, i.e. specific stream messages. Unsafe for production, prints all data.
-	UpdateExtProcHeader       bool   // Update a `x-extproc-names` header with the extproc name
-	UpdateDurationHeader      bool   // Update a `x-extproc-duration-ns` header with extproc duration (not request duration)
-	RequestIdHeaderName       string // Header name to use for request ID's
-	RequestIdFallback         string // Fallback value for a request id that does not exist (default empty string)
-	BufferStreamedBodies      bool   // Whether to buffer request/response bodies internally, instead of in envoy
-	PerRequestBodyBufferBytes int64  // Maximum allowed size of body buffers, ignored if not buffering (-1 for no limit); cast to a uint32
-	DecompressBodies          bool   // Flag to denote if the SDK itself should decompress bodies for processing, if possible and applicable
+	LogStream                      bool               // Log "stream" events, i.e. Process calls
+	LogPhases                      bool               // Log "phase" events, i.e. specific stream messages. Unsafe for production, prints all data.
+	UpdateExtProcHeader            bool               // Update a `x-extproc-names` header with the extproc name
+	UpdateDurationHeader           bool               // Update a `x-extproc-duration-ns` header with extproc duration (not request duration)
+	RequestIdHeaderName            string             // Header name to use for request ID's
+	RequestIdFallback              string             // Fallback value for a request id that does not exist (default empty string)
+	BufferStreamedBodies           bool               // Whether to buffer request/response bodies internally, instead of in envoy
+	PerRequestBodyBufferBytes      int64              // Maximum allowed size of body buffers, ignored if not buffering (-1 for no limit); cast to a uint32
+	MaxBufferedRequestBodyBytes    int64              // Maximum buffered request body size, ignored if not buffering (-1 or 0 for no limit); overflow handled per OnBodyOverflow
+	MaxBufferedResponseBodyBytes   int64              // Maximum buffered response body size, ignored if not buffering (-1 or 0 for no limit); overflow handled per OnBodyOverflow
+	MaxConcurrentBufferedBodyBytes int64              // Maximum bytes buffered at once across every stream sharing this ProcessingOptions (-1 or 0 for no limit), so the server can shed load under memory pressure
+	OnBodyOverflow                 BodyOverflowPolicy // Policy applied when a buffered body would exceed its limit; see BodyOverflowPolicy
+	OverflowResponseStatus         int32              // HTTP status used by OnBodyOverflowImmediateResponse (defaults to 413 if unset)
+	DecompressBodies               bool               // Flag to denote if the SDK itself should decompress bodies for processing, if possible and applicable
+	RecompressBodies               bool               // Whether PreProcessor/PostProcessor should re-compress a mutated body before forwarding it, negotiated against the peer's Accept-Encoding (see PreferredEncodings)
+	PreferredEncodings             []string           // Content-Encodings tried, in order, before falling back to the peer's own preference, when RecompressBodies negotiates a codec; e.g. []string{"zstd", "gzip"} to prefer zstd when the peer accepts it
+	ProcessingMode                 BodyProcessingMode // "buffered" (default) or "streamed"; see BodyProcessingMode
+	StreamHoldWatermark            int64              // Maximum bytes a StreamParseHandler may accumulate via RequestContext.HoldBodyChunk before it must yield (-1 for no limit)
+	Backoff                        *BackoffConfig     // delay applied between retries after consecutive stream phase-processing failures; nil disables backoff
+	MaxStreamLifetime              time.Duration      // proactively end a stream (envoy reconnects) once open this long, jittered by Backoff.Jitter to avoid herd reconnects; <= 0 disables
+	Observer                       PhaseObserver      // optional hook reporting per-phase latency/outcome and active-stream counts to an external metrics/tracing system; nil disables reporting
+
+	// globalBufferedBytes tracks bytes currently buffered across every
+	// RequestContext sharing this ProcessingOptions, enforcing
+	// MaxConcurrentBufferedBodyBytes. Unexported: not part of the public
+	// config surface, never populated from YAML attributes.
+	globalBufferedBytes atomic.Int64
+
+	// healthServer is the HealthServer registered alongside the
+	// ExternalProcessor service by ServeWithOptions/Attach, if any; it backs
+	// RequestContext.RegisterHealthDependency. Unexported: assigned by this
+	// package when the gRPC health service is wired up, never by callers.
+	healthServer *HealthServer
 }
 
 // Return default options, as not all the zero values are "correct".
 func NewDefaultOptions() *ProcessingOptions {
 	return &ProcessingOptions{
-		RequestIdHeaderName:  "x-request-id",
-		DecompressBodies:     true,
-		BufferStreamedBodies: false,
-		UpdateExtProcHeader:  false,
-		UpdateDurationHeader: false,
+		RequestIdHeaderName:            "x-request-id",
+		DecompressBodies:               true,
+		BufferStreamedBodies:           false,
+		UpdateExtProcHeader:            false,
+		UpdateDurationHeader:           false,
+		ProcessingMode:                 ProcessingModeBuffered,
+		StreamHoldWatermark:            -1,
+		MaxBufferedRequestBodyBytes:    -1,
+		MaxBufferedResponseBodyBytes:   -1,
+		MaxConcurrentBufferedBodyBytes: -1,
+		OnBodyOverflow:                 OnBodyOverflowTruncate,
 	}
 }
@@ -2,6 +2,7 @@ package extproc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -50,3 +51,40 @@ func TestOptions(t *testing.T) {
 		assert.False(t, options.DecompressBodies)
 	})
 }
+
+func TestBackoffConfigDelay(t *testing.T) {
+	t.Run("nil config disables backoff", func(t *testing.T) {
+		var b *BackoffConfig
+		assert.Equal(t, time.Duration(0), b.delay(3))
+	})
+
+	t.Run("zero BaseDelay disables backoff", func(t *testing.T) {
+		b := &BackoffConfig{Multiplier: 1.6}
+		assert.Equal(t, time.Duration(0), b.delay(3))
+	})
+
+	t.Run("grows exponentially and caps at MaxDelay", func(t *testing.T) {
+		b := &BackoffConfig{
+			BaseDelay:  100 * time.Millisecond,
+			MaxDelay:   1 * time.Second,
+			Multiplier: 2,
+		}
+		assert.Equal(t, 100*time.Millisecond, b.delay(0))
+		assert.Equal(t, 200*time.Millisecond, b.delay(1))
+		assert.Equal(t, 400*time.Millisecond, b.delay(2))
+		assert.Equal(t, 1*time.Second, b.delay(10)) // capped
+	})
+
+	t.Run("jitter stays within the expected range", func(t *testing.T) {
+		b := &BackoffConfig{
+			BaseDelay:  1 * time.Second,
+			Multiplier: 1,
+			Jitter:     0.2,
+		}
+		for i := 0; i < 20; i++ {
+			d := b.delay(0)
+			assert.GreaterOrEqual(t, d, 800*time.Millisecond)
+			assert.LessOrEqual(t, d, 1200*time.Millisecond)
+		}
+	})
+}
@@ -7,24 +7,51 @@ package extproc
 
 import (
 	"log"
+	"strings"
 )
 
 /**
  * PostProcessor pattern:
  * -  modifies response body or leave it unchanged
  * -  in case of error, logs it and leaves body unchanged
+ * -  if GetOptions().ProcessingMode is "streamed" and Handler implements
+ *    StreamingHandler, chunks are rewritten/dropped as they arrive instead
+ *    of waiting for the full body (see ProcessResponseBody)
+ * -  if GetOptions().ProcessingMode is "auto", the same streaming path is
+ *    used, but only for responses whose Content-Type looks like a
+ *    streaming protocol (see isStreamingContentType)
 **/
 type PostProcessor struct {
 	AbstractProcessor
 }
 
-func (s *PostProcessor) processResponse(ctx *RequestContext, body []byte) ([]byte, error) {
+// processResponse hands body to the configured Handler and, if
+// ProcessingOptions.RecompressBodies is set, re-compresses the (possibly
+// mutated) result before it is forwarded to the client. The returned
+// string is the Content-Encoding it was re-compressed as, or "" if left
+// as-is. Negotiation uses the original request's Accept-Encoding (see
+// RequestContext.requestAcceptEncoding), since AllHeaders has already been
+// overwritten with the response's own headers by this phase.
+func (s *PostProcessor) processResponse(ctx *RequestContext, body []byte) ([]byte, string, error) {
 	res, err := s.Handler.HandleEvent(ctx, body)
 	if err != nil {
-		return body, err
+		return body, "", err
 	}
-	return res.Body, nil
 
+	out := res.Body
+	var encoding string
+	if opts := s.GetOptions(); opts != nil {
+		encoded, chosen, ok, rErr := recompressBody(ctx, out, opts, ctx.requestAcceptEncoding)
+		if rErr != nil {
+			log.Printf("Error recompressing response body: %v", rErr)
+		} else if ok {
+			out, encoding = encoded, chosen
+			ctx.OverwriteHeader("content-encoding", HeaderValue{Value: encoding})
+			ctx.AppendHeader("vary", HeaderValue{Value: "Accept-Encoding"})
+		}
+	}
+
+	return out, encoding, nil
 }
 
 func (s *PostProcessor) GetName() string {
@@ -36,7 +63,22 @@ func (s *PostProcessor) ProcessResponseHeaders(ctx *RequestContext, headers AllH
 }
 
 func (s *PostProcessor) ProcessResponseBody(ctx *RequestContext, body []byte) error {
-	processed, err := s.processResponse(ctx, body)
+	if opts := s.GetOptions(); opts != nil {
+		switch opts.ProcessingMode {
+		case ProcessingModeStreamed:
+			if streamHandler, ok := s.Handler.(StreamingHandler); ok {
+				return s.processStreamedResponse(ctx, streamHandler, body)
+			}
+			log.Printf("ProcessingMode is \"streamed\" but Handler does not implement StreamingHandler, falling back to buffered")
+
+		case ProcessingModeAuto:
+			if streamHandler, ok := s.Handler.(StreamingHandler); ok && isStreamingContentType(ctx.bodybuffer) {
+				return s.processStreamedResponse(ctx, streamHandler, body)
+			}
+		}
+	}
+
+	processed, _, err := s.processResponse(ctx, body)
 	if err != nil {
 		log.Printf("Error: %v", err)
 	} else {
@@ -44,3 +86,36 @@ func (s *PostProcessor) ProcessResponseBody(ctx *RequestContext, body []byte) er
 	}
 	return ctx.ContinueRequest()
 }
+
+// isStreamingContentType reports whether buffer's Content-Type names a
+// streaming protocol (SSE's text/event-stream, or application/grpc and its
+// +proto/+json variants) that ProcessingModeAuto should stream through
+// OnResponseChunk rather than buffer. buffer may be nil, e.g. if response
+// headers never carried a Content-Type.
+func isStreamingContentType(buffer *EncodedBody) bool {
+	if buffer == nil {
+		return false
+	}
+
+	contentType, _, _ := strings.Cut(buffer.Type.ContentType, ";")
+	contentType = strings.ToLower(strings.TrimSpace(contentType))
+
+	return contentType == "text/event-stream" || strings.HasPrefix(contentType, "application/grpc")
+}
+
+// processStreamedResponse hands a single response body chunk to a
+// StreamingHandler and forwards whatever it returns: a nil chunk drops it,
+// otherwise it replaces it. A handler error is logged and the original
+// chunk is forwarded unchanged, matching ProcessResponseBody's "leave body
+// unchanged" behavior on error.
+func (s *PostProcessor) processStreamedResponse(ctx *RequestContext, handler StreamingHandler, chunk []byte) error {
+	out, err := handler.OnResponseChunk(ctx, chunk, ctx.EndOfStream)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		ctx.ReplaceStreamedBodyChunk(chunk)
+		return ctx.ContinueRequest()
+	}
+
+	ctx.ReplaceStreamedBodyChunk(out)
+	return ctx.ContinueRequest()
+}
@@ -1,7 +1,9 @@
 package extproc
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,6 +26,34 @@ func (h *mockHandler) HandleEvent(ctx *RequestContext, body []byte) (*EventRespo
 	}, nil
 }
 
+// mockStreamingHandler drops chunks equal to dropChunk (if set) and otherwise
+// wraps every chunk as an SSE "data: ...\n\n" frame, using ctx.StreamScratch
+// to count the chunks it has seen so far.
+type mockStreamingHandler struct {
+	dropChunk []byte
+	err       error
+}
+
+func (h *mockStreamingHandler) OnResponseChunk(ctx *RequestContext, chunk []byte, endOfStream bool) ([]byte, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	if h.dropChunk != nil && bytes.Equal(chunk, h.dropChunk) {
+		return nil, nil
+	}
+
+	seen, _ := ctx.StreamScratch.(int)
+	ctx.StreamScratch = seen + 1
+
+	return []byte(fmt.Sprintf("data: %s\n\n", chunk)), nil
+}
+
+// HandleEvent is unused by these tests; StreamingHandler must also satisfy
+// EventHandler since AbstractProcessor.Handler is typed as one.
+func (h *mockStreamingHandler) HandleEvent(ctx *RequestContext, body []byte) (*EventResponse, error) {
+	return nil, errors.New("HandleEvent not implemented by mockStreamingHandler")
+}
+
 func TestPostprocessor(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -98,3 +128,106 @@ func TestPostprocessor(t *testing.T) {
 		})
 	}
 }
+
+func TestPostprocessorStreamed(t *testing.T) {
+	newStreamedProcessor := func(handler EventHandler) *PostProcessor {
+		processor := &PostProcessor{}
+		processor.Init(&ProcessingOptions{ProcessingMode: ProcessingModeStreamed}, nil, handler)
+		return processor
+	}
+
+	t.Run("multiple chunks rewritten in place", func(t *testing.T) {
+		processor := newStreamedProcessor(&mockStreamingHandler{})
+		ctx := &RequestContext{AllHeaders: &AllHeaders{}}
+
+		chunks := [][]byte{[]byte("first"), []byte("second")}
+		for i, chunk := range chunks {
+			ctx.EndOfStream = i == len(chunks)-1
+			require.NoError(t, processor.ProcessResponseBody(ctx, chunk))
+			assert.Equal(t, []byte(fmt.Sprintf("data: %s\n\n", chunk)), ctx.response.bodyMutation.GetBody())
+		}
+		assert.Equal(t, len(chunks), ctx.StreamScratch)
+	})
+
+	t.Run("handler drops a middle chunk", func(t *testing.T) {
+		processor := newStreamedProcessor(&mockStreamingHandler{dropChunk: []byte("drop me")})
+		ctx := &RequestContext{AllHeaders: &AllHeaders{}}
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("keep me")))
+		assert.NotEmpty(t, ctx.response.bodyMutation.GetBody())
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("drop me")))
+		assert.Empty(t, ctx.response.bodyMutation.GetBody())
+
+		ctx.EndOfStream = true
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("keep me too")))
+		assert.NotEmpty(t, ctx.response.bodyMutation.GetBody())
+	})
+
+	t.Run("handler error forwards the original chunk", func(t *testing.T) {
+		processor := newStreamedProcessor(&mockStreamingHandler{err: errors.New("boom")})
+		ctx := &RequestContext{AllHeaders: &AllHeaders{}}
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("original")))
+		assert.Equal(t, []byte("original"), ctx.response.bodyMutation.GetBody())
+	})
+
+	t.Run("falls back to buffered when Handler is not a StreamingHandler", func(t *testing.T) {
+		processor := newStreamedProcessor(&mockHandler{response: []byte("processed")})
+		ctx := &RequestContext{AllHeaders: &AllHeaders{}}
+		ctx.ResetPhase()
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("original")))
+		assert.Equal(t, []byte("processed"), ctx.response.bodyMutation.GetBody())
+	})
+}
+
+func TestPostprocessorAuto(t *testing.T) {
+	newAutoProcessor := func(handler EventHandler) *PostProcessor {
+		processor := &PostProcessor{}
+		processor.Init(&ProcessingOptions{ProcessingMode: ProcessingModeAuto}, nil, handler)
+		return processor
+	}
+
+	ctxWithContentType := func(contentType string) *RequestContext {
+		ctx := &RequestContext{
+			AllHeaders: &AllHeaders{},
+			bodybuffer: &EncodedBody{Type: BodyType{ContentType: contentType}},
+		}
+		ctx.ResetPhase()
+		ctx.EndOfStream = true
+		return ctx
+	}
+
+	t.Run("streams an SSE response", func(t *testing.T) {
+		processor := newAutoProcessor(&mockStreamingHandler{})
+		ctx := ctxWithContentType("text/event-stream; charset=utf-8")
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("chunk")))
+		assert.Equal(t, []byte("data: chunk\n\n"), ctx.response.bodyMutation.GetBody())
+	})
+
+	t.Run("streams a gRPC response", func(t *testing.T) {
+		processor := newAutoProcessor(&mockStreamingHandler{})
+		ctx := ctxWithContentType("application/grpc+proto")
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("chunk")))
+		assert.Equal(t, []byte("data: chunk\n\n"), ctx.response.bodyMutation.GetBody())
+	})
+
+	t.Run("buffers a response whose content type isn't streaming", func(t *testing.T) {
+		processor := newAutoProcessor(&mockHandler{response: []byte("processed")})
+		ctx := ctxWithContentType("application/json")
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("original")))
+		assert.Equal(t, []byte("processed"), ctx.response.bodyMutation.GetBody())
+	})
+
+	t.Run("buffers when Handler is not a StreamingHandler despite a streaming content type", func(t *testing.T) {
+		processor := newAutoProcessor(&mockHandler{response: []byte("processed")})
+		ctx := ctxWithContentType("text/event-stream")
+
+		require.NoError(t, processor.ProcessResponseBody(ctx, []byte("original")))
+		assert.Equal(t, []byte("processed"), ctx.response.bodyMutation.GetBody())
+	})
+}
@@ -18,13 +18,31 @@ type PreProcessor struct {
 	AbstractProcessor
 }
 
-func (s *PreProcessor) processRequest(ctx *RequestContext, body []byte) ([]byte, error) {
+// processRequest hands body to the configured Handler and, if
+// ProcessingOptions.RecompressBodies is set, re-compresses the (possibly
+// mutated) result before it is forwarded upstream. The returned string is
+// the Content-Encoding it was re-compressed as, or "" if left as-is.
+func (s *PreProcessor) processRequest(ctx *RequestContext, body []byte) ([]byte, string, error) {
 	res, err := s.Handler.HandleEvent(ctx, body)
 	if err != nil {
-		return body, err
+		return body, "", err
 	}
-	return res.Body, nil
 
+	out := res.Body
+	var encoding string
+	if opts := s.GetOptions(); opts != nil {
+		acceptEncoding, _ := ctx.AllHeaders.GetHeaderValueAsString("accept-encoding")
+		encoded, chosen, ok, rErr := recompressBody(ctx, out, opts, acceptEncoding)
+		if rErr != nil {
+			log.Printf("Error recompressing request body: %v", rErr)
+		} else if ok {
+			out, encoding = encoded, chosen
+			ctx.OverwriteHeader("content-encoding", HeaderValue{Value: encoding})
+			ctx.AppendHeader("vary", HeaderValue{Value: "Accept-Encoding"})
+		}
+	}
+
+	return out, encoding, nil
 }
 
 func (s *PreProcessor) GetName() string {
@@ -32,8 +50,10 @@ func (s *PreProcessor) GetName() string {
 }
 
 func (s *PreProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHeaders) error {
+	advertiseAcceptEncoding(ctx, s.GetOptions(), &headers)
+
 	if !ctx.HasBody() {
-		_, err := s.processRequest(ctx, nil)
+		_, _, err := s.processRequest(ctx, nil)
 		if err != nil {
 			log.Printf("Error: %v", err)
 		}
@@ -42,8 +62,26 @@ func (s *PreProcessor) ProcessRequestHeaders(ctx *RequestContext, headers AllHea
 	return ctx.ContinueRequest()
 }
 
+// dispatchGRPCMessages hands any complete gRPC messages available in this
+// body call (see grpcMessagesFromBodyCall) to s.Handler, when it implements
+// GRPCMessageHandler and ctx's body is gRPC-framed (see IsGRPCContentType).
+// A no-op otherwise.
+func (s *PreProcessor) dispatchGRPCMessages(ctx *RequestContext, body []byte) {
+	grpcHandler, ok := s.Handler.(GRPCMessageHandler)
+	if !ok || ctx.bodybuffer == nil || !IsGRPCContentType(ctx.bodybuffer.Type.ContentType) {
+		return
+	}
+	for _, msg := range grpcMessagesFromBodyCall(ctx, body, false) {
+		if err := grpcHandler.ProcessGRPCMessage(ctx, msg); err != nil {
+			log.Printf("Error processing gRPC message: %v", err)
+		}
+	}
+}
+
 func (s *PreProcessor) ProcessRequestBody(ctx *RequestContext, body []byte) error {
-	processed, err := s.processRequest(ctx, body)
+	s.dispatchGRPCMessages(ctx, body)
+
+	processed, _, err := s.processRequest(ctx, body)
 	if err != nil {
 		log.Printf("Error: %v", err)
 	} else {
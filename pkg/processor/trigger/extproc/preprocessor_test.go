@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock EventHandler for testing
@@ -105,6 +106,62 @@ func TestPreProcessor_ProcessRequestBody(t *testing.T) {
 	}
 }
 
+// grpcEventHandler is a MockEventHandler that also implements
+// GRPCMessageHandler, to exercise PreProcessor/ObserveProcessor's optional
+// per-message dispatch.
+type grpcEventHandler struct {
+	MockEventHandler
+	messages []GRPCMessage
+}
+
+func (h *grpcEventHandler) ProcessGRPCMessage(ctx *RequestContext, msg GRPCMessage) error {
+	h.messages = append(h.messages, msg)
+	return nil
+}
+
+func TestPreProcessor_ProcessRequestBody_DispatchesGRPCMessages(t *testing.T) {
+	mockHandler := &grpcEventHandler{}
+	processor := &PreProcessor{
+		AbstractProcessor: AbstractProcessor{Handler: mockHandler},
+	}
+
+	body := EncodeGRPCMessage(GRPCMessage{Payload: []byte("ping")})
+
+	ctx := &RequestContext{
+		bodybuffer: &EncodedBody{Type: BodyType{ContentType: "application/grpc"}},
+		extProcOptions: &ProcessingOptions{
+			BufferStreamedBodies: false,
+		},
+	}
+	ctx.ResetPhase()
+
+	mockHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Body: []byte("ack")}, nil).Once()
+
+	err := processor.ProcessRequestBody(ctx, body)
+	assert.NoError(t, err)
+	require.Len(t, mockHandler.messages, 1)
+	assert.Equal(t, []byte("ping"), mockHandler.messages[0].Payload)
+}
+
+func TestPreProcessor_ProcessRequestBody_NonGRPCSkipsDispatch(t *testing.T) {
+	mockHandler := &grpcEventHandler{}
+	processor := &PreProcessor{
+		AbstractProcessor: AbstractProcessor{Handler: mockHandler},
+	}
+
+	body := []byte("plain body")
+	ctx := &RequestContext{
+		bodybuffer: &EncodedBody{Type: BodyType{ContentType: "application/json"}},
+	}
+	ctx.ResetPhase()
+
+	mockHandler.On("HandleEvent", ctx, body).Return(&EventResponse{Body: body}, nil).Once()
+
+	err := processor.ProcessRequestBody(ctx, body)
+	assert.NoError(t, err)
+	assert.Empty(t, mockHandler.messages)
+}
+
 func TestPreProcessor_ProcessRequest(t *testing.T) {
 	mockHandler := new(MockEventHandler)
 	processor := &PreProcessor{
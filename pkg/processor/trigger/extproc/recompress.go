@@ -0,0 +1,210 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extproc
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// encoderFactory wraps w with a compressing io.WriteCloser for one
+// Content-Encoding token (e.g. "gzip", "br"); Close must flush any
+// buffered output.
+type encoderFactory func(io.Writer) (io.WriteCloser, error)
+
+var (
+	encoderRegistryMu sync.RWMutex
+	encoderRegistry   = map[string]encoderFactory{}
+)
+
+func init() {
+	RegisterEncoder("gzip", func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	})
+	RegisterEncoder("deflate", func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.DefaultCompression)
+	})
+	RegisterEncoder("br", func(w io.Writer) (io.WriteCloser, error) {
+		return brotli.NewWriter(w), nil
+	})
+	RegisterEncoder("zstd", func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w)
+	})
+}
+
+// RegisterEncoder registers factory to compress bodies for Content-Encoding
+// name, e.g. "gzip" or "zstd". Registering an already-registered name
+// replaces it. Safe for concurrent use; intended both for this package's
+// built-in codecs and for SDK users adding custom ones at runtime; see
+// RegisterDecoder for the corresponding decompression side.
+func RegisterEncoder(name string, factory encoderFactory) {
+	encoderRegistryMu.Lock()
+	defer encoderRegistryMu.Unlock()
+	encoderRegistry[name] = factory
+}
+
+func encoderFor(name string) (encoderFactory, bool) {
+	encoderRegistryMu.RLock()
+	defer encoderRegistryMu.RUnlock()
+	factory, ok := encoderRegistry[name]
+	return factory, ok
+}
+
+// bufferPool reduces per-request allocation when recompressing bodies.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// encodeWith compresses data through a writer built by factory, using a
+// pooled buffer to accumulate its output.
+func encodeWith(factory encoderFactory, data []byte) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufferPool.Put(buf)
+
+	writer, err := factory(buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out, nil
+}
+
+// acceptedEncoding is one parsed token of an Accept-Encoding header.
+type acceptedEncoding struct {
+	name string
+	q    float64
+}
+
+// parseAcceptEncoding parses a comma-separated Accept-Encoding header value
+// (e.g. "gzip;q=0.8, br, *;q=0.1") into its tokens, defaulting an omitted
+// q-value to 1.0.
+func parseAcceptEncoding(value string) []acceptedEncoding {
+	var tokens []acceptedEncoding
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, params, hasParams := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		q := 1.0
+		if hasParams {
+			if qv, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+				if parsed, err := strconv.ParseFloat(qv, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		tokens = append(tokens, acceptedEncoding{name: name, q: q})
+	}
+	return tokens
+}
+
+// negotiateEncoding picks the Content-Encoding a mutated body should be
+// re-compressed as. preferred is tried first, in order: the first entry
+// both registered (see RegisterEncoder) and acceptable per acceptEncoding
+// wins. Failing that, the peer's own highest-quality accepted encoding with
+// a registered encoder is used. Failing that, fallback (typically the
+// body's originally-seen encoding, see RequestContext.GetBodyType) is used
+// if it is both registered and acceptable. Returns "" if recompression
+// should be skipped, e.g. the peer only accepts "identity" or rejects every
+// encoding this package can produce.
+func negotiateEncoding(acceptEncoding string, preferred []string, fallback string) string {
+	tokens := parseAcceptEncoding(acceptEncoding)
+
+	acceptable := func(name string) bool {
+		if len(tokens) == 0 {
+			return true // no Accept-Encoding sent: any registered encoding is fine
+		}
+		wildcardQ, sawWildcard := -1.0, false
+		for _, t := range tokens {
+			if t.name == name {
+				return t.q > 0
+			}
+			if t.name == "*" {
+				wildcardQ, sawWildcard = t.q, true
+			}
+		}
+		return sawWildcard && wildcardQ > 0
+	}
+
+	for _, name := range preferred {
+		if _, ok := encoderFor(name); ok && acceptable(name) {
+			return name
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, t := range tokens {
+		if t.name == "*" || t.q <= bestQ {
+			continue
+		}
+		if _, ok := encoderFor(t.name); ok {
+			best, bestQ = t.name, t.q
+		}
+	}
+	if best != "" {
+		return best
+	}
+
+	if fallback != "" && fallback != "identity" && acceptable(fallback) {
+		if _, ok := encoderFor(fallback); ok {
+			return fallback
+		}
+	}
+
+	return ""
+}
+
+// recompressBody re-compresses body per opts.RecompressBodies, negotiating
+// a Content-Encoding against acceptEncoding and opts.PreferredEncodings (see
+// negotiateEncoding), falling back to ctx's originally-declared encoding.
+// ok is false, and body is returned unchanged, when recompression is
+// disabled, the body is empty, or negotiation found no usable encoding.
+func recompressBody(ctx *RequestContext, body []byte, opts *ProcessingOptions, acceptEncoding string) (data []byte, encoding string, ok bool, err error) {
+	if opts == nil || !opts.RecompressBodies || len(body) == 0 {
+		return body, "", false, nil
+	}
+
+	encoding = negotiateEncoding(acceptEncoding, opts.PreferredEncodings, ctx.GetBodyType().ContentEncoding)
+	if encoding == "" {
+		return body, "", false, nil
+	}
+
+	factory, ok := encoderFor(encoding)
+	if !ok {
+		return body, "", false, nil
+	}
+
+	encoded, err := encodeWith(factory, body)
+	if err != nil {
+		return body, "", false, fmt.Errorf("%s compression failed: %w", encoding, err)
+	}
+
+	return encoded, encoding, true, nil
+}
@@ -0,0 +1,142 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extproc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		preferred      []string
+		fallback       string
+		expected       string
+	}{
+		{
+			name:           "no accept-encoding honors preferred order",
+			acceptEncoding: "",
+			preferred:      []string{"zstd", "gzip"},
+			fallback:       "gzip",
+			expected:       "zstd",
+		},
+		{
+			name:           "preferred codec rejected by peer falls through to peer's choice",
+			acceptEncoding: "gzip;q=0.5, zstd;q=0.9",
+			preferred:      []string{"br"},
+			fallback:       "gzip",
+			expected:       "zstd",
+		},
+		{
+			name:           "picks the peer's highest quality supported codec",
+			acceptEncoding: "br;q=0.5, zstd;q=0.9, gzip;q=1.0",
+			preferred:      nil,
+			fallback:       "gzip",
+			expected:       "gzip",
+		},
+		{
+			name:           "falls back to original encoding when peer accepts it",
+			acceptEncoding: "gzip",
+			preferred:      nil,
+			fallback:       "gzip",
+			expected:       "gzip",
+		},
+		{
+			name:           "peer only accepts identity disables recompression",
+			acceptEncoding: "identity",
+			preferred:      []string{"zstd"},
+			fallback:       "gzip",
+			expected:       "",
+		},
+		{
+			name:           "wildcard accepts any registered codec",
+			acceptEncoding: "*;q=0.3",
+			preferred:      []string{"zstd"},
+			fallback:       "gzip",
+			expected:       "zstd",
+		},
+		{
+			name:           "unregistered preferred codec is skipped",
+			acceptEncoding: "",
+			preferred:      []string{"x-unknown", "gzip"},
+			fallback:       "br",
+			expected:       "gzip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateEncoding(tt.acceptEncoding, tt.preferred, tt.fallback))
+		})
+	}
+}
+
+func TestRecompressBody(t *testing.T) {
+	ctx := &RequestContext{bodybuffer: &EncodedBody{Type: BodyType{ContentEncoding: "gzip"}}}
+
+	t.Run("disabled is a no-op", func(t *testing.T) {
+		data, encoding, ok, err := recompressBody(ctx, []byte("hello"), &ProcessingOptions{}, "")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, encoding)
+		assert.Equal(t, []byte("hello"), data)
+	})
+
+	t.Run("empty body is a no-op", func(t *testing.T) {
+		data, _, ok, err := recompressBody(ctx, nil, &ProcessingOptions{RecompressBodies: true}, "")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Nil(t, data)
+	})
+
+	t.Run("recompresses to the preferred codec and decodes back cleanly", func(t *testing.T) {
+		opts := &ProcessingOptions{RecompressBodies: true, PreferredEncodings: []string{"zstd"}}
+		data, encoding, ok, err := recompressBody(ctx, []byte("hello world"), opts, "")
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, "zstd", encoding)
+
+		body := &EncodedBody{Type: BodyType{ContentEncoding: encoding}, Value: data, Complete: true}
+		require.NoError(t, body.DecompressBody())
+		assert.Equal(t, []byte("hello world"), body.Value)
+	})
+}
+
+// TestPreProcessor_ProcessRequest_RecompressesMutatedBody is the chunk7-2
+// round-trip: a request arrives gzip-encoded, PreProcessor's Handler mutates
+// the (already decompressed) body, and the result is re-compressed as zstd
+// per PreferredEncodings.
+func TestPreProcessor_ProcessRequest_RecompressesMutatedBody(t *testing.T) {
+	mockHandler := new(MockEventHandler)
+	processor := &PreProcessor{AbstractProcessor: AbstractProcessor{Handler: mockHandler}}
+	processor.Init(&ProcessingOptions{RecompressBodies: true, PreferredEncodings: []string{"zstd"}}, nil, mockHandler)
+
+	headers := &AllHeaders{Headers: map[string]string{}, RawHeaders: map[string][]byte{}}
+	ctx := &RequestContext{
+		AllHeaders:     headers,
+		extProcOptions: &ProcessingOptions{RequestIdHeaderName: "x-request-id"},
+		bodybuffer:     &EncodedBody{Type: BodyType{ContentEncoding: "gzip"}},
+	}
+	ctx.ResetPhase()
+
+	mockHandler.On("HandleEvent", ctx, []byte("decompressed gzip body")).
+		Return(&EventResponse{Body: []byte("mutated body")}, nil).Once()
+
+	out, encoding, err := processor.processRequest(ctx, []byte("decompressed gzip body"))
+	require.NoError(t, err)
+	assert.Equal(t, "zstd", encoding)
+
+	body := &EncodedBody{Type: BodyType{ContentEncoding: encoding}, Value: out, Complete: true}
+	require.NoError(t, body.DecompressBody())
+	assert.Equal(t, []byte("mutated body"), body.Value)
+
+	mockHandler.AssertExpectations(t)
+}
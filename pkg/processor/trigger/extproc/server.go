@@ -6,8 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 package extproc
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
@@ -15,16 +18,73 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	epb "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
 	hpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
+
+	"github.com/nuclio/errors"
 )
 
+var errInvalidClientCA = errors.New("Failed to parse TLS client CA file")
+
+// tlsCipherSuiteByName maps the cipher suite names accepted in
+// TLSConfiguration.CipherSuites/ExtProcServerOptions.TLSCipherSuites to
+// their tls package IDs, built from tls.CipherSuites() so the accepted
+// names always match what this Go runtime actually supports.
+var tlsCipherSuiteByName = func() map[string]uint16 {
+	suites := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	return suites
+}()
+
+// tlsVersionByName maps TLSConfiguration.MinVersion's accepted values to
+// their tls package constants.
+var tlsVersionByName = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 // Supported gRPC service options in the Serve* helpers.
 type ExtProcServerOptions struct {
 	GracefulShutdownTimeout int
 	MaxConcurrentStreams    uint32
+
+	// TLSCertFile/TLSKeyFile, when both set, make the gRPC listener serve
+	// TLS instead of plaintext. TLSClientCAFile additionally enables mTLS;
+	// TLSClientAuth selects how strictly client certificates are checked
+	// (see TLSClientAuthType), defaulting to "verify" when TLSClientCAFile
+	// is set and "none" otherwise. The cert/key files are watched with
+	// fsnotify and reloaded on change, and on SIGHUP, without restarting
+	// the listener.
+	TLSCertFile      string
+	TLSKeyFile       string
+	TLSClientCAFile  string
+	TLSClientAuth    TLSClientAuthType
+	TLSMinVersion    string
+	TLSCipherSuites  []string
+	TLSALPNProtocols []string
+
+	// TLSCertReloadInterval, when > 0, makes the cert/key reloader also
+	// reload on this fixed cadence in addition to its fsnotify watch and
+	// SIGHUP handling, as a fallback for filesystems where fsnotify doesn't
+	// reliably observe a mounted secret's replacement.
+	TLSCertReloadInterval time.Duration
+
+	// AdminAddr, when set, serves a plain HTTP admin mux (currently
+	// /healthz, backed by the same HealthServer as the gRPC health
+	// service) on a separate listener multiplexed alongside the gRPC
+	// server's lifecycle.
+	AdminAddr string
+
+	// HealthCheckInterval controls how often a processor's CheckLiveness/
+	// CheckReadiness (see HealthReporter) are re-evaluated, when processor
+	// implements that interface. Defaults to defaultDependencyPollInterval
+	// if <= 0.
+	HealthCheckInterval time.Duration
 }
 
 // Default gRPC service options in the Serve* helpers.
@@ -35,6 +95,98 @@ func DefaultServerOptions() ExtProcServerOptions {
 	}
 }
 
+// buildTLSCredentials constructs server-side transport credentials and a
+// cert reloader from serverOpts, or returns (nil, nil, nil) if TLS is not
+// configured. The returned *tlsCertReloader is nil whenever credentials
+// are nil; otherwise the caller should call its watch method to pick up
+// cert/key rotation for the lifetime of the server.
+func buildTLSCredentials(serverOpts ExtProcServerOptions) (credentials.TransportCredentials, *tlsCertReloader, error) {
+	if serverOpts.TLSCertFile == "" {
+		return nil, nil, nil
+	}
+
+	reloader, err := newTLSCertReloader(serverOpts.TLSCertFile, serverOpts.TLSKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{GetCertificate: reloader.GetCertificate}
+
+	clientAuth := serverOpts.TLSClientAuth
+	if serverOpts.TLSClientCAFile != "" {
+		caBytes, err := os.ReadFile(serverOpts.TLSClientCAFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, nil, errInvalidClientCA
+		}
+		tlsConfig.ClientCAs = pool
+		if clientAuth == "" {
+			clientAuth = TLSClientAuthVerify
+		}
+	}
+
+	switch clientAuth {
+	case "", TLSClientAuthNone:
+		tlsConfig.ClientAuth = tls.NoClientCert
+	case TLSClientAuthRequest:
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	case TLSClientAuthRequire:
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	case TLSClientAuthVerify:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, nil, errors.Errorf("unsupported TLSClientAuth: %s", clientAuth)
+	}
+
+	if serverOpts.TLSMinVersion != "" {
+		version, ok := tlsVersionByName[serverOpts.TLSMinVersion]
+		if !ok {
+			return nil, nil, errors.Errorf("unsupported TLSMinVersion: %s", serverOpts.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	for _, name := range serverOpts.TLSCipherSuites {
+		id, ok := tlsCipherSuiteByName[name]
+		if !ok {
+			return nil, nil, errors.Errorf("unsupported TLSCipherSuites entry: %s", name)
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+
+	alpnProtocols := serverOpts.TLSALPNProtocols
+	if len(alpnProtocols) == 0 {
+		alpnProtocols = DefaultTLSALPNProtocols
+	}
+	tlsConfig.NextProtos = alpnProtocols
+
+	return credentials.NewTLS(tlsConfig), reloader, nil
+}
+
+func startAdminServer(addr string, health *HealthServer) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if health.getStatus("") != hpb.HealthCheckResponse_SERVING ||
+			health.getStatus(ExternalProcessorServiceName) != hpb.HealthCheckResponse_SERVING {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	admin := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := admin.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("admin server error: %v", err)
+		}
+	}()
+
+	return admin
+}
+
 // Wrapper for running gRPC ExternalProcessor service with a given RequestProcessor
 // implementation. Includes the standard gRPC Health service as well as reflection.
 //
@@ -67,9 +219,24 @@ func ServeWithOptions(port int, serverOpts ExtProcServerOptions, processor Reque
 	}
 
 	sopts := []grpc.ServerOption{grpc.MaxConcurrentStreams(serverOpts.MaxConcurrentStreams)}
+
+	tlsCreds, tlsReloader, err := buildTLSCredentials(serverOpts)
+	if err != nil {
+		log.Fatalf("failed to load TLS credentials: %v", err)
+	}
+	if tlsCreds != nil {
+		sopts = append(sopts, grpc.Creds(tlsCreds))
+	}
+	if tlsReloader != nil {
+		stopWatch := tlsReloader.watch(serverOpts.TLSCertReloadInterval)
+		defer close(stopWatch)
+	}
+
 	s := grpc.NewServer(sopts...)
 	reflection.Register(s)
 
+	var adminServer *http.Server
+
 	name := processor.GetName()
 	opts := processor.GetOptions() // TODO: figure out command line overrides
 	extproc := &GenericExtProcServer{
@@ -79,7 +246,18 @@ func ServeWithOptions(port int, serverOpts ExtProcServerOptions, processor Reque
 	}
 	epb.RegisterExternalProcessorServer(s, extproc)
 
-	hpb.RegisterHealthServer(s, &HealthServer{})
+	healthServer := NewHealthServer()
+	opts.healthServer = healthServer
+	healthServer.SetServingStatus(TriggerPortServiceName(port), hpb.HealthCheckResponse_SERVING)
+	hpb.RegisterHealthServer(s, healthServer)
+
+	if serverOpts.AdminAddr != "" {
+		adminServer = startAdminServer(serverOpts.AdminAddr, healthServer)
+	}
+
+	if reporter, ok := processor.(HealthReporter); ok {
+		healthServer.RegisterHealthReporter(reporter, serverOpts.HealthCheckInterval)
+	}
 
 	log.Printf("Starting ExtProc(%s) on port %d\n", name, port)
 
@@ -90,8 +268,69 @@ func ServeWithOptions(port int, serverOpts ExtProcServerOptions, processor Reque
 	signal.Notify(gracefulStop, syscall.SIGINT)
 	sig := <-gracefulStop
 	log.Printf("caught sig: %+v", sig)
-	log.Printf("Wait for %d seconds to finish processing\n", serverOpts.GracefulShutdownTimeout)
-	lis.Close()
 
-	time.Sleep(time.Duration(serverOpts.GracefulShutdownTimeout) * time.Second)
+	// Flip health status to NOT_SERVING first so probes route traffic away
+	// before we start refusing new streams, giving in-flight ones a chance
+	// to finish cleanly against the drain timeout below.
+	healthServer.Shutdown()
+	healthServer.SetServingStatus(TriggerPortServiceName(port), hpb.HealthCheckResponse_NOT_SERVING)
+	log.Printf("Draining in-flight streams (up to %ds) before shutdown\n", serverOpts.GracefulShutdownTimeout)
+
+	// GracefulStop stops the listener immediately and waits for in-flight
+	// streams to finish on their own; if they haven't within the
+	// configured timeout, fall back to a hard Stop so shutdown is bounded.
+	drained := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Printf("All streams drained cleanly\n")
+	case <-time.After(time.Duration(serverOpts.GracefulShutdownTimeout) * time.Second):
+		log.Printf("Graceful shutdown timed out, forcing stop\n")
+		s.Stop()
+	}
+
+	if adminServer != nil {
+		_ = adminServer.Close()
+	}
+}
+
+// Attach registers the ExternalProcessor (and Health) services for
+// `processor` onto a caller-managed *grpc.Server, for deployments where the
+// gRPC server's listener, TLS termination, and lifecycle are already owned
+// by the embedding application (an "unmanaged" processor mode) rather than
+// by Serve/ServeWithOptions. The caller is responsible for calling
+// grpc.Server.Serve and for shutting the server down; call
+// processor.Finish as appropriate during that shutdown.
+//
+// Returns the constructed *GenericExtProcServer so callers that need to
+// call HealthServer() or similar accessors can retain a reference. Callers
+// managing their own shutdown should call the returned server's
+// HealthServer().Shutdown() before grpc.Server.GracefulStop, mirroring what
+// ServeWithOptions does, so health probes drain traffic first.
+func Attach(server *grpc.Server, processor RequestProcessor) *GenericExtProcServer {
+	if processor == nil {
+		panic("cannot process request stream without `processor`")
+	}
+
+	opts := processor.GetOptions()
+	extproc := &GenericExtProcServer{
+		name:      processor.GetName(),
+		processor: processor,
+		options:   opts,
+	}
+	epb.RegisterExternalProcessorServer(server, extproc)
+
+	healthServer := NewHealthServer()
+	opts.healthServer = healthServer
+	hpb.RegisterHealthServer(server, healthServer)
+
+	if reporter, ok := processor.(HealthReporter); ok {
+		healthServer.RegisterHealthReporter(reporter, 0)
+	}
+
+	return extproc
 }
@@ -2,7 +2,18 @@ package extproc
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,6 +22,8 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -192,3 +205,243 @@ func TestServeWithNilProcessor(t *testing.T) {
 
 	Serve(0, nil)
 }
+
+func TestHealthServer(t *testing.T) {
+	t.Run("defaults to SERVING for any service", func(t *testing.T) {
+		h := NewHealthServer()
+		resp, err := h.Check(context.Background(), healthCheckRequest(""))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_SERVING, resp.Status)
+
+		resp, err = h.Check(context.Background(), healthCheckRequest(ExternalProcessorServiceName))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_SERVING, resp.Status)
+	})
+
+	t.Run("SetServingStatus only affects the named service", func(t *testing.T) {
+		h := NewHealthServer()
+		h.SetServingStatus(ExternalProcessorServiceName, hpb.HealthCheckResponse_NOT_SERVING)
+
+		resp, err := h.Check(context.Background(), healthCheckRequest(ExternalProcessorServiceName))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+		resp, err = h.Check(context.Background(), healthCheckRequest(""))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_SERVING, resp.Status)
+	})
+
+	t.Run("Shutdown flips overall and ExternalProcessor status", func(t *testing.T) {
+		h := NewHealthServer()
+		h.Shutdown()
+
+		for _, service := range []string{"", ExternalProcessorServiceName} {
+			resp, err := h.Check(context.Background(), healthCheckRequest(service))
+			require.NoError(t, err)
+			assert.Equal(t, hpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+		}
+	})
+
+	t.Run("TriggerPortServiceName reports a distinct status per port", func(t *testing.T) {
+		h := NewHealthServer()
+		h.SetServingStatus(TriggerPortServiceName(8443), hpb.HealthCheckResponse_SERVING)
+		h.SetServingStatus(TriggerPortServiceName(9443), hpb.HealthCheckResponse_NOT_SERVING)
+
+		resp, err := h.Check(context.Background(), healthCheckRequest(TriggerPortServiceName(8443)))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_SERVING, resp.Status)
+
+		resp, err = h.Check(context.Background(), healthCheckRequest(TriggerPortServiceName(9443)))
+		require.NoError(t, err)
+		assert.Equal(t, hpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+		list, err := h.List(context.Background(), &hpb.HealthListRequest{})
+		require.NoError(t, err)
+		assert.Contains(t, list.Statuses, TriggerPortServiceName(8443))
+		assert.Contains(t, list.Statuses, TriggerPortServiceName(9443))
+	})
+
+	t.Run("RegisterDependency drives ExternalProcessor status", func(t *testing.T) {
+		h := NewHealthServer()
+		healthy := make(chan bool, 1)
+		healthy <- true
+
+		h.RegisterDependency("fake-upstream", func() error {
+			if <-healthy {
+				healthy <- true
+				return nil
+			}
+			healthy <- false
+			return assert.AnError
+		}, 10*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			resp, _ := h.Check(context.Background(), healthCheckRequest(ExternalProcessorServiceName))
+			return resp.Status == hpb.HealthCheckResponse_SERVING
+		}, time.Second, 10*time.Millisecond)
+
+		<-healthy
+		healthy <- false
+
+		require.Eventually(t, func() bool {
+			resp, _ := h.Check(context.Background(), healthCheckRequest(ExternalProcessorServiceName))
+			return resp.Status == hpb.HealthCheckResponse_NOT_SERVING
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func healthCheckRequest(service string) *hpb.HealthCheckRequest {
+	return &hpb.HealthCheckRequest{Service: service}
+}
+
+// writeSelfSignedCert generates a self-signed ECDSA certificate valid for
+// "localhost"/127.0.0.1 and writes the cert/key PEM pair into dir,
+// returning their paths.
+func writeSelfSignedCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+
+	certOut, err := os.Create(certFile)
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	require.NoError(t, certOut.Close())
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	keyOut, err := os.Create(keyFile)
+	require.NoError(t, err)
+	require.NoError(t, pem.Encode(keyOut, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}))
+	require.NoError(t, keyOut.Close())
+
+	return certFile, keyFile
+}
+
+func TestBuildTLSCredentials(t *testing.T) {
+	t.Run("no cert file configured returns nil credentials", func(t *testing.T) {
+		creds, reloader, err := buildTLSCredentials(ExtProcServerOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, creds)
+		assert.Nil(t, reloader)
+	})
+
+	t.Run("missing key file fails to load the key pair", func(t *testing.T) {
+		certFile, _ := writeSelfSignedCert(t, t.TempDir())
+		creds, reloader, err := buildTLSCredentials(ExtProcServerOptions{
+			TLSCertFile: certFile,
+			TLSKeyFile:  filepath.Join(t.TempDir(), "missing-key.pem"),
+		})
+		assert.Error(t, err)
+		assert.Nil(t, creds)
+		assert.Nil(t, reloader)
+	})
+
+	t.Run("malformed CA file is rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeSelfSignedCert(t, dir)
+
+		badCAFile := filepath.Join(dir, "ca.pem")
+		require.NoError(t, os.WriteFile(badCAFile, []byte("not a real certificate"), 0o600))
+
+		creds, reloader, err := buildTLSCredentials(ExtProcServerOptions{
+			TLSCertFile:     certFile,
+			TLSKeyFile:      keyFile,
+			TLSClientCAFile: badCAFile,
+		})
+		assert.ErrorIs(t, err, errInvalidClientCA)
+		assert.Nil(t, creds)
+		assert.Nil(t, reloader)
+	})
+
+	t.Run("valid cert and CA yield verifying client-auth credentials", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeSelfSignedCert(t, dir)
+
+		creds, reloader, err := buildTLSCredentials(ExtProcServerOptions{
+			TLSCertFile:     certFile,
+			TLSKeyFile:      keyFile,
+			TLSClientCAFile: certFile,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, creds)
+		require.NotNil(t, reloader)
+	})
+}
+
+// TestServeWithOptions_TLS exercises chunk7-4's integration scenario end
+// to end: a TLS listener backed by a self-signed cert, a real client
+// connection negotiating that cert, and a ProcessRequestBody call
+// reaching the configured processor.
+func TestServeWithOptions_TLS(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+
+	mockProcessor := new(MockRequestProcessor)
+	mockProcessor.On("GetName").Return("test-processor")
+	mockProcessor.On("GetOptions").Return(NewDefaultOptions())
+	mockProcessor.On("ProcessRequestHeaders", mock.Anything, mock.Anything).Return(nil)
+	mockProcessor.On("ProcessRequestBody", mock.Anything, mock.Anything).Return(nil)
+
+	serverOpts := ExtProcServerOptions{
+		GracefulShutdownTimeout: 1,
+		MaxConcurrentStreams:    10,
+		TLSCertFile:             certFile,
+		TLSKeyFile:              keyFile,
+	}
+
+	go ServeWithOptions(port, serverOpts, mockProcessor)
+	time.Sleep(100 * time.Millisecond)
+
+	certPEM, err := os.ReadFile(certFile)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+	clientCreds := credentials.NewTLS(&tls.Config{RootCAs: pool, ServerName: "localhost"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, "127.0.0.1:"+strconv.Itoa(port), grpc.WithTransportCredentials(clientCreds), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := epb.NewExternalProcessorClient(conn)
+	stream, err := client.Process(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&epb.ProcessingRequest{
+		Request: &epb.ProcessingRequest_RequestHeaders{},
+	}))
+	require.NoError(t, stream.Send(&epb.ProcessingRequest{
+		Request: &epb.ProcessingRequest_RequestBody{},
+	}))
+
+	time.Sleep(100 * time.Millisecond)
+	stream.CloseSend()
+
+	mockProcessor.AssertExpectations(t)
+}
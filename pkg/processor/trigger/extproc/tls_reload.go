@@ -0,0 +1,147 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package extproc
+
+import (
+	"crypto/tls"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// tlsCertReloader serves a server certificate out of memory, reloading it
+// from certFile/keyFile whenever watch's fsnotify watcher observes either
+// file change or the process receives SIGHUP, so operators can rotate a
+// mounted cert/key pair without restarting the pod.
+type tlsCertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// newTLSCertReloader loads certFile/keyFile once, failing fast on startup
+// if they're missing or invalid.
+func newTLSCertReloader(certFile, keyFile string) (*tlsCertReloader, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tlsCertReloader{certFile: certFile, keyFile: keyFile, cert: &cert}, nil
+}
+
+// GetCertificate is a tls.Config.GetCertificate callback returning
+// whatever certificate is currently loaded.
+func (r *tlsCertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// reload re-reads certFile/keyFile from disk and swaps them in atomically.
+// A failed reload logs and keeps serving the previously loaded certificate.
+func (r *tlsCertReloader) reload() {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		log.Printf("Failed to reload TLS certificate, keeping previous one: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	log.Printf("Reloaded TLS certificate from %s", r.certFile)
+}
+
+// watch starts a goroutine that reloads the certificate whenever certFile
+// or keyFile changes on disk (detected via fsnotify on their containing
+// directories, since Kubernetes secret mounts replace files by renaming a
+// new directory into place rather than writing in place) or the process
+// receives SIGHUP. If reloadInterval is > 0, it also reloads on that fixed
+// cadence regardless of fsnotify events, as a fallback for filesystems
+// (certain network or overlay mounts) where fsnotify doesn't reliably
+// observe the rename. It returns a channel the caller closes to stop
+// watching.
+func (r *tlsCertReloader) watch(reloadInterval time.Duration) chan<- struct{} {
+	stop := make(chan struct{})
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("Failed to start TLS certificate watcher, hot-reload disabled: %v", err)
+		return stop
+	}
+
+	for _, dir := range uniqueDirs(r.certFile, r.keyFile) {
+		if err := watcher.Add(dir); err != nil {
+			log.Printf("Failed to watch %s for TLS certificate changes: %v", dir, err)
+		}
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var ticker *time.Ticker
+	var ticks <-chan time.Time
+	if reloadInterval > 0 {
+		ticker = time.NewTicker(reloadInterval)
+		ticks = ticker.C
+	}
+
+	go func() {
+		defer watcher.Close()
+		defer signal.Stop(sighup)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+
+		for {
+			select {
+			case <-stop:
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+					r.reload()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("TLS certificate watcher error: %v", err)
+			case <-sighup:
+				r.reload()
+			case <-ticks:
+				r.reload()
+			}
+		}
+	}()
+
+	return stop
+}
+
+// uniqueDirs returns the distinct containing directories of paths.
+func uniqueDirs(paths ...string) []string {
+	seen := map[string]bool{}
+	var dirs []string
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
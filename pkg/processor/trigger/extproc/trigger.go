@@ -7,6 +7,7 @@ package extproc
 
 import (
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/nuclio/errors"
@@ -20,14 +21,28 @@ import (
 	"github.com/nuclio/nuclio/pkg/processor/worker"
 )
 
+// workerOutcome arbitrates, per worker slot, whether a late worker
+// response or a timeout "wins" a given request: AllocateWorkerAndSubmitEvent
+// and TimeoutWorker race to CompareAndSwap it away from
+// workerOutcomePending, and whichever succeeds owns the outcome. This
+// replaces a prior implementation that flagged timeout/answered state with
+// plain uint64s and arbitrated the race with a fixed time.Sleep, which
+// could both answer and time out the same request under load.
+type workerOutcome int32
+
+const (
+	workerOutcomePending workerOutcome = iota
+	workerOutcomeAnswered
+	workerOutcomeTimedOut
+)
+
 type extproc struct {
 	trigger.AbstractTrigger
 	events         []Event
 	status         status.Status
 	activeContexts []*Event
 	configuration  *Configuration
-	timeouts       []uint64 // flag of worker is in timeout
-	answering      []uint64 // flag the worker is answering
+	outcomes       []atomic.Int32 // workerOutcome per worker slot
 }
 
 func newTrigger(logger logger.Logger,
@@ -53,8 +68,7 @@ func newTrigger(logger logger.Logger,
 		configuration:   configuration,
 		status:          status.Initializing,
 		activeContexts:  make([]*Event, numWorkers),
-		timeouts:        make([]uint64, numWorkers),
-		answering:       make([]uint64, numWorkers),
+		outcomes:        make([]atomic.Int32, numWorkers),
 	}
 
 	newTrigger.Trigger = &newTrigger
@@ -76,30 +90,48 @@ func (ep *extproc) Start(checkpoint functionconfig.Checkpoint) error {
 	if ep.configuration.MaxConcurrentStreams != 0 {
 		serverOptions.MaxConcurrentStreams = ep.configuration.MaxConcurrentStreams
 	}
+	if tlsConfig := ep.configuration.TLS; tlsConfig != nil {
+		serverOptions.TLSCertFile = tlsConfig.CertFile
+		serverOptions.TLSKeyFile = tlsConfig.KeyFile
+		serverOptions.TLSClientCAFile = tlsConfig.ClientCAFile
+		serverOptions.TLSClientAuth = tlsConfig.ClientAuth
+		serverOptions.TLSMinVersion = tlsConfig.MinVersion
+		serverOptions.TLSCipherSuites = tlsConfig.CipherSuites
+		serverOptions.TLSALPNProtocols = tlsConfig.ALPNProtocols
+	}
 
-	switch ep.configuration.Type {
-	case OperatorTypePre:
-		ep.Logger.Info("Starting preprocessor server")
-		proc := &PreProcessor{}
-		proc.Init(ep.configuration.ProcessingOptions, nil, ep)
-		go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
-	case OperatorTypePost:
-		ep.Logger.Info("Starting postprocessor server")
-		proc := &PostProcessor{}
-		proc.Init(ep.configuration.ProcessingOptions, nil, ep)
-		go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
-	case OperatorTypeWrap:
-		ep.Logger.Info("Starting wrapprocessor server")
-		proc := &WrapProcessor{}
-		proc.Init(ep.configuration.ProcessingOptions, nil, ep)
-		go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
-	case OperatorTypeObserve:
-		ep.Logger.Info("Starting observeprocessor server")
-		proc := &ObserveProcessor{}
-		proc.Init(ep.configuration.ProcessingOptions, nil, ep)
+	if len(ep.configuration.Pipeline) > 0 {
+		ep.Logger.InfoWith("Starting pipeline server", "stages", len(ep.configuration.Pipeline))
+		proc, err := NewChainProcessor(ep.configuration.Pipeline, ep.configuration.ProcessingOptions, ep)
+		if err != nil {
+			return errors.Wrap(err, "Failed to build extproc pipeline")
+		}
 		go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
-	default:
-		return errors.New("Unknown operator type: " + string(ep.configuration.Type))
+	} else {
+		switch ep.configuration.Type {
+		case OperatorTypePre:
+			ep.Logger.Info("Starting preprocessor server")
+			proc := &PreProcessor{}
+			proc.Init(ep.configuration.ProcessingOptions, nil, ep)
+			go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
+		case OperatorTypePost:
+			ep.Logger.Info("Starting postprocessor server")
+			proc := &PostProcessor{}
+			proc.Init(ep.configuration.ProcessingOptions, nil, ep)
+			go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
+		case OperatorTypeWrap:
+			ep.Logger.Info("Starting wrapprocessor server")
+			proc := &WrapProcessor{}
+			proc.Init(ep.configuration.ProcessingOptions, nil, ep)
+			go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
+		case OperatorTypeObserve:
+			ep.Logger.Info("Starting observeprocessor server")
+			proc := &ObserveProcessor{}
+			proc.Init(ep.configuration.ProcessingOptions, nil, ep)
+			go ServeWithOptions(ep.configuration.Port, serverOptions, proc)
+		default:
+			return errors.New("Unknown operator type: " + string(ep.configuration.Type))
+		}
 	}
 
 	ep.status = status.Ready
@@ -116,10 +148,48 @@ func (ep *extproc) Stop(force bool) (functionconfig.Checkpoint, error) {
 	return nil, nil
 }
 
+// AllocateWorkerAndSubmitEvent allocates a worker and submits req to it,
+// retrying worker-allocation and upstream-submission failures with
+// exponential backoff per ep.configuration.RetryPolicy. A timed-out
+// response (from TimeoutWorker racing the submission) is never retried,
+// since the caller has already given up on it.
 func (ep *extproc) AllocateWorkerAndSubmitEvent(req *Event,
 	functionLogger logger.Logger,
 	timeout time.Duration) (response interface{}, timedOut bool, submitError error, processError error) {
 
+	policy := ep.configuration.RetryPolicy
+	if policy == nil {
+		policy = NewDefaultRetryPolicy()
+	}
+
+	backoff := time.Duration(policy.InitialBackoffMilliseconds) * time.Millisecond
+	maxBackoff := time.Duration(policy.MaxBackoffMilliseconds) * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		response, timedOut, submitError, processError = ep.allocateWorkerAndSubmitEventOnce(req, functionLogger, timeout)
+
+		if (submitError == nil && processError == nil) || timedOut || attempt >= policy.MaxAttempts {
+			return response, timedOut, submitError, processError
+		}
+
+		ep.Logger.WarnWith("Retrying worker allocation/submission",
+			"attempt", attempt,
+			"backoff", backoff,
+			"submitError", submitError,
+			"processError", processError)
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (ep *extproc) allocateWorkerAndSubmitEventOnce(req *Event,
+	functionLogger logger.Logger,
+	timeout time.Duration) (response interface{}, timedOut bool, submitError error, processError error) {
+
 	var workerInstance *worker.Worker
 
 	defer ep.HandleSubmitPanic(workerInstance, &submitError)
@@ -140,8 +210,7 @@ func (ep *extproc) AllocateWorkerAndSubmitEvent(req *Event,
 	}
 
 	ep.activeContexts[workerIndex] = req
-	ep.timeouts[workerIndex] = 0
-	ep.answering[workerIndex] = 0
+	ep.outcomes[workerIndex].Store(int32(workerOutcomePending))
 	event := &ep.events[workerIndex]
 	event.ctx = req.ctx
 	event.Body = req.Body
@@ -151,11 +220,13 @@ func (ep *extproc) AllocateWorkerAndSubmitEvent(req *Event,
 	// release worker when we're done
 	ep.WorkerAllocator.Release(workerInstance)
 
-	if ep.timeouts[workerIndex] == 1 {
+	// whichever of the response (here) and TimeoutWorker (on another
+	// goroutine) claims the outcome first wins; the loser's result is
+	// discarded.
+	if !ep.outcomes[workerIndex].CompareAndSwap(int32(workerOutcomePending), int32(workerOutcomeAnswered)) {
 		return nil, true, nil, nil
 	}
 
-	ep.answering[workerIndex] = 1
 	ep.activeContexts[workerIndex] = nil
 
 	return response, false, nil, processError
@@ -167,9 +238,7 @@ func (ep *extproc) TimeoutWorker(worker *worker.Worker) error {
 		return errors.Errorf("Worker %d out of range", workerIndex)
 	}
 
-	ep.timeouts[workerIndex] = 1
-	time.Sleep(time.Millisecond) // Let worker do it's thing
-	if ep.answering[workerIndex] == 1 {
+	if !ep.outcomes[workerIndex].CompareAndSwap(int32(workerOutcomePending), int32(workerOutcomeTimedOut)) {
 		return errors.Errorf("Worker %d answered the request", workerIndex)
 	}
 
@@ -22,6 +22,114 @@ type Configuration struct {
 	GracefulShutdownTimeout int                `json:"gracefulShutdownTimeout,omitempty"`
 	MaxConcurrentStreams    uint32             `json:"maxConcurrentStreams,omitempty"`
 	ProcessingOptions       *ProcessingOptions `json:"processingOptions"`
+
+	// RetryPolicy governs retries of worker allocation and upstream
+	// submission failures in AllocateWorkerAndSubmitEvent.
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// TLS, when set, makes the gRPC listener serve TLS (and, with
+	// ClientCAFile set, mTLS) instead of plaintext.
+	TLS *TLSConfiguration `json:"tls,omitempty"`
+
+	// Pipeline, when non-empty, overrides Type with an ordered chain of
+	// stages built by ChainProcessor: request-phase processing runs the
+	// stages in this order, response-phase processing runs them in
+	// reverse. See StageConfig.
+	Pipeline []StageConfig `json:"pipeline,omitempty"`
+}
+
+// DefaultStageErrorStatus is the HTTP status CancelRequest sends when a
+// pipeline stage fails and its ContinueOnError is false.
+const DefaultStageErrorStatus = 500
+
+// StageConfig configures one stage of Configuration.Pipeline. Each stage
+// reuses the same AbstractProcessor.Handler pattern as a standalone
+// extproc trigger: Name lets that one Handler tell stages apart (e.g. via
+// RequestContext values it sets for itself), while Type selects which of
+// the four processing patterns (preprocessor, postprocessor,
+// wrapprocessor, observeprocessor) this stage behaves as.
+type StageConfig struct {
+	// Name identifies this stage in logs and to its Handler; must be
+	// unique within a Pipeline.
+	Name string `json:"name"`
+
+	// Type selects this stage's processing pattern (see OperatorType).
+	Type OperatorType `json:"type"`
+
+	// ContinueOnError makes a failing stage's error be logged and the
+	// chain move on to the next stage with the body unchanged, instead of
+	// short-circuiting the request with an immediate ErrorStatus response.
+	ContinueOnError bool `json:"continueOnError,omitempty"`
+
+	// ErrorStatus is the HTTP status sent back via an immediate response
+	// when this stage fails and ContinueOnError is false. Defaults to
+	// DefaultStageErrorStatus (500).
+	ErrorStatus int `json:"errorStatus,omitempty"`
+}
+
+// TLSClientAuthType selects how the gRPC server handles client
+// certificates; see TLSConfiguration.ClientAuth.
+type TLSClientAuthType string
+
+const (
+	TLSClientAuthNone    TLSClientAuthType = "none"
+	TLSClientAuthRequest TLSClientAuthType = "request"
+	TLSClientAuthRequire TLSClientAuthType = "require"
+	TLSClientAuthVerify  TLSClientAuthType = "verify"
+)
+
+// DefaultTLSALPNProtocols is advertised during the TLS handshake when
+// TLSConfiguration.ALPNProtocols is left unset.
+var DefaultTLSALPNProtocols = []string{"h2"}
+
+// TLSConfiguration configures TLS/mTLS termination for the extproc gRPC
+// server. CertFile/KeyFile are reloaded from disk whenever they change or
+// on SIGHUP, so rotating them does not require a pod restart.
+type TLSConfiguration struct {
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+
+	// ClientCAFile, when set, makes the server verify client certificates
+	// against this CA; leave it unset to terminate TLS without mTLS.
+	ClientCAFile string `json:"clientCAFile,omitempty"`
+
+	// ClientAuth selects how client certificates are handled: "none" (the
+	// default unless ClientCAFile is set, in which case it behaves as
+	// "verify"), "request" (ask for one but don't require or verify it),
+	// "require" (require one but don't verify it against ClientCAFile), or
+	// "verify" (require one and verify it against ClientCAFile).
+	ClientAuth TLSClientAuthType `json:"clientAuth,omitempty"`
+
+	// MinVersion is the minimum TLS version to accept: "1.2" or "1.3".
+	// Defaults to the Go standard library's default (currently TLS 1.2).
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// CipherSuites restricts the negotiated cipher suite to this list of
+	// names (e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"). Empty leaves
+	// the Go standard library's default preference order in place. Ignored
+	// for TLS 1.3, which does not allow configuring cipher suites.
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+
+	// ALPNProtocols are advertised during the handshake; defaults to
+	// DefaultTLSALPNProtocols ([]string{"h2"}) when unset.
+	ALPNProtocols []string `json:"alpnProtocols,omitempty"`
+}
+
+// RetryPolicy configures exponential backoff with a maximum attempt count.
+type RetryPolicy struct {
+	MaxAttempts                int `json:"maxAttempts"`
+	InitialBackoffMilliseconds int `json:"initialBackoffMilliseconds"`
+	MaxBackoffMilliseconds     int `json:"maxBackoffMilliseconds"`
+}
+
+// NewDefaultRetryPolicy disables retries (a single attempt), preserving
+// the trigger's historical behavior.
+func NewDefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:                1,
+		InitialBackoffMilliseconds: 50,
+		MaxBackoffMilliseconds:     2000,
+	}
 }
 
 type OperatorType string
@@ -51,16 +159,98 @@ func NewConfiguration(id string,
 	}
 
 	// validate required fields
-	if newConfiguration.Type == "" {
+	if len(newConfiguration.Pipeline) == 0 && newConfiguration.Type == "" {
 		return nil, errors.New("Operator type not specified")
 	}
 	if newConfiguration.Port == 0 {
 		return nil, errors.New("Port not specified")
 	}
 
+	if len(newConfiguration.Pipeline) > 0 {
+		if err := validatePipeline(newConfiguration.Pipeline); err != nil {
+			return nil, errors.Wrap(err, "Failed to validate pipeline configuration")
+		}
+	}
+
 	if newConfiguration.ProcessingOptions == nil {
 		newConfiguration.ProcessingOptions = NewDefaultOptions()
 	}
 
+	if newConfiguration.RetryPolicy == nil {
+		newConfiguration.RetryPolicy = NewDefaultRetryPolicy()
+	}
+
+	if newConfiguration.TLS != nil {
+		if err := newConfiguration.TLS.validate(); err != nil {
+			return nil, errors.Wrap(err, "Failed to validate tls configuration")
+		}
+	}
+
 	return &newConfiguration, nil
 }
+
+// validate checks required fields and enum values, and fills in
+// ALPNProtocols with DefaultTLSALPNProtocols if unset. It does not touch
+// the filesystem: missing/unreadable cert, key, or CA files are reported
+// later by buildTLSCredentials, once at startup.
+func (t *TLSConfiguration) validate() error {
+	if t.CertFile == "" {
+		return errors.New("tls.certFile is required")
+	}
+	if t.KeyFile == "" {
+		return errors.New("tls.keyFile is required")
+	}
+
+	switch t.ClientAuth {
+	case "", TLSClientAuthNone, TLSClientAuthRequest, TLSClientAuthRequire, TLSClientAuthVerify:
+	default:
+		return errors.Errorf("unsupported tls.clientAuth: %s", t.ClientAuth)
+	}
+
+	switch t.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return errors.Errorf("unsupported tls.minVersion: %s", t.MinVersion)
+	}
+
+	for _, suite := range t.CipherSuites {
+		if _, ok := tlsCipherSuiteByName[suite]; !ok {
+			return errors.Errorf("unsupported tls.cipherSuites entry: %s", suite)
+		}
+	}
+
+	if len(t.ALPNProtocols) == 0 {
+		t.ALPNProtocols = DefaultTLSALPNProtocols
+	}
+
+	return nil
+}
+
+// validatePipeline checks that every stage has a unique, non-empty name
+// and a supported Type, and fills in ErrorStatus with
+// DefaultStageErrorStatus wherever a stage left it unset.
+func validatePipeline(stages []StageConfig) error {
+	seen := make(map[string]bool, len(stages))
+
+	for i, stage := range stages {
+		if stage.Name == "" {
+			return errors.Errorf("pipeline stage %d: name is required", i)
+		}
+		if seen[stage.Name] {
+			return errors.Errorf("pipeline stage %d: duplicate name %q", i, stage.Name)
+		}
+		seen[stage.Name] = true
+
+		switch stage.Type {
+		case OperatorTypePre, OperatorTypePost, OperatorTypeWrap, OperatorTypeObserve:
+		default:
+			return errors.Errorf("pipeline stage %q: unsupported type %q", stage.Name, stage.Type)
+		}
+
+		if stages[i].ErrorStatus == 0 {
+			stages[i].ErrorStatus = DefaultStageErrorStatus
+		}
+	}
+
+	return nil
+}
@@ -130,6 +130,63 @@ func TestTypes(t *testing.T) {
 		assert.Equal(t, uint32(100), config.MaxConcurrentStreams)
 	})
 
+	t.Run("NewConfiguration_TLS_MissingKeyFile", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "extproc",
+			Attributes: map[string]interface{}{
+				"type": "preprocessor",
+				"port": 8080,
+				"tls": map[string]interface{}{
+					"certFile": "/tmp/cert.pem",
+				},
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "tls.keyFile is required")
+	})
+
+	t.Run("NewConfiguration_TLS_UnsupportedClientAuth", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "extproc",
+			Attributes: map[string]interface{}{
+				"type": "preprocessor",
+				"port": 8080,
+				"tls": map[string]interface{}{
+					"certFile":   "/tmp/cert.pem",
+					"keyFile":    "/tmp/key.pem",
+					"clientAuth": "sometimes",
+				},
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "unsupported tls.clientAuth")
+	})
+
+	t.Run("NewConfiguration_TLS_DefaultsALPNProtocols", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "extproc",
+			Attributes: map[string]interface{}{
+				"type": "preprocessor",
+				"port": 8080,
+				"tls": map[string]interface{}{
+					"certFile": "/tmp/cert.pem",
+					"keyFile":  "/tmp/key.pem",
+				},
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.NoError(t, err)
+		assert.NotNil(t, config)
+		assert.Equal(t, DefaultTLSALPNProtocols, config.TLS.ALPNProtocols)
+	})
+
 	t.Run("NewConfiguration_AllOperatorTypes", func(t *testing.T) {
 		operatorTypes := []OperatorType{
 			OperatorTypePre,
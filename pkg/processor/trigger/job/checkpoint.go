@@ -0,0 +1,166 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/nuclio/errors"
+)
+
+// CheckpointStore persists and retrieves a single opaque checkpoint blob
+// across job trigger restarts. Save must be atomic with respect to
+// concurrent Loads (a reader never observes a partially written blob).
+type CheckpointStore interface {
+	Load() ([]byte, error)
+	Save(data []byte) error
+}
+
+// NewCheckpointStore builds the CheckpointStore described by url:
+// "s3://bucket/key" for an S3 object, anything else (including a bare
+// path, or a "file://" URL) for a local file. An empty url disables
+// checkpointing, in which case NewCheckpointStore returns a nil store and
+// no error.
+func NewCheckpointStore(url string) (CheckpointStore, error) {
+	switch {
+	case url == "":
+		return nil, nil //nolint:nilnil
+
+	case strings.HasPrefix(url, "s3://"):
+		return newS3CheckpointStore(url)
+
+	case strings.HasPrefix(url, "file://"):
+		return newFileCheckpointStore(strings.TrimPrefix(url, "file://")), nil
+
+	default:
+		return newFileCheckpointStore(url), nil
+	}
+}
+
+// fileCheckpointStore persists the checkpoint as a local file, writing via
+// a temp-file-then-rename so a reader never sees a partially written file.
+type fileCheckpointStore struct {
+	path string
+}
+
+func newFileCheckpointStore(path string) *fileCheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
+
+func (s *fileCheckpointStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read checkpoint file")
+	}
+
+	return data, nil
+}
+
+func (s *fileCheckpointStore) Save(data []byte) error {
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return errors.Wrap(err, "Failed to write checkpoint file")
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return errors.Wrap(err, "Failed to atomically replace checkpoint file")
+	}
+
+	return nil
+}
+
+// s3CheckpointStore persists the checkpoint as a single S3 object;
+// PutObject already replaces the object atomically from a reader's
+// perspective, so no separate temp-object dance is needed.
+type s3CheckpointStore struct {
+	client *s3.Client
+	bucket string
+	key    string
+}
+
+func newS3CheckpointStore(url string) (*s3CheckpointStore, error) {
+	bucket, key, err := parseS3URL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to load AWS configuration")
+	}
+
+	return &s3CheckpointStore{
+		client: s3.NewFromConfig(cfg),
+		bucket: bucket,
+		key:    key,
+	}, nil
+}
+
+func parseS3URL(url string) (bucket string, key string, err error) {
+	rest := strings.TrimPrefix(url, "s3://")
+	parts := strings.SplitN(rest, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid s3 checkpoint store url: %q, expected s3://bucket/key", url)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func (s *s3CheckpointStore) Load() ([]byte, error) {
+	output, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if isS3NotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get checkpoint object")
+	}
+	defer output.Body.Close() // nolint: errcheck
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read checkpoint object body")
+	}
+
+	return data, nil
+}
+
+func (s *s3CheckpointStore) Save(data []byte) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to put checkpoint object")
+	}
+
+	return nil
+}
+
+// isS3NotFound reports whether err is an S3 "key does not exist" error, in
+// which case Load should behave like a fresh, never-checkpointed job
+// rather than failing.
+func isS3NotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return strings.Contains(err.Error(), "NoSuchKey") || strings.Contains(err.Error(), "NotFound")
+}
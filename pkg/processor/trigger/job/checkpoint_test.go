@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCheckpointStoreDisabled(t *testing.T) {
+	store, err := NewCheckpointStore("")
+	require.NoError(t, err)
+	assert.Nil(t, store)
+}
+
+func TestFileCheckpointStoreLoadMissing(t *testing.T) {
+	store, err := NewCheckpointStore(filepath.Join(t.TempDir(), "does-not-exist"))
+	require.NoError(t, err)
+	require.NotNil(t, store)
+
+	data, err := store.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestFileCheckpointStoreSaveLoadRoundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	store, err := NewCheckpointStore(path)
+	require.NoError(t, err)
+	require.NotNil(t, store)
+
+	require.NoError(t, store.Save([]byte("frame-42")))
+
+	data, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("frame-42"), data)
+
+	// Saving again overwrites, rather than appends to, the checkpoint.
+	require.NoError(t, store.Save([]byte("frame-99")))
+	data, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("frame-99"), data)
+}
+
+func TestFileCheckpointStoreFileURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	store, err := NewCheckpointStore("file://" + path)
+	require.NoError(t, err)
+	require.NotNil(t, store)
+
+	require.NoError(t, store.Save([]byte("progress")))
+	data, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("progress"), data)
+}
+
+func TestParseS3URL(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		bucket, key, err := parseS3URL("s3://my-bucket/path/to/checkpoint")
+		require.NoError(t, err)
+		assert.Equal(t, "my-bucket", bucket)
+		assert.Equal(t, "path/to/checkpoint", key)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, _, err := parseS3URL("s3://my-bucket")
+		assert.Error(t, err)
+	})
+}
@@ -9,6 +9,8 @@ package job
 import (
 	"strconv"
 	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 )
 
 // Event contains the data for a job event
@@ -16,6 +18,78 @@ type Event struct {
 	Body       []byte
 	Attributes map[string]interface{}
 	timestamp  time.Time
+
+	// ceID/ceType/ceSource/ceDataContentType/ceSubject hold the CloudEvents
+	// v1.0 context attributes when this event was built from (or is
+	// destined for) a CE envelope; see NewEventFromCloudEvent/ToCloudEvent.
+	// They're empty for a plain job event, in which case the accessors
+	// below keep this package's historical defaults.
+	ceID              string
+	ceType            string
+	ceSource          string
+	ceDataContentType string
+	ceSubject         string
+}
+
+// NewEventFromCloudEvent builds a job Event out of a CloudEvents v1.0
+// envelope, so a job trigger driven by CE-formatted messages (Kafka, NATS,
+// HTTP) can be handled the same way as any other job event: the CE payload
+// becomes Body, CE extensions become Attributes, and the CE context
+// attributes are retained so GetID/GetType/GetContentType/GetHeaders
+// reflect them instead of falling back to their historical defaults.
+func NewEventFromCloudEvent(ce cloudevents.Event) Event {
+	var attrs map[string]interface{}
+	if extensions := ce.Extensions(); len(extensions) > 0 {
+		attrs = make(map[string]interface{}, len(extensions))
+		for key, value := range extensions {
+			attrs[key] = value
+		}
+	}
+
+	timestamp := ce.Time()
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return Event{
+		Body:              ce.Data(),
+		Attributes:        attrs,
+		timestamp:         timestamp,
+		ceID:              ce.ID(),
+		ceType:            ce.Type(),
+		ceSource:          ce.Source(),
+		ceDataContentType: ce.DataContentType(),
+		ceSubject:         ce.Subject(),
+	}
+}
+
+// ToCloudEvent converts e into a CloudEvents v1.0 envelope: Body becomes
+// the CE payload (content-typed via GetContentType, defaulting to
+// "application/octet-stream" when unset) and Attributes become CE
+// extensions. GetID/GetType supply the id/type context attributes, so a
+// plain job event (not built via NewEventFromCloudEvent) round-trips with
+// an empty id and type "job", matching their existing fallback behavior.
+func (e Event) ToCloudEvent() cloudevents.Event {
+	ce := cloudevents.NewEvent()
+	ce.SetID(e.GetID())
+	ce.SetType(e.GetType())
+	ce.SetSource(e.ceSource)
+	ce.SetTime(e.GetTimestamp())
+	if e.ceSubject != "" {
+		ce.SetSubject(e.ceSubject)
+	}
+
+	contentType := e.ceDataContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_ = ce.SetData(contentType, e.Body)
+
+	for key, value := range e.Attributes {
+		ce.SetExtension(key, value)
+	}
+
+	return ce
 }
 
 // GetBody returns the body of the job event
@@ -48,8 +122,12 @@ func (e *Event) GetShardID() int {
 	return 0
 }
 
-// GetType returns "job" as the type
+// GetType returns the CloudEvents "type" context attribute when this event
+// was built from a CE envelope, or "job" otherwise
 func (e *Event) GetType() string {
+	if e.ceType != "" {
+		return e.ceType
+	}
 	return "job"
 }
 
@@ -63,9 +141,10 @@ func (e *Event) GetVersion() int {
 	return 0
 }
 
-// GetID returns an empty string since job events don't have an ID
+// GetID returns the CloudEvents "id" context attribute when this event was
+// built from a CE envelope, or an empty string otherwise
 func (e *Event) GetID() string {
-	return ""
+	return e.ceID
 }
 
 // GetTriggerInfo returns a map with trigger information
@@ -75,9 +154,35 @@ func (e *Event) GetTriggerInfo() map[string]interface{} {
 	}
 }
 
-// GetHeaders returns nil since job events don't have headers
+// GetHeaders returns the CloudEvents context attributes (id, type, source,
+// datacontenttype, subject, time) when this event was built from a CE
+// envelope, or nil otherwise since plain job events don't have headers
 func (e *Event) GetHeaders() map[string]interface{} {
-	return nil
+	if e.ceID == "" && e.ceType == "" && e.ceSource == "" && e.ceDataContentType == "" && e.ceSubject == "" {
+		return nil
+	}
+
+	headers := map[string]interface{}{}
+	if e.ceID != "" {
+		headers["id"] = e.ceID
+	}
+	if e.ceType != "" {
+		headers["type"] = e.ceType
+	}
+	if e.ceSource != "" {
+		headers["source"] = e.ceSource
+	}
+	if e.ceDataContentType != "" {
+		headers["datacontenttype"] = e.ceDataContentType
+	}
+	if e.ceSubject != "" {
+		headers["subject"] = e.ceSubject
+	}
+	if !e.timestamp.IsZero() {
+		headers["time"] = e.timestamp
+	}
+
+	return headers
 }
 
 // GetTimestamp returns the event timestamp
@@ -85,9 +190,11 @@ func (e *Event) GetTimestamp() time.Time {
 	return e.timestamp
 }
 
-// GetContentType returns an empty string since job events don't have a content type
+// GetContentType returns the CloudEvents "datacontenttype" context
+// attribute when this event was built from a CE envelope, or an empty
+// string otherwise
 func (e *Event) GetContentType() string {
-	return ""
+	return e.ceDataContentType
 }
 
 // GetFields returns the event attributes
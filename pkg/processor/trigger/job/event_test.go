@@ -8,7 +8,9 @@ package job
 
 import (
 	"testing"
+	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,3 +120,65 @@ func TestJobEvent(t *testing.T) {
 		})
 	}
 }
+
+// TestJobEventCloudEvents covers NewEventFromCloudEvent/ToCloudEvent: a job
+// event built from a CE envelope must surface the CE context attributes
+// through GetID/GetType/GetContentType/GetHeaders and preserve extensions
+// as Attributes, and converting it back must reproduce the same envelope.
+func TestJobEventCloudEvents(t *testing.T) {
+	t.Run("NewEventFromCloudEvent surfaces context attributes and extensions", func(t *testing.T) {
+		ce := cloudevents.NewEvent()
+		ce.SetID("event-id")
+		ce.SetType("com.example.order.created")
+		ce.SetSource("/orders")
+		ce.SetSubject("order-42")
+		when := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		ce.SetTime(when)
+		ce.SetExtension("tenant", "acme")
+		require.NoError(t, ce.SetData("application/json", []byte(`{"amount":10}`)))
+
+		event := NewEventFromCloudEvent(ce)
+
+		assert.Equal(t, []byte(`{"amount":10}`), event.GetBody())
+		assert.Equal(t, "event-id", event.GetID())
+		assert.Equal(t, "com.example.order.created", event.GetType())
+		assert.Equal(t, "application/json", event.GetContentType())
+		assert.Equal(t, "acme", event.Attributes["tenant"])
+		assert.True(t, event.GetTimestamp().Equal(when))
+
+		headers := event.GetHeaders()
+		assert.Equal(t, "event-id", headers["id"])
+		assert.Equal(t, "com.example.order.created", headers["type"])
+		assert.Equal(t, "/orders", headers["source"])
+		assert.Equal(t, "order-42", headers["subject"])
+		assert.Equal(t, "application/json", headers["datacontenttype"])
+	})
+
+	t.Run("ToCloudEvent round-trips through NewEventFromCloudEvent", func(t *testing.T) {
+		original := cloudevents.NewEvent()
+		original.SetID("round-trip-id")
+		original.SetType("com.example.roundtrip")
+		original.SetSource("/test")
+		original.SetExtension("region", "eu-west-1")
+		require.NoError(t, original.SetData("text/plain", []byte("hello")))
+
+		roundTripped := NewEventFromCloudEvent(original).ToCloudEvent()
+
+		assert.Equal(t, original.ID(), roundTripped.ID())
+		assert.Equal(t, original.Type(), roundTripped.Type())
+		assert.Equal(t, original.Source(), roundTripped.Source())
+		assert.Equal(t, original.DataContentType(), roundTripped.DataContentType())
+		assert.Equal(t, original.Data(), roundTripped.Data())
+		assert.Equal(t, "eu-west-1", roundTripped.Extensions()["region"])
+	})
+
+	t.Run("ToCloudEvent on a plain job event falls back to historical defaults", func(t *testing.T) {
+		event := Event{Body: []byte("plain body")}
+
+		ce := event.ToCloudEvent()
+		assert.Equal(t, "", ce.ID())
+		assert.Equal(t, "job", ce.Type())
+		assert.Equal(t, "application/octet-stream", ce.DataContentType())
+		assert.Equal(t, []byte("plain body"), ce.Data())
+	})
+}
@@ -0,0 +1,65 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/nuclio/nuclio/pkg/processor/trigger"
+	"github.com/nuclio/nuclio/pkg/processor/worker"
+)
+
+type factory struct {
+	trigger.Factory
+}
+
+func (f *factory) Create(parentLogger logger.Logger,
+	id string,
+	triggerConfiguration *functionconfig.Trigger,
+	runtimeConfiguration *runtime.Configuration,
+	namedWorkerAllocators *worker.AllocatorSyncMap,
+	restartTriggerChan chan trigger.Trigger) (trigger.Trigger, error) {
+
+	triggerLogger := parentLogger.GetChild(triggerConfiguration.Kind)
+
+	configuration, err := NewConfiguration(id, triggerConfiguration, runtimeConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create trigger")
+	}
+
+	workerAllocator, err := f.GetWorkerAllocator(
+		triggerConfiguration.WorkerAllocatorName,
+		namedWorkerAllocators,
+		func() (worker.Allocator, error) {
+			return worker.WorkerFactorySingleton.CreateFixedPoolWorkerAllocator(
+				triggerLogger,
+				configuration.NumWorkers,
+				runtimeConfiguration)
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create worker allocator")
+	}
+
+	triggerInstance, err := newTrigger(
+		triggerLogger,
+		workerAllocator,
+		configuration,
+		restartTriggerChan)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create trigger")
+	}
+
+	triggerLogger.InfoWith("✓ Job trigger created", "name", configuration.Name)
+
+	return triggerInstance, nil
+}
+
+func init() {
+	trigger.RegistrySingleton.Register("job", &factory{})
+}
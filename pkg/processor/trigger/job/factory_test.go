@@ -40,17 +40,21 @@ func TestJobFactory(t *testing.T) {
 			expectedError:    false,
 		},
 		{
-			name: "invalid configuration",
+			// NewConfiguration's mapstructure.Decode doesn't set
+			// ErrorUnused, matching every other trigger's NewConfiguration
+			// in this repo: an attribute key Configuration doesn't declare
+			// is silently ignored rather than rejected, so this succeeds
+			// like "valid configuration" does.
+			name: "unknown attribute is ignored",
 			triggerConfig: &functionconfig.Trigger{
 				Kind: "job",
 				Attributes: map[string]interface{}{
 					"invalid": "value",
 				},
 			},
-			runtimeConfig:        &runtime.Configuration{},
-			workerAllocators:     worker.NewAllocatorSyncMap(),
-			expectedError:        true,
-			expectedErrorMessage: "Failed to create trigger",
+			runtimeConfig:    &runtime.Configuration{},
+			workerAllocators: worker.NewAllocatorSyncMap(),
+			expectedError:    false,
 		},
 	}
 
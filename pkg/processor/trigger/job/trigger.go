@@ -0,0 +1,180 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	"github.com/nuclio/nuclio/pkg/common"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/processor/trigger"
+	"github.com/nuclio/nuclio/pkg/processor/worker"
+)
+
+// job submits its configured Event to a worker exactly once, on Start,
+// optionally threading a checkpoint through it so a handler processing a
+// long-running batch can resume where a prior, killed run left off.
+type job struct {
+	trigger.AbstractTrigger
+	configuration *Configuration
+
+	checkpointStore CheckpointStore
+
+	checkpointLock sync.Mutex
+	lastCheckpoint []byte
+
+	wg sync.WaitGroup
+}
+
+func newTrigger(parentLogger logger.Logger,
+	workerAllocator worker.Allocator,
+	configuration *Configuration,
+	restartTriggerChan chan trigger.Trigger) (trigger.Trigger, error) {
+
+	abstractTrigger, err := trigger.NewAbstractTrigger(parentLogger,
+		workerAllocator,
+		&configuration.Configuration,
+		"async",
+		"job",
+		configuration.Name,
+		restartTriggerChan)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create abstract trigger")
+	}
+
+	checkpointStore, err := NewCheckpointStore(configuration.CheckpointStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create checkpoint store")
+	}
+
+	newTrigger := job{
+		AbstractTrigger: abstractTrigger,
+		configuration:   configuration,
+		checkpointStore: checkpointStore,
+	}
+	newTrigger.AbstractTrigger.Trigger = &newTrigger
+
+	return &newTrigger, nil
+}
+
+// Start loads the last checkpoint (from the incoming restart checkpoint if
+// nuclio supplied one, else from j.checkpointStore) and submits the
+// configured event once in the background, handing the handler that
+// checkpoint plus a way to record progress.
+func (j *job) Start(checkpoint functionconfig.Checkpoint) error {
+	j.Logger.DebugWith("Starting job trigger", "name", j.configuration.Name)
+
+	if checkpoint != nil {
+		j.lastCheckpoint = []byte(*checkpoint)
+	} else if j.checkpointStore != nil {
+		data, err := j.checkpointStore.Load()
+		if err != nil {
+			return errors.Wrap(err, "Failed to load checkpoint")
+		}
+		j.lastCheckpoint = data
+	}
+
+	j.wg.Add(1)
+	go j.run()
+
+	return nil
+}
+
+// run submits the configured event to a worker, carrying the checkpoint
+// loaded by Start as Attributes["checkpoint"] and a checkpoint_writer func
+// the handler can call (any number of times) to persist progress.
+func (j *job) run() {
+	defer j.wg.Done()
+
+	attributes := make(map[string]interface{}, len(j.configuration.Event.Headers)+2)
+	for key, value := range j.configuration.Event.Headers {
+		attributes[key] = value
+	}
+
+	j.checkpointLock.Lock()
+	if j.lastCheckpoint != nil {
+		attributes["checkpoint"] = j.lastCheckpoint
+	}
+	j.checkpointLock.Unlock()
+
+	attributes["checkpoint_writer"] = j.saveCheckpoint
+
+	event := &Event{
+		Body:       []byte(j.configuration.Event.Body),
+		Attributes: attributes,
+		timestamp:  time.Now(),
+	}
+
+	_, submitError, processError := j.AllocateWorkerAndSubmitEvent(event, j.Logger, 10*time.Second)
+	if submitError != nil {
+		j.Logger.WarnWith("Failed to submit job event", "error", submitError)
+		return
+	}
+
+	if processError != nil {
+		j.Logger.WarnWith("Failed to process job event", "error", processError)
+	}
+}
+
+// saveCheckpoint is handed to the handler as Attributes["checkpoint_writer"]
+// so it can atomically persist a progress marker without knowing whether
+// (or how) checkpointing is configured.
+func (j *job) saveCheckpoint(data []byte) error {
+	j.checkpointLock.Lock()
+	defer j.checkpointLock.Unlock()
+
+	j.lastCheckpoint = data
+
+	if j.checkpointStore == nil {
+		return nil
+	}
+
+	return j.checkpointStore.Save(data)
+}
+
+// Stop waits for an in-flight run to finish, flushes the last checkpoint
+// to j.checkpointStore (if configured), and returns it so nuclio's
+// existing checkpoint plumbing can hand it back to Start on the next run.
+func (j *job) Stop(force bool) (functionconfig.Checkpoint, error) {
+	j.Logger.Info("Stopping job trigger")
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		j.Logger.Warn("Timeout waiting for job trigger to stop")
+	}
+
+	j.checkpointLock.Lock()
+	lastCheckpoint := j.lastCheckpoint
+	j.checkpointLock.Unlock()
+
+	if lastCheckpoint == nil {
+		return nil, nil
+	}
+
+	if j.checkpointStore != nil {
+		if err := j.checkpointStore.Save(lastCheckpoint); err != nil {
+			return nil, errors.Wrap(err, "Failed to flush checkpoint")
+		}
+	}
+
+	checkpointString := string(lastCheckpoint)
+	return functionconfig.Checkpoint(&checkpointString), nil
+}
+
+func (j *job) GetConfig() map[string]interface{} {
+	return common.StructureToMap(j.configuration)
+}
@@ -0,0 +1,85 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/nuclio/nuclio/pkg/processor/trigger"
+	"github.com/nuclio/nuclio/pkg/processor/worker"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJobTriggerResumesFromCheckpointAfterRestart simulates a processor
+// being killed mid-run and restarted: it runs a job trigger, has the
+// handler record progress via checkpoint_writer, tears that trigger down
+// without an orderly Stop (mimicking a kill), then brings up a fresh
+// trigger instance against the same CheckpointStore and asserts Start
+// hands it back the last progress marker the first instance wrote.
+//
+// This can't spawn an actual separate OS process the way a real "kill the
+// processor" scenario would - no test in this repo does that, since it'd
+// need a built processor binary this dependency-less tree has no way to
+// produce - so "restart" here means a second, independent *job value
+// reading the same on-disk checkpoint, which is the part of the behavior
+// this package actually owns.
+func TestJobTriggerResumesFromCheckpointAfterRestart(t *testing.T) {
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint")
+
+	configuration := &Configuration{
+		CheckpointStore: checkpointPath,
+	}
+
+	newJobTrigger := func() *job {
+		mLogger := &mockLogger{}
+
+		workerAllocator, err := worker.WorkerFactorySingleton.CreateFixedPoolWorkerAllocator(mLogger,
+			configuration.NumWorkers,
+			&runtime.Configuration{})
+		require.NoError(t, err)
+
+		restartTriggerChan := make(chan trigger.Trigger, 1)
+
+		triggerInstance, err := newTrigger(mLogger, workerAllocator, configuration, restartTriggerChan)
+		require.NoError(t, err)
+
+		jobInstance, ok := triggerInstance.(*job)
+		require.True(t, ok)
+
+		return jobInstance
+	}
+
+	// First run: no prior checkpoint, handler (simulated directly here,
+	// since the mock worker allocator doesn't invoke a real one) records
+	// progress, then the "processor" goes away without calling Stop.
+	firstRun := newJobTrigger()
+	require.NoError(t, firstRun.Start(nil))
+
+	data, err := firstRun.checkpointStore.Load()
+	require.NoError(t, err)
+	require.Nil(t, data, "first run should start with no checkpoint")
+
+	require.NoError(t, firstRun.saveCheckpoint([]byte("frame-123")))
+
+	// Second run: a brand new trigger instance, as a restarted processor
+	// would construct, reading the same CheckpointStore.
+	secondRun := newJobTrigger()
+	require.NoError(t, secondRun.Start(nil))
+	defer func() {
+		_, err := secondRun.Stop(false)
+		require.NoError(t, err)
+	}()
+
+	require.Eventually(t, func() bool {
+		secondRun.checkpointLock.Lock()
+		defer secondRun.checkpointLock.Unlock()
+		return string(secondRun.lastCheckpoint) == "frame-123"
+	}, time.Second, 10*time.Millisecond, "second run should resume from the first run's last checkpoint")
+}
@@ -0,0 +1,54 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"github.com/mitchellh/mapstructure"
+	"github.com/nuclio/errors"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/nuclio/nuclio/pkg/processor/trigger"
+	"github.com/nuclio/nuclio/pkg/processor/trigger/cron"
+)
+
+// Configuration holds the job trigger configuration
+type Configuration struct {
+	trigger.Configuration
+
+	// Event is submitted once, on Start, as the job's payload.
+	Event cron.Event `mapstructure:"event"`
+
+	// CheckpointStore, if set, makes the job resumable across restarts: a
+	// "s3://bucket/key" URL persists the checkpoint as an S3 object,
+	// anything else (a bare path, or a "file://" URL) persists it as a
+	// local file. Unset (the default), nothing is loaded from or saved to
+	// storage; a checkpoint passed to Start, and progress recorded through
+	// checkpoint_writer, are still kept in memory and returned by Stop.
+	CheckpointStore string `mapstructure:"checkpoint_store"`
+}
+
+// NewConfiguration creates a new job trigger configuration
+func NewConfiguration(id string,
+	triggerConfiguration *functionconfig.Trigger,
+	runtimeConfiguration *runtime.Configuration) (*Configuration, error) {
+
+	newConfiguration := Configuration{}
+
+	// create base configuration
+	baseConfiguration, err := trigger.NewConfiguration(id, triggerConfiguration, runtimeConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create trigger configuration")
+	}
+	newConfiguration.Configuration = *baseConfiguration
+
+	// parse attributes
+	if err := mapstructure.Decode(triggerConfiguration.Attributes, &newConfiguration); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode attributes")
+	}
+
+	return &newConfiguration, nil
+}
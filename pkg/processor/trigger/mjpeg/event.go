@@ -7,18 +7,132 @@ SPDX-License-Identifier: Apache-2.0
 package mjpeg
 
 import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/nuclio/errors"
 	"github.com/nuclio/nuclio-sdk-go"
 )
 
 // Event contains the data for a MJPEG frame event
 type Event struct {
 	nuclio.AbstractEvent
-	body      []byte
-	timestamp time.Time
-	frameNum  int64
-	url       string
+	body          []byte
+	timestamp     time.Time
+	frameNum      int64
+	url           string
+	droppedFrames int64
+
+	// decodeFrames mirrors Configuration.DecodeFrames: GetDecodedImage
+	// refuses to decode unless it's set, so a handler that never touches
+	// pixels doesn't pay jpeg.Decode's cost just because it called a
+	// getter.
+	decodeFrames bool
+
+	decodeOnce   sync.Once
+	decodedImage image.Image
+	decodeErr    error
+	width        int
+	height       int
+	pixelFormat  string
+
+	rgbaOnce  sync.Once
+	rgbaImage *image.RGBA
+}
+
+// GetDecodedImage lazily JPEG-decodes the frame body and caches the
+// result, populating GetWidth/GetHeight/GetPixelFormat as a side effect.
+// It returns an error unless Configuration.DecodeFrames is enabled on the
+// trigger, since every caller sharing that cost by default would defeat
+// the point of making it opt-in.
+func (e *Event) GetDecodedImage() (image.Image, error) {
+	if !e.decodeFrames {
+		return nil, errors.New("frame decoding is disabled; set decode_frames: true on the mjpeg trigger")
+	}
+
+	e.decodeOnce.Do(func() {
+		e.decodedImage, e.decodeErr = jpeg.Decode(bytes.NewReader(e.body))
+		if e.decodeErr != nil {
+			e.decodeErr = errors.Wrap(e.decodeErr, "Failed to decode JPEG frame")
+			return
+		}
+
+		bounds := e.decodedImage.Bounds()
+		e.width = bounds.Dx()
+		e.height = bounds.Dy()
+		e.pixelFormat = strings.TrimPrefix(fmt.Sprintf("%T", e.decodedImage), "*image.")
+	})
+
+	return e.decodedImage, e.decodeErr
+}
+
+// rgba returns the frame converted to *image.RGBA, decoding and
+// converting at most once per event.
+func (e *Event) rgba() *image.RGBA {
+	img, err := e.GetDecodedImage()
+	if err != nil || img == nil {
+		return nil
+	}
+
+	e.rgbaOnce.Do(func() {
+		if rgba, ok := img.(*image.RGBA); ok {
+			e.rgbaImage = rgba
+			return
+		}
+
+		bounds := img.Bounds()
+		converted := image.NewRGBA(bounds)
+		draw.Draw(converted, bounds, img, bounds.Min, draw.Src)
+		e.rgbaImage = converted
+	})
+
+	return e.rgbaImage
+}
+
+// GetRGBA returns the frame's pixels converted to 8-bit RGBA, or nil if
+// decoding is disabled or failed. Use GetStride to index into it.
+func (e *Event) GetRGBA() []byte {
+	if rgba := e.rgba(); rgba != nil {
+		return rgba.Pix
+	}
+	return nil
+}
+
+// GetStride returns the row stride (in bytes) of the slice GetRGBA
+// returns, or 0 if decoding is disabled or failed.
+func (e *Event) GetStride() int {
+	if rgba := e.rgba(); rgba != nil {
+		return rgba.Stride
+	}
+	return 0
+}
+
+// GetWidth returns the decoded frame's width in pixels, or 0 if decoding
+// is disabled or failed.
+func (e *Event) GetWidth() int {
+	_, _ = e.GetDecodedImage()
+	return e.width
+}
+
+// GetHeight returns the decoded frame's height in pixels, or 0 if
+// decoding is disabled or failed.
+func (e *Event) GetHeight() int {
+	_, _ = e.GetDecodedImage()
+	return e.height
+}
+
+// GetPixelFormat returns the decoded image's underlying Go image type
+// (e.g. "YCbCr", "Gray"), or an empty string if decoding is disabled or
+// failed.
+func (e *Event) GetPixelFormat() string {
+	_, _ = e.GetDecodedImage()
+	return e.pixelFormat
 }
 
 // GetBody returns the frame data (JPEG image bytes)
@@ -103,11 +217,22 @@ func (e *Event) GetContentType() string {
 
 // GetFields returns the event fields
 func (e *Event) GetFields() map[string]interface{} {
-	return map[string]interface{}{
-		"frame_num": e.frameNum,
-		"url":       e.url,
-		"timestamp": e.timestamp,
+	fields := map[string]interface{}{
+		"frame_num":      e.frameNum,
+		"url":            e.url,
+		"timestamp":      e.timestamp,
+		"dropped_frames": e.droppedFrames,
+	}
+
+	if e.decodeFrames {
+		if _, err := e.GetDecodedImage(); err == nil {
+			fields["width"] = e.width
+			fields["height"] = e.height
+			fields["pixel_format"] = e.pixelFormat
+		}
 	}
+
+	return fields
 }
 
 // GetField returns a specific event field
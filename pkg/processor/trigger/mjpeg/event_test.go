@@ -7,12 +7,29 @@ SPDX-License-Identifier: Apache-2.0
 package mjpeg
 
 import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// encodeTestJPEG builds a minimal 2x1 JPEG in memory for decode tests.
+func encodeTestJPEG(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	img.Set(1, 0, color.RGBA{B: 255, A: 255})
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("failed to encode test JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
 func TestEvent(t *testing.T) {
 	now := time.Now()
 	frameData := []byte{0xFF, 0xD8, 0xFF, 0xE0} // JPEG header
@@ -159,6 +176,69 @@ func TestEvent(t *testing.T) {
 	})
 }
 
+func TestEventDecodedImage(t *testing.T) {
+	// 2x1 red/blue JPEG, encoded in memory by encodeTestJPEG to avoid a test fixture file.
+	frameData := encodeTestJPEG(t)
+
+	t.Run("DecodingDisabled", func(t *testing.T) {
+		event := &Event{body: frameData}
+
+		img, err := event.GetDecodedImage()
+		assert.Error(t, err)
+		assert.Nil(t, img)
+		assert.Nil(t, event.GetRGBA())
+		assert.Equal(t, 0, event.GetStride())
+		assert.Equal(t, 0, event.GetWidth())
+		assert.Equal(t, 0, event.GetHeight())
+		assert.Equal(t, "", event.GetPixelFormat())
+
+		fields := event.GetFields()
+		assert.NotContains(t, fields, "width")
+		assert.NotContains(t, fields, "height")
+		assert.NotContains(t, fields, "pixel_format")
+	})
+
+	t.Run("DecodingEnabled", func(t *testing.T) {
+		event := &Event{body: frameData, decodeFrames: true}
+
+		img, err := event.GetDecodedImage()
+		assert.NoError(t, err)
+		assert.NotNil(t, img)
+
+		assert.Equal(t, 2, event.GetWidth())
+		assert.Equal(t, 1, event.GetHeight())
+		assert.NotEmpty(t, event.GetPixelFormat())
+
+		rgba := event.GetRGBA()
+		assert.NotNil(t, rgba)
+		assert.Equal(t, event.GetStride()*event.GetHeight(), len(rgba))
+
+		fields := event.GetFields()
+		assert.Equal(t, 2, fields["width"])
+		assert.Equal(t, 1, fields["height"])
+	})
+
+	t.Run("InvalidBody", func(t *testing.T) {
+		event := &Event{body: []byte("not a jpeg"), decodeFrames: true}
+
+		img, err := event.GetDecodedImage()
+		assert.Error(t, err)
+		assert.Nil(t, img)
+		assert.Nil(t, event.GetRGBA())
+	})
+}
+
+func TestEventDroppedFrames(t *testing.T) {
+	event := &Event{
+		body:          []byte{0xFF, 0xD8, 0xFF, 0xE0},
+		frameNum:      5,
+		droppedFrames: 3,
+	}
+
+	fields := event.GetFields()
+	assert.Equal(t, int64(3), fields["dropped_frames"])
+}
+
 func TestEventWithDifferentData(t *testing.T) {
 	t.Run("LargeFrame", func(t *testing.T) {
 		largeData := make([]byte, 1024*1024) // 1MB
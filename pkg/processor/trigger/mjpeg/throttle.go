@@ -0,0 +1,56 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mjpeg
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+)
+
+// frameThrottle holds at most the single most recently pushed frame,
+// counting how many were dropped to make room for a newer one - the
+// backpressure policy Configuration.MaxFPS needs (drop the oldest queued
+// frame, not the newest) so a slow consumer still sees the most current
+// frame once it catches up, rather than working through a backlog.
+type frameThrottle struct {
+	ch      chan packets.Frame
+	dropped int64
+}
+
+// newFrameThrottle creates a frameThrottle holding a single frame.
+func newFrameThrottle() *frameThrottle {
+	return &frameThrottle{ch: make(chan packets.Frame, 1)}
+}
+
+// push enqueues frame, dropping and counting whatever frame was already
+// queued (but not yet consumed) if there was one.
+func (t *frameThrottle) push(frame packets.Frame) {
+	select {
+	case <-t.ch:
+		atomic.AddInt64(&t.dropped, 1)
+	default:
+	}
+
+	t.ch <- frame
+}
+
+// pop blocks until a frame is available or ctx is done.
+func (t *frameThrottle) pop(ctx context.Context) (packets.Frame, bool) {
+	select {
+	case frame := <-t.ch:
+		return frame, true
+	case <-ctx.Done():
+		return packets.Frame{}, false
+	}
+}
+
+// Dropped returns the number of frames dropped so far.
+func (t *frameThrottle) Dropped() int64 {
+	return atomic.LoadInt64(&t.dropped)
+}
@@ -0,0 +1,44 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mjpeg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameThrottleDropsOldest(t *testing.T) {
+	throttle := newFrameThrottle()
+
+	first := packets.Frame{Data: []byte("first")}
+	second := packets.Frame{Data: []byte("second")}
+
+	throttle.push(first)
+	throttle.push(second)
+
+	assert.Equal(t, int64(1), throttle.Dropped())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frame, ok := throttle.pop(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, second, frame)
+}
+
+func TestFrameThrottlePopCancelled(t *testing.T) {
+	throttle := newFrameThrottle()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, ok := throttle.pop(ctx)
+	assert.False(t, ok)
+}
@@ -7,12 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package mjpeg
 
 import (
-	"bufio"
-	"bytes"
 	"context"
-	"io"
-	"net/http"
-	"strconv"
 	"sync"
 	"time"
 
@@ -22,16 +17,27 @@ import (
 	"github.com/nuclio/nuclio/pkg/functionconfig"
 	"github.com/nuclio/nuclio/pkg/processor/trigger"
 	"github.com/nuclio/nuclio/pkg/processor/worker"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/ffmpeg"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/gortsplib"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/httpmjpeg"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/packets"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
 )
 
 type mjpeg struct {
 	trigger.AbstractTrigger
 	configuration *Configuration
+	sink          sink.Sink
 
 	ctx        context.Context
 	cancel     context.CancelFunc
 	wg         sync.WaitGroup
 	frameCount int64
+
+	// throttle is set instead of calling processFrame directly when
+	// Configuration.MaxFPS > 0.
+	throttle *frameThrottle
 }
 
 func newTrigger(logger logger.Logger,
@@ -67,12 +73,78 @@ func newTrigger(logger logger.Logger,
 func (m *mjpeg) Start(checkpoint functionconfig.Checkpoint) error {
 	m.Logger.DebugWith("Starting MJPEG trigger", "url", m.configuration.URL)
 
+	if m.configuration.Sink != nil {
+		if err := m.startSink(); err != nil {
+			return errors.Wrap(err, "Failed to start sink")
+		}
+	}
+
+	if m.configuration.MaxFPS > 0 {
+		m.throttle = newFrameThrottle()
+		m.wg.Add(1)
+		go m.pumpThrottled()
+	}
+
 	m.wg.Add(1)
 	go m.streamFrames()
 
 	return nil
 }
 
+// pumpThrottled consumes m.throttle at Configuration.MaxFPS, calling
+// processFrame on whatever frame is queued - the most recent one pushed,
+// since frameThrottle itself already drops older ones.
+func (m *mjpeg) pumpThrottled() {
+	defer m.wg.Done()
+
+	interval := time.Duration(float64(time.Second) / m.configuration.MaxFPS)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			if frame, ok := m.throttle.pop(m.ctx); ok {
+				m.processFrame(frame)
+			}
+		}
+	}
+}
+
+// startSink instantiates the pluggable sink described by
+// m.configuration.Sink via the shared sink registry and starts it.
+// Processed frames are additionally written to it from processFrame,
+// alongside the existing direct-to-worker submission.
+func (m *mjpeg) startSink() error {
+	s, err := sink.RegistrySingleton.Create(m.Logger, m.configuration.Sink.Kind, m.configuration.Sink.Attributes)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create sink of kind %q", m.configuration.Sink.Kind)
+	}
+
+	if err := s.Start(); err != nil {
+		return errors.Wrap(err, "Failed to start sink")
+	}
+
+	m.sink = s
+	return nil
+}
+
+// newCaptureClient builds the capture.Client selected by
+// m.configuration.Backend.
+func (m *mjpeg) newCaptureClient() capture.Client {
+	switch m.configuration.Backend {
+	case "gortsplib":
+		return gortsplib.NewClient(m.configuration.URL)
+	case "ffmpeg":
+		return ffmpeg.NewClient(m.configuration.URL)
+	default:
+		readTimeout := time.Duration(m.configuration.ReadTimeoutSeconds) * time.Second
+		return httpmjpeg.NewClient(m.configuration.URL, m.configuration.MaxFrameSize, readTimeout)
+	}
+}
+
 func (m *mjpeg) streamFrames() {
 	defer m.wg.Done()
 
@@ -96,71 +168,22 @@ func (m *mjpeg) streamFrames() {
 	}
 }
 
+// connectAndStream connects the configured capture backend and loops over
+// its frames until it errors out or the trigger is stopped.
 func (m *mjpeg) connectAndStream() error {
-	m.Logger.InfoWith("Connecting to MJPEG stream", "url", m.configuration.URL)
+	m.Logger.InfoWith("Connecting to MJPEG stream",
+		"url", m.configuration.URL,
+		"backend", m.configuration.Backend)
 
-	req, err := http.NewRequestWithContext(m.ctx, "GET", m.configuration.URL, nil)
-	if err != nil {
-		return errors.Wrap(err, "Failed to create request")
-	}
+	client := m.newCaptureClient()
+	defer client.Close()
 
-	client := &http.Client{
-		Timeout: 0, // No timeout for streaming
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "Failed to connect to MJPEG stream")
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf("Unexpected status code: %d", resp.StatusCode)
+	if err := client.Connect(m.ctx); err != nil {
+		return errors.Wrap(err, "Failed to connect capture client")
 	}
 
 	m.Logger.Info("Connected to MJPEG stream, reading frames")
 
-	// Read the boundary from Content-Type header
-	boundary := m.extractBoundary(resp.Header.Get("Content-Type"))
-	if boundary == "" {
-		m.Logger.Warn("Could not extract boundary from Content-Type, using default")
-		boundary = "--myboundary"
-	} else {
-		boundary = "--" + boundary
-	}
-
-	return m.readFrames(resp.Body, boundary)
-}
-
-func (m *mjpeg) extractBoundary(contentType string) string {
-	// Parse Content-Type header to extract boundary
-	// Expected format: multipart/x-mixed-replace;boundary=myboundary
-	// or with spaces: multipart/x-mixed-replace; boundary = myboundary
-
-	// First, find the "boundary" keyword
-	idx := bytes.Index([]byte(contentType), []byte("boundary"))
-	if idx == -1 {
-		return ""
-	}
-
-	// Get the substring starting from "boundary"
-	remaining := contentType[idx+len("boundary"):]
-
-	// Find the "=" sign
-	eqIdx := bytes.IndexByte([]byte(remaining), '=')
-	if eqIdx == -1 {
-		return ""
-	}
-
-	// Get everything after the "=" and trim spaces
-	boundary := bytes.TrimSpace([]byte(remaining[eqIdx+1:]))
-	return string(boundary)
-}
-
-func (m *mjpeg) readFrames(body io.ReadCloser, boundary string) error {
-	reader := bufio.NewReader(body)
-	boundaryBytes := []byte(boundary)
-
 	for {
 		select {
 		case <-m.ctx.Done():
@@ -168,106 +191,55 @@ func (m *mjpeg) readFrames(body io.ReadCloser, boundary string) error {
 		default:
 		}
 
-		// Read until boundary
-		_, err := m.readUntil(reader, boundaryBytes)
+		frame, err := client.NextFrame(m.ctx)
 		if err != nil {
-			return errors.Wrap(err, "Failed to read boundary")
-		}
-
-		// Read headers
-		headers, err := m.readHeaders(reader)
-		if err != nil {
-			return errors.Wrap(err, "Failed to read headers")
-		}
-
-		// Get content length
-		contentLength := m.getContentLength(headers)
-		if contentLength <= 0 {
-			m.Logger.Warn("Invalid or missing Content-Length header")
-			continue
-		}
-
-		// Read frame data
-		frameData := make([]byte, contentLength)
-		_, err = io.ReadFull(reader, frameData)
-		if err != nil {
-			return errors.Wrap(err, "Failed to read frame data")
+			return errors.Wrap(err, "Failed to read frame")
 		}
 
 		m.frameCount++
 
 		// Apply processing factor (skip frames if needed)
 		if m.frameCount%int64(m.configuration.ProcessingFactor) == 0 {
-			m.processFrame(frameData)
+			if m.throttle != nil {
+				m.throttle.push(frame)
+			} else {
+				m.processFrame(frame)
+			}
 		} else {
 			m.Logger.DebugWith("Skipping frame", "frame", m.frameCount, "factor", m.configuration.ProcessingFactor)
 		}
 	}
 }
 
-func (m *mjpeg) readUntil(reader *bufio.Reader, delimiter []byte) ([]byte, error) {
-	var result []byte
-	for {
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, line...)
-		if bytes.Contains(line, delimiter) {
-			return result, nil
-		}
-	}
-}
-
-func (m *mjpeg) readHeaders(reader *bufio.Reader) (map[string]string, error) {
-	headers := make(map[string]string)
-	for {
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			return nil, err
-		}
-
-		// Empty line marks end of headers
-		trimmed := bytes.TrimSpace(line)
-		if len(trimmed) == 0 {
-			break
-		}
-
-		// Parse header
-		parts := bytes.SplitN(trimmed, []byte(":"), 2)
-		if len(parts) == 2 {
-			key := string(bytes.TrimSpace(parts[0]))
-			value := string(bytes.TrimSpace(parts[1]))
-			headers[key] = value
-		}
+func (m *mjpeg) processFrame(frame packets.Frame) {
+	var dropped int64
+	if m.throttle != nil {
+		dropped = m.throttle.Dropped()
 	}
-	return headers, nil
-}
 
-func (m *mjpeg) getContentLength(headers map[string]string) int {
-	// Try different case variations
-	for _, key := range []string{"Content-Length", "content-length", "Content-length"} {
-		if val, ok := headers[key]; ok {
-			length, err := strconv.Atoi(val)
-			if err == nil && length > 0 {
-				return length
-			}
-		}
-	}
-	return 0
-}
-
-func (m *mjpeg) processFrame(frameData []byte) {
 	event := &Event{
-		body:      frameData,
-		timestamp: time.Now(),
-		frameNum:  m.frameCount,
-		url:       m.configuration.URL,
+		body:          frame.Data,
+		timestamp:     time.Now(),
+		frameNum:      m.frameCount,
+		url:           m.configuration.URL,
+		droppedFrames: dropped,
+		decodeFrames:  m.configuration.DecodeFrames,
 	}
 
 	m.Logger.DebugWith("Processing frame",
 		"frame", m.frameCount,
-		"size", len(frameData))
+		"size", len(frame.Data))
+
+	if m.sink != nil {
+		metadata := map[string]interface{}{
+			"url":       m.configuration.URL,
+			"frameNum":  m.frameCount,
+			"timestamp": event.timestamp,
+		}
+		if err := m.sink.Write(m.ctx, frame.Data, metadata); err != nil {
+			m.Logger.WarnWith("Failed to write frame to sink", "error", err)
+		}
+	}
 
 	// Allocate worker and submit event
 	response, submitError, processError := m.AllocateWorkerAndSubmitEvent(
@@ -295,6 +267,12 @@ func (m *mjpeg) Stop(force bool) (functionconfig.Checkpoint, error) {
 
 	m.cancel()
 
+	if m.sink != nil {
+		if err := m.sink.Stop(force); err != nil {
+			m.Logger.WarnWith("Failed to stop sink", "error", err)
+		}
+	}
+
 	// Wait for goroutines to finish with timeout
 	done := make(chan struct{})
 	go func() {
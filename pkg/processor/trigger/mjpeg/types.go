@@ -13,12 +13,31 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/nuclio/errors"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/capture/httpmjpeg"
 )
 
 const (
 	DefaultProcessingFactor = 1 // Process every frame by default
+
+	// DefaultBackend is the capture.Client implementation used when
+	// attributes.backend is not set.
+	DefaultBackend = "http"
+
+	// DefaultMaxFrameSize and DefaultReadTimeoutSeconds configure the
+	// "http" backend's httpmjpeg.Client; see its doc comments for why
+	// each exists.
+	DefaultMaxFrameSize       = httpmjpeg.DefaultMaxFrameSize
+	DefaultReadTimeoutSeconds = 10
 )
 
+// validBackends are the capture.Client implementations selectable via
+// attributes.backend.
+var validBackends = map[string]bool{
+	"http":      true,
+	"gortsplib": true,
+	"ffmpeg":    true,
+}
+
 // SinkConfiguration holds the sink configuration for the trigger
 type SinkConfiguration struct {
 	Kind       string                 `mapstructure:"kind"`
@@ -31,6 +50,35 @@ type Configuration struct {
 	URL              string             `mapstructure:"url"`
 	ProcessingFactor int                `mapstructure:"processing_factor"`
 	Sink             *SinkConfiguration `mapstructure:"sink"`
+
+	// Backend selects the capture.Client implementation used to read
+	// frames from URL: "http" (multipart/x-mixed-replace, the default),
+	// "gortsplib" (RTSP MJPEG track), or "ffmpeg" (spawns ffmpeg and reads
+	// its raw mjpeg muxer output, for sources neither of the others can
+	// reach directly).
+	Backend string `mapstructure:"backend"`
+
+	// MaxFrameSize and ReadTimeoutSeconds configure the "http" backend:
+	// MaxFrameSize rejects pathological frames, and ReadTimeoutSeconds
+	// bounds how long it waits for the next byte of a frame before
+	// forcing a reconnect instead of hanging inside the multipart reader.
+	// Both are ignored by the "gortsplib" and "ffmpeg" backends.
+	MaxFrameSize       int `mapstructure:"max_frame_size"`
+	ReadTimeoutSeconds int `mapstructure:"read_timeout_seconds"`
+
+	// DecodeFrames enables Event.GetDecodedImage/GetRGBA/GetWidth/
+	// GetHeight/GetPixelFormat by JPEG-decoding a frame the first time one
+	// of them is called and caching the result on the event. False (the default) leaves frames
+	// as opaque bytes, avoiding the decode cost for handlers that forward
+	// the JPEG body untouched.
+	DecodeFrames bool `mapstructure:"decode_frames"`
+
+	// MaxFPS, if > 0, throttles frame processing to at most this many
+	// frames per second: once frames arrive faster than that, the oldest
+	// not-yet-processed frame is dropped in favor of the newest one, so
+	// handlers always see current, not stale, frames. 0 (the default)
+	// processes every frame ProcessingFactor selects, unthrottled.
+	MaxFPS float64 `mapstructure:"max_fps"`
 }
 
 // NewConfiguration creates a new MJPEG trigger configuration
@@ -39,7 +87,10 @@ func NewConfiguration(id string,
 	runtimeConfiguration *runtime.Configuration) (*Configuration, error) {
 
 	newConfiguration := Configuration{
-		ProcessingFactor: DefaultProcessingFactor,
+		ProcessingFactor:   DefaultProcessingFactor,
+		Backend:            DefaultBackend,
+		MaxFrameSize:       DefaultMaxFrameSize,
+		ReadTimeoutSeconds: DefaultReadTimeoutSeconds,
 	}
 
 	// create base configuration
@@ -64,5 +115,30 @@ func NewConfiguration(id string,
 		return nil, errors.New("processing_factor must be >= 1")
 	}
 
+	// validate sink, if configured
+	if newConfiguration.Sink != nil && newConfiguration.Sink.Kind == "" {
+		return nil, errors.New("sink.kind is required when sink is configured")
+	}
+
+	// validate backend
+	if newConfiguration.Backend == "" {
+		newConfiguration.Backend = DefaultBackend
+	}
+	if !validBackends[newConfiguration.Backend] {
+		return nil, errors.Errorf("backend must be one of 'http', 'gortsplib', 'ffmpeg', got %q", newConfiguration.Backend)
+	}
+
+	// validate http backend tuning
+	if newConfiguration.MaxFrameSize <= 0 {
+		newConfiguration.MaxFrameSize = DefaultMaxFrameSize
+	}
+	if newConfiguration.ReadTimeoutSeconds <= 0 {
+		newConfiguration.ReadTimeoutSeconds = DefaultReadTimeoutSeconds
+	}
+
+	if newConfiguration.MaxFPS < 0 {
+		return nil, errors.New("max_fps must not be negative")
+	}
+
 	return &newConfiguration, nil
 }
@@ -106,6 +106,53 @@ func TestTypes(t *testing.T) {
 		assert.Contains(t, err.Error(), "processing_factor must be >= 1")
 	})
 
+	t.Run("NewConfiguration_DefaultDecodeFramesAndMaxFPS", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "mjpeg",
+			Attributes: map[string]interface{}{
+				"url": "http://example.com/stream.mjpg",
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.NoError(t, err)
+		assert.NotNil(t, config)
+		assert.False(t, config.DecodeFrames)
+		assert.Equal(t, float64(0), config.MaxFPS)
+	})
+
+	t.Run("NewConfiguration_DecodeFramesAndMaxFPS", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "mjpeg",
+			Attributes: map[string]interface{}{
+				"url":           "http://example.com/stream.mjpg",
+				"decode_frames": true,
+				"max_fps":       5.0,
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.NoError(t, err)
+		assert.NotNil(t, config)
+		assert.True(t, config.DecodeFrames)
+		assert.Equal(t, 5.0, config.MaxFPS)
+	})
+
+	t.Run("NewConfiguration_NegativeMaxFPS", func(t *testing.T) {
+		triggerConfig := &functionconfig.Trigger{
+			Kind: "mjpeg",
+			Attributes: map[string]interface{}{
+				"url":     "http://example.com/stream.mjpg",
+				"max_fps": -1.0,
+			},
+		}
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+		assert.Nil(t, config)
+		assert.Contains(t, err.Error(), "max_fps must not be negative")
+	})
+
 	t.Run("NewConfiguration_ProcessingFactor10", func(t *testing.T) {
 		triggerConfig := &functionconfig.Trigger{
 			Kind: "mjpeg",
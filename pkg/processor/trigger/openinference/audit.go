@@ -0,0 +1,215 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+)
+
+// auditTensorSummary is the redacted view of a single tensor recorded in an
+// auditRecord. Datatype/Shape are omitted when Configuration.AuditRedaction
+// is "full", and Data is populated only when it is "none".
+type auditTensorSummary struct {
+	Name     string  `json:"name"`
+	Datatype string  `json:"datatype,omitempty"`
+	Shape    []int64 `json:"shape,omitempty"`
+	Data     any     `json:"data,omitempty"`
+}
+
+// auditRecord is the JSON document written to every sink named in
+// Configuration.AuditSinks after a ModelInfer or ModelStreamInfer message
+// completes.
+type auditRecord struct {
+	RequestID    string               `json:"request_id"`
+	ModelName    string               `json:"model_name"`
+	ModelVersion string               `json:"model_version"`
+	Protocol     string               `json:"protocol"`
+	Inputs       []auditTensorSummary `json:"inputs,omitempty"`
+	Outputs      []auditTensorSummary `json:"outputs,omitempty"`
+	LatencyMS    int64                `json:"latency_ms"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// auditTensor builds the redacted audit view of a single tensor according
+// to redaction ("full", "shape-only", or "none").
+func auditTensor(name, datatype string, shape []int64, data any, redaction string) auditTensorSummary {
+	summary := auditTensorSummary{Name: name}
+	if redaction == AuditRedactionFull {
+		return summary
+	}
+
+	summary.Datatype = datatype
+	summary.Shape = shape
+	if redaction == AuditRedactionNone {
+		summary.Data = data
+	}
+	return summary
+}
+
+// auditDispatcher fans audit records out to the sinks resolved from
+// Configuration.AuditSinks on a best-effort, non-blocking basis: submit
+// never waits on a sink, and a full queue drops the record and increments
+// droppedCount instead of slowing down inference. There is no Prometheus
+// (or other) metrics registry wired into this trigger yet, so droppedCount
+// is a small in-memory counter for now, mirroring errorMetrics.
+type auditDispatcher struct {
+	logger logger.Logger
+	sinks  []sink.Sink
+	queue  chan *auditRecord
+
+	dropped int64
+	wg      sync.WaitGroup
+}
+
+// newAuditDispatcher resolves kinds against sink.RegistrySingleton, starts
+// every resolved sink, and launches the background goroutine that drains
+// queued records to them. Returns nil, nil when kinds is empty, which
+// disables auditing entirely.
+func newAuditDispatcher(logger logger.Logger, kinds []string, queueSize int) (*auditDispatcher, error) {
+	if len(kinds) == 0 {
+		return nil, nil
+	}
+
+	sinks := make([]sink.Sink, 0, len(kinds))
+	for _, kind := range kinds {
+		auditSink, err := sink.RegistrySingleton.Create(logger, kind, map[string]interface{}{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create audit sink %q", kind)
+		}
+
+		if err := auditSink.Start(); err != nil {
+			return nil, errors.Wrapf(err, "Failed to start audit sink %q", kind)
+		}
+
+		sinks = append(sinks, auditSink)
+	}
+
+	dispatcher := &auditDispatcher{
+		logger: logger,
+		sinks:  sinks,
+		queue:  make(chan *auditRecord, queueSize),
+	}
+
+	dispatcher.wg.Add(1)
+	go dispatcher.run()
+
+	return dispatcher, nil
+}
+
+func (d *auditDispatcher) run() {
+	defer d.wg.Done()
+
+	for record := range d.queue {
+		d.write(record)
+	}
+}
+
+func (d *auditDispatcher) write(record *auditRecord) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		d.logger.WarnWith("Failed to marshal audit record", "error", err)
+		return
+	}
+
+	for _, auditSink := range d.sinks {
+		if err := auditSink.Write(context.Background(), data, nil); err != nil {
+			d.logger.WarnWith("Failed to write audit record", "sink", auditSink.GetKind(), "error", err)
+		}
+	}
+}
+
+// submit enqueues record without blocking. A nil dispatcher (auditing
+// disabled) and a full queue are both silently absorbed, the latter
+// incrementing droppedCount.
+func (d *auditDispatcher) submit(record *auditRecord) {
+	if d == nil {
+		return
+	}
+
+	select {
+	case d.queue <- record:
+	default:
+		atomic.AddInt64(&d.dropped, 1)
+	}
+}
+
+// droppedCount returns the number of audit records dropped so far because
+// the queue was full.
+func (d *auditDispatcher) droppedCount() int64 {
+	if d == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&d.dropped)
+}
+
+// stop drains the queue and stops every configured sink. Safe to call on a
+// nil dispatcher.
+func (d *auditDispatcher) stop() {
+	if d == nil {
+		return
+	}
+
+	close(d.queue)
+	d.wg.Wait()
+
+	for _, auditSink := range d.sinks {
+		if err := auditSink.Stop(false); err != nil {
+			d.logger.WarnWith("Failed to stop audit sink", "sink", auditSink.GetKind(), "error", err)
+		}
+	}
+}
+
+// emitAuditRecord builds and submits an audit record for one ModelInfer (or
+// ModelStreamInfer message) on a best-effort basis; it never returns an
+// error since auditing must never affect the inference response.
+func (oi *openInference) emitAuditRecord(
+	requestID string,
+	protocol string,
+	req *RESTInferenceRequest,
+	resp *RESTInferenceResponse,
+	latency time.Duration,
+	inferErr error,
+) {
+	if oi.auditDispatcher == nil {
+		return
+	}
+
+	redaction := oi.configuration.AuditRedaction
+	record := &auditRecord{
+		RequestID:    requestID,
+		ModelName:    oi.configuration.ModelName,
+		ModelVersion: oi.configuration.ModelVersion,
+		Protocol:     protocol,
+		LatencyMS:    latency.Milliseconds(),
+	}
+
+	if inferErr != nil {
+		record.Error = inferErr.Error()
+	}
+
+	if req != nil {
+		for _, input := range req.Inputs {
+			record.Inputs = append(record.Inputs, auditTensor(input.Name, input.Datatype, input.Shape, input.Data, redaction))
+		}
+	}
+
+	if resp != nil {
+		for _, output := range resp.Outputs {
+			record.Outputs = append(record.Outputs, auditTensor(output.Name, output.Datatype, output.Shape, output.Data, redaction))
+		}
+	}
+
+	oi.auditDispatcher.submit(record)
+}
@@ -0,0 +1,99 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAuditSink is a minimal sink.Sink used only to exercise auditDispatcher
+// without depending on a concrete sink-kind package.
+type fakeAuditSink struct{}
+
+func (s *fakeAuditSink) Start() error          { return nil }
+func (s *fakeAuditSink) Stop(force bool) error { return nil }
+func (s *fakeAuditSink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	return nil
+}
+func (s *fakeAuditSink) GetKind() string                   { return "fake-audit-sink" }
+func (s *fakeAuditSink) GetConfig() map[string]interface{} { return nil }
+
+type fakeAuditSinkFactory struct{}
+
+func (f *fakeAuditSinkFactory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
+	return &fakeAuditSink{}, nil
+}
+func (f *fakeAuditSinkFactory) GetKind() string { return "fake-audit-sink" }
+
+func init() {
+	sink.RegistrySingleton.Register("fake-audit-sink", &fakeAuditSinkFactory{})
+}
+
+func TestAuditTensorRedaction(t *testing.T) {
+	shape := []int64{1, 3}
+
+	full := auditTensor("input", "FP32", shape, []float32{1, 2, 3}, AuditRedactionFull)
+	assert.Equal(t, "input", full.Name)
+	assert.Empty(t, full.Datatype)
+	assert.Nil(t, full.Shape)
+	assert.Nil(t, full.Data)
+
+	shapeOnly := auditTensor("input", "FP32", shape, []float32{1, 2, 3}, AuditRedactionShapeOnly)
+	assert.Equal(t, "FP32", shapeOnly.Datatype)
+	assert.Equal(t, shape, shapeOnly.Shape)
+	assert.Nil(t, shapeOnly.Data)
+
+	none := auditTensor("input", "FP32", shape, []float32{1, 2, 3}, AuditRedactionNone)
+	assert.Equal(t, []float32{1, 2, 3}, none.Data)
+}
+
+func TestAuditDispatcherEmptyKindsDisablesAuditing(t *testing.T) {
+	dispatcher, err := newAuditDispatcher(nil, nil, DefaultAuditQueueSize)
+	assert.NoError(t, err)
+	assert.Nil(t, dispatcher)
+
+	// submit/droppedCount/stop must all be safe no-ops on a nil dispatcher.
+	dispatcher.submit(&auditRecord{RequestID: "req-1"})
+	assert.Equal(t, int64(0), dispatcher.droppedCount())
+	dispatcher.stop()
+}
+
+func TestAuditDispatcherUnknownKind(t *testing.T) {
+	dispatcher, err := newAuditDispatcher(nil, []string{"no-such-sink-kind"}, DefaultAuditQueueSize)
+	assert.Error(t, err)
+	assert.Nil(t, dispatcher)
+}
+
+func TestAuditDispatcherDropsWhenQueueFull(t *testing.T) {
+	dispatcher, err := newAuditDispatcher(nil, []string{"fake-audit-sink"}, 1)
+	assert.NoError(t, err)
+	defer dispatcher.stop()
+
+	// Fill the queue faster than the drain goroutine can empty it by
+	// submitting a burst; at least one submission should be dropped.
+	for i := 0; i < 100; i++ {
+		dispatcher.submit(&auditRecord{RequestID: "req"})
+	}
+
+	assert.Eventually(t, func() bool {
+		return dispatcher.droppedCount() > 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEmitAuditRecordNilDispatcherIsNoop(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	assert.Nil(t, oi.auditDispatcher)
+
+	// Must not panic when auditing is disabled.
+	oi.emitAuditRecord("req-1", "grpc", &RESTInferenceRequest{}, nil, time.Millisecond, nil)
+}
@@ -0,0 +1,515 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+
+KServe v2 "binary tensor data" extension support, shared by the REST and
+gRPC transports:
+https://github.com/kserve/open-inference-protocol/blob/main/specification/protocol/binary_data.md
+*/
+
+package openinference
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// InferenceHeaderContentLengthHeader is the HTTP header (and, symmetrically,
+// the field the gRPC path mirrors into raw_input_contents/raw_output_contents
+// framing) that tells a binary-extension request/response where its leading
+// JSON portion ends and its raw binary tensor data begins.
+const InferenceHeaderContentLengthHeader = "Inference-Header-Content-Length"
+
+// tensorElementSize returns the per-element byte width of every binary
+// datatype except BYTES, which has no fixed width (see DecodeBinaryTensorData).
+func tensorElementSize(datatype string) (int, error) {
+	switch datatype {
+	case "BOOL", "UINT8", "INT8":
+		return 1, nil
+	case "UINT16", "INT16", "FP16":
+		return 2, nil
+	case "UINT32", "INT32", "FP32":
+		return 4, nil
+	case "UINT64", "INT64", "FP64":
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("unsupported binary datatype: %s", datatype)
+	}
+}
+
+// SplitBinaryRequestBody splits body into its JSON header and raw binary
+// suffix per the KServe v2 binary tensor data extension: headerLen (parsed
+// from the Inference-Header-Content-Length request header) is the number of
+// leading bytes that are the JSON inference request; everything after it is
+// binary tensor data referenced by each tensor's parameters.binary_data_size.
+func SplitBinaryRequestBody(body []byte, headerLen int) ([]byte, []byte, error) {
+	if headerLen < 0 || headerLen > len(body) {
+		return nil, nil, fmt.Errorf("invalid %s: %d exceeds body length %d", InferenceHeaderContentLengthHeader, headerLen, len(body))
+	}
+	return body[:headerLen], body[headerLen:], nil
+}
+
+// decodeBinaryInputTensors consumes binaryData in order against every input
+// tensor carrying a parameters.binary_data_size, replacing its Data with the
+// typed slice DecodeBinaryTensorData produces. Tensors without
+// binary_data_size are left untouched (their Data, if any, came from the
+// JSON header as usual). It also returns the raw (still-encoded) bytes
+// consumed for each such tensor, in input order, so callers that need the
+// original wire bytes (see Event.rawInputs) don't have to re-walk binaryData.
+func decodeBinaryInputTensors(inputs []RESTInferInputTensor, binaryData []byte) ([][]byte, error) {
+	var rawInputs [][]byte
+
+	offset := 0
+	for i := range inputs {
+		tensor := &inputs[i]
+
+		size, ok := tensor.Parameters["binary_data_size"]
+		if !ok {
+			continue
+		}
+
+		sizeInt, err := toBinaryDataSize(size)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", tensor.Name, err)
+		}
+		if sizeInt < 0 {
+			return nil, fmt.Errorf("tensor %q: binary_data_size must not be negative, got %d", tensor.Name, sizeInt)
+		}
+		if offset+sizeInt > len(binaryData) {
+			return nil, fmt.Errorf("tensor %q: binary_data_size %d exceeds remaining binary data", tensor.Name, sizeInt)
+		}
+
+		raw := binaryData[offset : offset+sizeInt]
+		if err := validateRawTensorByteLength(tensor.Datatype, raw, tensor.Shape); err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", tensor.Name, err)
+		}
+
+		decoded, err := DecodeBinaryTensorData(tensor.Datatype, raw)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", tensor.Name, err)
+		}
+		tensor.Data = decoded
+		rawInputs = append(rawInputs, raw)
+		offset += sizeInt
+	}
+	return rawInputs, nil
+}
+
+// encodeBinaryOutputTensors binary-encodes every output tensor whose
+// parameters request it (parameters.binary_data == true), replacing its
+// Data with nil and setting parameters.binary_data_size so the REST client
+// can split the appended binary suffix back into tensors. Returns nil if no
+// output tensor requested binary encoding.
+func encodeBinaryOutputTensors(outputs []RESTInferOutputTensor) ([]byte, error) {
+	var binaryData []byte
+
+	for i := range outputs {
+		tensor := &outputs[i]
+
+		binaryRequested, _ := tensor.Parameters["binary_data"].(bool)
+		if !binaryRequested {
+			continue
+		}
+
+		encoded, err := EncodeBinaryTensorData(tensor.Datatype, tensor.Data)
+		if err != nil {
+			return nil, fmt.Errorf("tensor %q: %w", tensor.Name, err)
+		}
+
+		params := make(map[string]any, len(tensor.Parameters))
+		for k, v := range tensor.Parameters {
+			params[k] = v
+		}
+		delete(params, "binary_data")
+		params["binary_data_size"] = len(encoded)
+		tensor.Parameters = params
+		tensor.Data = nil
+
+		binaryData = append(binaryData, encoded...)
+	}
+
+	return binaryData, nil
+}
+
+func toBinaryDataSize(v any) (int, error) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), nil
+	case int:
+		return n, nil
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err
+	default:
+		return 0, fmt.Errorf("binary_data_size must be a number, got %T", v)
+	}
+}
+
+// DecodeBinaryTensorData decodes raw little-endian KServe v2 binary tensor
+// data into a typed Go slice matching datatype: BYTES decodes into [][]byte
+// via its length-prefixed framing (a uint32 little-endian length followed by
+// that many bytes, repeated); every other datatype decodes into a slice of
+// its natural Go numeric type (FP16 is returned as []uint16 raw half-float
+// bits, since Go has no native float16).
+func DecodeBinaryTensorData(datatype string, raw []byte) (any, error) {
+	if datatype == "BYTES" {
+		return decodeBinaryBytesTensor(raw)
+	}
+
+	size, err := tensorElementSize(datatype)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%size != 0 {
+		return nil, fmt.Errorf("binary data length %d is not a multiple of element size %d for datatype %s", len(raw), size, datatype)
+	}
+	count := len(raw) / size
+
+	switch datatype {
+	case "BOOL":
+		out := make([]bool, count)
+		for i := range out {
+			out[i] = raw[i] != 0
+		}
+		return out, nil
+
+	case "UINT8":
+		out := make([]uint8, count)
+		copy(out, raw)
+		return out, nil
+
+	case "INT8":
+		out := make([]int8, count)
+		for i := range out {
+			out[i] = int8(raw[i])
+		}
+		return out, nil
+
+	case "UINT16", "FP16":
+		out := make([]uint16, count)
+		for i := range out {
+			out[i] = binary.LittleEndian.Uint16(raw[i*2:])
+		}
+		return out, nil
+
+	case "INT16":
+		out := make([]int16, count)
+		for i := range out {
+			out[i] = int16(binary.LittleEndian.Uint16(raw[i*2:]))
+		}
+		return out, nil
+
+	case "UINT32":
+		out := make([]uint32, count)
+		for i := range out {
+			out[i] = binary.LittleEndian.Uint32(raw[i*4:])
+		}
+		return out, nil
+
+	case "INT32":
+		out := make([]int32, count)
+		for i := range out {
+			out[i] = int32(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		return out, nil
+
+	case "FP32":
+		out := make([]float32, count)
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		return out, nil
+
+	case "UINT64":
+		out := make([]uint64, count)
+		for i := range out {
+			out[i] = binary.LittleEndian.Uint64(raw[i*8:])
+		}
+		return out, nil
+
+	case "INT64":
+		out := make([]int64, count)
+		for i := range out {
+			out[i] = int64(binary.LittleEndian.Uint64(raw[i*8:]))
+		}
+		return out, nil
+
+	case "FP64":
+		out := make([]float64, count)
+		for i := range out {
+			out[i] = math.Float64frombits(binary.LittleEndian.Uint64(raw[i*8:]))
+		}
+		return out, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported binary datatype: %s", datatype)
+	}
+}
+
+// validateRawTensorByteLength checks that raw's length is consistent with
+// shape for a fixed-width datatype (BYTES has no fixed per-element size, so
+// it isn't checked here), treating any -1 entry in shape as a dynamic
+// dimension that's skipped rather than enforced. Used by the gRPC
+// transport's raw_input_contents path and by decodeBinaryInputTensors on the
+// REST binary-data path to catch a declared shape that lies about the
+// tensor's actual size.
+func validateRawTensorByteLength(datatype string, raw []byte, shape []int64) error {
+	if datatype == "BYTES" {
+		return nil
+	}
+	size, err := tensorElementSize(datatype)
+	if err != nil {
+		return err
+	}
+
+	want := int64(1)
+	for _, dim := range shape {
+		if dim < 0 {
+			return nil // dynamic dimension: no exact expected length to check against
+		}
+		want *= dim
+	}
+
+	if int64(len(raw)) != want*int64(size) {
+		return fmt.Errorf("raw tensor data is %d bytes, shape %v at %d bytes/element wants %d",
+			len(raw), shape, size, want*int64(size))
+	}
+	return nil
+}
+
+func decodeBinaryBytesTensor(raw []byte) ([][]byte, error) {
+	var out [][]byte
+	for len(raw) > 0 {
+		if len(raw) < 4 {
+			return nil, fmt.Errorf("truncated BYTES tensor element length prefix")
+		}
+		n := binary.LittleEndian.Uint32(raw[:4])
+		raw = raw[4:]
+		if uint64(len(raw)) < uint64(n) {
+			return nil, fmt.Errorf("truncated BYTES tensor element data")
+		}
+		elem := make([]byte, n)
+		copy(elem, raw[:n])
+		out = append(out, elem)
+		raw = raw[n:]
+	}
+	return out, nil
+}
+
+// EncodeBinaryTensorData is the inverse of DecodeBinaryTensorData: it encodes
+// tensor data back into raw little-endian KServe v2 binary tensor bytes.
+// data may already be one of the typed slices DecodeBinaryTensorData
+// produces, or a []any of JSON-decoded values (as found in a
+// RESTInferOutputTensor.Data built by a function that doesn't itself speak
+// the binary extension), which is converted through datatype first.
+func EncodeBinaryTensorData(datatype string, data any) ([]byte, error) {
+	if datatype == "BYTES" {
+		return encodeBinaryBytesTensor(data)
+	}
+
+	if jsonValues, ok := data.([]any); ok {
+		converted, err := convertJSONNumbersToTyped(datatype, jsonValues)
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	}
+
+	var buf bytes.Buffer
+	switch v := data.(type) {
+	case []bool:
+		for _, b := range v {
+			if b {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		}
+	case []uint8:
+		buf.Write(v)
+	case []int8:
+		for _, n := range v {
+			buf.WriteByte(byte(n))
+		}
+	case []uint16:
+		for _, n := range v {
+			var b [2]byte
+			binary.LittleEndian.PutUint16(b[:], n)
+			buf.Write(b[:])
+		}
+	case []int16:
+		for _, n := range v {
+			var b [2]byte
+			binary.LittleEndian.PutUint16(b[:], uint16(n))
+			buf.Write(b[:])
+		}
+	case []uint32:
+		for _, n := range v {
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], n)
+			buf.Write(b[:])
+		}
+	case []int32:
+		for _, n := range v {
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], uint32(n))
+			buf.Write(b[:])
+		}
+	case []float32:
+		for _, n := range v {
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], math.Float32bits(n))
+			buf.Write(b[:])
+		}
+	case []uint64:
+		for _, n := range v {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], n)
+			buf.Write(b[:])
+		}
+	case []int64:
+		for _, n := range v {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], uint64(n))
+			buf.Write(b[:])
+		}
+	case []float64:
+		for _, n := range v {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(n))
+			buf.Write(b[:])
+		}
+	default:
+		return nil, fmt.Errorf("cannot binary-encode %T as datatype %s", data, datatype)
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeBinaryBytesTensor(data any) ([]byte, error) {
+	var elems [][]byte
+	switch v := data.(type) {
+	case [][]byte:
+		elems = v
+	case []any:
+		for _, val := range v {
+			s, ok := val.(string)
+			if !ok {
+				return nil, fmt.Errorf("BYTES tensor element must be a string, got %T", val)
+			}
+			elems = append(elems, []byte(s))
+		}
+	default:
+		return nil, fmt.Errorf("cannot binary-encode %T as datatype BYTES", data)
+	}
+
+	var buf bytes.Buffer
+	for _, elem := range elems {
+		var lenBuf [4]byte
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(elem)))
+		buf.Write(lenBuf[:])
+		buf.Write(elem)
+	}
+	return buf.Bytes(), nil
+}
+
+// convertJSONNumbersToTyped converts the []any a JSON decoder produces for a
+// tensor's "data" field (float64/bool/string values) into the typed slice
+// EncodeBinaryTensorData expects for datatype.
+func convertJSONNumbersToTyped(datatype string, values []any) (any, error) {
+	if datatype == "BOOL" {
+		out := make([]bool, len(values))
+		for i, val := range values {
+			b, ok := val.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected bool value for BOOL tensor, got %T", val)
+			}
+			out[i] = b
+		}
+		return out, nil
+	}
+
+	floats := make([]float64, len(values))
+	for i, val := range values {
+		f, err := toFloat64(val)
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = f
+	}
+
+	switch datatype {
+	case "UINT8":
+		out := make([]uint8, len(floats))
+		for i, f := range floats {
+			out[i] = uint8(f)
+		}
+		return out, nil
+	case "INT8":
+		out := make([]int8, len(floats))
+		for i, f := range floats {
+			out[i] = int8(f)
+		}
+		return out, nil
+	case "UINT16", "FP16":
+		out := make([]uint16, len(floats))
+		for i, f := range floats {
+			out[i] = uint16(f)
+		}
+		return out, nil
+	case "INT16":
+		out := make([]int16, len(floats))
+		for i, f := range floats {
+			out[i] = int16(f)
+		}
+		return out, nil
+	case "UINT32":
+		out := make([]uint32, len(floats))
+		for i, f := range floats {
+			out[i] = uint32(f)
+		}
+		return out, nil
+	case "INT32":
+		out := make([]int32, len(floats))
+		for i, f := range floats {
+			out[i] = int32(f)
+		}
+		return out, nil
+	case "FP32":
+		out := make([]float32, len(floats))
+		for i, f := range floats {
+			out[i] = float32(f)
+		}
+		return out, nil
+	case "UINT64":
+		out := make([]uint64, len(floats))
+		for i, f := range floats {
+			out[i] = uint64(f)
+		}
+		return out, nil
+	case "INT64":
+		out := make([]int64, len(floats))
+		for i, f := range floats {
+			out[i] = int64(f)
+		}
+		return out, nil
+	case "FP64":
+		return floats, nil
+	default:
+		return nil, fmt.Errorf("unsupported binary datatype: %s", datatype)
+	}
+}
+
+func toFloat64(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case json.Number:
+		return n.Float64()
+	default:
+		return 0, fmt.Errorf("expected numeric value, got %T", v)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode is a KServe v2 style error code, shared verbatim between the
+// REST and gRPC transports so clients see identical error shapes on both.
+type ErrorCode string
+
+const (
+	ErrCodeNotFound         ErrorCode = "NOT_FOUND"
+	ErrCodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrCodeModelNotFound    ErrorCode = "MODEL_NOT_FOUND"
+	ErrCodeInvalidInput     ErrorCode = "INVALID_INPUT"
+	ErrCodeInferenceFailed  ErrorCode = "INFERENCE_FAILED"
+	ErrCodeInternal         ErrorCode = "INTERNAL"
+)
+
+// APIError is the single error shape returned by both the REST and gRPC
+// transports: REST serializes it as JSON via writeAPIError, gRPC transports
+// it as a status.Status via GRPCStatus (picked up automatically by
+// google.golang.org/grpc/status.FromError).
+type APIError struct {
+	HTTPStatusCode int            `json:"-"`
+	Code           ErrorCode      `json:"code"`
+	Message        string         `json:"message"`
+	RequestID      string         `json:"request_id,omitempty"`
+	Details        map[string]any `json:"details,omitempty"`
+}
+
+// NewAPIError creates an APIError for code, defaulting its HTTP status code
+// from the KServe error code.
+func NewAPIError(code ErrorCode, message string) *APIError {
+	return &APIError{
+		HTTPStatusCode: httpStatusForCode(code),
+		Code:           code,
+		Message:        message,
+	}
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// WithRequestID attaches the request-scoped correlation ID and returns e for
+// chaining at the call site.
+func (e *APIError) WithRequestID(requestID string) *APIError {
+	e.RequestID = requestID
+	return e
+}
+
+// WithDetails attaches additional structured context and returns e for
+// chaining at the call site.
+func (e *APIError) WithDetails(details map[string]any) *APIError {
+	e.Details = details
+	return e
+}
+
+// GRPCStatus implements the interface google.golang.org/grpc/status looks
+// for on returned errors, so a *APIError can be returned directly from a
+// gRPC handler and still surface the matching status code to the client.
+func (e *APIError) GRPCStatus() *status.Status {
+	return status.New(grpcCodeForCode(e.Code), e.Message)
+}
+
+func httpStatusForCode(code ErrorCode) int {
+	switch code {
+	case ErrCodeNotFound, ErrCodeModelNotFound:
+		return http.StatusNotFound
+	case ErrCodeMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case ErrCodeInvalidInput:
+		return http.StatusBadRequest
+	case ErrCodeInferenceFailed, ErrCodeInternal:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func grpcCodeForCode(code ErrorCode) codes.Code {
+	switch code {
+	case ErrCodeNotFound, ErrCodeModelNotFound:
+		return codes.NotFound
+	case ErrCodeMethodNotAllowed:
+		return codes.Unimplemented
+	case ErrCodeInvalidInput:
+		return codes.InvalidArgument
+	case ErrCodeInferenceFailed, ErrCodeInternal:
+		return codes.Internal
+	default:
+		return codes.Unknown
+	}
+}
+
+// errorMetrics counts API errors per code. There is no Prometheus (or other)
+// metrics registry wired into this trigger yet, so this is a small in-memory
+// counter surfaced through logging; it can be swapped for a real metrics
+// backend later without touching call sites.
+type errorMetrics struct {
+	mu     sync.Mutex
+	counts map[ErrorCode]int64
+}
+
+func newErrorMetrics() *errorMetrics {
+	return &errorMetrics{counts: make(map[ErrorCode]int64)}
+}
+
+// inc increments the counter for code and returns its new value.
+func (m *errorMetrics) inc(code ErrorCode) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[code]++
+	return m.counts[code]
+}
+
+// snapshot returns a copy of the current per-code counts.
+func (m *errorMetrics) snapshot() map[ErrorCode]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[ErrorCode]int64, len(m.counts))
+	for code, count := range m.counts {
+		result[code] = count
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAPIError(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	w := httptest.NewRecorder()
+	oi.writeAPIError(w, NewAPIError(ErrCodeModelNotFound, "no such model").WithRequestID("req-1"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var apiErr APIError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+	assert.Equal(t, ErrCodeModelNotFound, apiErr.Code)
+	assert.Equal(t, "no such model", apiErr.Message)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+
+	assert.Equal(t, int64(1), oi.errorMetrics.snapshot()[ErrCodeModelNotFound])
+}
+
+func TestAPIErrorGRPCStatus(t *testing.T) {
+	apiErr := NewAPIError(ErrCodeInvalidInput, "bad tensor shape")
+
+	st, ok := status.FromError(apiErr)
+	assert.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "bad tensor shape", st.Message())
+}
+
+func TestErrorMetricsIncrementsPerCode(t *testing.T) {
+	m := newErrorMetrics()
+	m.inc(ErrCodeInternal)
+	m.inc(ErrCodeInternal)
+	m.inc(ErrCodeInvalidInput)
+
+	snapshot := m.snapshot()
+	assert.Equal(t, int64(2), snapshot[ErrCodeInternal])
+	assert.Equal(t, int64(1), snapshot[ErrCodeInvalidInput])
+}
@@ -21,6 +21,22 @@ type Event struct {
 	modelName    string
 	modelVersion string
 	parameters   map[string]any
+
+	// contentType holds the request's actual Content-Type when it isn't
+	// the default "application/json" (e.g. the KServe v2 binary tensor
+	// data extension's "application/vnd.kserve.inference-v2+json", or
+	// "application/octet-stream"); see GetContentType.
+	contentType string
+
+	// headerContentLength is the parsed Inference-Header-Content-Length
+	// header: the number of leading bytes of body that are the JSON
+	// inference request, with any remaining bytes being raw binary tensor
+	// data. Zero means body carries no binary tensor data extension.
+	headerContentLength int
+
+	// rawInputs holds the still-encoded bytes consumed for each input
+	// tensor that carried a binary_data_size, in input order.
+	rawInputs [][]byte
 }
 
 // triggerInfo implements nuclio.TriggerInfoProvider
@@ -46,8 +62,14 @@ func (ti *triggerInfo) GetName() string {
 	return ti.kind
 }
 
-// GetContentType returns the content type
+// GetContentType returns the request's Content-Type, defaulting to
+// "application/json" for a plain inference request; the KServe v2 binary
+// tensor data extension sets it to "application/vnd.kserve.inference-v2+json"
+// or "application/octet-stream" instead (see GetInferenceHeader/GetInferenceData).
 func (e *Event) GetContentType() string {
+	if e.contentType != "" {
+		return e.contentType
+	}
 	return "application/json"
 }
 
@@ -56,6 +78,34 @@ func (e *Event) GetBody() []byte {
 	return e.body
 }
 
+// GetInferenceHeader returns the JSON inference request metadata: all of
+// body when the KServe v2 binary tensor data extension wasn't used, or the
+// leading headerContentLength bytes (everything but the raw binary tensor
+// suffix) when it was.
+func (e *Event) GetInferenceHeader() []byte {
+	if e.headerContentLength <= 0 || e.headerContentLength > len(e.body) {
+		return e.body
+	}
+	return e.body[:e.headerContentLength]
+}
+
+// GetInferenceData returns the raw binary tensor data appended after the
+// JSON inference request metadata, or nil if the KServe v2 binary tensor
+// data extension wasn't used.
+func (e *Event) GetInferenceData() []byte {
+	if e.headerContentLength <= 0 || e.headerContentLength > len(e.body) {
+		return nil
+	}
+	return e.body[e.headerContentLength:]
+}
+
+// GetRawInputs returns the still-encoded bytes consumed for each input
+// tensor that carried binary_data_size, in input order, or nil if the
+// KServe v2 binary tensor data extension wasn't used.
+func (e *Event) GetRawInputs() [][]byte {
+	return e.rawInputs
+}
+
 // GetHeaderByteSlice returns a header value as a byte slice
 func (e *Event) GetHeaderByteSlice(key string) []byte {
 	if val, ok := e.headers[key]; ok {
@@ -12,11 +12,15 @@ package openinference
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/nuclio/nuclio-sdk-go"
 	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 // grpcInferenceServer implements the GRPCInferenceService
@@ -25,13 +29,41 @@ type grpcInferenceServer struct {
 	trigger *openInference
 }
 
-// Register gRPC handlers
-func (oi *openInference) registerGRPCHandlers(server *grpc.Server) {
+// apiError counts apiErr against its code and returns it; apiErr implements
+// GRPCStatus, so google.golang.org/grpc/status picks up the matching code
+// automatically once this is returned as the handler's error.
+func (s *grpcInferenceServer) apiError(apiErr *APIError) error {
+	count := s.trigger.errorMetrics.inc(apiErr.Code)
+	s.trigger.Logger.WarnWith("API error",
+		"code", apiErr.Code,
+		"message", apiErr.Message,
+		"requestID", apiErr.RequestID,
+		"count", count)
+
+	return apiErr
+}
+
+// registerGRPCHandlers builds the grpc.Server, wiring in the interceptor
+// chain requested by Configuration.GRPCInterceptors (interceptors must be
+// grpc.ServerOptions at construction time, so the server can't be built
+// beforehand by the caller) and registers grpcInferenceServer onto it.
+func (oi *openInference) registerGRPCHandlers() *grpc.Server {
+	server := grpc.NewServer(oi.buildGRPCServerOptions()...)
+
 	grpcServer := &grpcInferenceServer{
 		trigger: oi,
 	}
 	pb.RegisterGRPCInferenceServiceServer(server, grpcServer)
-	oi.Logger.InfoWith("gRPC handlers registered")
+
+	if oi.configuration.EnableGRPCReflection {
+		reflection.Register(server)
+	}
+
+	oi.Logger.InfoWith("gRPC handlers registered",
+		"interceptors", oi.configuration.GRPCInterceptors,
+		"reflection", oi.configuration.EnableGRPCReflection)
+
+	return server
 }
 
 // ServerLive - Check liveness of the inference server
@@ -63,15 +95,27 @@ func (s *grpcInferenceServer) ModelReady(ctx context.Context, req *pb.ModelReady
 
 // ServerMetadata - Get server metadata
 func (s *grpcInferenceServer) ServerMetadata(ctx context.Context, req *pb.ServerMetadataRequest) (*pb.ServerMetadataResponse, error) {
+	extensions := []string{"binary_tensor_data"}
+	if s.trigger.configuration.EnableModelRepository {
+		extensions = append(extensions, "model_repository")
+	}
+
 	return &pb.ServerMetadataResponse{
 		Name:       "digitalhub-serverless",
 		Version:    "1.0.0",
-		Extensions: []string{},
+		Extensions: extensions,
 	}, nil
 }
 
 // ModelMetadata - Get model metadata
 func (s *grpcInferenceServer) ModelMetadata(ctx context.Context, req *pb.ModelMetadataRequest) (*pb.ModelMetadataResponse, error) {
+	if req.Name != "" && req.Name != s.trigger.configuration.ModelName {
+		return nil, s.apiError(NewAPIError(ErrCodeModelNotFound, fmt.Sprintf("no such model: %s", req.Name)))
+	}
+	if req.Version != "" && req.Version != s.trigger.configuration.ModelVersion {
+		return nil, s.apiError(NewAPIError(ErrCodeModelNotFound, fmt.Sprintf("no such model version: %s", req.Version)))
+	}
+
 	// Convert input tensor definitions to metadata
 	inputs := make([]*pb.TensorMetadata, len(s.trigger.configuration.InputTensors))
 	for i, tensor := range s.trigger.configuration.InputTensors {
@@ -103,14 +147,38 @@ func (s *grpcInferenceServer) ModelMetadata(ctx context.Context, req *pb.ModelMe
 
 // ModelInfer - Perform inference using a specific model
 func (s *grpcInferenceServer) ModelInfer(ctx context.Context, req *pb.ModelInferRequest) (*pb.ModelInferResponse, error) {
+	resp, _, err := s.runInfer(req, "grpc")
+	return resp, err
+}
+
+// runInfer converts req into an Event, submits it to a worker, and converts
+// the result back into a gRPC response. Shared by the unary ModelInfer and
+// the per-message loop inside ModelStreamInfer so both dispatch identically.
+// restResp is the same successful response in its REST/JSON shape, exposed
+// so ModelStreamInfer can publish it to stream sinks without re-deriving it;
+// it is nil whenever inferErr is non-nil.
+func (s *grpcInferenceServer) runInfer(req *pb.ModelInferRequest, protocol string) (resp *pb.ModelInferResponse, restResp *RESTInferenceResponse, inferErr error) {
+	start := time.Now()
+
 	// Convert gRPC request to REST format for processing
-	restRequest := s.convertGRPCToRESTRequest(req)
+	restRequest, err := s.convertGRPCToRESTRequest(req)
+	if err != nil {
+		s.trigger.Logger.WarnWith("Invalid gRPC inference request", "error", err)
+		inferErr = s.apiError(NewAPIError(ErrCodeInvalidInput, err.Error()).WithRequestID(req.Id))
+		return nil, nil, inferErr
+	}
+
+	var restResponse *RESTInferenceResponse
+	defer func() {
+		s.trigger.emitAuditRecord(req.Id, protocol, restRequest, restResponse, time.Since(start), inferErr)
+	}()
 
 	// Marshal to JSON for the event body
 	body, err := json.Marshal(restRequest)
 	if err != nil {
 		s.trigger.Logger.WarnWith("Failed to marshal request", "error", err)
-		return nil, err
+		inferErr = s.apiError(NewAPIError(ErrCodeInternal, "failed to marshal inference request").WithRequestID(req.Id))
+		return nil, nil, inferErr
 	}
 
 	// Create nuclio event
@@ -120,7 +188,7 @@ func (s *grpcInferenceServer) ModelInfer(ctx context.Context, req *pb.ModelInfer
 			"X-Model-Name":    s.trigger.configuration.ModelName,
 			"X-Model-Version": s.trigger.configuration.ModelVersion,
 			"X-Request-ID":    req.Id,
-			"X-Protocol":      "grpc",
+			"X-Protocol":      protocol,
 		},
 		timestamp:    time.Now(),
 		modelName:    req.ModelName,
@@ -128,8 +196,8 @@ func (s *grpcInferenceServer) ModelInfer(ctx context.Context, req *pb.ModelInfer
 		parameters:   convertParametersToMap(req.Parameters),
 	}
 
-	// Submit to worker
-	response, submitError, processError := s.trigger.AllocateWorkerAndSubmitEvent(
+	// Submit to worker (or forward to a reattach target, if configured)
+	response, submitError, processError := s.trigger.dispatchEvent(
 		event,
 		s.trigger.Logger,
 		10*time.Second,
@@ -137,29 +205,161 @@ func (s *grpcInferenceServer) ModelInfer(ctx context.Context, req *pb.ModelInfer
 
 	if submitError != nil {
 		s.trigger.Logger.WarnWith("Failed to submit event", "error", submitError)
-		return nil, submitError
+		inferErr = s.apiError(NewAPIError(ErrCodeInferenceFailed, "failed to submit inference request").WithRequestID(req.Id))
+		return nil, nil, inferErr
 	}
 
 	if processError != nil {
 		s.trigger.Logger.WarnWith("Failed to process event", "error", processError)
-		return nil, processError
+		inferErr = s.apiError(NewAPIError(ErrCodeInferenceFailed, "failed to process inference request").WithRequestID(req.Id))
+		return nil, nil, inferErr
 	}
 
 	// Convert response to gRPC format
 	switch typedResponse := response.(type) {
 	case nuclio.Response:
 		// Parse the response body
-		var restResponse RESTInferenceResponse
-		if err := json.Unmarshal(typedResponse.Body, &restResponse); err != nil {
+		restResponse = &RESTInferenceResponse{}
+		if err := json.Unmarshal(typedResponse.Body, restResponse); err != nil {
 			s.trigger.Logger.WarnWith("Failed to parse function response", "error", err)
-			return nil, err
+			restResponse = nil
+			inferErr = s.apiError(NewAPIError(ErrCodeInternal, "invalid function response").WithRequestID(req.Id))
+			return nil, nil, inferErr
 		}
 
-		return s.convertRESTToGRPCResponse(&restResponse, req.Id), nil
+		// A request that sent its inputs via raw_input_contents gets its
+		// response back the same way, via raw_output_contents, rather than
+		// the typed per-datatype Contents fields: an RT inference client
+		// that opted into the compact binary transfer format for inputs
+		// expects it on the way out too.
+		usedRawContents := len(req.RawInputContents) > 0 && len(req.RawInputContents) == len(req.Inputs)
+
+		resp = s.convertRESTToGRPCResponse(restResponse, req.Id, usedRawContents)
+		return resp, restResponse, nil
 
 	default:
 		s.trigger.Logger.WarnWith("Unexpected response type", "type", typedResponse)
-		return nil, nil
+		inferErr = s.apiError(NewAPIError(ErrCodeInternal, "unexpected response type from function").WithRequestID(req.Id))
+		return nil, nil, inferErr
+	}
+}
+
+// ModelStreamInfer - Perform inference over a bidirectional stream:
+// each inbound ModelInferRequest is dispatched through the same worker
+// pipeline as ModelInfer, and its response is sent back on the stream as
+// soon as it's ready, independently of request order of arrival. Intended
+// for a client that keeps emitting chunks over time (e.g. a websocket
+// DataProcessorStream forwarding rolling audio windows) rather than
+// call-and-wait-for-exactly-one-response semantics.
+func (s *grpcInferenceServer) ModelStreamInfer(stream pb.GRPCInferenceService_ModelStreamInferServer) error {
+	ctx := stream.Context()
+	responses := make(chan *pb.ModelInferResponse, s.trigger.configuration.StreamQueueSize)
+
+	// inflight bounds how many requests from this stream are dispatched to
+	// the worker pool at once; MaxInflightPerStream requests can run, the
+	// rest wait in the loop below for a slot to free up.
+	inflight := make(chan struct{}, s.trigger.configuration.MaxInflightPerStream)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	sendErrCh := make(chan error, 1)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resp, ok := <-responses:
+				if !ok {
+					return
+				}
+				if err := stream.Send(resp); err != nil {
+					select {
+					case sendErrCh <- err:
+					default:
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			wg.Wait()
+			close(responses)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case err := <-sendErrCh:
+			return err
+		default:
+		}
+
+		if err := ctx.Err(); err != nil {
+			wg.Wait()
+			close(responses)
+			return err
+		}
+
+		select {
+		case inflight <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			close(responses)
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(req *pb.ModelInferRequest) {
+			defer wg.Done()
+			defer func() { <-inflight }()
+
+			resp, restResp, err := s.runInfer(req, "grpc-stream")
+			if err != nil {
+				s.trigger.Logger.WarnWith("Stream inference failed", "requestID", req.Id, "error", err)
+				return
+			}
+
+			s.trigger.publishStreamOutput(ctx, req.Id, "grpc-stream", restResp)
+			s.enqueueStreamResponse(ctx, responses, resp)
+		}(req)
+	}
+}
+
+// enqueueStreamResponse delivers resp to the stream's send loop, applying
+// Configuration.StreamBackpressureMode when responses is full: "block"
+// waits for room, "drop-oldest" discards the oldest queued response to make
+// room for resp instead of stalling the worker that produced it.
+func (s *grpcInferenceServer) enqueueStreamResponse(
+	ctx context.Context, responses chan *pb.ModelInferResponse, resp *pb.ModelInferResponse) {
+
+	if s.trigger.configuration.StreamBackpressureMode == StreamBackpressureBlock {
+		select {
+		case responses <- resp:
+		case <-ctx.Done():
+		}
+		return
+	}
+
+	for {
+		select {
+		case responses <- resp:
+			return
+		default:
+		}
+
+		select {
+		case <-responses:
+		case <-ctx.Done():
+			return
+		default:
+		}
 	}
 }
 
@@ -179,7 +379,7 @@ func convertParametersToMap(params map[string]*pb.InferParameter) map[string]any
 }
 
 // Helper to convert gRPC request to REST request
-func (s *grpcInferenceServer) convertGRPCToRESTRequest(req *pb.ModelInferRequest) *RESTInferenceRequest {
+func (s *grpcInferenceServer) convertGRPCToRESTRequest(req *pb.ModelInferRequest) (*RESTInferenceRequest, error) {
 	restReq := &RESTInferenceRequest{
 		ID:         req.Id,
 		Parameters: make(map[string]any),
@@ -221,6 +421,34 @@ func (s *grpcInferenceServer) convertGRPCToRESTRequest(req *pb.ModelInferRequest
 		}
 	}
 
+	// raw_input_contents carries every input tensor's data back-to-back, in
+	// request order, in place of each tensor's typed Contents field: the
+	// gRPC transport's equivalent of the REST binary-data extension
+	// (SplitBinaryRequestBody/decodeBinaryInputTensors in binary.go). Decode
+	// it with the same DecodeBinaryTensorData both transports share, and
+	// overwrite the (empty, since Contents wasn't set) Data populated above.
+	if len(req.RawInputContents) > 0 {
+		if len(req.RawInputContents) != len(req.Inputs) {
+			s.trigger.Logger.WarnWith("raw_input_contents count does not match inputs count, ignoring",
+				"rawCount", len(req.RawInputContents), "inputsCount", len(req.Inputs))
+		} else {
+			for i, raw := range req.RawInputContents {
+				tensor := &restReq.Inputs[i]
+				if err := validateRawTensorByteLength(tensor.Datatype, raw, tensor.Shape); err != nil {
+					return nil, fmt.Errorf("raw_input_contents tensor %q: %w", tensor.Name, err)
+				}
+
+				decoded, err := DecodeBinaryTensorData(tensor.Datatype, raw)
+				if err != nil {
+					s.trigger.Logger.WarnWith("Failed to decode raw_input_contents tensor",
+						"name", tensor.Name, "error", err)
+					continue
+				}
+				tensor.Data = decoded
+			}
+		}
+	}
+
 	// Convert requested outputs
 	for i, output := range req.Outputs {
 		restReq.Outputs[i] = RESTInferOutputTensor{
@@ -240,11 +468,18 @@ func (s *grpcInferenceServer) convertGRPCToRESTRequest(req *pb.ModelInferRequest
 		}
 	}
 
-	return restReq
+	return restReq, nil
 }
 
-// Helper to convert REST response to gRPC response
-func (s *grpcInferenceServer) convertRESTToGRPCResponse(resp *RESTInferenceResponse, requestID string) *pb.ModelInferResponse {
+// Helper to convert REST response to gRPC response. When useRawContents is
+// set (the request came in via raw_input_contents), each output tensor's
+// data is packed into RawOutputContents instead of its typed Contents
+// field, via the same EncodeBinaryTensorData the REST binary-data extension
+// uses, so a client that opted into the compact binary format gets it back
+// symmetrically.
+func (s *grpcInferenceServer) convertRESTToGRPCResponse(
+	resp *RESTInferenceResponse, requestID string, useRawContents bool) *pb.ModelInferResponse {
+
 	grpcResp := &pb.ModelInferResponse{
 		ModelName:    resp.ModelName,
 		ModelVersion: resp.ModelVersion,
@@ -253,6 +488,10 @@ func (s *grpcInferenceServer) convertRESTToGRPCResponse(resp *RESTInferenceRespo
 		Outputs:      make([]*pb.ModelInferResponse_InferOutputTensor, len(resp.Outputs)),
 	}
 
+	if useRawContents {
+		grpcResp.RawOutputContents = make([][]byte, len(resp.Outputs))
+	}
+
 	if grpcResp.Id == "" {
 		grpcResp.Id = requestID
 	}
@@ -286,7 +525,19 @@ func (s *grpcInferenceServer) convertRESTToGRPCResponse(resp *RESTInferenceRespo
 			Datatype:   output.Datatype,
 			Shape:      output.Shape,
 			Parameters: make(map[string]*pb.InferParameter),
-			Contents:   s.convertDataToTensorContents(output.Data, output.Datatype),
+		}
+
+		if useRawContents {
+			raw, err := EncodeBinaryTensorData(output.Datatype, output.Data)
+			if err != nil {
+				s.trigger.Logger.WarnWith("Failed to encode raw_output_contents tensor, falling back to typed contents",
+					"name", output.Name, "error", err)
+				tensor.Contents = s.convertDataToTensorContents(output.Data, output.Datatype)
+			} else {
+				grpcResp.RawOutputContents[i] = raw
+			}
+		} else {
+			tensor.Contents = s.convertDataToTensorContents(output.Data, output.Datatype)
 		}
 
 		for key, value := range output.Parameters {
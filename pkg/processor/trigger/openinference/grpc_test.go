@@ -102,5 +102,18 @@ func TestGRPCModelMetadata(t *testing.T) {
 	assert.Equal(t, "output", resp.Outputs[0].Name)
 }
 
+func TestGRPCModelMetadataUnknownModel(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	server := &grpcInferenceServer{trigger: oi}
+
+	req := &pb.ModelMetadataRequest{
+		Name: "no-such-model",
+	}
+	resp, err := server.ModelMetadata(context.Background(), req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
 // Note: The following tests are removed because they test private functions.
 // The actual conversion logic is tested through integration tests.
@@ -0,0 +1,392 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nuclio/errors"
+	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDContextKey is the context key the "tags" interceptor stores the
+// generated request/trace ID under.
+type requestIDContextKey struct{}
+
+// requestIDFromContext returns the ID injected by the "tags" interceptor,
+// or "" if it wasn't installed.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// newRequestID generates a short opaque ID for correlating a single RPC
+// across logs; this repo has no UUID dependency, so it follows the same
+// crypto/rand convention used by the websocket trigger's newFilterID.
+func newRequestID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// grpcCodeFromName maps a gRPC status code's string name (e.g.
+// "UNAVAILABLE") to its codes.Code, for validating and interpreting
+// Configuration.GRPCRetryCodes.
+func grpcCodeFromName(name string) (codes.Code, error) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.EqualFold(c.String(), name) {
+			return c, nil
+		}
+	}
+	return codes.OK, errors.Errorf("unknown gRPC status code: %s", name)
+}
+
+// buildGRPCServerOptions assembles the grpc.ServerOption chain requested by
+// c.GRPCInterceptors, in the fixed order defined by grpcInterceptorOrder.
+// Interceptors must be ServerOptions at grpc.NewServer construction time,
+// so this is called from registerGRPCHandlers rather than added to an
+// already-running server.
+func (oi *openInference) buildGRPCServerOptions() []grpc.ServerOption {
+	// MaxMessageSize applies unconditionally, independent of whatever
+	// interceptor chain (if any) is configured below.
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(oi.configuration.MaxMessageSize),
+		grpc.MaxSendMsgSize(oi.configuration.MaxMessageSize),
+	}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+
+	for _, name := range grpcInterceptorOrder {
+		if !oi.configuration.hasGRPCInterceptor(name) {
+			continue
+		}
+		switch name {
+		case GRPCInterceptorRecovery:
+			unary = append(unary, oi.recoveryUnaryInterceptor)
+			stream = append(stream, oi.recoveryStreamInterceptor)
+		case GRPCInterceptorAuth:
+			unary = append(unary, oi.authUnaryInterceptor)
+			stream = append(stream, oi.authStreamInterceptor)
+		case GRPCInterceptorLogging:
+			unary = append(unary, oi.loggingUnaryInterceptor)
+			stream = append(stream, oi.loggingStreamInterceptor)
+		case GRPCInterceptorTags:
+			unary = append(unary, oi.tagsUnaryInterceptor)
+			stream = append(stream, oi.tagsStreamInterceptor)
+		case GRPCInterceptorRetry:
+			unary = append(unary, oi.retryUnaryInterceptor)
+		case GRPCInterceptorValidation:
+			unary = append(unary, oi.validationUnaryInterceptor)
+		}
+	}
+
+	if len(unary) == 0 && len(stream) == 0 {
+		return options
+	}
+
+	return append(options,
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+}
+
+// recoveryUnaryInterceptor converts a panic in handler into a codes.Internal
+// error instead of crashing the gRPC server.
+func (oi *openInference) recoveryUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			oi.Logger.ErrorWith("Recovered from panic in gRPC handler", "method", info.FullMethod, "panic", r)
+			err = status.Errorf(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func (oi *openInference) recoveryStreamInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			oi.Logger.ErrorWith("Recovered from panic in gRPC handler", "method", info.FullMethod, "panic", r)
+			err = status.Errorf(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// loggingUnaryInterceptor logs method, duration and resulting status code
+// for every call, and additionally logs the request/response payloads when
+// GRPCLogPayloads is set, sampled at GRPCLogSampleRate via the request ID
+// injected by the "tags" interceptor (falling back to always-sample when
+// "tags" isn't also enabled).
+func (oi *openInference) loggingUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	duration := time.Since(start)
+
+	code := status.Code(err)
+	oi.Logger.InfoWith("gRPC call",
+		"method", info.FullMethod,
+		"durationMs", duration.Milliseconds(),
+		"code", code.String(),
+		"requestID", requestIDFromContext(ctx))
+
+	if oi.configuration.GRPCLogPayloads && oi.shouldSampleLog(ctx) {
+		oi.Logger.DebugWith("gRPC payload", "method", info.FullMethod, "request", req, "response", resp)
+	}
+
+	return resp, err
+}
+
+func (oi *openInference) loggingStreamInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	start := time.Now()
+	err := handler(srv, ss)
+	duration := time.Since(start)
+
+	oi.Logger.InfoWith("gRPC stream",
+		"method", info.FullMethod,
+		"durationMs", duration.Milliseconds(),
+		"code", status.Code(err).String())
+
+	return err
+}
+
+// shouldSampleLog decides whether the current call falls within
+// GRPCLogSampleRate, deterministically keyed off the request ID so repeated
+// log lines for the same call agree.
+func (oi *openInference) shouldSampleLog(ctx context.Context) bool {
+	rate := oi.configuration.GRPCLogSampleRate
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+
+	id := requestIDFromContext(ctx)
+	if id == "" {
+		return true
+	}
+
+	// Derive a stable [0,1) value from the request ID's low byte: the
+	// trailing two hex digits of a newRequestID ID, or the last character
+	// of any other ID.
+	last := id[len(id)-1]
+	if len(id) >= 2 {
+		if b, err := hex.DecodeString(id[len(id)-2:]); err == nil {
+			last = b[0]
+		}
+	}
+	return float64(last)/256.0 < rate
+}
+
+// tagsUnaryInterceptor injects a per-call request/trace ID into the context
+// so downstream interceptors and handlers can correlate logs.
+func (oi *openInference) tagsUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	return handler(context.WithValue(ctx, requestIDContextKey{}, newRequestID()), req)
+}
+
+func (oi *openInference) tagsStreamInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	return handler(srv, &taggedServerStream{
+		ServerStream: ss,
+		ctx:          context.WithValue(ss.Context(), requestIDContextKey{}, newRequestID()),
+	})
+}
+
+// taggedServerStream overrides Context so a streamed call observes the
+// request ID injected by tagsStreamInterceptor.
+type taggedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *taggedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authUnaryInterceptor requires either a bearer token (GRPCAuthToken) or,
+// when GRPCAuthAllowedCNs is set, an mTLS client certificate whose subject
+// common name appears in the allowlist.
+func (oi *openInference) authUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	if err := oi.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (oi *openInference) authStreamInterceptor(
+	srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	if err := oi.authenticate(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (oi *openInference) authenticate(ctx context.Context) error {
+	if len(oi.configuration.GRPCAuthAllowedCNs) > 0 {
+		return oi.authenticateMTLS(ctx)
+	}
+	return oi.authenticateBearerToken(ctx)
+}
+
+func (oi *openInference) authenticateMTLS(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return status.Error(codes.Unauthenticated, "mTLS client certificate required")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return status.Error(codes.Unauthenticated, "mTLS client certificate required")
+	}
+
+	cn := tlsInfo.State.PeerCertificates[0].Subject.CommonName
+	for _, allowed := range oi.configuration.GRPCAuthAllowedCNs {
+		if cn == allowed {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, "client certificate CN %q is not allowed", cn)
+}
+
+func (oi *openInference) authenticateBearerToken(ctx context.Context) error {
+	if oi.configuration.GRPCAuthToken == "" {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	const prefix = "Bearer "
+	token := values[0]
+	if strings.HasPrefix(token, prefix) {
+		token = strings.TrimPrefix(token, prefix)
+	}
+
+	if token != oi.configuration.GRPCAuthToken {
+		return status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	return nil
+}
+
+// retryUnaryInterceptor re-invokes handler up to GRPCRetryMax additional
+// times when it returns one of GRPCRetryCodes. Only safe for unary calls:
+// a stream's handler may have already written to the client, so no stream
+// variant is offered.
+func (oi *openInference) retryUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	var resp any
+	var err error
+
+	for attempt := 0; attempt <= oi.configuration.GRPCRetryMax; attempt++ {
+		resp, err = handler(ctx, req)
+		if err == nil || !oi.isRetryableCode(status.Code(err)) {
+			return resp, err
+		}
+		oi.Logger.WarnWith("Retrying gRPC call", "method", info.FullMethod, "attempt", attempt+1, "error", err)
+	}
+
+	return resp, err
+}
+
+func (oi *openInference) isRetryableCode(code codes.Code) bool {
+	for _, name := range oi.configuration.GRPCRetryCodes {
+		if c, err := grpcCodeFromName(name); err == nil && c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// validationUnaryInterceptor checks a ModelInferRequest's Inputs against
+// Configuration.InputTensors (name/datatype/shape) before the request ever
+// reaches AllocateWorkerAndSubmitEvent. Other RPCs pass through unchanged.
+func (oi *openInference) validationUnaryInterceptor(
+	ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+
+	inferReq, ok := req.(*pb.ModelInferRequest)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	if err := oi.validateInferInputs(inferReq); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return handler(ctx, req)
+}
+
+func (oi *openInference) validateInferInputs(req *pb.ModelInferRequest) error {
+	expected := make(map[string]TensorDef, len(oi.configuration.InputTensors))
+	for _, tensor := range oi.configuration.InputTensors {
+		expected[tensor.Name] = tensor
+	}
+
+	for _, input := range req.Inputs {
+		tensor, known := expected[input.Name]
+		if !known {
+			return fmt.Errorf("unexpected input tensor %q", input.Name)
+		}
+		if input.Datatype != tensor.DataType {
+			return fmt.Errorf("input tensor %q: expected datatype %s, got %s", input.Name, tensor.DataType, input.Datatype)
+		}
+		if !shapesMatch(tensor.Shape, input.Shape) {
+			return fmt.Errorf("input tensor %q: expected shape %v, got %v", input.Name, tensor.Shape, input.Shape)
+		}
+	}
+
+	return nil
+}
+
+// shapesMatch compares a configured tensor shape against a request's,
+// treating a configured dimension of -1 as a wildcard (variable batch size).
+func shapesMatch(expected, actual []int64) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i, dim := range expected {
+		if dim != -1 && dim != actual[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,177 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestBuildGRPCServerOptionsOnlyMessageSizeWhenNoInterceptors(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	// MaxRecvMsgSize and MaxSendMsgSize only; no interceptor chain.
+	assert.Len(t, oi.buildGRPCServerOptions(), 2)
+}
+
+func TestBuildGRPCServerOptionsNonEmptyWhenConfigured(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.GRPCInterceptors = []string{GRPCInterceptorRecovery}
+	// The two message-size options plus the unary and stream chains.
+	assert.Len(t, oi.buildGRPCServerOptions(), 4)
+}
+
+func TestRecoveryUnaryInterceptorCatchesPanic(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	}
+
+	resp, err := oi.recoveryUnaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestAuthUnaryInterceptorBearerToken(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.GRPCAuthToken = "secret"
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Method"}
+
+	t.Run("MissingToken", func(t *testing.T) {
+		_, err := oi.authUnaryInterceptor(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("WrongToken", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", "Bearer wrong"))
+		_, err := oi.authUnaryInterceptor(ctx, nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("CorrectToken", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs("authorization", "Bearer secret"))
+		resp, err := oi.authUnaryInterceptor(ctx, nil, info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+}
+
+func TestRetryUnaryInterceptorRetriesOnConfiguredCode(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.GRPCRetryCodes = []string{"UNAVAILABLE"}
+	oi.configuration.GRPCRetryMax = 2
+
+	attempts := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, status.Error(codes.Unavailable, "try again")
+		}
+		return "ok", nil
+	}
+
+	resp, err := oi.retryUnaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryUnaryInterceptorDoesNotRetryOtherCodes(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.GRPCRetryCodes = []string{"UNAVAILABLE"}
+	oi.configuration.GRPCRetryMax = 2
+
+	attempts := 0
+	handler := func(ctx context.Context, req any) (any, error) {
+		attempts++
+		return nil, status.Error(codes.InvalidArgument, "bad request")
+	}
+
+	_, err := oi.retryUnaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 1, attempts)
+}
+
+func TestValidationUnaryInterceptorRejectsMismatchedInput(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	handlerCalled := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		handlerCalled = true
+		return nil, nil
+	}
+
+	req := &pb.ModelInferRequest{
+		Inputs: []*pb.ModelInferRequest_InferInputTensor{
+			{Name: "input", Datatype: "INT64", Shape: []int64{1, 3}},
+		},
+	}
+
+	_, err := oi.validationUnaryInterceptor(context.Background(), req,
+		&grpc.UnaryServerInfo{FullMethod: "/inference.GRPCInferenceService/ModelInfer"}, handler)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, handlerCalled)
+}
+
+func TestValidationUnaryInterceptorAllowsMatchingInput(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	handlerCalled := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		handlerCalled = true
+		return "ok", nil
+	}
+
+	req := &pb.ModelInferRequest{
+		Inputs: []*pb.ModelInferRequest_InferInputTensor{
+			{Name: "input", Datatype: "FP32", Shape: []int64{1, 3}},
+		},
+	}
+
+	resp, err := oi.validationUnaryInterceptor(context.Background(), req,
+		&grpc.UnaryServerInfo{FullMethod: "/inference.GRPCInferenceService/ModelInfer"}, handler)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+	assert.True(t, handlerCalled)
+}
+
+func TestTagsUnaryInterceptorInjectsRequestID(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	var seen string
+	handler := func(ctx context.Context, req any) (any, error) {
+		seen = requestIDFromContext(ctx)
+		return nil, nil
+	}
+
+	_, err := oi.tagsUnaryInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, seen)
+}
@@ -0,0 +1,152 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	"github.com/nuclio/nuclio-sdk-go"
+	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// reattachClient forwards inference traffic to, and health-checks, the
+// externally managed model server named by Configuration.Reattach. Built
+// once in newTrigger and never torn down by Stop - the whole point of
+// reattach mode is that this trigger doesn't own that process's lifecycle.
+type reattachClient struct {
+	logger     logger.Logger
+	config     *ReattachConfig
+	httpClient *http.Client
+	grpcConn   *grpc.ClientConn
+	grpcClient pb.GRPCInferenceServiceClient
+}
+
+// newReattachClient dials config.GRPC when set; grpc.Dial without
+// WithBlock doesn't actually connect yet, it just registers the target, so
+// this never blocks startup even if the reattach target isn't up yet -
+// healthCheck is what actually confirms it's reachable.
+func newReattachClient(log logger.Logger, config *ReattachConfig) (*reattachClient, error) {
+	client := &reattachClient{
+		logger:     log,
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if config.GRPC != "" {
+		conn, err := grpc.Dial(config.GRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to dial reattach gRPC target %q", config.GRPC)
+		}
+		client.grpcConn = conn
+		client.grpcClient = pb.NewGRPCInferenceServiceClient(conn)
+	}
+
+	return client, nil
+}
+
+// healthCheck confirms the externally managed server is up and ready,
+// using whichever transport config.Protocol selects.
+func (c *reattachClient) healthCheck(ctx context.Context) error {
+	if c.config.Protocol == ReattachProtocolGRPC {
+		resp, err := c.grpcClient.ServerReady(ctx, &pb.ServerReadyRequest{})
+		if err != nil {
+			return errors.Wrap(err, "reattach gRPC health check failed")
+		}
+		if !resp.Ready {
+			return errors.New("reattach target reports not ready")
+		}
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.REST+"/v2/health/ready", nil)
+	if err != nil {
+		return errors.Wrap(err, "Failed to build reattach health check request")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "reattach REST health check failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("reattach target returned status %d", resp.StatusCode)
+	}
+
+	var ready ServerReadyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ready); err != nil {
+		return errors.Wrap(err, "Failed to parse reattach health check response")
+	}
+	if !ready.Ready {
+		return errors.New("reattach target reports not ready")
+	}
+	return nil
+}
+
+// dispatch forwards event to the externally managed server instead of a
+// local worker, matching AllocateWorkerAndSubmitEvent's (response,
+// submitError, processError) shape so every call site - runInfer,
+// handleModelInfer, handleModelInferStream - can treat the two
+// interchangeably via openInference.dispatchEvent.
+//
+// Forwarding always goes out over REST regardless of config.Protocol:
+// every inbound request in this package, gRPC or REST, is already
+// converted to this same JSON shape before it would otherwise reach a
+// local worker (see convertGRPCToRESTRequest), so reusing it here avoids a
+// second, redundant gRPC request/response conversion path solely for
+// reattach. Protocol "grpc" only selects the ServerReady health check for
+// now - a pure-gRPC reattach target with no REST endpoint at all isn't
+// supported yet, and dispatch reports that clearly via processError
+// instead of guessing.
+func (c *reattachClient) dispatch(event *Event) (interface{}, error, error) {
+	if c.config.REST == "" {
+		return nil, nil, errors.New("reattach forwarding requires reattach.rest; grpc-only forwarding is not implemented")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.REST+event.GetPath(), bytes.NewReader(event.GetBody()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to build reattach request"), nil
+	}
+	req.Header.Set("Content-Type", event.GetContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to reach reattach target"), nil
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to read reattach response")
+	}
+
+	return nuclio.Response{
+		StatusCode:  resp.StatusCode,
+		ContentType: "application/json",
+		Body:        body,
+	}, nil, nil
+}
+
+// close releases this client's own local resources (its gRPC connection).
+// It never contacts the reattach target to stop it - that process's
+// lifecycle is external by design.
+func (c *reattachClient) close() {
+	if c.grpcConn != nil {
+		if err := c.grpcConn.Close(); err != nil {
+			c.logger.WarnWith("Failed to close reattach gRPC connection", "error", err)
+		}
+	}
+}
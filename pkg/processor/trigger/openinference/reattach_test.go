@@ -0,0 +1,149 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/nuclio/nuclio-sdk-go"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/platformconfig"
+	"github.com/nuclio/nuclio/pkg/processor"
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReattachTriggerConfig() (*functionconfig.Trigger, *runtime.Configuration) {
+	runtimeConfig := &runtime.Configuration{
+		Configuration: &processor.Configuration{
+			Config: functionconfig.Config{
+				Meta: functionconfig.Meta{Name: "test-function", Namespace: "default"},
+				Spec: functionconfig.Spec{Runtime: "python:3.11", Handler: "test_handler:handler"},
+			},
+			PlatformConfig: &platformconfig.Config{Kind: "local"},
+		},
+	}
+
+	triggerConfig := &functionconfig.Trigger{
+		Kind:       "openinference",
+		Attributes: map[string]interface{}{"model_name": "test-model"},
+	}
+
+	return triggerConfig, runtimeConfig
+}
+
+func TestReattachConfigValidation(t *testing.T) {
+	triggerConfig, runtimeConfig := newTestReattachTriggerConfig()
+
+	t.Run("DefaultsToREST", func(t *testing.T) {
+		triggerConfig.Attributes["reattach"] = map[string]interface{}{"rest": "http://127.0.0.1:8081"}
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		require.NoError(t, err)
+		require.NotNil(t, config.Reattach)
+		assert.Equal(t, ReattachProtocolREST, config.Reattach.Protocol)
+	})
+
+	t.Run("RESTProtocolRequiresRESTAddress", func(t *testing.T) {
+		triggerConfig.Attributes["reattach"] = map[string]interface{}{"protocol": "rest"}
+		_, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("GRPCProtocolRequiresGRPCAddress", func(t *testing.T) {
+		triggerConfig.Attributes["reattach"] = map[string]interface{}{"protocol": "grpc"}
+		_, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("UnsupportedProtocol", func(t *testing.T) {
+		triggerConfig.Attributes["reattach"] = map[string]interface{}{"protocol": "carrier-pigeon"}
+		_, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		assert.Error(t, err)
+	})
+
+	t.Run("EnvFallbackUsedWhenAttributeUnset", func(t *testing.T) {
+		delete(triggerConfig.Attributes, "reattach")
+		require.NoError(t, os.Setenv(EnvOpenInferenceReattach, `{"rest":"http://127.0.0.1:8082"}`))
+		defer os.Unsetenv(EnvOpenInferenceReattach)
+
+		config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+		require.NoError(t, err)
+		require.NotNil(t, config.Reattach)
+		assert.Equal(t, "http://127.0.0.1:8082", config.Reattach.REST)
+	})
+}
+
+func TestReattachClientDispatchForwardsToREST(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id":"req-1"}`))
+	}))
+	defer server.Close()
+
+	client, err := newReattachClient(nil, &ReattachConfig{REST: server.URL, Protocol: ReattachProtocolREST})
+	require.NoError(t, err)
+	defer client.close()
+
+	event := &Event{body: []byte(`{}`), modelName: "test-model", contentType: "application/json"}
+	response, submitErr, processErr := client.dispatch(event)
+	require.NoError(t, submitErr)
+	require.NoError(t, processErr)
+
+	nuclioResponse, ok := response.(nuclio.Response)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, nuclioResponse.StatusCode)
+	assert.Equal(t, "/v2/models/test-model/infer", receivedPath)
+}
+
+func TestReattachClientDispatchWithoutRESTIsNotImplemented(t *testing.T) {
+	client, err := newReattachClient(nil, &ReattachConfig{Protocol: ReattachProtocolGRPC})
+	require.NoError(t, err)
+
+	event := &Event{body: []byte(`{}`), modelName: "test-model"}
+	_, _, processErr := client.dispatch(event)
+	assert.Error(t, processErr)
+}
+
+func TestReattachClientHealthCheckREST(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/health/ready", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ready":true}`))
+	}))
+	defer server.Close()
+
+	client, err := newReattachClient(nil, &ReattachConfig{REST: server.URL, Protocol: ReattachProtocolREST})
+	require.NoError(t, err)
+	defer client.close()
+
+	assert.NoError(t, client.healthCheck(context.Background()))
+}
+
+func TestGetConfigRedactsReattachAddresses(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.Reattach = &ReattachConfig{
+		REST:     "http://127.0.0.1:8081",
+		Protocol: ReattachProtocolREST,
+	}
+
+	config := oi.GetConfig()
+
+	reattach, ok := config["reattach"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, ReattachProtocolREST, reattach["protocol"])
+	assert.Equal(t, true, reattach["rest_set"])
+	assert.Equal(t, false, reattach["grpc_set"])
+	assert.NotContains(t, reattach, "rest")
+	assert.NotContains(t, reattach, "grpc")
+}
@@ -0,0 +1,235 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+
+KServe v2 "model repository" extension support:
+https://github.com/kserve/open-inference-protocol/blob/main/specification/protocol/extension_model_repository.md
+*/
+
+package openinference
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ModelState is the lifecycle state of a ModelRegistry entry.
+type ModelState string
+
+const (
+	ModelStateUnavailable ModelState = "UNAVAILABLE"
+	ModelStateLoading     ModelState = "LOADING"
+	ModelStateReady       ModelState = "READY"
+)
+
+// ModelEntry tracks one (name, version) model the repository-extension
+// endpoints have been asked to load.
+type ModelEntry struct {
+	Name        string
+	Version     string
+	State       ModelState
+	ArtifactURL string
+	LocalPath   string
+	LoadedAt    time.Time
+	Reason      string
+}
+
+// ModelRegistry tracks models the repository-extension endpoints
+// (/v2/repository/...) have loaded or unloaded, staging each one's artifact
+// under a per-(name, version) directory beneath stageDir.
+//
+// It deliberately stops at discovery/staging: a dynamically loaded model is
+// reported READY by Index/Get and its artifact is fetched to LocalPath, but
+// this trigger has no per-model runtime dispatcher to actually run inference
+// against it - ModelInfer still only ever serves
+// configuration.ModelName/ModelVersion, the one bound to this function's own
+// worker pool at startup. Wiring a loaded model's LocalPath into a runtime
+// that can serve it is future work, not something a single-worker-pool
+// trigger can retrofit here.
+type ModelRegistry struct {
+	mu               sync.RWMutex
+	stageDir         string
+	allowedHosts     []string
+	maxArtifactBytes int64
+	models           map[string]map[string]*ModelEntry
+	httpClient       *http.Client
+}
+
+// NewModelRegistry creates a ModelRegistry that stages downloaded artifacts
+// under stageDir. Only artifactURLs whose host appears in allowedHosts are
+// fetched - see stageArtifact - and a fetch is aborted once maxArtifactBytes
+// have been read.
+func NewModelRegistry(stageDir string, allowedHosts []string, maxArtifactBytes int64) *ModelRegistry {
+	return &ModelRegistry{
+		stageDir:         stageDir,
+		allowedHosts:     allowedHosts,
+		maxArtifactBytes: maxArtifactBytes,
+		models:           make(map[string]map[string]*ModelEntry),
+		httpClient:       &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Seed registers (name, version) as READY without staging an artifact, for
+// the statically configured model every trigger instance serves regardless
+// of whether the repository API is ever used.
+func (r *ModelRegistry) Seed(name, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.set(&ModelEntry{Name: name, Version: version, State: ModelStateReady, LoadedAt: time.Now()})
+}
+
+func (r *ModelRegistry) set(entry *ModelEntry) {
+	if r.models[entry.Name] == nil {
+		r.models[entry.Name] = make(map[string]*ModelEntry)
+	}
+	r.models[entry.Name][entry.Version] = entry
+}
+
+// Load stages artifactURL (if non-empty) under stageDir/name/version and
+// marks the model READY, or UNAVAILABLE with Reason set if the fetch fails.
+// An empty artifactURL registers the model READY without staging anything.
+func (r *ModelRegistry) Load(ctx context.Context, name, version, artifactURL string) (*ModelEntry, error) {
+	entry := &ModelEntry{Name: name, Version: version, State: ModelStateLoading, ArtifactURL: artifactURL}
+	r.mu.Lock()
+	r.set(entry)
+	r.mu.Unlock()
+
+	var localPath string
+	if artifactURL != "" {
+		path, err := r.stageArtifact(ctx, name, version, artifactURL)
+		if err != nil {
+			r.mu.Lock()
+			entry.State = ModelStateUnavailable
+			entry.Reason = err.Error()
+			r.mu.Unlock()
+			return nil, err
+		}
+		localPath = path
+	}
+
+	r.mu.Lock()
+	entry.State = ModelStateReady
+	entry.LocalPath = localPath
+	entry.LoadedAt = time.Now()
+	r.mu.Unlock()
+
+	return entry, nil
+}
+
+// stageArtifact fetches artifactURL (a DigitalHub artifact store URL, or any
+// plain HTTP(S) URL whose host is in r.allowedHosts) and writes it to
+// stageDir/name/version/artifact. artifactURL is otherwise attacker-facing
+// (it comes straight from a repository-load request body), so the host
+// allowlist and the maxArtifactBytes cap on the response body both guard
+// against it being used to make this server fetch arbitrary internal URLs
+// or write an unbounded amount of data to disk.
+func (r *ModelRegistry) stageArtifact(ctx context.Context, name, version, artifactURL string) (string, error) {
+	if err := r.checkArtifactURL(artifactURL); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifactURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact url: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch artifact: %w", err)
+	}
+	defer resp.Body.Close() // nolint: errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("artifact fetch returned status %d", resp.StatusCode)
+	}
+
+	dir := filepath.Join(r.stageDir, name, version)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	dest := filepath.Join(dir, "artifact")
+	f, err := os.Create(dest)
+	if err != nil {
+		return "", fmt.Errorf("failed to create staged artifact file: %w", err)
+	}
+	defer f.Close() // nolint: errcheck
+
+	limited := io.LimitReader(resp.Body, r.maxArtifactBytes+1)
+	written, err := io.Copy(f, limited)
+	if err != nil {
+		return "", fmt.Errorf("failed to write staged artifact: %w", err)
+	}
+	if written > r.maxArtifactBytes {
+		return "", fmt.Errorf("artifact exceeds max_artifact_bytes (%d)", r.maxArtifactBytes)
+	}
+
+	return dest, nil
+}
+
+// checkArtifactURL rejects artifactURL unless it's plain HTTP(S) and its
+// host matches one of r.allowedHosts exactly. An empty r.allowedHosts
+// rejects every artifactURL, since that's the only safe default for an
+// endpoint whose URL comes from the request body.
+func (r *ModelRegistry) checkArtifactURL(artifactURL string) error {
+	parsed, err := url.Parse(artifactURL)
+	if err != nil {
+		return fmt.Errorf("invalid artifact url: %w", err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("artifact url scheme %q is not allowed", parsed.Scheme)
+	}
+
+	for _, allowed := range r.allowedHosts {
+		if parsed.Host == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("artifact url host %q is not in artifact_allowed_hosts", parsed.Host)
+}
+
+// Unload marks a loaded model UNAVAILABLE. Unloading a model that was never
+// loaded is not an error.
+func (r *ModelRegistry) Unload(name, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if versions, ok := r.models[name]; ok {
+		if entry, ok := versions[version]; ok {
+			entry.State = ModelStateUnavailable
+		}
+	}
+}
+
+// Get returns the entry tracked for (name, version), or nil if it was never
+// loaded or seeded.
+func (r *ModelRegistry) Get(name, version string) *ModelEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if versions, ok := r.models[name]; ok {
+		return versions[version]
+	}
+	return nil
+}
+
+// Index returns every tracked model entry, for /v2/repository/index.
+func (r *ModelRegistry) Index() []*ModelEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*ModelEntry
+	for _, versions := range r.models {
+		for _, entry := range versions {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
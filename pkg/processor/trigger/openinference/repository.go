@@ -0,0 +1,148 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+
+KServe v2 model repository extension REST handlers, backed by ModelRegistry:
+https://github.com/kserve/open-inference-protocol/blob/main/specification/protocol/extension_model_repository.md
+*/
+
+package openinference
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RepositoryIndexEntry describes one tracked model in a
+// /v2/repository/index response.
+type RepositoryIndexEntry struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RepositoryLoadRequest is the body of a
+// POST /v2/repository/models/{name}/load request. Parameters.ArtifactURL,
+// when set, is pulled from the DigitalHub artifact store (or any plain
+// HTTP(S) URL) and staged locally before the model is reported READY.
+type RepositoryLoadRequest struct {
+	Parameters RepositoryLoadParameters `json:"parameters,omitempty"`
+}
+
+type RepositoryLoadParameters struct {
+	ArtifactURL string `json:"artifact_url,omitempty"`
+	Version     string `json:"version,omitempty"`
+}
+
+// ModelConfigResponse is the body of a
+// GET /v2/models/{name}/versions/{v}/config response.
+type ModelConfigResponse struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+func (oi *openInference) handleRepositoryIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		oi.writeAPIError(w, NewAPIError(ErrCodeMethodNotAllowed, "only GET/POST is supported for the repository index"))
+		return
+	}
+
+	entries := oi.modelRegistry.Index()
+	response := make([]RepositoryIndexEntry, len(entries))
+	for i, entry := range entries {
+		response[i] = RepositoryIndexEntry{
+			Name:   entry.Name,
+			State:  string(entry.State),
+			Reason: entry.Reason,
+		}
+	}
+
+	oi.writeJSONResponse(w, http.StatusOK, response)
+}
+
+// handleRepositoryModelAction dispatches
+// POST /v2/repository/models/{name}/load and .../unload.
+func (oi *openInference) handleRepositoryModelAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		oi.writeAPIError(w, NewAPIError(ErrCodeMethodNotAllowed, "only POST is supported for repository model actions"))
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/v2/repository/models/")
+	var name, action string
+	if idx := strings.LastIndex(rest, "/"); idx >= 0 {
+		name, action = rest[:idx], rest[idx+1:]
+	}
+	if name == "" || (action != "load" && action != "unload") {
+		oi.writeAPIError(w, NewAPIError(ErrCodeNotFound, fmt.Sprintf("no such repository endpoint: %s", r.URL.Path)))
+		return
+	}
+
+	switch action {
+	case "load":
+		oi.handleRepositoryLoad(w, r, name)
+	case "unload":
+		oi.handleRepositoryUnload(w, name)
+	}
+}
+
+func (oi *openInference) handleRepositoryLoad(w http.ResponseWriter, r *http.Request, name string) {
+	var loadRequest RepositoryLoadRequest
+	if r.ContentLength != 0 {
+		defer r.Body.Close() // nolint: errcheck
+		if err := json.NewDecoder(r.Body).Decode(&loadRequest); err != nil {
+			oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, "invalid load request body"))
+			return
+		}
+	}
+
+	version := loadRequest.Parameters.Version
+	if version == "" {
+		version = oi.configuration.ModelVersion
+	}
+
+	if _, err := oi.modelRegistry.Load(r.Context(), name, version, loadRequest.Parameters.ArtifactURL); err != nil {
+		oi.Logger.WarnWith("Failed to load model", "name", name, "version", version, "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, fmt.Sprintf("failed to load model %q: %s", name, err)))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (oi *openInference) handleRepositoryUnload(w http.ResponseWriter, name string) {
+	// The extension doesn't carry a version on unload; unload every version
+	// this registry is tracking for name.
+	for _, entry := range oi.modelRegistry.Index() {
+		if entry.Name == name {
+			oi.modelRegistry.Unload(entry.Name, entry.Version)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleModelConfig serves GET /v2/models/{name}/versions/{v}/config.
+func (oi *openInference) handleModelConfig(w http.ResponseWriter, r *http.Request, path string) {
+	rest := strings.TrimPrefix(path, "/v2/models/")
+	rest = strings.TrimSuffix(rest, "/config")
+
+	name, version, ok := strings.Cut(rest, "/versions/")
+	if !ok {
+		name = rest
+		version = oi.configuration.ModelVersion
+	}
+
+	entry := oi.modelRegistry.Get(name, version)
+	if entry == nil {
+		oi.writeAPIError(w, NewAPIError(ErrCodeModelNotFound, fmt.Sprintf("no such model: %s/%s", name, version)))
+		return
+	}
+
+	oi.writeJSONResponse(w, http.StatusOK, ModelConfigResponse{
+		Name:  entry.Name,
+		State: string(entry.State),
+	})
+}
@@ -0,0 +1,100 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepositoryIndexIncludesSeededModel(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/repository/index", nil)
+	w := httptest.NewRecorder()
+
+	oi.handleRepositoryIndex(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var entries []RepositoryIndexEntry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, oi.configuration.ModelName, entries[0].Name)
+	assert.Equal(t, string(ModelStateReady), entries[0].State)
+}
+
+func TestRepositoryLoadAndUnload(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	artifactServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fake-model-bytes")) // nolint: errcheck
+	}))
+	defer artifactServer.Close()
+
+	artifactURL, err := url.Parse(artifactServer.URL)
+	assert.NoError(t, err)
+	oi.modelRegistry.allowedHosts = []string{artifactURL.Host}
+
+	body := `{"parameters":{"artifact_url":"` + artifactServer.URL + `","version":"2.0"}}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/repository/models/extra-model/load", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	oi.handleRepositoryModelAction(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	entry := oi.modelRegistry.Get("extra-model", "2.0")
+	assert.NotNil(t, entry)
+	assert.Equal(t, ModelStateReady, entry.State)
+	assert.NotEmpty(t, entry.LocalPath)
+
+	unloadReq := httptest.NewRequest(http.MethodPost, "/v2/repository/models/extra-model/unload", nil)
+	unloadW := httptest.NewRecorder()
+	oi.handleRepositoryModelAction(unloadW, unloadReq)
+	assert.Equal(t, http.StatusOK, unloadW.Code)
+
+	entry = oi.modelRegistry.Get("extra-model", "2.0")
+	assert.Equal(t, ModelStateUnavailable, entry.State)
+}
+
+func TestRepositoryLoadRejectsDisallowedArtifactHost(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	artifactServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fake-model-bytes")) // nolint: errcheck
+	}))
+	defer artifactServer.Close()
+
+	// No allowedHosts entry for artifactServer's host, so the fetch must be
+	// rejected instead of being made.
+	body := `{"parameters":{"artifact_url":"` + artifactServer.URL + `","version":"3.0"}}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/repository/models/extra-model/load", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	oi.handleRepositoryModelAction(w, req)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+
+	entry := oi.modelRegistry.Get("extra-model", "3.0")
+	assert.Equal(t, ModelStateUnavailable, entry.State)
+}
+
+func TestHandleModelConfigUnknownModel(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/models/no-such-model/versions/1/config", nil)
+	w := httptest.NewRecorder()
+
+	oi.handleModelConfig(w, req, req.URL.Path)
+
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
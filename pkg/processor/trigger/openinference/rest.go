@@ -11,8 +11,10 @@ package openinference
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -96,6 +98,12 @@ func (oi *openInference) registerRESTHandlers(mux *http.ServeMux) {
 	// Metadata endpoints
 	mux.HandleFunc("/v2", oi.handleServerMetadata)
 
+	// Model repository extension endpoints
+	if oi.configuration.EnableModelRepository {
+		mux.HandleFunc("/v2/repository/index", oi.handleRepositoryIndex)
+		mux.HandleFunc("/v2/repository/models/", oi.handleRepositoryModelAction)
+	}
+
 	oi.Logger.InfoWith("REST handlers registered")
 }
 
@@ -118,25 +126,34 @@ func (oi *openInference) handleModelEndpoints(w http.ResponseWriter, r *http.Req
 	// Simple routing based on path suffix
 	if strings.HasSuffix(path, "/ready") {
 		oi.handleModelReady(w, r)
+	} else if strings.HasSuffix(path, "/infer_stream") {
+		oi.handleModelInferStream(w, r)
 	} else if strings.HasSuffix(path, "/infer") {
 		oi.handleModelInfer(w, r)
+	} else if oi.configuration.EnableModelRepository && strings.HasSuffix(path, "/config") {
+		oi.handleModelConfig(w, r, path)
 	} else if strings.HasSuffix(path, "/models/"+oi.configuration.ModelName) || strings.HasSuffix(path, "/models/"+oi.configuration.ModelName+"/versions/"+oi.configuration.ModelVersion) {
 		oi.handleModelMetadata(w, r)
 	} else {
-		http.Error(w, "Not found", http.StatusNotFound)
+		oi.writeAPIError(w, NewAPIError(ErrCodeModelNotFound, fmt.Sprintf("no such model endpoint: %s", path)))
 	}
 }
 
 func (oi *openInference) handleServerMetadata(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/v2" {
-		http.Error(w, "Not found", http.StatusNotFound)
+		oi.writeAPIError(w, NewAPIError(ErrCodeNotFound, "no such server endpoint"))
 		return
 	}
 
+	extensions := []string{"binary_tensor_data"}
+	if oi.configuration.EnableModelRepository {
+		extensions = append(extensions, "model_repository")
+	}
+
 	response := ServerMetadataResponse{
 		Name:       "digitalhub-serverless",
 		Version:    "1.0.0",
-		Extensions: []string{},
+		Extensions: extensions,
 	}
 	oi.writeJSONResponse(w, http.StatusOK, response)
 }
@@ -177,28 +194,67 @@ func (oi *openInference) handleModelReady(w http.ResponseWriter, _ *http.Request
 
 func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		oi.writeAPIError(w, NewAPIError(ErrCodeMethodNotAllowed, "only POST is supported for inference"))
 		return
 	}
 
-	// Read request body
+	// Read request body, capped at MaxRESTBodyBytes: the binary tensor data
+	// extension can append an arbitrarily large raw suffix after the JSON
+	// header, so this can't be left unbounded like a plain JSON request body.
+	r.Body = http.MaxBytesReader(w, r.Body, oi.configuration.MaxRESTBodyBytes)
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		oi.Logger.WarnWith("Failed to read request body", "error", err)
-		http.Error(w, "Failed to read request", http.StatusBadRequest)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, "request body too large or unreadable"))
 		return
 	}
 	defer r.Body.Close()
 
+	// The KServe v2 binary tensor data extension: when present, the header
+	// splits body into a JSON prefix (the inference request, sans binary
+	// tensor data) and a raw binary suffix, consumed per-tensor below via
+	// each input's parameters.binary_data_size.
+	jsonBody := body
+	var binaryData []byte
+	var headerContentLength int
+	if headerLenStr := r.Header.Get(InferenceHeaderContentLengthHeader); headerLenStr != "" {
+		headerLen, err := strconv.Atoi(headerLenStr)
+		if err != nil {
+			oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, fmt.Sprintf("invalid %s", InferenceHeaderContentLengthHeader)))
+			return
+		}
+		jsonBody, binaryData, err = SplitBinaryRequestBody(body, headerLen)
+		if err != nil {
+			oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, err.Error()))
+			return
+		}
+		headerContentLength = headerLen
+	}
+
 	// Parse inference request
 	var inferRequest RESTInferenceRequest
-	if err := json.Unmarshal(body, &inferRequest); err != nil {
+	if err := json.Unmarshal(jsonBody, &inferRequest); err != nil {
 		oi.Logger.WarnWith("Failed to parse inference request", "error", err)
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, "invalid request format").WithDetails(map[string]any{
+			"parse_error": err.Error(),
+		}))
 		return
 	}
 
-	// Create nuclio event with the REST inference request as body
+	var rawInputs [][]byte
+	if binaryData != nil {
+		rawInputs, err = decodeBinaryInputTensors(inferRequest.Inputs, binaryData)
+		if err != nil {
+			oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, err.Error()))
+			return
+		}
+	}
+
+	// Create nuclio event with the REST inference request as body. The
+	// request's own Content-Type (application/json by default, or one of
+	// the KServe v2 binary tensor data extension's
+	// application/vnd.kserve.inference-v2+json / application/octet-stream)
+	// is preserved as-is so GetContentType reflects what the client sent.
 	event := &Event{
 		body: body,
 		headers: map[string]any{
@@ -206,14 +262,17 @@ func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request
 			"X-Model-Version": oi.configuration.ModelVersion,
 			"X-Request-ID":    inferRequest.ID,
 		},
-		timestamp:    time.Now(),
-		modelName:    oi.configuration.ModelName,
-		modelVersion: oi.configuration.ModelVersion,
-		parameters:   inferRequest.Parameters,
+		timestamp:           time.Now(),
+		modelName:           oi.configuration.ModelName,
+		modelVersion:        oi.configuration.ModelVersion,
+		parameters:          inferRequest.Parameters,
+		contentType:         r.Header.Get("Content-Type"),
+		headerContentLength: headerContentLength,
+		rawInputs:           rawInputs,
 	}
 
-	// Submit to worker
-	response, submitError, processError := oi.AllocateWorkerAndSubmitEvent(
+	// Submit to worker (or forward to a reattach target, if configured)
+	response, submitError, processError := oi.dispatchEvent(
 		event,
 		oi.Logger,
 		10*time.Second,
@@ -221,13 +280,13 @@ func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request
 
 	if submitError != nil {
 		oi.Logger.WarnWith("Failed to submit event", "error", submitError)
-		http.Error(w, "Failed to process request", http.StatusInternalServerError)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInferenceFailed, "failed to submit inference request").WithRequestID(inferRequest.ID))
 		return
 	}
 
 	if processError != nil {
 		oi.Logger.WarnWith("Failed to process event", "error", processError)
-		http.Error(w, "Failed to process request", http.StatusInternalServerError)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInferenceFailed, "failed to process inference request").WithRequestID(inferRequest.ID))
 		return
 	}
 
@@ -238,7 +297,7 @@ func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request
 		var inferResponse RESTInferenceResponse
 		if err := json.Unmarshal(typedResponse.Body, &inferResponse); err != nil {
 			oi.Logger.WarnWith("Failed to parse function response", "error", err)
-			http.Error(w, "Invalid function response", http.StatusInternalServerError)
+			oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "invalid function response").WithRequestID(inferRequest.ID))
 			return
 		}
 
@@ -253,7 +312,18 @@ func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request
 			inferResponse.ID = inferRequest.ID
 		}
 
-		oi.writeJSONResponse(w, typedResponse.StatusCode, inferResponse)
+		binaryData, err := encodeBinaryOutputTensors(inferResponse.Outputs)
+		if err != nil {
+			oi.Logger.WarnWith("Failed to binary-encode output tensors", "error", err)
+			oi.writeAPIError(w, NewAPIError(ErrCodeInternal, err.Error()).WithRequestID(inferRequest.ID))
+			return
+		}
+
+		if binaryData != nil {
+			oi.writeBinaryInferenceResponse(w, typedResponse.StatusCode, inferResponse, binaryData)
+		} else {
+			oi.writeJSONResponse(w, typedResponse.StatusCode, inferResponse)
+		}
 
 	default:
 		// If response is not a nuclio.Response, convert it to JSON
@@ -261,6 +331,111 @@ func (oi *openInference) handleModelInfer(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// handleModelInferStream serves the KServe v2 server-sent-events streaming
+// extension: the client POSTs the same RESTInferenceRequest shape as
+// /infer and gets its response back over text/event-stream instead of a
+// single JSON body, and that response is also published to every
+// configured stream sink (see Configuration.Sinks, publishStreamOutput).
+// The binary tensor data extension isn't supported on this route, unlike
+// /infer - a streamed model's output is expected to be token/frame data
+// suited to JSON or a sink, not bulk tensors needing the binary format.
+func (oi *openInference) handleModelInferStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		oi.writeAPIError(w, NewAPIError(ErrCodeMethodNotAllowed, "only POST is supported for inference"))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "streaming not supported by this response writer"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, oi.configuration.MaxRESTBodyBytes)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		oi.Logger.WarnWith("Failed to read request body", "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, "request body too large or unreadable"))
+		return
+	}
+	defer r.Body.Close()
+
+	var inferRequest RESTInferenceRequest
+	if err := json.Unmarshal(body, &inferRequest); err != nil {
+		oi.Logger.WarnWith("Failed to parse inference request", "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInvalidInput, "invalid request format").WithDetails(map[string]any{
+			"parse_error": err.Error(),
+		}))
+		return
+	}
+
+	event := &Event{
+		body: body,
+		headers: map[string]any{
+			"X-Model-Name":    oi.configuration.ModelName,
+			"X-Model-Version": oi.configuration.ModelVersion,
+			"X-Request-ID":    inferRequest.ID,
+			"X-Protocol":      "rest-sse",
+		},
+		timestamp:    time.Now(),
+		modelName:    oi.configuration.ModelName,
+		modelVersion: oi.configuration.ModelVersion,
+		parameters:   inferRequest.Parameters,
+		contentType:  r.Header.Get("Content-Type"),
+	}
+
+	response, submitError, processError := oi.dispatchEvent(event, oi.Logger, 10*time.Second)
+	if submitError != nil {
+		oi.Logger.WarnWith("Failed to submit event", "error", submitError)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInferenceFailed, "failed to submit inference request").WithRequestID(inferRequest.ID))
+		return
+	}
+	if processError != nil {
+		oi.Logger.WarnWith("Failed to process event", "error", processError)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInferenceFailed, "failed to process inference request").WithRequestID(inferRequest.ID))
+		return
+	}
+
+	typedResponse, ok := response.(nuclio.Response)
+	if !ok {
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "unexpected response type from function").WithRequestID(inferRequest.ID))
+		return
+	}
+
+	var inferResponse RESTInferenceResponse
+	if err := json.Unmarshal(typedResponse.Body, &inferResponse); err != nil {
+		oi.Logger.WarnWith("Failed to parse function response", "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "invalid function response").WithRequestID(inferRequest.ID))
+		return
+	}
+	if inferResponse.ModelName == "" {
+		inferResponse.ModelName = oi.configuration.ModelName
+	}
+	if inferResponse.ModelVersion == "" {
+		inferResponse.ModelVersion = oi.configuration.ModelVersion
+	}
+	if inferResponse.ID == "" && inferRequest.ID != "" {
+		inferResponse.ID = inferRequest.ID
+	}
+
+	oi.publishStreamOutput(r.Context(), inferResponse.ID, "rest-sse", &inferResponse)
+
+	data, err := json.Marshal(inferResponse)
+	if err != nil {
+		oi.Logger.WarnWith("Failed to marshal streamed inference response", "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "failed to marshal inference response").WithRequestID(inferRequest.ID))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
+}
+
 func (oi *openInference) writeJSONResponse(w http.ResponseWriter, statusCode int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -269,3 +444,43 @@ func (oi *openInference) writeJSONResponse(w http.ResponseWriter, statusCode int
 		oi.Logger.WarnWith("Failed to write JSON response", "error", err)
 	}
 }
+
+// writeBinaryInferenceResponse writes inferResponse per the KServe v2 binary
+// tensor data extension: the JSON header (with binary output tensors'
+// "data" already stripped by encodeBinaryOutputTensors) followed by
+// binaryData, with Inference-Header-Content-Length set to the header's
+// length so the client knows where to split them back apart.
+func (oi *openInference) writeBinaryInferenceResponse(w http.ResponseWriter, statusCode int, inferResponse RESTInferenceResponse, binaryData []byte) {
+	header, err := json.Marshal(inferResponse)
+	if err != nil {
+		oi.Logger.WarnWith("Failed to marshal inference response header", "error", err)
+		oi.writeAPIError(w, NewAPIError(ErrCodeInternal, "failed to marshal inference response"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set(InferenceHeaderContentLengthHeader, strconv.Itoa(len(header)))
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(header); err != nil {
+		oi.Logger.WarnWith("Failed to write binary response header", "error", err)
+		return
+	}
+	if _, err := w.Write(binaryData); err != nil {
+		oi.Logger.WarnWith("Failed to write binary response data", "error", err)
+	}
+}
+
+// writeAPIError counts apiErr against its code and writes it as the JSON
+// error body, so REST clients see the same {code, message, ...} shape
+// regardless of which handler produced it.
+func (oi *openInference) writeAPIError(w http.ResponseWriter, apiErr *APIError) {
+	count := oi.errorMetrics.inc(apiErr.Code)
+	oi.Logger.WarnWith("API error",
+		"code", apiErr.Code,
+		"message", apiErr.Message,
+		"requestID", apiErr.RequestID,
+		"count", count)
+
+	oi.writeJSONResponse(w, apiErr.HTTPStatusCode, apiErr)
+}
@@ -73,8 +73,13 @@ func createTestOpenInferenceTrigger(t *testing.T) *openInference {
 	config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
 	assert.NoError(t, err)
 
+	modelRegistry := NewModelRegistry(config.ModelRepositoryDir, config.ArtifactAllowedHosts, config.MaxArtifactBytes)
+	modelRegistry.Seed(config.ModelName, config.ModelVersion)
+
 	oi := &openInference{
 		configuration: config,
+		errorMetrics:  newErrorMetrics(),
+		modelRegistry: modelRegistry,
 	}
 	oi.Logger = testLogger
 
@@ -113,6 +118,22 @@ func TestRESTHandleServerReady(t *testing.T) {
 	assert.True(t, response.Ready)
 }
 
+func TestRESTHandleServerMetadataAdvertisesBinaryTensorData(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+
+	req := httptest.NewRequest("GET", "/v2", nil)
+	w := httptest.NewRecorder()
+
+	oi.handleServerMetadata(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response ServerMetadataResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response.Extensions, "binary_tensor_data")
+}
+
 func TestRESTHandleModelMetadata(t *testing.T) {
 	oi := createTestOpenInferenceTrigger(t)
 
@@ -172,4 +193,109 @@ func TestRESTJSONSerialization(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "output", decoded.Name)
 	})
+
+	t.Run("MixedJSONAndBinaryInputTensors", func(t *testing.T) {
+		jsonTensor := RESTInferInputTensor{
+			Name:     "text_input",
+			Datatype: "BYTES",
+			Shape:    []int64{1},
+			Data:     []interface{}{"hello"},
+		}
+
+		floats := []float32{1.5, -2.25, 3}
+		binaryData, err := EncodeBinaryTensorData("FP32", floats)
+		assert.NoError(t, err)
+
+		binaryTensor := RESTInferInputTensor{
+			Name:       "embedding_input",
+			Datatype:   "FP32",
+			Shape:      []int64{1, 3},
+			Parameters: map[string]any{"binary_data_size": len(binaryData)},
+		}
+
+		request := RESTInferenceRequest{
+			Inputs: []RESTInferInputTensor{jsonTensor, binaryTensor},
+		}
+
+		header, err := json.Marshal(request)
+		assert.NoError(t, err)
+
+		body := append(append([]byte{}, header...), binaryData...)
+
+		jsonBody, suffix, err := SplitBinaryRequestBody(body, len(header))
+		assert.NoError(t, err)
+		assert.Equal(t, binaryData, suffix)
+
+		var decoded RESTInferenceRequest
+		assert.NoError(t, json.Unmarshal(jsonBody, &decoded))
+		_, err = decodeBinaryInputTensors(decoded.Inputs, suffix)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []interface{}{"hello"}, decoded.Inputs[0].Data)
+		assert.Equal(t, []float32{1.5, -2.25, 3}, decoded.Inputs[1].Data)
+	})
+
+	t.Run("BinaryInputTensorShapeMismatchRejected", func(t *testing.T) {
+		floats := []float32{1.5, -2.25, 3}
+		binaryData, err := EncodeBinaryTensorData("FP32", floats)
+		assert.NoError(t, err)
+
+		tensor := RESTInferInputTensor{
+			Name: "embedding_input",
+			// Declares 4 elements but only 3 are present in binaryData.
+			Datatype:   "FP32",
+			Shape:      []int64{1, 4},
+			Parameters: map[string]any{"binary_data_size": len(binaryData)},
+		}
+
+		_, err = decodeBinaryInputTensors([]RESTInferInputTensor{tensor}, binaryData)
+		assert.Error(t, err)
+	})
+
+	t.Run("BinaryInputTensorNegativeSizeRejected", func(t *testing.T) {
+		binaryData := []byte{1, 2, 3, 4}
+
+		tensor := RESTInferInputTensor{
+			Name:       "embedding_input",
+			Datatype:   "FP32",
+			Shape:      []int64{1},
+			Parameters: map[string]any{"binary_data_size": -1},
+		}
+
+		_, err := decodeBinaryInputTensors([]RESTInferInputTensor{tensor}, binaryData)
+		assert.Error(t, err)
+	})
+}
+
+// TestEventInferenceSplit covers the Event-level fields a KServe v2 binary
+// tensor data extension request populates: GetContentType reflects what the
+// client actually sent, and GetInferenceHeader/GetInferenceData/GetRawInputs
+// expose the split metadata/binary portions of body.
+func TestEventInferenceSplit(t *testing.T) {
+	t.Run("plain JSON request falls back to historical defaults", func(t *testing.T) {
+		event := &Event{body: []byte(`{"inputs":[]}`)}
+
+		assert.Equal(t, "application/json", event.GetContentType())
+		assert.Equal(t, event.body, event.GetInferenceHeader())
+		assert.Nil(t, event.GetInferenceData())
+		assert.Nil(t, event.GetRawInputs())
+	})
+
+	t.Run("binary tensor data extension request splits header from data", func(t *testing.T) {
+		header := []byte(`{"inputs":[{"name":"x","datatype":"FP32","shape":[3],"parameters":{"binary_data_size":12}}]}`)
+		binaryData, err := EncodeBinaryTensorData("FP32", []float32{1, 2, 3})
+		assert.NoError(t, err)
+
+		event := &Event{
+			body:                append(append([]byte{}, header...), binaryData...),
+			contentType:         "application/vnd.kserve.inference-v2+json",
+			headerContentLength: len(header),
+			rawInputs:           [][]byte{binaryData},
+		}
+
+		assert.Equal(t, "application/vnd.kserve.inference-v2+json", event.GetContentType())
+		assert.Equal(t, header, event.GetInferenceHeader())
+		assert.Equal(t, binaryData, event.GetInferenceData())
+		assert.Equal(t, [][]byte{binaryData}, event.GetRawInputs())
+	})
 }
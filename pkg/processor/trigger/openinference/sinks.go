@@ -0,0 +1,132 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+)
+
+// SinkConfig names one sink.Factory kind and the configuration passed to
+// sink.RegistrySingleton.Create for it, e.g. {Kind: "rtsp", Config:
+// {"type": "video", ...}} to publish video frames, or {Kind: "websocket"}
+// to publish tokens. Unlike AuditSinks (kind names only, always created
+// with an empty config map), each entry here carries its own config since
+// a sink meant to carry live stream output typically needs one (an
+// address, a codec, a path) to be useful.
+type SinkConfig struct {
+	Kind   string                 `mapstructure:"kind"`
+	Config map[string]interface{} `mapstructure:"config"`
+}
+
+// streamSinkDispatcher publishes every partial streaming response (see
+// ModelStreamInfer and handleModelInferStream) to the sinks resolved from
+// Configuration.Sinks. Unlike auditDispatcher it does not queue: frame and
+// token ordering matters to a downstream rtsp or websocket sink in a way
+// it doesn't for an audit trail, and the sinks in this package already
+// serialize their own Write calls internally, so publish writes straight
+// through instead of adding a second queue in front of theirs.
+type streamSinkDispatcher struct {
+	logger logger.Logger
+	sinks  []sink.Sink
+}
+
+// newStreamSinkDispatcher resolves each configured kind against
+// sink.RegistrySingleton and starts it. Returns nil, nil when configs is
+// empty, which disables stream sink fan-out entirely.
+func newStreamSinkDispatcher(logger logger.Logger, configs []SinkConfig) (*streamSinkDispatcher, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	sinks := make([]sink.Sink, 0, len(configs))
+	for _, config := range configs {
+		streamSink, err := sink.RegistrySingleton.Create(logger, config.Kind, config.Config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create stream sink %q", config.Kind)
+		}
+
+		if err := streamSink.Start(); err != nil {
+			return nil, errors.Wrapf(err, "Failed to start stream sink %q", config.Kind)
+		}
+
+		sinks = append(sinks, streamSink)
+	}
+
+	return &streamSinkDispatcher{
+		logger: logger,
+		sinks:  sinks,
+	}, nil
+}
+
+// publish writes data to every configured sink, best-effort: a failing
+// sink is logged and skipped rather than aborting the rest, since one
+// stalled sink (e.g. an rtsp client that stopped reading) must not stop a
+// different one (e.g. a websocket client still reading fine) from getting
+// this message. A nil dispatcher (no sinks configured) is a no-op.
+func (d *streamSinkDispatcher) publish(ctx context.Context, requestID string, data []byte) {
+	if d == nil {
+		return
+	}
+
+	for _, streamSink := range d.sinks {
+		if err := streamSink.Write(ctx, data, map[string]interface{}{"request_id": requestID}); err != nil {
+			d.logger.WarnWith("Failed to write to stream sink", "sink", streamSink.GetKind(), "requestID", requestID, "error", err)
+		}
+	}
+}
+
+// stop stops every configured sink, forwarding force so an operator
+// forcing the trigger to stop also forces its stream sinks to, rather than
+// always giving them the graceful path regardless of what was asked. Safe
+// to call on a nil dispatcher.
+func (d *streamSinkDispatcher) stop(force bool) {
+	if d == nil {
+		return
+	}
+
+	for _, streamSink := range d.sinks {
+		if err := streamSink.Stop(force); err != nil {
+			d.logger.WarnWith("Failed to stop stream sink", "sink", streamSink.GetKind(), "error", err)
+		}
+	}
+}
+
+// publishStreamOutput marshals restResponse and publishes it to
+// oi.streamSinkDispatcher under requestID/protocol, logging rather than
+// failing the call on a marshal error since a sink fan-out problem must
+// never fail the inference response it's derived from.
+//
+// This is the point where the request for a worker.StreamingEvent
+// carrying a chan []byte back from the function runtime would plug in:
+// that would let a single invocation publish many partial responses of
+// its own choosing (token-by-token, frame-by-frame) as it runs.
+// pkg/processor/worker here is github.com/nuclio/nuclio's own package,
+// not one this module owns or vendors, so it can't be given a new
+// exported type from here. What this wires up instead is everything on
+// this side of that boundary: ModelStreamInfer and handleModelInferStream
+// already deliver one response per inbound message/request, and each of
+// those responses now gets published to every configured sink the same
+// way a function emitting its own chan []byte eventually would.
+func (oi *openInference) publishStreamOutput(ctx context.Context, requestID, protocol string, restResponse *RESTInferenceResponse) {
+	if oi.streamSinkDispatcher == nil || restResponse == nil {
+		return
+	}
+
+	data, err := json.Marshal(restResponse)
+	if err != nil {
+		oi.Logger.WarnWith("Failed to marshal stream response for sink fan-out",
+			"requestID", requestID, "protocol", protocol, "error", err)
+		return
+	}
+
+	oi.streamSinkDispatcher.publish(ctx, requestID, data)
+}
@@ -0,0 +1,128 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/nuclio/logger"
+	nucliozap "github.com/nuclio/zap"
+	"github.com/scc-digitalhub/digitalhub-serverless/pkg/processor/sink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStreamSink is a minimal sink.Sink used only to exercise
+// streamSinkDispatcher without depending on a concrete sink-kind package.
+type fakeStreamSink struct {
+	mu      sync.Mutex
+	written [][]byte
+	stopped bool
+	force   bool
+	failing bool
+}
+
+func (s *fakeStreamSink) Start() error { return nil }
+func (s *fakeStreamSink) Stop(force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	s.force = force
+	return nil
+}
+func (s *fakeStreamSink) Write(ctx context.Context, data []byte, metadata map[string]interface{}) error {
+	if s.failing {
+		return assert.AnError
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written = append(s.written, data)
+	return nil
+}
+func (s *fakeStreamSink) GetKind() string                   { return "fake-stream-sink" }
+func (s *fakeStreamSink) GetConfig() map[string]interface{} { return nil }
+
+type fakeStreamSinkFactory struct {
+	sink *fakeStreamSink
+}
+
+func (f *fakeStreamSinkFactory) Create(logger logger.Logger, configuration map[string]interface{}) (sink.Sink, error) {
+	return f.sink, nil
+}
+func (f *fakeStreamSinkFactory) GetKind() string { return "fake-stream-sink" }
+
+func init() {
+	sink.RegistrySingleton.Register("fake-stream-sink", &fakeStreamSinkFactory{sink: &fakeStreamSink{}})
+}
+
+func TestStreamSinkDispatcherEmptyConfigsDisablesFanOut(t *testing.T) {
+	dispatcher, err := newStreamSinkDispatcher(nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, dispatcher)
+
+	// publish/stop must both be safe no-ops on a nil dispatcher.
+	dispatcher.publish(context.Background(), "req-1", []byte("data"))
+	dispatcher.stop(false)
+}
+
+func TestStreamSinkDispatcherUnknownKind(t *testing.T) {
+	dispatcher, err := newStreamSinkDispatcher(nil, []SinkConfig{{Kind: "no-such-sink-kind"}})
+	assert.Error(t, err)
+	assert.Nil(t, dispatcher)
+}
+
+func TestStreamSinkDispatcherPublishWritesToEverySink(t *testing.T) {
+	fakeSink := &fakeStreamSink{}
+	sink.RegistrySingleton.Register("fake-stream-sink", &fakeStreamSinkFactory{sink: fakeSink})
+
+	dispatcher, err := newStreamSinkDispatcher(nil, []SinkConfig{{Kind: "fake-stream-sink"}})
+	require.NoError(t, err)
+	require.NotNil(t, dispatcher)
+
+	dispatcher.publish(context.Background(), "req-1", []byte(`{"outputs":[]}`))
+
+	require.Len(t, fakeSink.written, 1)
+	assert.Equal(t, []byte(`{"outputs":[]}`), fakeSink.written[0])
+}
+
+func TestStreamSinkDispatcherPublishSkipsFailingSinkWithoutPanicking(t *testing.T) {
+	fakeSink := &fakeStreamSink{failing: true}
+	sink.RegistrySingleton.Register("fake-stream-sink", &fakeStreamSinkFactory{sink: fakeSink})
+
+	testLogger, err := nucliozap.NewNuclioZapTest("test")
+	require.NoError(t, err)
+
+	dispatcher, err := newStreamSinkDispatcher(testLogger, []SinkConfig{{Kind: "fake-stream-sink"}})
+	require.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		dispatcher.publish(context.Background(), "req-1", []byte("data"))
+	})
+}
+
+func TestStreamSinkDispatcherStopForwardsForce(t *testing.T) {
+	fakeSink := &fakeStreamSink{}
+	sink.RegistrySingleton.Register("fake-stream-sink", &fakeStreamSinkFactory{sink: fakeSink})
+
+	dispatcher, err := newStreamSinkDispatcher(nil, []SinkConfig{{Kind: "fake-stream-sink"}})
+	require.NoError(t, err)
+
+	dispatcher.stop(true)
+
+	assert.True(t, fakeSink.stopped)
+	assert.True(t, fakeSink.force)
+}
+
+func TestPublishStreamOutputNilDispatcherIsNoop(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	assert.Nil(t, oi.streamSinkDispatcher)
+
+	// Must not panic when stream sink fan-out is disabled.
+	oi.publishStreamOutput(context.Background(), "req-1", "grpc-stream", &RESTInferenceResponse{})
+}
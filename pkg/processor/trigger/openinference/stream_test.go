@@ -0,0 +1,104 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package openinference
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeModelStreamServer is a minimal pb.GRPCInferenceService_ModelStreamInferServer,
+// embedding grpc.ServerStream (left nil) so only the few methods
+// ModelStreamInfer actually calls need implementations.
+type fakeModelStreamServer struct {
+	grpc.ServerStream
+	ctx   context.Context
+	recvs []*pb.ModelInferRequest
+	idx   int
+	sent  []*pb.ModelInferResponse
+}
+
+func (f *fakeModelStreamServer) Context() context.Context { return f.ctx }
+
+func (f *fakeModelStreamServer) Send(resp *pb.ModelInferResponse) error {
+	f.sent = append(f.sent, resp)
+	return nil
+}
+
+func (f *fakeModelStreamServer) Recv() (*pb.ModelInferRequest, error) {
+	if f.idx >= len(f.recvs) {
+		return nil, io.EOF
+	}
+	req := f.recvs[f.idx]
+	f.idx++
+	return req, nil
+}
+
+// TestModelStreamInferStopsDispatchingOnceContextCancelled exercises
+// MaxInflightPerStream's cancellation path directly: once ctx is done, a
+// request already queued by Recv must not be dispatched to the worker pool
+// (which this test has no fake for), and ModelStreamInfer must return
+// promptly with ctx.Err() instead of blocking on the inflight semaphore.
+func TestModelStreamInferStopsDispatchingOnceContextCancelled(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	server := &grpcInferenceServer{trigger: oi}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stream := &fakeModelStreamServer{
+		ctx:   ctx,
+		recvs: []*pb.ModelInferRequest{{Id: "req-1"}},
+	}
+
+	err := server.ModelStreamInfer(stream)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, stream.sent)
+}
+
+func TestEnqueueStreamResponseDropOldest(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.StreamBackpressureMode = StreamBackpressureDropOldest
+	server := &grpcInferenceServer{trigger: oi}
+
+	responses := make(chan *pb.ModelInferResponse, 1)
+	first := &pb.ModelInferResponse{Id: "first"}
+	second := &pb.ModelInferResponse{Id: "second"}
+
+	server.enqueueStreamResponse(context.Background(), responses, first)
+	server.enqueueStreamResponse(context.Background(), responses, second)
+
+	assert.Len(t, responses, 1)
+	assert.Equal(t, "second", (<-responses).Id)
+}
+
+func TestEnqueueStreamResponseBlockWaitsForRoom(t *testing.T) {
+	oi := createTestOpenInferenceTrigger(t)
+	oi.configuration.StreamBackpressureMode = StreamBackpressureBlock
+	server := &grpcInferenceServer{trigger: oi}
+
+	responses := make(chan *pb.ModelInferResponse, 1)
+	responses <- &pb.ModelInferResponse{Id: "first"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.enqueueStreamResponse(ctx, responses, &pb.ModelInferResponse{Id: "second"})
+		close(done)
+	}()
+
+	cancel()
+	<-done
+
+	// Block mode never drops the queued response to make room.
+	assert.Equal(t, "first", (<-responses).Id)
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/nuclio/errors"
 	"github.com/nuclio/logger"
@@ -30,6 +31,31 @@ type openInference struct {
 	restServer *http.Server
 	grpcServer *grpc.Server
 
+	// errorMetrics counts API errors emitted on either transport, keyed by
+	// ErrorCode.
+	errorMetrics *errorMetrics
+
+	// auditDispatcher fans out audit records to configuration.AuditSinks;
+	// nil when AuditSinks is empty.
+	auditDispatcher *auditDispatcher
+
+	// streamSinkDispatcher fans out partial ModelStreamInfer/infer_stream
+	// responses to configuration.Sinks; nil when Sinks is empty.
+	streamSinkDispatcher *streamSinkDispatcher
+
+	// modelRegistry backs the repository-extension endpoints
+	// (/v2/repository/...) when EnableModelRepository is set. It is always
+	// created and seeded with the statically configured
+	// ModelName/ModelVersion so /v2/repository/index reports it
+	// consistently with ModelReady/ModelMetadata, which still serve it
+	// directly from configuration rather than through the registry.
+	modelRegistry *ModelRegistry
+
+	// reattachClient forwards inference requests to an externally managed
+	// model server instead of a local worker when configuration.Reattach
+	// is set; nil otherwise. See reattach.go.
+	reattachClient *reattachClient
+
 	// Context and synchronization
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -58,14 +84,27 @@ func newTrigger(
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	modelRegistry := NewModelRegistry(configuration.ModelRepositoryDir, configuration.ArtifactAllowedHosts, configuration.MaxArtifactBytes)
+	modelRegistry.Seed(configuration.ModelName, configuration.ModelVersion)
+
 	newTrigger := &openInference{
 		AbstractTrigger: abstract,
 		configuration:   configuration,
+		errorMetrics:    newErrorMetrics(),
+		modelRegistry:   modelRegistry,
 		ctx:             ctx,
 		cancel:          cancel,
 	}
 	newTrigger.Trigger = newTrigger
 
+	if configuration.Reattach != nil {
+		reattachClient, err := newReattachClient(logger, configuration.Reattach)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create reattach client")
+		}
+		newTrigger.reattachClient = reattachClient
+	}
+
 	logger.InfoWith("OpenInference trigger created",
 		"modelName", configuration.ModelName,
 		"modelVersion", configuration.ModelVersion,
@@ -82,6 +121,35 @@ func (oi *openInference) Start(checkpoint functionconfig.Checkpoint) error {
 		"modelName", oi.configuration.ModelName,
 		"modelVersion", oi.configuration.ModelVersion)
 
+	auditDispatcher, err := newAuditDispatcher(oi.Logger, oi.configuration.AuditSinks, oi.configuration.AuditQueueSize)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create audit dispatcher")
+	}
+	oi.auditDispatcher = auditDispatcher
+
+	streamSinkDispatcher, err := newStreamSinkDispatcher(oi.Logger, oi.configuration.Sinks)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create stream sink dispatcher")
+	}
+	oi.streamSinkDispatcher = streamSinkDispatcher
+
+	// Reattach mode: the model server is already running and managed
+	// externally (modeled on Terraform's TF_REATTACH_PROVIDERS), so this
+	// trigger never starts its own REST/gRPC servers or owns that
+	// process's lifecycle - it only health-checks it and forwards
+	// inference traffic via dispatchEvent.
+	if oi.reattachClient != nil {
+		oi.Logger.InfoWith("Reattaching to externally managed model server; skipping local REST/gRPC servers",
+			"protocol", oi.configuration.Reattach.Protocol)
+
+		if err := oi.reattachClient.healthCheck(oi.ctx); err != nil {
+			return errors.Wrap(err, "Reattach target failed health check")
+		}
+
+		oi.Logger.InfoWith("OpenInference trigger started successfully in reattach mode")
+		return nil
+	}
+
 	// Start REST server if enabled
 	if oi.configuration.EnableREST {
 		oi.wg.Add(1)
@@ -109,11 +177,16 @@ func (oi *openInference) Start(checkpoint functionconfig.Checkpoint) error {
 }
 
 func (oi *openInference) Stop(force bool) (functionconfig.Checkpoint, error) {
-	oi.Logger.InfoWith("Stopping OpenInference trigger")
+	oi.Logger.InfoWith("Stopping OpenInference trigger", "force", force)
 
 	// Cancel context to signal shutdown
 	oi.cancel()
 
+	// restServer.Shutdown and grpcServer.GracefulStop both already wait for
+	// their own in-flight requests - including a long-lived
+	// ModelStreamInfer call or an open /infer_stream SSE connection - to
+	// finish before returning, so no separate draining step is needed here.
+
 	// Stop REST server
 	if oi.restServer != nil {
 		if err := oi.restServer.Shutdown(context.Background()); err != nil {
@@ -129,12 +202,51 @@ func (oi *openInference) Stop(force bool) (functionconfig.Checkpoint, error) {
 	// Wait for goroutines to finish
 	oi.wg.Wait()
 
+	oi.auditDispatcher.stop()
+	oi.streamSinkDispatcher.stop(force)
+
+	// Only this trigger's own local resources (e.g. its gRPC connection to
+	// the reattach target) are released here - the reattach target itself
+	// is never stopped, since its lifecycle is external by design.
+	if oi.reattachClient != nil {
+		oi.reattachClient.close()
+	}
+
 	oi.Logger.InfoWith("OpenInference trigger stopped")
 	return nil, nil
 }
 
 func (oi *openInference) GetConfig() map[string]interface{} {
-	return common.StructureToMap(oi.configuration)
+	config := common.StructureToMap(oi.configuration)
+
+	// Never expose the reattach target's raw addresses through GetConfig -
+	// only whether reattach mode is on and how it's configured.
+	if oi.configuration.Reattach != nil {
+		config["reattach"] = map[string]interface{}{
+			"protocol": oi.configuration.Reattach.Protocol,
+			"rest_set": oi.configuration.Reattach.REST != "",
+			"grpc_set": oi.configuration.Reattach.GRPC != "",
+		}
+	}
+
+	return config
+}
+
+// dispatchEvent submits event to a local worker, unless this trigger is in
+// reattach mode, in which case it is forwarded to the externally managed
+// model server instead. Every caller - runInfer, handleModelInfer,
+// handleModelInferStream - goes through this instead of
+// AllocateWorkerAndSubmitEvent directly so reattach mode is transparent to
+// them.
+func (oi *openInference) dispatchEvent(
+	event *Event,
+	log logger.Logger,
+	timeout time.Duration,
+) (interface{}, error, error) {
+	if oi.reattachClient != nil {
+		return oi.reattachClient.dispatch(event)
+	}
+	return oi.AllocateWorkerAndSubmitEvent(event, log, timeout)
 }
 
 func (oi *openInference) startRESTServer() error {
@@ -165,8 +277,7 @@ func (oi *openInference) startGRPCServer() error {
 		return errors.Wrap(err, "Failed to create gRPC listener")
 	}
 
-	oi.grpcServer = grpc.NewServer()
-	oi.registerGRPCHandlers(oi.grpcServer)
+	oi.grpcServer = oi.registerGRPCHandlers()
 
 	oi.Logger.InfoWith("Starting gRPC server", "address", addr)
 
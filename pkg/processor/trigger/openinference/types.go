@@ -7,6 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package openinference
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/nuclio/errors"
 	"github.com/nuclio/nuclio/pkg/functionconfig"
@@ -19,8 +23,126 @@ const (
 	DefaultGRPCPort     = 9000
 	DefaultModelName    = "model"
 	DefaultModelVersion = "1"
+
+	// DefaultGRPCLogSampleRate logs every request when payload logging is
+	// sampled, matching the historical (pre-sampling) behavior.
+	DefaultGRPCLogSampleRate = 1.0
+
+	// DefaultGRPCRetryMax preserves the historical behavior of not
+	// retrying at all when the "retry" interceptor is opted into without
+	// an explicit limit.
+	DefaultGRPCRetryMax = 0
+
+	// DefaultStreamBackpressureMode drops the oldest unsent response
+	// rather than blocking ModelStreamInfer's receive loop when a client
+	// reads slower than the worker produces responses.
+	DefaultStreamBackpressureMode = StreamBackpressureDropOldest
+
+	// DefaultStreamQueueSize bounds the in-flight response queue per
+	// ModelStreamInfer call.
+	DefaultStreamQueueSize = 16
+
+	// DefaultMaxInflightPerStream bounds how many ModelInferRequests a
+	// single ModelStreamInfer call dispatches to the worker pool at once.
+	DefaultMaxInflightPerStream = 8
+
+	// DefaultAuditRedaction excludes tensor contents from audit records by
+	// default, logging only shapes and data types.
+	DefaultAuditRedaction = AuditRedactionShapeOnly
+
+	// DefaultAuditQueueSize bounds the in-flight audit record queue before
+	// records are dropped.
+	DefaultAuditQueueSize = 256
+
+	// DefaultMaxMessageSize is the gRPC server's max receive/send message
+	// size in bytes when max_message_size isn't set, matching grpc-go's own
+	// built-in default (4 MiB) rather than introducing a different one.
+	DefaultMaxMessageSize = 4 * 1024 * 1024
+
+	// DefaultMaxRESTBodyBytes bounds a REST /infer request body (JSON header
+	// plus any KServe v2 binary tensor data appended after it) when
+	// max_rest_body_bytes isn't set. Matches DefaultMaxMessageSize, the
+	// equivalent cap the gRPC transport already enforces.
+	DefaultMaxRESTBodyBytes = 4 * 1024 * 1024
+
+	// DefaultMaxArtifactBytes bounds an artifact_url fetch when
+	// max_artifact_bytes isn't set.
+	DefaultMaxArtifactBytes = 1024 * 1024 * 1024
+)
+
+// Supported values for Configuration.Reattach.Protocol.
+const (
+	ReattachProtocolREST = "rest"
+	ReattachProtocolGRPC = "grpc"
+)
+
+// EnvOpenInferenceReattach is the environment variable NewConfiguration
+// falls back to for Configuration.Reattach when the trigger's own
+// attributes don't set "reattach", JSON-encoded as {"rest":"host:port",
+// "grpc":"host:port","protocol":"grpc"}. Modeled on Terraform's
+// TF_REATTACH_PROVIDERS: a developer running the model server separately
+// under a debugger sets this instead of editing the function spec.
+const EnvOpenInferenceReattach = "DHUB_OPENINFERENCE_REATTACH"
+
+// Supported values for Configuration.AuditRedaction: "full" redacts tensors
+// down to their names, "shape-only" (the default) keeps shapes and data types
+// but never raw values, "none" includes raw tensor values too.
+const (
+	AuditRedactionFull      = "full"
+	AuditRedactionShapeOnly = "shape-only"
+	AuditRedactionNone      = "none"
+)
+
+// Supported values for Configuration.StreamBackpressureMode.
+const (
+	StreamBackpressureDropOldest = "drop-oldest"
+	StreamBackpressureBlock      = "block"
+)
+
+// Supported values for Configuration.GRPCInterceptors, applied in this
+// order regardless of how they're listed, so "recovery" always runs
+// outermost and "validation" always runs last, right before the handler.
+const (
+	GRPCInterceptorRecovery   = "recovery"
+	GRPCInterceptorAuth       = "auth"
+	GRPCInterceptorTags       = "tags"
+	GRPCInterceptorLogging    = "logging"
+	GRPCInterceptorRetry      = "retry"
+	GRPCInterceptorValidation = "validation"
 )
 
+// grpcInterceptorOrder is the fixed execution order for the interceptors
+// named in Configuration.GRPCInterceptors, independent of the order
+// operators list them in.
+var grpcInterceptorOrder = []string{
+	GRPCInterceptorRecovery,
+	GRPCInterceptorAuth,
+	GRPCInterceptorTags,
+	GRPCInterceptorLogging,
+	GRPCInterceptorRetry,
+	GRPCInterceptorValidation,
+}
+
+// ReattachConfig points the openinference trigger at an already-running,
+// externally managed model server instead of having it serve locally - see
+// Configuration.Reattach and reattach.go.
+type ReattachConfig struct {
+	// REST is the externally managed server's base URL (e.g.
+	// "http://127.0.0.1:8081"). Required when Protocol is "rest", and also
+	// the address inference traffic is forwarded to regardless of
+	// Protocol - see reattachClient.dispatch for why.
+	REST string `mapstructure:"rest"`
+
+	// GRPC is the externally managed server's gRPC address (e.g.
+	// "127.0.0.1:9001"). Required when Protocol is "grpc".
+	GRPC string `mapstructure:"grpc"`
+
+	// Protocol selects which transport is used for the startup health
+	// check: "rest" (default) probes REST's /v2/health/ready, "grpc"
+	// probes gRPC's ServerReady instead.
+	Protocol string `mapstructure:"protocol"`
+}
+
 // TensorDef defines the shape and data type of a tensor
 type TensorDef struct {
 	Name     string  `mapstructure:"name"`
@@ -45,6 +167,126 @@ type Configuration struct {
 	// Tensor definitions
 	InputTensors  []TensorDef `mapstructure:"input_tensors"`
 	OutputTensors []TensorDef `mapstructure:"output_tensors"`
+
+	// GRPCInterceptors opts the gRPC server into the named middleware,
+	// built by registerGRPCHandlers via grpc.ChainUnaryInterceptor and
+	// grpc.ChainStreamInterceptor: "recovery", "auth", "logging", "tags",
+	// "retry", "validation". Empty (the default) leaves the gRPC server
+	// exactly as it was before this chain existed.
+	GRPCInterceptors []string `mapstructure:"grpc_interceptors"`
+
+	// GRPCAuthToken, when set, is the bearer token the "auth" interceptor
+	// requires in the "authorization" metadata key.
+	GRPCAuthToken string `mapstructure:"grpc_auth_token"`
+
+	// GRPCAuthAllowedCNs, when non-empty, makes the "auth" interceptor
+	// require mTLS and check the client certificate's subject common name
+	// against this allowlist instead of a bearer token.
+	GRPCAuthAllowedCNs []string `mapstructure:"grpc_auth_allowed_cns"`
+
+	// GRPCLogPayloads makes the "logging" interceptor include request and
+	// response payloads, not just method/duration/code.
+	GRPCLogPayloads bool `mapstructure:"grpc_log_payloads"`
+
+	// GRPCLogSampleRate is the fraction (0.0-1.0) of requests the
+	// "logging" interceptor logs at payload detail when GRPCLogPayloads
+	// is set; the method/duration/code summary is always logged.
+	GRPCLogSampleRate float64 `mapstructure:"grpc_log_sample_rate"`
+
+	// GRPCRetryCodes lists the gRPC status codes (e.g. "UNAVAILABLE") the
+	// "retry" interceptor re-invokes the handler for.
+	GRPCRetryCodes []string `mapstructure:"grpc_retry_codes"`
+
+	// GRPCRetryMax is the maximum number of retries the "retry"
+	// interceptor performs on a matching code.
+	GRPCRetryMax int `mapstructure:"grpc_retry_max"`
+
+	// StreamBackpressureMode selects how ModelStreamInfer copes with a
+	// client that reads responses slower than the worker produces them:
+	// "drop-oldest" (default) discards the oldest unsent response to make
+	// room for the newest, "block" makes the receive loop wait for the
+	// client to catch up instead.
+	StreamBackpressureMode string `mapstructure:"stream_backpressure_mode"`
+
+	// StreamQueueSize bounds the in-flight response queue per
+	// ModelStreamInfer call before StreamBackpressureMode kicks in.
+	StreamQueueSize int `mapstructure:"stream_queue_size"`
+
+	// MaxInflightPerStream bounds how many ModelInferRequests a single
+	// ModelStreamInfer call dispatches to the worker pool concurrently;
+	// additional requests already received from the client wait their turn
+	// before starting inference.
+	MaxInflightPerStream int `mapstructure:"max_inflight_per_stream"`
+
+	// AuditSinks names sink.Registry kinds (e.g. "stdout", "webhook") that
+	// receive an audit record after every ModelInfer/ModelStreamInfer
+	// completion. Empty (the default) disables auditing entirely.
+	AuditSinks []string `mapstructure:"audit_sinks"`
+
+	// AuditRedaction controls how much of each request/response tensor the
+	// audit record retains: "full", "shape-only" (default), or "none".
+	AuditRedaction string `mapstructure:"audit_redaction"`
+
+	// AuditQueueSize bounds the in-flight audit record queue; once full,
+	// new records are dropped and counted rather than blocking inference.
+	AuditQueueSize int `mapstructure:"audit_queue_size"`
+
+	// Sinks resolves, via sink.RegistrySingleton, the sink.Sink instances
+	// that receive every partial response produced by ModelStreamInfer or
+	// the /infer_stream SSE route - e.g. an "rtsp" sink to republish a
+	// video model's output frames, or a "websocket" sink to forward an
+	// LLM's tokens to browser clients. Empty (the default) disables stream
+	// sink fan-out entirely.
+	Sinks []SinkConfig `mapstructure:"sinks"`
+
+	// EnableGRPCReflection registers the gRPC reflection service
+	// (google.golang.org/grpc/reflection) on the server, letting tools like
+	// grpcurl discover GRPCInferenceService without a local copy of the
+	// proto file.
+	EnableGRPCReflection bool `mapstructure:"enable_grpc_reflection"`
+
+	// MaxMessageSize caps both the largest message the gRPC server will
+	// receive and the largest it will send, in bytes; tensors large enough
+	// to exceed grpc-go's 4 MiB built-in default (e.g. image/embedding
+	// batches) need this raised explicitly.
+	MaxMessageSize int `mapstructure:"max_message_size"`
+
+	// MaxRESTBodyBytes caps the size of a REST /infer request body (the
+	// KServe v2 binary tensor data extension can append an arbitrarily
+	// large raw binary suffix after the JSON header), rejecting anything
+	// larger with ErrCodeInvalidInput instead of reading it fully into
+	// memory first.
+	MaxRESTBodyBytes int64 `mapstructure:"max_rest_body_bytes"`
+
+	// EnableModelRepository registers the KServe v2 model repository
+	// extension endpoints (/v2/repository/index, .../models/{name}/load,
+	// .../models/{name}/unload) and advertises "model_repository" in
+	// ServerMetadataResponse.Extensions.
+	EnableModelRepository bool `mapstructure:"enable_model_repository"`
+
+	// ModelRepositoryDir is the local directory ModelRegistry stages a
+	// loaded model's artifact under, one subdirectory per (name, version).
+	ModelRepositoryDir string `mapstructure:"model_repository_dir"`
+
+	// ArtifactAllowedHosts lists the host[:port] values a repository-load
+	// request's parameters.artifact_url is allowed to target (e.g. the
+	// DigitalHub artifact store's host). Empty (the default) disables
+	// fetching artifact_url entirely, since without an allowlist the
+	// endpoint would let any caller make the server fetch an arbitrary
+	// URL, including internal/metadata endpoints not meant to be reachable
+	// from outside.
+	ArtifactAllowedHosts []string `mapstructure:"artifact_allowed_hosts"`
+
+	// MaxArtifactBytes caps how much of an artifact_url response
+	// ModelRegistry will stage to disk; the fetch is aborted once this
+	// many bytes have been read.
+	MaxArtifactBytes int64 `mapstructure:"max_artifact_bytes"`
+
+	// Reattach, when set (directly or via EnvOpenInferenceReattach), makes
+	// the trigger forward inference traffic to an already-running,
+	// externally managed model server instead of serving it locally - see
+	// ReattachConfig and reattach.go.
+	Reattach *ReattachConfig `mapstructure:"reattach"`
 }
 
 // NewConfiguration creates a new OpenInference trigger configuration
@@ -53,12 +295,22 @@ func NewConfiguration(id string,
 	runtimeConfiguration *runtime.Configuration) (*Configuration, error) {
 
 	newConfiguration := Configuration{
-		RESTPort:     DefaultRESTPort,
-		GRPCPort:     DefaultGRPCPort,
-		EnableREST:   true,
-		EnableGRPC:   true,
-		ModelName:    DefaultModelName,
-		ModelVersion: DefaultModelVersion,
+		RESTPort:               DefaultRESTPort,
+		GRPCPort:               DefaultGRPCPort,
+		EnableREST:             true,
+		EnableGRPC:             true,
+		ModelName:              DefaultModelName,
+		ModelVersion:           DefaultModelVersion,
+		GRPCLogSampleRate:      DefaultGRPCLogSampleRate,
+		GRPCRetryMax:           DefaultGRPCRetryMax,
+		StreamBackpressureMode: DefaultStreamBackpressureMode,
+		StreamQueueSize:        DefaultStreamQueueSize,
+		MaxInflightPerStream:   DefaultMaxInflightPerStream,
+		AuditRedaction:         DefaultAuditRedaction,
+		AuditQueueSize:         DefaultAuditQueueSize,
+		MaxMessageSize:         DefaultMaxMessageSize,
+		MaxRESTBodyBytes:       DefaultMaxRESTBodyBytes,
+		MaxArtifactBytes:       DefaultMaxArtifactBytes,
 	}
 
 	// Create base configuration
@@ -73,6 +325,19 @@ func NewConfiguration(id string,
 		return nil, errors.Wrap(err, "Failed to decode OpenInference trigger attributes")
 	}
 
+	// Fall back to EnvOpenInferenceReattach when the trigger spec itself
+	// doesn't set a reattach target, so a developer can reattach to a
+	// locally running model server without editing the function spec.
+	if newConfiguration.Reattach == nil {
+		if raw := os.Getenv(EnvOpenInferenceReattach); raw != "" {
+			var reattach ReattachConfig
+			if err := json.Unmarshal([]byte(raw), &reattach); err != nil {
+				return nil, errors.Wrapf(err, "Failed to parse %s", EnvOpenInferenceReattach)
+			}
+			newConfiguration.Reattach = &reattach
+		}
+	}
+
 	// Validate configuration
 	if !newConfiguration.EnableREST && !newConfiguration.EnableGRPC {
 		return nil, errors.New("At least one of enable_rest or enable_grpc must be true")
@@ -82,5 +347,101 @@ func NewConfiguration(id string,
 		return nil, errors.New("model_name is required")
 	}
 
+	for _, interceptor := range newConfiguration.GRPCInterceptors {
+		switch interceptor {
+		case GRPCInterceptorRecovery, GRPCInterceptorAuth, GRPCInterceptorLogging,
+			GRPCInterceptorTags, GRPCInterceptorRetry, GRPCInterceptorValidation:
+		default:
+			return nil, errors.Errorf("unsupported grpc_interceptors entry: %s", interceptor)
+		}
+	}
+
+	if newConfiguration.GRPCLogSampleRate < 0 || newConfiguration.GRPCLogSampleRate > 1 {
+		return nil, errors.New("grpc_log_sample_rate must be between 0 and 1")
+	}
+
+	if newConfiguration.GRPCRetryMax < 0 {
+		return nil, errors.New("grpc_retry_max must not be negative")
+	}
+
+	for _, code := range newConfiguration.GRPCRetryCodes {
+		if _, err := grpcCodeFromName(code); err != nil {
+			return nil, errors.Wrap(err, "Failed to validate grpc_retry_codes")
+		}
+	}
+
+	switch newConfiguration.StreamBackpressureMode {
+	case StreamBackpressureDropOldest, StreamBackpressureBlock:
+	default:
+		return nil, errors.Errorf("unsupported stream_backpressure_mode: %s", newConfiguration.StreamBackpressureMode)
+	}
+
+	if newConfiguration.StreamQueueSize <= 0 {
+		return nil, errors.New("stream_queue_size must be positive")
+	}
+
+	if newConfiguration.MaxInflightPerStream <= 0 {
+		return nil, errors.New("max_inflight_per_stream must be positive")
+	}
+
+	switch newConfiguration.AuditRedaction {
+	case AuditRedactionFull, AuditRedactionShapeOnly, AuditRedactionNone:
+	default:
+		return nil, errors.Errorf("unsupported audit_redaction: %s", newConfiguration.AuditRedaction)
+	}
+
+	if newConfiguration.AuditQueueSize <= 0 {
+		return nil, errors.New("audit_queue_size must be positive")
+	}
+
+	for _, sinkConfig := range newConfiguration.Sinks {
+		if sinkConfig.Kind == "" {
+			return nil, errors.New("sinks entries must name a kind")
+		}
+	}
+
+	if newConfiguration.Reattach != nil {
+		switch newConfiguration.Reattach.Protocol {
+		case "":
+			newConfiguration.Reattach.Protocol = ReattachProtocolREST
+		case ReattachProtocolREST, ReattachProtocolGRPC:
+		default:
+			return nil, errors.Errorf("unsupported reattach protocol: %s", newConfiguration.Reattach.Protocol)
+		}
+
+		if newConfiguration.Reattach.Protocol == ReattachProtocolREST && newConfiguration.Reattach.REST == "" {
+			return nil, errors.New("reattach.rest is required when reattach.protocol is \"rest\"")
+		}
+		if newConfiguration.Reattach.Protocol == ReattachProtocolGRPC && newConfiguration.Reattach.GRPC == "" {
+			return nil, errors.New("reattach.grpc is required when reattach.protocol is \"grpc\"")
+		}
+	}
+
+	if newConfiguration.MaxMessageSize <= 0 {
+		newConfiguration.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	if newConfiguration.MaxRESTBodyBytes <= 0 {
+		newConfiguration.MaxRESTBodyBytes = DefaultMaxRESTBodyBytes
+	}
+
+	if newConfiguration.ModelRepositoryDir == "" {
+		newConfiguration.ModelRepositoryDir = filepath.Join(os.TempDir(), "openinference-models")
+	}
+
+	if newConfiguration.MaxArtifactBytes <= 0 {
+		newConfiguration.MaxArtifactBytes = DefaultMaxArtifactBytes
+	}
+
 	return &newConfiguration, nil
 }
+
+// hasGRPCInterceptor reports whether name appears in c.GRPCInterceptors.
+func (c *Configuration) hasGRPCInterceptor(name string) bool {
+	for _, interceptor := range c.GRPCInterceptors {
+		if interceptor == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"time"
+
+	"github.com/bluenviron/gortsplib/v5/pkg/description"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/pion/rtp"
+)
+
+// RTSPTrack is a transport-agnostic description of one track an RTSPClient
+// is publishing: enough for MediaPipeline to build a depacketizer (when
+// Format is non-nil) or, for a backend that hands over already-decoded
+// frames, the Codec to tag them with directly (Format nil).
+type RTSPTrack struct {
+	PT     uint8
+	Kind   string // "video" or "audio"
+	Codec  string // used verbatim when Format is nil
+	Media  *description.Media
+	Format format.Format
+}
+
+// OnPacketFunc receives one unit of media from an RTSPClient: either a
+// still-packetized RTP packet on a track whose Format is non-nil (for
+// MediaPipeline.ProcessRTP to depacketize and decode), or, for a track
+// with a nil Format, a complete already-decoded frame carried in
+// pkt.Payload that the pipeline should forward as-is. pts is the best
+// presentation timestamp the implementation could derive, zero if none.
+type OnPacketFunc func(track RTSPTrack, pkt *rtp.Packet, pts time.Duration)
+
+// RTSPClient is the transport abstraction rtspTrigger drives: connect to a
+// source, learn what it's publishing, prepare and start playback. The
+// depacketize -> decode -> JPEG-encode -> webhook pipeline built on top of
+// it (MediaPipeline, DataProcessorStream, the webhook dispatch in
+// trigger.go) is unaware of which implementation is in use, so a camera
+// with a quirky RTSP dialect (TCP-only interleaved, non-standard SDP,
+// missing in-band SPS/PPS) can be worked around by selecting a different
+// Configuration.Backend instead of touching that pipeline. Mirrors the
+// split kerberos.io uses between its Golibrtsp and Joy4 capture backends.
+type RTSPClient interface {
+	// Connect opens the session against url (DESCRIBE, in RTSP terms).
+	Connect(url string) error
+
+	// Describe returns every track the connected source is publishing.
+	Describe() ([]RTSPTrack, error)
+
+	// Setup prepares tracks for playback (RTSP SETUP, or the
+	// implementation's transport-specific equivalent).
+	Setup(tracks []RTSPTrack) error
+
+	// Play starts playback (RTSP PLAY) and blocks, invoking onPacket for
+	// every unit of media received, until the session ends or Close is
+	// called.
+	Play(onPacket OnPacketFunc) error
+
+	// Close tears down the session; safe to call even if Connect was
+	// never called or failed.
+	Close() error
+}
+
+// newRTSPClient selects the RTSPClient implementation named by
+// configuration.Backend, defaulting to the gortsplib-backed one.
+func newRTSPClient(configuration *Configuration) RTSPClient {
+	switch configuration.Backend {
+	case "ffmpeg":
+		return newFFmpegClient(configuration)
+	default:
+		return newGortsplibClient(configuration)
+	}
+}
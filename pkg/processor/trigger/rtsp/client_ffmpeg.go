@@ -0,0 +1,157 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"bufio"
+	"os/exec"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/pion/rtp"
+)
+
+const ffmpegReadBufferSize = 64 * 1024
+
+// ffmpegClient is an alternative RTSPClient implementation for sources
+// whose RTSP dialect gortsplib struggles with (TCP-only interleaved
+// quirks, non-standard SDP, missing in-band SPS/PPS): it shells out to
+// ffmpeg, which already knows how to negotiate around those, and re-muxes
+// the video track to raw MJPEG frames on its stdout the same way
+// capture/ffmpeg.Client does for the plain mjpeg trigger. It only exposes
+// a single synthetic video track with a nil Format, so MediaPipeline's
+// depacketize/H264-decode path is skipped entirely: frames arrive already
+// decoded.
+type ffmpegClient struct {
+	configuration *Configuration
+
+	cmd    *exec.Cmd
+	reader *bufio.Reader
+	start  time.Time
+	done   chan struct{}
+}
+
+func newFFmpegClient(configuration *Configuration) *ffmpegClient {
+	return &ffmpegClient{configuration: configuration}
+}
+
+func (f *ffmpegClient) Connect(url string) error {
+	cmd := exec.Command("ffmpeg",
+		"-rtsp_transport", f.configuration.Transport,
+		"-i", url,
+		"-an",
+		"-c:v", "mjpeg",
+		"-f", "mjpeg",
+		"pipe:1")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "Failed to open ffmpeg stdout pipe")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "Failed to start ffmpeg")
+	}
+
+	f.cmd = cmd
+	f.reader = bufio.NewReaderSize(stdout, ffmpegReadBufferSize)
+	f.start = time.Now()
+	f.done = make(chan struct{})
+
+	return nil
+}
+
+// Describe returns the single synthetic video track ffmpeg's mjpeg muxer
+// produces; ffmpeg demuxes/decodes the source itself so there's nothing
+// else to advertise.
+func (f *ffmpegClient) Describe() ([]RTSPTrack, error) {
+	return []RTSPTrack{{Kind: "video", Codec: "mjpeg"}}, nil
+}
+
+// Setup is a no-op: ffmpeg already negotiated and started pulling the
+// source's tracks during Connect.
+func (f *ffmpegClient) Setup(tracks []RTSPTrack) error {
+	return nil
+}
+
+func (f *ffmpegClient) Play(onPacket OnPacketFunc) error {
+	if f.reader == nil {
+		return errors.New("ffmpeg client: not connected")
+	}
+
+	track := RTSPTrack{Kind: "video", Codec: "mjpeg"}
+
+	for {
+		select {
+		case <-f.done:
+			return nil
+		default:
+		}
+
+		data, err := readJPEGFrame(f.reader)
+		if err != nil {
+			return errors.Wrap(err, "Failed to read frame from ffmpeg")
+		}
+
+		onPacket(track, &rtp.Packet{Payload: data}, time.Since(f.start))
+	}
+}
+
+func (f *ffmpegClient) Close() error {
+	if f.done != nil {
+		select {
+		case <-f.done:
+		default:
+			close(f.done)
+		}
+	}
+
+	if f.cmd == nil || f.cmd.Process == nil {
+		return nil
+	}
+	_ = f.cmd.Process.Kill()
+	_ = f.cmd.Wait()
+	return nil
+}
+
+// readJPEGFrame scans r for one JPEG image delimited by the SOI (0xFFD8)
+// and EOI (0xFFD9) markers ffmpeg's raw mjpeg muxer emits back-to-back,
+// with no multipart-style framing of its own (mirrors capture/ffmpeg's
+// identically named helper).
+func readJPEGFrame(r *bufio.Reader) ([]byte, error) {
+	if err := skipToSOI(r); err != nil {
+		return nil, err
+	}
+
+	frame := []byte{0xff, 0xd8}
+	prev := byte(0)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		frame = append(frame, b)
+		if prev == 0xff && b == 0xd9 {
+			return frame, nil
+		}
+		prev = b
+	}
+}
+
+func skipToSOI(r *bufio.Reader) error {
+	prev := byte(0)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if prev == 0xff && b == 0xd8 {
+			return nil
+		}
+		prev = b
+	}
+}
@@ -0,0 +1,151 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"crypto/tls"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bluenviron/gortsplib/v5"
+	"github.com/bluenviron/gortsplib/v5/pkg/base"
+	"github.com/bluenviron/gortsplib/v5/pkg/description"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/nuclio/errors"
+	"github.com/pion/rtp"
+)
+
+// gortsplibClient is the default RTSPClient implementation, built on
+// github.com/bluenviron/gortsplib. It performs standard DESCRIBE/SETUP/PLAY
+// negotiation over TCP or UDP and relies on in-band/SDP SPS-PPS discovery;
+// cameras whose RTSP dialect trips that up should select Configuration.Backend
+// "ffmpeg" instead.
+type gortsplibClient struct {
+	configuration *Configuration
+
+	lock   sync.Mutex
+	client *gortsplib.Client
+	desc   *description.Session
+}
+
+func newGortsplibClient(configuration *Configuration) *gortsplibClient {
+	return &gortsplibClient{configuration: configuration}
+}
+
+func (g *gortsplibClient) Connect(rawURL string) error {
+	u, err := base.ParseURL(rawURL)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse RTSP URL")
+	}
+
+	if g.configuration.Username != "" {
+		u.User = url.UserPassword(g.configuration.Username, g.configuration.Password)
+	}
+
+	transport := gortsplib.TransportTCP
+	if g.configuration.Transport == "udp" {
+		transport = gortsplib.TransportUDP
+	}
+
+	client := &gortsplib.Client{Transport: &transport}
+	if u.Scheme == "rtsps" {
+		client.TLSConfig = &tls.Config{InsecureSkipVerify: g.configuration.InsecureSkipVerify} //nolint:gosec
+	}
+
+	if err := client.Start(u.Scheme, u.Host); err != nil {
+		return errors.Wrap(err, "Failed to connect to RTSP server")
+	}
+
+	desc, _, err := client.Describe(u)
+	if err != nil {
+		client.Close()
+		return errors.Wrap(err, "Failed to DESCRIBE RTSP stream")
+	}
+
+	g.lock.Lock()
+	g.client = client
+	g.desc = desc
+	g.lock.Unlock()
+
+	return nil
+}
+
+func (g *gortsplibClient) Describe() ([]RTSPTrack, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.desc == nil {
+		return nil, errors.New("gortsplib client: not connected")
+	}
+
+	var tracks []RTSPTrack
+	for _, media := range g.desc.Medias {
+		kind := trackKind(media)
+		for _, forma := range media.Formats {
+			tracks = append(tracks, RTSPTrack{
+				PT:     forma.PayloadType(),
+				Kind:   kind,
+				Media:  media,
+				Format: forma,
+			})
+		}
+	}
+
+	return tracks, nil
+}
+
+func (g *gortsplibClient) Setup(tracks []RTSPTrack) error {
+	g.lock.Lock()
+	client, desc := g.client, g.desc
+	g.lock.Unlock()
+
+	if client == nil || desc == nil {
+		return errors.New("gortsplib client: not connected")
+	}
+
+	return client.SetupAll(desc.BaseURL, desc.Medias)
+}
+
+func (g *gortsplibClient) Play(onPacket OnPacketFunc) error {
+	g.lock.Lock()
+	client := g.client
+	g.lock.Unlock()
+
+	if client == nil {
+		return errors.New("gortsplib client: not connected")
+	}
+
+	client.OnPacketRTPAny(func(medi *description.Media, forma format.Format, pkt *rtp.Packet) {
+		var pts time.Duration
+		if p, ok := client.PacketPTS(medi, pkt); ok {
+			pts = p
+		}
+		onPacket(RTSPTrack{
+			PT:     forma.PayloadType(),
+			Kind:   trackKind(medi),
+			Media:  medi,
+			Format: forma,
+		}, pkt, pts)
+	})
+
+	if _, err := client.Play(nil); err != nil {
+		return errors.Wrap(err, "Failed to PLAY RTSP stream")
+	}
+
+	return client.Wait()
+}
+
+func (g *gortsplibClient) Close() error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.client != nil {
+		g.client.Close()
+	}
+	return nil
+}
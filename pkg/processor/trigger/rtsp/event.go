@@ -0,0 +1,161 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nuclio/nuclio-sdk-go"
+)
+
+// Event wraps a single decoded media snapshot (a JPEG video frame or a PCM
+// audio chunk) demuxed from an RTSP track.
+type Event struct {
+	nuclio.AbstractEvent
+	body       []byte
+	attributes map[string]interface{}
+	timestamp  time.Time
+
+	// frameNum is the per-track sequence number of this snapshot (mirrors
+	// mjpeg.Event.frameNum), so handlers written against the mjpeg trigger's
+	// "frameNum"/"url" fields work unchanged against a video track here.
+	frameNum int64
+}
+
+// GetBody returns the decoded frame/chunk data
+func (e *Event) GetBody() []byte {
+	return e.body
+}
+
+// GetBodyString returns the frame/chunk data as a string (not recommended
+// for binary data)
+func (e *Event) GetBodyString() string {
+	return string(e.body)
+}
+
+// GetBodyObject returns nil since RTSP events don't have a body object
+func (e *Event) GetBodyObject() interface{} {
+	return nil
+}
+
+// GetContentType returns a MIME type derived from the depacketized codec:
+// "image/jpeg" for MJPEG/re-encoded H264 frames, "video/x-vp8"/"video/x-vp9"
+// for passthrough VP8/VP9 frames, and "application/octet-stream" for
+// everything else (audio, and any other unrecognized codec).
+func (e *Event) GetContentType() string {
+	switch codec, _ := e.attributes["codec"].(string); codec {
+	case "mjpeg":
+		return "image/jpeg"
+	case "vp8":
+		return "video/x-vp8"
+	case "vp9":
+		return "video/x-vp9"
+	case "combined+json":
+		return "application/json"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// GetMethod returns "rtsp" as the method
+func (e *Event) GetMethod() string {
+	return "rtsp"
+}
+
+// GetURL returns the RTSP source URL (mirrors mjpeg.Event.GetURL)
+func (e *Event) GetURL() string {
+	url, _ := e.attributes["url"].(string)
+	return url
+}
+
+// GetPath returns empty path (not applicable for RTSP)
+func (e *Event) GetPath() string {
+	return ""
+}
+
+// GetHeader returns an attribute value as an interface{}
+func (e *Event) GetHeader(key string) interface{} {
+	if e.attributes == nil {
+		return nil
+	}
+	return e.attributes[key]
+}
+
+// GetHeaders returns all attributes as headers
+func (e *Event) GetHeaders() map[string]interface{} {
+	return e.attributes
+}
+
+// GetHeaderByteSlice returns an attribute value as a byte slice
+func (e *Event) GetHeaderByteSlice(key string) []byte {
+	if val, ok := e.attributes[key].(string); ok {
+		return []byte(val)
+	}
+	return nil
+}
+
+// GetHeaderString returns an attribute value as a string
+func (e *Event) GetHeaderString(key string) string {
+	return string(e.GetHeaderByteSlice(key))
+}
+
+// GetHeaderInt returns an attribute value as an int
+func (e *Event) GetHeaderInt(key string) (int, error) {
+	switch typedValue := e.GetHeader(key).(type) {
+	case int:
+		return typedValue, nil
+	case int64:
+		return int(typedValue), nil
+	case float64:
+		return int(typedValue), nil
+	case string:
+		return strconv.Atoi(typedValue)
+	default:
+		return 0, nil
+	}
+}
+
+// GetFields returns all attributes, plus "frameNum" (mirrors mjpeg.Event.GetFields)
+func (e *Event) GetFields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(e.attributes)+1)
+	for k, v := range e.attributes {
+		fields[k] = v
+	}
+	fields["frameNum"] = e.frameNum
+	return fields
+}
+
+// GetField returns an attribute (or "frameNum") by key
+func (e *Event) GetField(key string) interface{} {
+	return e.GetFields()[key]
+}
+
+// GetFieldByteSlice returns an attribute value as a byte slice
+func (e *Event) GetFieldByteSlice(key string) []byte {
+	return e.GetHeaderByteSlice(key)
+}
+
+// GetFieldString returns an attribute value as a string
+func (e *Event) GetFieldString(key string) string {
+	return e.GetHeaderString(key)
+}
+
+// GetFieldInt returns an attribute value as an int
+func (e *Event) GetFieldInt(key string) (int, error) {
+	return e.GetHeaderInt(key)
+}
+
+// GetTimestamp returns the event timestamp
+func (e *Event) GetTimestamp() time.Time {
+	return e.timestamp
+}
+
+// GetSize returns the size of the frame/chunk data
+func (e *Event) GetSize() int {
+	return len(e.body)
+}
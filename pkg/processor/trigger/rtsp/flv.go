@@ -0,0 +1,264 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FLV tag types, per the FLV file format spec section 1.
+const (
+	flvTagTypeAudio = 8
+	flvTagTypeVideo = 9
+)
+
+// flvClient is one connected HTTP-FLV player: tags are pushed onto ch by
+// flvStream.Push and written out by serveLive's own goroutine, so a slow
+// reader can't stall the RTP receive loop.
+type flvClient struct {
+	ch chan []byte
+}
+
+// flvStream serves the ingested H264 video track as HTTP-FLV: one
+// AVCDecoderConfigurationRecord tag handed to every client as it connects,
+// followed by a live feed of AVCC NALU tags broadcast to all of them. Audio
+// isn't muxed in: an AAC seq-header tag needs an AudioSpecificConfig, and
+// nothing in this package currently surfaces one for the MPEG4Audio track
+// (its depacketizer hands back raw AAC access units with no ASC alongside
+// them) - scoped out here the same way chunk9-2's HLS segmenter scoped out
+// H264-video-only.
+type flvStream struct {
+	streamKey string
+
+	lock         sync.Mutex
+	sps, pps     []byte
+	seqHeaderTag []byte
+	baseTS       time.Duration
+	haveBaseTS   bool
+	clients      map[*flvClient]struct{}
+
+	server *http.Server
+}
+
+func newFLVStream(streamKey string) *flvStream {
+	return &flvStream{
+		streamKey: streamKey,
+		clients:   make(map[*flvClient]struct{}),
+	}
+}
+
+// Start spins up the HTTP server serving /live/<streamKey>.flv and /streams;
+// failures are surfaced to connecting clients as connection resets rather
+// than here, matching hlsSegmenter.Start's fire-and-forget ListenAndServe.
+func (f *flvStream) Start(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/live/", f.serveLive)
+	mux.HandleFunc("/streams", f.serveStreams)
+
+	f.server = &http.Server{Addr: addr, Handler: mux}
+	go f.server.ListenAndServe() //nolint:errcheck
+}
+
+func (f *flvStream) Stop() {
+	if f.server != nil {
+		f.server.Close()
+	}
+}
+
+// Reset clears learned SPS/PPS and the per-connection base timestamp across
+// an RTSP reconnect, mirroring hlsSegmenter.Reset; connected HTTP clients
+// are left alone, they just stop receiving tags until the new connection's
+// first keyframe rebuilds seqHeaderTag.
+func (f *flvStream) Reset() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.sps, f.pps, f.seqHeaderTag = nil, nil, nil
+	f.haveBaseTS = false
+}
+
+// Push learns SPS/PPS from the access unit's parameter-set NALUs, repacks
+// the rest into an AVCC sample via avccSample, and broadcasts it as an FLV
+// video tag to every connected client. Frames arriving before the first
+// SPS+PPS pair (and hence before seqHeaderTag exists) are dropped, since a
+// client can't decode AVCC NALUs without the decoder config record first.
+func (f *flvStream) Push(nalus [][]byte, keyframe bool, pts time.Duration) {
+	f.lock.Lock()
+
+	for _, n := range nalus {
+		if len(n) == 0 {
+			continue
+		}
+		switch n[0] & 0x1F {
+		case 7:
+			f.sps = append([]byte(nil), n...)
+		case 8:
+			f.pps = append([]byte(nil), n...)
+		}
+	}
+	if f.seqHeaderTag == nil && len(f.sps) > 0 && len(f.pps) > 0 {
+		f.seqHeaderTag = flvTag(flvTagTypeVideo, 0, buildAVCSeqHeaderPayload(f.sps, f.pps))
+	}
+
+	if !f.haveBaseTS {
+		f.baseTS = pts
+		f.haveBaseTS = true
+	}
+
+	if f.seqHeaderTag == nil {
+		f.lock.Unlock()
+		return
+	}
+
+	sample := avccSample(nalus)
+	if len(sample) == 0 {
+		f.lock.Unlock()
+		return
+	}
+
+	ts := uint32((pts - f.baseTS).Milliseconds())
+	tag := flvTag(flvTagTypeVideo, ts, buildAVCNALUPayload(sample, keyframe))
+
+	clients := make([]*flvClient, 0, len(f.clients))
+	for c := range f.clients {
+		clients = append(clients, c)
+	}
+	f.lock.Unlock()
+
+	for _, c := range clients {
+		select {
+		case c.ch <- tag:
+		default: // slow client: drop this tag rather than block the caller
+		}
+	}
+}
+
+// serveLive streams the FLV header, the current AVCDecoderConfigurationRecord
+// tag (if one has been built yet), and then every subsequently pushed video
+// tag to one HTTP client until it disconnects.
+func (f *flvStream) serveLive(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/live/"), ".flv")
+	if key != f.streamKey {
+		http.NotFound(w, r)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := &flvClient{ch: make(chan []byte, 64)}
+
+	f.lock.Lock()
+	seqHeaderTag := f.seqHeaderTag
+	f.clients[client] = struct{}{}
+	f.lock.Unlock()
+
+	defer func() {
+		f.lock.Lock()
+		delete(f.clients, client)
+		f.lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "video/x-flv")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(flvHeader(true, false)); err != nil {
+		return
+	}
+	if seqHeaderTag != nil {
+		if _, err := w.Write(seqHeaderTag); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tag := <-client.ch:
+			if _, err := w.Write(tag); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// serveStreams lists this trigger's single stream in the style of livego's
+// /streams endpoint: key, whether a publisher (the RTSP source) is feeding
+// it, and how many players are currently connected.
+func (f *flvStream) serveStreams(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	viewers := len(f.clients)
+	publishing := f.seqHeaderTag != nil
+	f.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode([]map[string]any{ //nolint:errcheck
+		{"key": f.streamKey, "publisher": publishing, "viewers": viewers},
+	})
+}
+
+// flvHeader builds the 13-byte FLV file header: signature+version, flags
+// for which media types follow, header size, and the PreviousTagSize0 field
+// that precedes the first tag.
+func flvHeader(hasVideo, hasAudio bool) []byte {
+	var flags byte
+	if hasAudio {
+		flags |= 0x04
+	}
+	if hasVideo {
+		flags |= 0x01
+	}
+
+	header := []byte{'F', 'L', 'V', 0x01, flags}
+	header = append(header, be32(9)...) // DataOffset: header size
+	header = append(header, be32(0)...) // PreviousTagSize0
+	return header
+}
+
+// flvTag builds one FLV tag (11-byte tag header, payload, trailing 4-byte
+// PreviousTagSize covering this tag) per the FLV file format spec section 1.
+func flvTag(tagType byte, timestampMs uint32, payload []byte) []byte {
+	dataSize := len(payload)
+
+	body := make([]byte, 0, 11+dataSize)
+	body = append(body, tagType)
+	body = append(body, byte(dataSize>>16), byte(dataSize>>8), byte(dataSize))
+	body = append(body, byte(timestampMs>>16), byte(timestampMs>>8), byte(timestampMs), byte(timestampMs>>24))
+	body = append(body, 0, 0, 0) // StreamID, always 0
+	body = append(body, payload...)
+
+	return append(body, be32(uint32(len(body)))...)
+}
+
+// buildAVCSeqHeaderPayload builds an AVCVIDEOPACKET's AVC sequence header
+// (FrameType=key, CodecID=AVC, AVCPacketType=0, CompositionTime=0) wrapping
+// the raw AVCDecoderConfigurationRecord built from sps/pps.
+func buildAVCSeqHeaderPayload(sps, pps []byte) []byte {
+	return append([]byte{0x17, 0x00, 0x00, 0x00, 0x00}, avcDecoderConfigRecord(sps, pps)...)
+}
+
+// buildAVCNALUPayload builds an AVCVIDEOPACKET carrying one AVCC-formatted
+// access unit (AVCPacketType=1, CompositionTime=0; this muxer never reorders
+// frames, so there's no B-frame offset to encode).
+func buildAVCNALUPayload(sample []byte, keyframe bool) []byte {
+	frameType := byte(0x27) // inter frame, AVC
+	if keyframe {
+		frameType = 0x17 // key frame, AVC
+	}
+	header := []byte{frameType, 0x01, 0x00, 0x00, 0x00}
+	return append(header, sample...)
+}
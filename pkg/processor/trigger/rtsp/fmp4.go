@@ -0,0 +1,301 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import "encoding/binary"
+
+// fmp4Timescale is the track timescale (ticks per second) used for every
+// fMP4 box the HLS segmenter builds; RTP PTS values are converted to it.
+const fmp4Timescale = 90000
+
+// Sample flag values stored in trun, per ISO/IEC 14496-12 8.8.3: a keyframe
+// doesn't depend on another sample (sample_depends_on=2), a non-keyframe
+// does and is also flagged as a non-sync sample.
+const (
+	keyframeSampleFlags    = 0x02000000
+	nonKeyframeSampleFlags = 0x01010000
+)
+
+// fmp4Sample is one access unit queued for muxing into a fragment's
+// moof/mdat, already repacked into AVCC (length-prefixed NALU) form.
+type fmp4Sample struct {
+	data     []byte
+	keyframe bool
+	ptsTicks uint64
+	duration uint32 // in fmp4Timescale ticks; filled in once the segment closes
+}
+
+func be16(v uint16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return b
+}
+
+func be32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func be64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// box wraps payload in an ISO BMFF box with the given four-character type.
+func box(boxType string, payload ...[]byte) []byte {
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	out := make([]byte, 0, size)
+	out = append(out, be32(uint32(size))...)
+	out = append(out, boxType...)
+	for _, p := range payload {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// fullBox is box with the version+flags header ISO BMFF "full boxes" add.
+func fullBox(boxType string, version byte, flags uint32, payload ...[]byte) []byte {
+	header := []byte{version, byte(flags >> 16), byte(flags >> 8), byte(flags)}
+	return box(boxType, append([][]byte{header}, payload...)...)
+}
+
+func identityMatrix() []byte {
+	var out []byte
+	for _, v := range [9]uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000} {
+		out = append(out, be32(v)...)
+	}
+	return out
+}
+
+func buildFtyp() []byte {
+	return box("ftyp", []byte("isom"), be32(512), []byte("isomiso5dash"))
+}
+
+func buildMvhd(timescale, nextTrackID uint32) []byte {
+	return fullBox("mvhd", 0, 0,
+		be32(0), be32(0), // creation/modification time
+		be32(timescale),
+		be32(0),          // duration: unknown, the movie is fragmented
+		be32(0x00010000), // rate 1.0
+		be16(0x0100),     // volume 1.0
+		be16(0),          // reserved
+		be32(0), be32(0), // reserved
+		identityMatrix(),
+		make([]byte, 24), // pre_defined
+		be32(nextTrackID),
+	)
+}
+
+func buildTkhd(trackID uint32, width, height int) []byte {
+	return fullBox("tkhd", 0, 0x000007, // enabled, in movie, in preview
+		be32(0), be32(0), // creation/modification time
+		be32(trackID),
+		be32(0),         // reserved
+		be32(0),         // duration
+		make([]byte, 8), // reserved
+		be16(0),         // layer
+		be16(0),         // alternate_group
+		be16(0),         // volume: 0 for video tracks
+		be16(0),         // reserved
+		identityMatrix(),
+		be32(uint32(width)<<16),
+		be32(uint32(height)<<16),
+	)
+}
+
+func buildMdhd(timescale uint32) []byte {
+	return fullBox("mdhd", 0, 0,
+		be32(0), be32(0), // creation/modification time
+		be32(timescale),
+		be32(0),      // duration
+		be16(0x55c4), // language "und"
+		be16(0),
+	)
+}
+
+func buildHdlr() []byte {
+	return fullBox("hdlr", 0, 0,
+		be32(0),
+		[]byte("vide"),
+		make([]byte, 12), // reserved
+		[]byte("VideoHandler\x00"),
+	)
+}
+
+func buildVmhd() []byte {
+	return fullBox("vmhd", 0, 1, be16(0), be16(0), be16(0), be16(0))
+}
+
+func buildDinf() []byte {
+	selfContainedURL := fullBox("url ", 0, 1) // flags=1: media data is in this file
+	dref := fullBox("dref", 0, 0, be32(1), selfContainedURL)
+	return box("dinf", dref)
+}
+
+// avcDecoderConfigRecord builds the raw AVCDecoderConfigurationRecord bytes
+// (ISO/IEC 14496-15 5.2.4.1) from a single SPS/PPS, with 4-byte NALU length
+// prefixes (matching the AVCC encoding avccSample produces). Shared by
+// buildAvcC (which boxes it for the fMP4 avc1 sample entry) and the FLV
+// muxer's AVCDecoderConfigurationRecord tag, which wants these bytes
+// unboxed.
+func avcDecoderConfigRecord(sps, pps []byte) []byte {
+	profile, compat, level := byte(0), byte(0), byte(0)
+	if len(sps) >= 4 {
+		profile, compat, level = sps[1], sps[2], sps[3]
+	}
+
+	record := []byte{
+		1, // configurationVersion
+		profile, compat, level,
+		0xFF, // reserved(6)=1 | lengthSizeMinusOne=3 (4-byte lengths)
+		0xE1, // reserved(3)=1 | numOfSequenceParameterSets=1
+	}
+	record = append(record, be16(uint16(len(sps)))...)
+	record = append(record, sps...)
+	record = append(record, 1) // numOfPictureParameterSets
+	record = append(record, be16(uint16(len(pps)))...)
+	record = append(record, pps...)
+
+	return record
+}
+
+// buildAvcC builds the avcC box the avc1 sample entry carries, wrapping
+// avcDecoderConfigRecord.
+func buildAvcC(sps, pps []byte) []byte {
+	return box("avcC", avcDecoderConfigRecord(sps, pps))
+}
+
+func buildAvc1(width, height int, avcC []byte) []byte {
+	return box("avc1",
+		make([]byte, 6),  // reserved
+		be16(1),          // data_reference_index
+		be16(0), be16(0), // pre_defined, reserved
+		make([]byte, 12), // pre_defined
+		be16(uint16(width)),
+		be16(uint16(height)),
+		be32(0x00480000), // horizresolution: 72 dpi
+		be32(0x00480000), // vertresolution: 72 dpi
+		be32(0),          // reserved
+		be16(1),          // frame_count
+		make([]byte, 32), // compressorname
+		be16(0x0018),     // depth: 24
+		be16(0xFFFF),     // pre_defined
+		avcC,
+	)
+}
+
+func buildStsd(width, height int, avcC []byte) []byte {
+	return fullBox("stsd", 0, 0, be32(1), buildAvc1(width, height, avcC))
+}
+
+// The sample tables below stay empty: with a fragmented movie, samples are
+// described per-fragment in moof/traf instead of here.
+func buildStts() []byte { return fullBox("stts", 0, 0, be32(0)) }
+func buildStsc() []byte { return fullBox("stsc", 0, 0, be32(0)) }
+func buildStsz() []byte { return fullBox("stsz", 0, 0, be32(0), be32(0)) }
+func buildStco() []byte { return fullBox("stco", 0, 0, be32(0)) }
+
+func buildStbl(width, height int, avcC []byte) []byte {
+	return box("stbl", buildStsd(width, height, avcC), buildStts(), buildStsc(), buildStsz(), buildStco())
+}
+
+func buildMinf(width, height int, avcC []byte) []byte {
+	return box("minf", buildVmhd(), buildDinf(), buildStbl(width, height, avcC))
+}
+
+func buildMdia(timescale uint32, width, height int, avcC []byte) []byte {
+	return box("mdia", buildMdhd(timescale), buildHdlr(), buildMinf(width, height, avcC))
+}
+
+func buildTrak(trackID, timescale uint32, width, height int, avcC []byte) []byte {
+	return box("trak", buildTkhd(trackID, width, height), buildMdia(timescale, width, height, avcC))
+}
+
+func buildMvex(trackID uint32) []byte {
+	trex := fullBox("trex", 0, 0,
+		be32(trackID),
+		be32(1), // default_sample_description_index
+		be32(0), // default_sample_duration
+		be32(0), // default_sample_size
+		be32(0), // default_sample_flags
+	)
+	return box("mvex", trex)
+}
+
+// buildMoov builds the init segment's moov box: one video track, declared
+// fragmented via mvex/trex, with avcC (built from the stream's SPS/PPS) as
+// its only sample description.
+func buildMoov(trackID, timescale uint32, width, height int, avcC []byte) []byte {
+	return box("moov",
+		buildMvhd(timescale, trackID+1),
+		buildTrak(trackID, timescale, width, height, avcC),
+		buildMvex(trackID),
+	)
+}
+
+// buildMoof builds one fragment's moof box for the given samples, all
+// belonging to trackID, with baseDecodeTime (in fmp4Timescale ticks) as the
+// first sample's decode time. The trun box's data_offset is patched in
+// after the box is built, once the moof's total size (and hence the mdat
+// payload's start, right after mdat's own 8-byte header) is known.
+func buildMoof(sequenceNumber, trackID uint32, baseDecodeTime uint64, samples []fmp4Sample) []byte {
+	mfhd := fullBox("mfhd", 0, 0, be32(sequenceNumber))
+	tfhd := fullBox("tfhd", 0, 0x020000, be32(trackID)) // default-base-is-moof
+	tfdt := fullBox("tfdt", 1, 0, be64(baseDecodeTime))
+
+	const trunFlags = 0x000001 | 0x000100 | 0x000200 | 0x000400 // data-offset, duration, size, flags present
+	trunPayload := [][]byte{be32(uint32(len(samples))), be32(0)}
+	for _, s := range samples {
+		flags := uint32(nonKeyframeSampleFlags)
+		if s.keyframe {
+			flags = keyframeSampleFlags
+		}
+		trunPayload = append(trunPayload, be32(s.duration), be32(uint32(len(s.data))), be32(flags))
+	}
+	trun := fullBox("trun", 0, trunFlags, trunPayload...)
+
+	traf := box("traf", tfhd, tfdt, trun)
+	moof := box("moof", mfhd, traf)
+
+	dataOffset := uint32(len(moof) + 8) // samples start right after mdat's header
+	dataOffsetPos := 8 + len(mfhd) + 8 + len(tfhd) + len(tfdt) + 12 + 4
+	binary.BigEndian.PutUint32(moof[dataOffsetPos:], dataOffset)
+
+	return moof
+}
+
+func buildMdat(samples []fmp4Sample) []byte {
+	var payload []byte
+	for _, s := range samples {
+		payload = append(payload, s.data...)
+	}
+	return box("mdat", payload)
+}
+
+// avccSample repacks an access unit (a slice of raw NALUs, as returned by
+// MediaPipeline.ProcessRTPRaw) into AVCC length-prefixed form, dropping
+// SPS/PPS/AUD NALUs since parameter sets are carried once in the init
+// segment's avcC instead of inline in every sample.
+func avccSample(nalus [][]byte) []byte {
+	var out []byte
+	for _, n := range nalus {
+		if len(n) == 0 {
+			continue
+		}
+		switch n[0] & 0x1F {
+		case 7, 8, 9: // SPS, PPS, access unit delimiter
+			continue
+		}
+		out = append(out, be32(uint32(len(n)))...)
+		out = append(out, n...)
+	}
+	return out
+}
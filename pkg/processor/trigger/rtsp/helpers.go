@@ -10,13 +10,12 @@ import (
 	"encoding/binary"
 	"image"
 	"image/jpeg"
-	"mime/multipart"
-	"net/http"
 	"sync"
 	"time"
 
 	"github.com/bluenviron/gortsplib/v5/pkg/description"
 	"github.com/bluenviron/gortsplib/v5/pkg/format"
+	"github.com/nuclio/errors"
 	"github.com/pion/rtp"
 )
 
@@ -26,6 +25,10 @@ import (
 // ============================================================
 //
 
+// DataProcessorStream aggregates decoded track data for periodic emission:
+// in video mode the rolling buffer is replaced by each new decoded frame; in
+// audio mode incoming PCM is accumulated into a trimmed rolling buffer, the
+// same way mjpeg/websocket data processors do.
 type DataProcessorStream struct {
 	lock       sync.Mutex
 	chunkBytes int
@@ -134,52 +137,77 @@ func (dp *DataProcessorStream) Output() <-chan *Event {
 	return dp.output
 }
 
-type MediaPipeline struct {
-	trigger *rtspTrigger
+//
+// ============================================================
+// MEDIA PIPELINE
+// ============================================================
+//
 
-	depacketizers map[uint8]any
+// Frame is a single depacketized, codec-tagged unit of media emitted by
+// MediaPipeline.ProcessRTP, replacing a bare []byte so callers can tell
+// what's inside it without re-deriving that from the format.Format and
+// whether it's safe to start decoding a stream from.
+type Frame struct {
+	Data     []byte
+	Codec    string
+	Keyframe bool
+
+	// RawNALUs holds the depacketized H264 access unit for callers that
+	// bypass ProcessRTP's YUV-decode/JPEG-encode step (the HLS fMP4
+	// segmenter, via ProcessRTPRaw); nil on every other path.
+	RawNALUs [][]byte
+}
+
+// MediaPipeline demuxes RTP packets per track into depacketized access units
+// and, for H264/H265 video tracks, decodes and re-encodes them to JPEG so
+// downstream functions always receive a single still-image format regardless
+// of the camera's codec.
+type MediaPipeline struct {
+	depacketizers map[uint8]interface{}
+	codecs        map[uint8]string
 
 	h264Decoders map[uint8]*OpenH264Decoder
 	h264FirstIDR map[uint8]bool
-}
 
-func NewMediaPipeline(t *rtspTrigger, medias []*description.Media) (*MediaPipeline, error) {
+	vp8FirstKey map[uint8]bool
+	vp9FirstKey map[uint8]bool
+}
 
+// NewMediaPipeline builds depacketizers for every format advertised by the
+// session description. Supported formats: H264/H265 video (decoded and
+// re-encoded to JPEG), VP8/VP9/MJPEG passthrough, and LPCM/MPEG4Audio/
+// MPEG1Audio audio. Unrecognized formats fall back to raw RTP payload
+// passthrough.
+func NewMediaPipeline(medias []*description.Media) (*MediaPipeline, error) {
 	mp := &MediaPipeline{
-		trigger:       t,
-		depacketizers: make(map[uint8]any),
+		depacketizers: make(map[uint8]interface{}),
+		codecs:        make(map[uint8]string),
 		h264Decoders:  make(map[uint8]*OpenH264Decoder),
 		h264FirstIDR:  make(map[uint8]bool),
+		vp8FirstKey:   make(map[uint8]bool),
+		vp9FirstKey:   make(map[uint8]bool),
 	}
 
 	for _, media := range medias {
 		for _, forma := range media.Formats {
-
 			switch f := forma.(type) {
 
-			// audio
-			case *format.LPCM:
-				dec, err := f.CreateDecoder()
-				if err == nil {
-					mp.depacketizers[forma.PayloadType()] = dec
-				}
-
-			// video
+			// ---------- video ----------
 			case *format.H264:
 				dep, err := f.CreateDecoder()
 				if err != nil {
 					continue
 				}
 				mp.depacketizers[forma.PayloadType()] = dep
+				mp.codecs[forma.PayloadType()] = "h264"
 
-				op, err := NewOpenH264Decoder()
+				dec, err := NewOpenH264Decoder()
 				if err != nil {
 					return nil, err
 				}
-				mp.h264Decoders[forma.PayloadType()] = op
+				mp.h264Decoders[forma.PayloadType()] = dec
 
-				// feed SPS/PPS to decoder
-				initNALUs := [][]byte{}
+				var initNALUs [][]byte
 				if len(f.SPS) > 0 {
 					initNALUs = append(initNALUs, f.SPS)
 				}
@@ -187,19 +215,58 @@ func NewMediaPipeline(t *rtspTrigger, medias []*description.Media) (*MediaPipeli
 					initNALUs = append(initNALUs, f.PPS)
 				}
 				if len(initNALUs) > 0 {
-					op.Decode(initNALUs)
+					dec.Decode(initNALUs)
 				}
 
-				// t.dataProcessor.isVideo = true
-				t.Logger.Info("Video stream detected (H264)")
-
-			// ---------- H265 passthrough ----------
 			case *format.H265:
 				dep, err := f.CreateDecoder()
 				if err == nil {
 					mp.depacketizers[forma.PayloadType()] = dep
+					mp.codecs[forma.PayloadType()] = "h265"
+				}
+
+			case *format.VP8:
+				dep, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dep
+					mp.codecs[forma.PayloadType()] = "vp8"
+				}
+
+			case *format.VP9:
+				dep, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dep
+					mp.codecs[forma.PayloadType()] = "vp9"
+				}
+
+			case *format.MJPEG:
+				dep, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dep
+					mp.codecs[forma.PayloadType()] = "mjpeg"
+				}
+
+			// ---------- audio ----------
+			case *format.LPCM:
+				dec, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dec
+					mp.codecs[forma.PayloadType()] = "lpcm"
+				}
+
+			case *format.MPEG4Audio:
+				dec, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dec
+					mp.codecs[forma.PayloadType()] = "mpeg4audio"
+				}
+
+			case *format.MPEG1Audio:
+				dec, err := f.CreateDecoder()
+				if err == nil {
+					mp.depacketizers[forma.PayloadType()] = dec
+					mp.codecs[forma.PayloadType()] = "mpeg1audio"
 				}
-				// t.dataProcessor.isVideo = true
 			}
 		}
 	}
@@ -207,65 +274,147 @@ func NewMediaPipeline(t *rtspTrigger, medias []*description.Media) (*MediaPipeli
 	return mp, nil
 }
 
-func (mp *MediaPipeline) ProcessRTP(pkt *rtp.Packet, forma format.Format) ([]byte, error) {
+// ProcessRTP depacketizes a single RTP packet into a codec-tagged Frame ready
+// for emission: a JPEG-encoded frame for H264 video (once the decoder has
+// synced to the first IDR), the raw access unit for every other recognized
+// format, or the unmodified RTP payload when the format has no registered
+// depacketizer. A nil Frame.Data (with a nil error) means the packet didn't
+// complete a unit and should be dropped rather than emitted.
+func (mp *MediaPipeline) ProcessRTP(pkt *rtp.Packet, forma format.Format) (Frame, error) {
+	pt := forma.PayloadType()
 
-	dep, ok := mp.depacketizers[forma.PayloadType()]
+	dep, ok := mp.depacketizers[pt]
 	if !ok {
-		return pkt.Payload, nil
+		return Frame{Data: pkt.Payload, Codec: "raw", Keyframe: true}, nil
 	}
 
-	if dec, ok := mp.h264Decoders[forma.PayloadType()]; ok {
+	codec := mp.codecs[pt]
 
-		type h264Dep interface {
+	if dec, ok := mp.h264Decoders[pt]; ok {
+		type auDecoder interface {
 			Decode(*rtp.Packet) ([][]byte, error)
 		}
 
-		if hdec, ok := dep.(h264Dep); ok {
-
-			au, err := hdec.Decode(pkt)
-			if err != nil || au == nil {
-				return nil, err
-			}
+		hdec, ok := dep.(auDecoder)
+		if !ok {
+			return Frame{Data: pkt.Payload, Codec: codec, Keyframe: true}, nil
+		}
 
-			pt := forma.PayloadType()
+		au, err := hdec.Decode(pkt)
+		if err != nil || au == nil {
+			return Frame{}, err
+		}
 
-			// WAIT FIRST IDR
-			if !mp.h264FirstIDR[pt] {
-				if !containsIDR(au) {
-					return nil, nil
-				}
-				mp.h264FirstIDR[pt] = true
+		isIDR := containsIDR(au)
+		if !mp.h264FirstIDR[pt] {
+			if !isIDR {
+				return Frame{}, nil
 			}
+			mp.h264FirstIDR[pt] = true
+		}
 
-			yuv, w, h, err := dec.Decode(au)
-			if err != nil || yuv == nil {
-				return nil, err
-			}
+		yuv, w, h, err := dec.Decode(au)
+		if err != nil || yuv == nil {
+			return Frame{}, err
+		}
 
-			return EncodeFrameToJPEG(yuv, w, h, 80)
+		jpegData, err := EncodeFrameToJPEG(yuv, w, h, 80)
+		if err != nil {
+			return Frame{}, err
 		}
+
+		// The access unit is re-encoded to a still JPEG image, so the emitted
+		// frame is tagged "mjpeg" rather than the source "h264" codec.
+		return Frame{Data: jpegData, Codec: "mjpeg", Keyframe: isIDR}, nil
 	}
 
-	// ===============================
-	// GENERIC / AUDIO
-	// ===============================
 	switch d := dep.(type) {
-
 	case interface {
 		Decode(*rtp.Packet) ([]byte, error)
 	}:
 		payload, err := d.Decode(pkt)
 		if err != nil || len(payload) == 0 {
-			return nil, err
+			return Frame{}, err
+		}
+		if _, ok := forma.(*format.LPCM); ok {
+			// go2rtp (and most RTSP cameras) stream LPCM in network (big-endian)
+			// byte order; downstream audio processing expects little-endian PCM.
+			payload = convertBigEndianToLittleEndian(payload)
+		}
+
+		// VP8/VP9 decoders hand back a complete compressed frame per call,
+		// already reassembled from however many RTP packets it spanned
+		// (picture ID/S-or-B/E-bit tracking is the depacketizer's job, not
+		// ours); what's still missing is per-codec keyframe detection and
+		// discarding until the first one, the same gate h264FirstIDR gives
+		// H264 above.
+		keyframe := true
+		switch codec {
+		case "vp8":
+			keyframe = isVP8Keyframe(payload)
+			if !mp.vp8FirstKey[pt] {
+				if !keyframe {
+					return Frame{}, nil
+				}
+				mp.vp8FirstKey[pt] = true
+			}
+		case "vp9":
+			keyframe = isVP9Keyframe(payload)
+			if !mp.vp9FirstKey[pt] {
+				if !keyframe {
+					return Frame{}, nil
+				}
+				mp.vp9FirstKey[pt] = true
+			}
+		}
+
+		return Frame{Data: payload, Codec: codec, Keyframe: keyframe}, nil
+
+	case interface {
+		Decode(*rtp.Packet) ([][]byte, error)
+	}:
+		aus, err := d.Decode(pkt)
+		if err != nil || len(aus) == 0 {
+			return Frame{}, err
 		}
 
-		// convert big endian PCM to little endian (common format for audio processing)
-		// go2rtp always streams in big endian
-		payload = convertBigEndianToLittleEndian(payload)
-		return payload, nil
+		keyframe := true
+		if codec == "h265" {
+			keyframe = isH265Keyframe(aus)
+		}
+		return Frame{Data: bytes.Join(aus, nil), Codec: codec, Keyframe: keyframe}, nil
+	}
+
+	return Frame{Data: pkt.Payload, Codec: codec, Keyframe: true}, nil
+}
+
+// ProcessRTPRaw depacketizes a single RTP packet into its raw H264 access
+// unit, skipping the YUV decode and JPEG re-encode ProcessRTP performs for
+// this codec: the HLS fMP4 segmenter calls this instead, since it muxes the
+// elementary stream straight into fMP4 samples and would otherwise pay for
+// a decode/re-encode cycle it doesn't need. A nil Frame.RawNALUs (with a
+// nil error) means the packet didn't complete an access unit.
+func (mp *MediaPipeline) ProcessRTPRaw(pkt *rtp.Packet, forma *format.H264) (Frame, error) {
+	pt := forma.PayloadType()
+
+	dep, ok := mp.depacketizers[pt]
+	if !ok {
+		return Frame{}, errors.New("ProcessRTPRaw: no depacketizer registered for this payload type")
+	}
+
+	hdec, ok := dep.(interface {
+		Decode(*rtp.Packet) ([][]byte, error)
+	})
+	if !ok {
+		return Frame{}, errors.New("ProcessRTPRaw: depacketizer does not produce access units")
+	}
+
+	au, err := hdec.Decode(pkt)
+	if err != nil || au == nil {
+		return Frame{}, err
 	}
 
-	return pkt.Payload, nil
+	return Frame{RawNALUs: au, Codec: "h264", Keyframe: containsIDR(au)}, nil
 }
 
 func containsIDR(nalus [][]byte) bool {
@@ -280,9 +429,54 @@ func containsIDR(nalus [][]byte) bool {
 	return false
 }
 
-// JPEG encoding helper (for video frames)
-func EncodeFrameToJPEG(yuv []byte, width, height int, quality int) ([]byte, error) {
+// isH265Keyframe reports whether nalus contains an H265 IRAP NAL unit
+// (types 16-23: BLA/IDR/CRA), the family of NAL types a decoder can safely
+// start a stream from.
+func isH265Keyframe(nalus [][]byte) bool {
+	for _, n := range nalus {
+		if len(n) == 0 {
+			continue
+		}
+		naluType := (n[0] >> 1) & 0x3F
+		if naluType >= 16 && naluType <= 23 {
+			return true
+		}
+	}
+	return false
+}
+
+// isVP8Keyframe reports whether a decoded VP8 frame's uncompressed payload
+// header (RFC 6386 section 9.1) marks it as a key frame: the P bit is the
+// low bit of the first byte, 0 for key frames.
+func isVP8Keyframe(frame []byte) bool {
+	return len(frame) > 0 && frame[0]&0x01 == 0
+}
+
+// isVP9Keyframe reports whether a decoded VP9 frame's uncompressed header
+// (the frame_marker/profile/show_existing_frame/frame_type fields at the
+// very start of the bitstream) marks it as a key frame. Profile 3's extra
+// reserved_zero bit is accounted for; SVC spatial-layer signaling beyond
+// that is not parsed.
+func isVP9Keyframe(frame []byte) bool {
+	if len(frame) == 0 {
+		return false
+	}
+
+	r := newBitReader(frame)
+	r.readBits(2) // frame_marker
+	profileLowBit := r.readBit()
+	profileHighBit := r.readBit()
+	if profileHighBit<<1|profileLowBit == 3 {
+		r.readBit() // reserved_zero
+	}
+	if r.readBit() == 1 { // show_existing_frame
+		return false
+	}
+	return r.readBit() == 0 // frame_type: 0 = KEY_FRAME
+}
 
+// EncodeFrameToJPEG encodes a YUV420 (I420) frame to JPEG bytes.
+func EncodeFrameToJPEG(yuv []byte, width, height int, quality int) ([]byte, error) {
 	img := image.NewYCbCr(
 		image.Rect(0, 0, width, height),
 		image.YCbCrSubsampleRatio420,
@@ -296,92 +490,12 @@ func EncodeFrameToJPEG(yuv []byte, width, height int, quality int) ([]byte, erro
 	copy(img.Cr, yuv[ySize+uvSize:])
 
 	var buf bytes.Buffer
-	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
-	if err != nil {
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
 
-// ============================================================
-// WEBHOOK
-// ============================================================
-func (t *rtspTrigger) postToWebhook(body []byte) {
-	if t.webhookURL == "" {
-		return
-	}
-
-	// Send webhook with multipart/form-data
-	// body contains the handler response; typically text or binary data
-	t.postToWebhookWithData("", body)
-}
-
-// postToWebhookWithData sends data to webhook using multipart/form-data
-// text and data fields are optional (can be empty/nil)
-func (t *rtspTrigger) postToWebhookWithData(text string, data []byte) {
-	if t.webhookURL == "" {
-		return
-	}
-
-	// Create multipart form
-	buf := &bytes.Buffer{}
-	writer := multipart.NewWriter(buf)
-
-	// Add text field if provided
-	if text != "" {
-		fw, err := writer.CreateFormField("text")
-		if err != nil {
-			t.Logger.WarnWith("Failed to create text form field", "err", err)
-			return
-		}
-		if _, err := fw.Write([]byte(text)); err != nil {
-			t.Logger.WarnWith("Failed to write text field", "err", err)
-			return
-		}
-	}
-
-	// Add data field if provided
-	if len(data) > 0 {
-		fw, err := writer.CreateFormFile("data", "frame.bin")
-		if err != nil {
-			t.Logger.WarnWith("Failed to create data form file", "err", err)
-			return
-		}
-		if _, err := fw.Write(data); err != nil {
-			t.Logger.WarnWith("Failed to write data file", "err", err)
-			return
-		}
-	}
-
-	if err := writer.Close(); err != nil {
-		t.Logger.WarnWith("Failed to close multipart writer", "err", err)
-		return
-	}
-
-	// Create and send request
-	req, err := http.NewRequest("POST", t.webhookURL, buf)
-	if err != nil {
-		t.Logger.WarnWith("Failed to create webhook request", "err", err)
-		return
-	}
-
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		t.Logger.WarnWith("Webhook POST failed", "err", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		t.Logger.WarnWith("Webhook returned non-success status", "status", resp.StatusCode)
-		return
-	}
-
-	t.Logger.DebugWith("Webhook POST succeeded", "status", resp.StatusCode, "text_len", len(text), "data_len", len(data))
-}
-
 func convertBigEndianToLittleEndian(in []byte) []byte {
 	out := make([]byte, len(in))
 	for i := 0; i+1 < len(in); i += 2 {
@@ -0,0 +1,245 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hlsFragment is one already-muxed fMP4 fragment (moof+mdat) held in the
+// segmenter's rolling playlist window.
+type hlsFragment struct {
+	sequence int
+	data     []byte
+	duration float64 // seconds, for the playlist's EXTINF
+}
+
+// hlsSegmenter builds and serves a live fMP4 HLS rendition of the RTSP
+// trigger's H264 video track directly from depacketized access units
+// (MediaPipeline.ProcessRTPRaw), instead of the per-frame YUV-decode/
+// JPEG-re-encode path ProcessRTP uses for the webhook output: consuming the
+// elementary stream as-is avoids paying that decode/encode cost on every
+// frame just to serve a live view of the camera.
+//
+// Scope: one H264 video rendition, fMP4/CMAF-ish segments good enough for
+// Safari/hls.js, no audio track, no discontinuity/low-latency signaling -
+// enough for a single IP camera feed, not a general-purpose packager.
+type hlsSegmenter struct {
+	segmentDuration time.Duration
+	playlistSize    int
+
+	lock        sync.Mutex
+	sps, pps    []byte
+	initSegment []byte
+
+	pendingSamples []fmp4Sample
+	segmentStart   time.Duration
+	havePTS        bool
+
+	fragments []hlsFragment
+	nextSeq   int
+	mediaSeq  int
+
+	server *http.Server
+}
+
+func newHLSSegmenter(segmentDuration time.Duration, playlistSize int) *hlsSegmenter {
+	return &hlsSegmenter{
+		segmentDuration: segmentDuration,
+		playlistSize:    playlistSize,
+	}
+}
+
+// Start launches the segmenter's HTTP server in the background; it serves
+// until Stop is called, surviving RTSP reconnects underneath it.
+func (h *hlsSegmenter) Start(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.m3u8", h.servePlaylist)
+	mux.HandleFunc("/init.mp4", h.serveInit)
+	mux.HandleFunc("/", h.serveSegment)
+
+	h.server = &http.Server{Addr: addr, Handler: mux}
+	go h.server.ListenAndServe() //nolint:errcheck
+}
+
+func (h *hlsSegmenter) Stop() {
+	if h.server != nil {
+		h.server.Close()
+	}
+}
+
+// Reset drops any in-progress segment and learned SPS/PPS/init segment, for
+// a fresh RTSP connection that may (re)send its own parameter sets.
+// Already-published fragments and sequence numbers are left alone so the
+// playlist doesn't jump backwards mid-session.
+func (h *hlsSegmenter) Reset() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.sps, h.pps, h.initSegment = nil, nil, nil
+	h.pendingSamples = nil
+	h.havePTS = false
+}
+
+// Push feeds one depacketized H264 access unit into the segmenter. It
+// learns SPS/PPS the first time it sees them and builds the init segment
+// from them, then accumulates samples until a keyframe arrives at least
+// segmentDuration after the current segment started, at which point the
+// segment is closed, muxed, and published to the playlist.
+func (h *hlsSegmenter) Push(nalus [][]byte, keyframe bool, pts time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, n := range nalus {
+		if len(n) == 0 {
+			continue
+		}
+		switch n[0] & 0x1F {
+		case 7:
+			h.sps = append([]byte(nil), n...)
+		case 8:
+			h.pps = append([]byte(nil), n...)
+		}
+	}
+
+	if h.initSegment == nil {
+		if len(h.sps) == 0 || len(h.pps) == 0 {
+			return // wait for parameter sets before emitting anything
+		}
+		width, height, err := parseSPSDimensions(h.sps)
+		if err != nil {
+			return
+		}
+		avcC := buildAvcC(h.sps, h.pps)
+		h.initSegment = append(buildFtyp(), buildMoov(1, fmp4Timescale, width, height, avcC)...)
+	}
+
+	sampleData := avccSample(nalus)
+	if len(sampleData) == 0 {
+		return
+	}
+
+	if !h.havePTS {
+		h.segmentStart = pts
+		h.havePTS = true
+	}
+
+	if keyframe && len(h.pendingSamples) > 0 && pts-h.segmentStart >= h.segmentDuration {
+		h.finalizeSegment()
+		h.segmentStart = pts
+	}
+
+	h.pendingSamples = append(h.pendingSamples, fmp4Sample{
+		data:     sampleData,
+		keyframe: keyframe,
+		ptsTicks: uint64(pts.Seconds() * fmp4Timescale),
+	})
+}
+
+// finalizeSegment derives each queued sample's duration from the gap to the
+// next sample's PTS (the last sample reuses the previous gap, or a whole
+// segmentDuration if it's the only sample), muxes the segment into one
+// moof+mdat fragment, and appends it to the rolling playlist window.
+func (h *hlsSegmenter) finalizeSegment() {
+	samples := h.pendingSamples
+	h.pendingSamples = nil
+	if len(samples) == 0 {
+		return
+	}
+
+	for i := range samples {
+		switch {
+		case i+1 < len(samples):
+			samples[i].duration = uint32(samples[i+1].ptsTicks - samples[i].ptsTicks)
+		case i > 0:
+			samples[i].duration = samples[i-1].duration
+		default:
+			samples[i].duration = uint32(h.segmentDuration.Seconds() * fmp4Timescale)
+		}
+	}
+
+	moof := buildMoof(uint32(h.nextSeq+1), 1, samples[0].ptsTicks, samples)
+	mdat := buildMdat(samples)
+
+	var durationSeconds float64
+	for _, s := range samples {
+		durationSeconds += float64(s.duration) / fmp4Timescale
+	}
+
+	h.fragments = append(h.fragments, hlsFragment{
+		sequence: h.nextSeq,
+		data:     append(moof, mdat...),
+		duration: durationSeconds,
+	})
+	h.nextSeq++
+
+	if len(h.fragments) > h.playlistSize {
+		drop := len(h.fragments) - h.playlistSize
+		h.fragments = h.fragments[drop:]
+		h.mediaSeq += drop
+	}
+}
+
+func (h *hlsSegmenter) serveInit(w http.ResponseWriter, r *http.Request) {
+	h.lock.Lock()
+	init := h.initSegment
+	h.lock.Unlock()
+
+	if init == nil {
+		http.Error(w, "HLS init segment not ready yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp4")
+	w.Write(init) //nolint:errcheck
+}
+
+func (h *hlsSegmenter) serveSegment(w http.ResponseWriter, r *http.Request) {
+	var seq int
+	if _, err := fmt.Sscanf(r.URL.Path, "/seg-%d.m4s", &seq); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, f := range h.fragments {
+		if f.sequence == seq {
+			w.Header().Set("Content-Type", "video/iso.segment")
+			w.Write(f.data) //nolint:errcheck
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (h *hlsSegmenter) servePlaylist(w http.ResponseWriter, r *http.Request) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	target := int(h.segmentDuration.Seconds())
+	if target < 1 {
+		target = 1
+	}
+
+	var b strings.Builder
+	fmt.Fprint(&b, "#EXTM3U\n")
+	fmt.Fprint(&b, "#EXT-X-VERSION:7\n")
+	fmt.Fprintf(&b, "#EXT-X-TARGETDURATION:%d\n", target)
+	fmt.Fprintf(&b, "#EXT-X-MEDIA-SEQUENCE:%d\n", h.mediaSeq)
+	fmt.Fprint(&b, "#EXT-X-MAP:URI=\"init.mp4\"\n")
+	for _, f := range h.fragments {
+		fmt.Fprintf(&b, "#EXTINF:%.3f,\n", f.duration)
+		fmt.Fprintf(&b, "seg-%d.m4s\n", f.sequence)
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Write([]byte(b.String())) //nolint:errcheck
+}
@@ -0,0 +1,133 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+)
+
+// hlsOutput is the Output.kind "hls" sink: unlike hlsSegmenter (which
+// republishes the raw ingested video track untouched, for Configuration.
+// HLSEnabled live view), this tees whatever the handler itself returned -
+// e.g. frames with faces blurred or inference results burned in - into a
+// second ffmpeg process that segments them into a rolling set of .ts files
+// plus an m3u8 playlist under root, served over an embedded
+// http.FileServer on addr. That lets a function ingest RTSP, run handler
+// logic on each frame, and republish the result as a browser-playable
+// stream without an external media server.
+type hlsOutput struct {
+	logger logger.Logger
+
+	root           string
+	addr           string
+	segmentSeconds int
+	listSize       int
+
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	server *http.Server
+}
+
+func newHLSOutput(parentLogger logger.Logger, root, addr string, segmentSeconds, listSize int) *hlsOutput {
+	return &hlsOutput{
+		logger:         parentLogger,
+		root:           root,
+		addr:           addr,
+		segmentSeconds: segmentSeconds,
+		listSize:       listSize,
+	}
+}
+
+// Start creates root (if needed), launches the segmenting ffmpeg process
+// reading handler output from its stdin, and serves root over addr.
+func (h *hlsOutput) Start() error {
+	if err := os.MkdirAll(h.root, 0o755); err != nil {
+		return errors.Wrap(err, "Failed to create HLS output root")
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-i", "pipe:0",
+		"-c", "copy",
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(h.segmentSeconds),
+		"-hls_list_size", strconv.Itoa(h.listSize),
+		"-hls_flags", "delete_segments",
+		filepath.Join(h.root, "index.m3u8"))
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return errors.Wrap(err, "Failed to open HLS segmenting ffmpeg stdin pipe")
+	}
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "Failed to start HLS segmenting ffmpeg process")
+	}
+
+	h.cmd = cmd
+	h.stdin = stdin
+
+	h.server = &http.Server{Addr: h.addr, Handler: http.FileServer(http.Dir(h.root))}
+	go func() {
+		if err := h.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			h.logger.WarnWith("HLS output file server stopped", "error", err)
+		}
+	}()
+
+	h.logger.InfoWith("HLS output segmenter started", "root", h.root, "addr", h.addr)
+	return nil
+}
+
+// Write feeds one handler response body into the segmenting ffmpeg
+// process's stdin, logging (rather than failing the caller) on error: a
+// broken segmenter shouldn't take the rest of the pipeline down with it.
+func (h *hlsOutput) Write(data []byte) {
+	if h.stdin == nil || len(data) == 0 {
+		return
+	}
+	if _, err := h.stdin.Write(data); err != nil {
+		h.logger.WarnWith("Failed to write to HLS segmenting ffmpeg process", "error", err)
+	}
+}
+
+// outputConfigInt reads an int out of an Output.config value decoded from
+// YAML/JSON (so it may have landed as int, int64, or float64), falling back
+// to def if key is absent or not a recognized numeric type.
+func outputConfigInt(value interface{}, def int) int {
+	switch typed := value.(type) {
+	case int:
+		return typed
+	case int64:
+		return int(typed)
+	case float64:
+		return int(typed)
+	default:
+		return def
+	}
+}
+
+// Stop closes the ffmpeg process's stdin, waits for it to exit, and shuts
+// down the file server. Already-written segments are left on disk.
+func (h *hlsOutput) Stop() {
+	if h.stdin != nil {
+		_ = h.stdin.Close()
+	}
+	if h.cmd != nil && h.cmd.Process != nil {
+		_ = h.cmd.Process.Kill()
+		_ = h.cmd.Wait()
+	}
+	if h.server != nil {
+		_ = h.server.Close()
+	}
+}
@@ -0,0 +1,68 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+)
+
+// resizeJPEG decodes a JPEG frame and downscales it to fit within
+// maxWidth x maxHeight (0 on either axis means unlimited on that axis)
+// using nearest-neighbor sampling, preserving aspect ratio, then
+// re-encodes it. A frame that already fits is returned unchanged.
+func resizeJPEG(data []byte, maxWidth, maxHeight int) ([]byte, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	targetWidth, targetHeight := scaledDimensions(width, height, maxWidth, maxHeight)
+	if targetWidth == width && targetHeight == height {
+		return data, nil
+	}
+
+	resized := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		srcY := bounds.Min.Y + y*height/targetHeight
+		for x := 0; x < targetWidth; x++ {
+			srcX := bounds.Min.X + x*width/targetWidth
+			resized.Set(x, y, img.At(srcX, srcY))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaledDimensions computes the largest width/height no larger than
+// maxWidth/maxHeight (0 meaning unlimited on that axis) that preserves the
+// source aspect ratio.
+func scaledDimensions(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		if s := float64(maxWidth) / float64(width); s < scale {
+			scale = s
+		}
+	}
+	if maxHeight > 0 && height > maxHeight {
+		if s := float64(maxHeight) / float64(height); s < scale {
+			scale = s
+		}
+	}
+	if scale == 1.0 {
+		return width, height
+	}
+	return int(float64(width) * scale), int(float64(height) * scale)
+}
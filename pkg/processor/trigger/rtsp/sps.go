@@ -0,0 +1,212 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"github.com/nuclio/errors"
+)
+
+// maxNumRefFramesInPicOrderCnt bounds num_ref_frames_in_pic_order_cnt_cycle,
+// an Exp-Golomb field read straight off the wire and then used as a loop
+// count over further readSE calls: the H264 spec itself constrains it to
+// [0, 255], but an attacker-crafted SPS can claim any ue(v) value, so cap it
+// rather than let a malicious stream stall the segmenter goroutine in this
+// loop.
+const maxNumRefFramesInPicOrderCnt = 256
+
+// bitReader is a big-endian, MSB-first bit reader over a byte slice, used by
+// parseSPSDimensions to walk an H264 SPS without pulling in a full codec
+// parsing library.
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func newBitReader(data []byte) *bitReader {
+	return &bitReader{data: data}
+}
+
+func (r *bitReader) readBit() int {
+	if r.pos/8 >= len(r.data) {
+		return 0
+	}
+	b := r.data[r.pos/8]
+	bit := (b >> uint(7-r.pos%8)) & 1
+	r.pos++
+	return int(bit)
+}
+
+func (r *bitReader) readBits(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		v = (v << 1) | uint32(r.readBit())
+	}
+	return v
+}
+
+// readUE reads an Exp-Golomb unsigned code, as used throughout H264 SPS/PPS.
+func (r *bitReader) readUE() uint32 {
+	leadingZeros := 0
+	for r.readBit() == 0 {
+		leadingZeros++
+		if leadingZeros > 32 {
+			return 0
+		}
+	}
+	if leadingZeros == 0 {
+		return 0
+	}
+	return (1 << uint(leadingZeros)) - 1 + r.readBits(leadingZeros)
+}
+
+func (r *bitReader) readSE() int32 {
+	ue := r.readUE()
+	if ue%2 == 0 {
+		return -int32(ue / 2)
+	}
+	return int32(ue+1) / 2
+}
+
+// removeEmulationPrevention strips the 0x03 emulation-prevention bytes H264
+// inserts after any 0x00 0x00 run, so the Exp-Golomb reader walks the real
+// RBSP bitstream rather than its NALU-safe encoding.
+func removeEmulationPrevention(nalu []byte) []byte {
+	out := make([]byte, 0, len(nalu))
+	zeroRun := 0
+	for _, b := range nalu {
+		if zeroRun >= 2 && b == 0x03 {
+			zeroRun = 0
+			continue
+		}
+		if b == 0 {
+			zeroRun++
+		} else {
+			zeroRun = 0
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func skipScalingList(r *bitReader, size int) {
+	lastScale, nextScale := 8, 8
+	for i := 0; i < size; i++ {
+		if nextScale != 0 {
+			deltaScale := r.readSE()
+			nextScale = (lastScale + int(deltaScale) + 256) % 256
+		}
+		if nextScale != 0 {
+			lastScale = nextScale
+		}
+	}
+}
+
+// parseSPSDimensions extracts the coded picture width/height from a raw
+// H264 SPS NALU (NALU header byte still attached) without decoding a single
+// frame, so the HLS segmenter can size its fMP4 init segment before the
+// first YUV decode would otherwise happen. Covers progressive and
+// interlaced frame_mbs_only_flag, 4:2:0/4:2:2 frame cropping and the
+// high-profile scaling-matrix extension; exotic SPS extensions beyond that
+// (SVC/MVC, separate colour planes) are not handled.
+func parseSPSDimensions(nalu []byte) (width, height int, err error) {
+	if len(nalu) < 4 {
+		return 0, 0, errors.New("SPS NALU too short")
+	}
+
+	r := newBitReader(removeEmulationPrevention(nalu[1:]))
+
+	profileIdc := r.readBits(8)
+	r.readBits(8) // constraint_set flags + reserved
+	r.readBits(8) // level_idc
+	r.readUE()    // seq_parameter_set_id
+
+	chromaFormatIdc := uint32(1)
+	switch profileIdc {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		chromaFormatIdc = r.readUE()
+		if chromaFormatIdc == 3 {
+			r.readBit() // separate_colour_plane_flag
+		}
+		r.readUE()  // bit_depth_luma_minus8
+		r.readUE()  // bit_depth_chroma_minus8
+		r.readBit() // qpprime_y_zero_transform_bypass_flag
+		if r.readBit() == 1 {
+			count := 8
+			if chromaFormatIdc == 3 {
+				count = 12
+			}
+			for i := 0; i < count; i++ {
+				if r.readBit() == 1 {
+					size := 16
+					if i >= 6 {
+						size = 64
+					}
+					skipScalingList(r, size)
+				}
+			}
+		}
+	}
+
+	r.readUE() // log2_max_frame_num_minus4
+	picOrderCntType := r.readUE()
+	switch picOrderCntType {
+	case 0:
+		r.readUE() // log2_max_pic_order_cnt_lsb_minus4
+	case 1:
+		r.readBit() // delta_pic_order_always_zero_flag
+		r.readSE()  // offset_for_non_ref_pic
+		r.readSE()  // offset_for_top_to_bottom_field
+		numRefFrames := r.readUE()
+		if numRefFrames > maxNumRefFramesInPicOrderCnt {
+			numRefFrames = maxNumRefFramesInPicOrderCnt
+		}
+		for i := uint32(0); i < numRefFrames; i++ {
+			r.readSE()
+		}
+	}
+	r.readUE()  // max_num_ref_frames
+	r.readBit() // gaps_in_frame_num_value_allowed_flag
+
+	picWidthInMbsMinus1 := r.readUE()
+	picHeightInMapUnitsMinus1 := r.readUE()
+	frameMbsOnlyFlag := r.readBit()
+	if frameMbsOnlyFlag == 0 {
+		r.readBit() // mb_adaptive_frame_field_flag
+	}
+	r.readBit() // direct_8x8_inference_flag
+
+	width = int(picWidthInMbsMinus1+1) * 16
+	height = int(picHeightInMapUnitsMinus1+1) * 16
+	if frameMbsOnlyFlag == 0 {
+		height *= 2
+	}
+
+	if r.readBit() == 1 { // frame_cropping_flag
+		cropLeft := r.readUE()
+		cropRight := r.readUE()
+		cropTop := r.readUE()
+		cropBottom := r.readUE()
+
+		cropUnitX, cropUnitY := uint32(1), uint32(1)
+		switch chromaFormatIdc {
+		case 1: // 4:2:0
+			cropUnitX, cropUnitY = 2, 2
+		case 2: // 4:2:2
+			cropUnitX, cropUnitY = 2, 1
+		}
+		if frameMbsOnlyFlag == 0 {
+			cropUnitY *= 2
+		}
+
+		width -= int((cropLeft + cropRight) * cropUnitX)
+		height -= int((cropTop + cropBottom) * cropUnitY)
+	}
+
+	if width <= 0 || height <= 0 {
+		return 0, 0, errors.New("SPS parsed invalid dimensions")
+	}
+	return width, height, nil
+}
@@ -0,0 +1,153 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rtsp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// pendingEntry is one track kind's most recent unpaired event, waiting for
+// a sample from the other kind to land within tolerance.
+type pendingEntry struct {
+	event *Event
+	pts   time.Duration
+	at    time.Time
+}
+
+// frameSyncer batches a "video" and an "audio" Event whose PTS fall within
+// tolerance of each other into a single combined Event, for
+// Configuration.SyncToleranceMS. An event that never finds a partner within
+// tolerance is flushed on its own via FlushStale, so a quiet track doesn't
+// hold the other one's events forever.
+type frameSyncer struct {
+	mu        sync.Mutex
+	tolerance time.Duration
+	pending   map[string]*pendingEntry
+	emit      func(*Event)
+}
+
+func newFrameSyncer(tolerance time.Duration, emit func(*Event)) *frameSyncer {
+	return &frameSyncer{
+		tolerance: tolerance,
+		pending:   make(map[string]*pendingEntry),
+		emit:      emit,
+	}
+}
+
+// Offer buffers event as kind's newest sample, pairing and emitting it
+// immediately if the other kind has a pending sample within tolerance.
+func (fs *frameSyncer) Offer(kind string, event *Event, pts time.Duration) {
+	other := "audio"
+	if kind == "audio" {
+		other = "video"
+	}
+
+	fs.mu.Lock()
+	peer, hasPeer := fs.pending[other]
+	if hasPeer {
+		delete(fs.pending, other)
+	} else {
+		fs.pending[kind] = &pendingEntry{event: event, pts: pts, at: time.Now()}
+	}
+	fs.mu.Unlock()
+
+	if !hasPeer {
+		return
+	}
+
+	delta := pts - peer.pts
+	if delta < 0 {
+		delta = -delta
+	}
+
+	if delta > fs.tolerance {
+		// peer fell outside tolerance while waiting; flush it alone and
+		// let this sample start a fresh pending entry for its own kind.
+		fs.emit(peer.event)
+		fs.mu.Lock()
+		fs.pending[kind] = &pendingEntry{event: event, pts: pts, at: time.Now()}
+		fs.mu.Unlock()
+		return
+	}
+
+	combined, err := combineEvents(kind, event, pts, peer.event, peer.pts)
+	if err != nil {
+		// Never silently drop a frame: emit both uncombined on a marshal
+		// failure, which in practice can't happen (see combineEvents).
+		fs.emit(peer.event)
+		fs.emit(event)
+		return
+	}
+
+	fs.emit(combined)
+}
+
+// FlushStale emits (on its own) any pending sample older than maxAge, whose
+// partner never showed up within tolerance.
+func (fs *frameSyncer) FlushStale(maxAge time.Duration) {
+	fs.mu.Lock()
+	var stale []*Event
+	now := time.Now()
+	for kind, entry := range fs.pending {
+		if now.Sub(entry.at) > maxAge {
+			stale = append(stale, entry.event)
+			delete(fs.pending, kind)
+		}
+	}
+	fs.mu.Unlock()
+
+	for _, event := range stale {
+		fs.emit(event)
+	}
+}
+
+// combinedBody is the JSON body of a batched audio+video Event: the raw
+// payloads don't share a container format, so each is carried base64-encoded
+// alongside its own PTS.
+type combinedBody struct {
+	VideoBase64 string `json:"video_base64"`
+	VideoPTSMS  int64  `json:"video_pts_ms"`
+	AudioBase64 string `json:"audio_base64"`
+	AudioPTSMS  int64  `json:"audio_pts_ms"`
+}
+
+// combineEvents merges a video and an audio Event (in either order) into a
+// single Event carrying a combinedBody JSON payload.
+func combineEvents(kind string, event *Event, pts time.Duration, peerEvent *Event, peerPTS time.Duration) (*Event, error) {
+	videoEvent, videoPTS, audioEvent, audioPTS := peerEvent, peerPTS, event, pts
+	if kind == "video" {
+		videoEvent, videoPTS, audioEvent, audioPTS = event, pts, peerEvent, peerPTS
+	}
+
+	body, err := json.Marshal(combinedBody{
+		VideoBase64: base64.StdEncoding.EncodeToString(videoEvent.body),
+		VideoPTSMS:  videoPTS.Milliseconds(),
+		AudioBase64: base64.StdEncoding.EncodeToString(audioEvent.body),
+		AudioPTSMS:  audioPTS.Milliseconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	combined := &Event{
+		body:      body,
+		timestamp: time.Now(),
+		frameNum:  videoEvent.frameNum,
+		attributes: map[string]interface{}{
+			"kind":     "combined",
+			"codec":    "combined+json",
+			"url":      videoEvent.attributes["url"],
+			"videoPts": videoPTS,
+			"audioPts": audioPTS,
+		},
+	}
+
+	return combined, nil
+}
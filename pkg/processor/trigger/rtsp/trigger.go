@@ -8,12 +8,9 @@ package rtsp
 
 import (
 	"bytes"
-	"encoding/json"
-	"fmt"
-	"io"
+	"context"
+	"mime/multipart"
 	"net/http"
-	"os"
-	"os/exec"
 	"sync"
 	"time"
 
@@ -24,18 +21,43 @@ import (
 	"github.com/nuclio/nuclio/pkg/functionconfig"
 	"github.com/nuclio/nuclio/pkg/processor/trigger"
 	"github.com/nuclio/nuclio/pkg/processor/worker"
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/gortsplib/v5/pkg/description"
+	"github.com/bluenviron/gortsplib/v5/pkg/format"
 )
 
-type rtsp struct {
+// videoProcessingInterval is the cadence at which the most recently decoded
+// video frame is emitted, decoupling emission from the camera's frame rate.
+const videoProcessingInterval = 200 * time.Millisecond
+
+type rtspTrigger struct {
 	trigger.AbstractTrigger
 	configuration *Configuration
-	events        []Event
-	ffmpegCmd     *exec.Cmd
-	ffmpegStdout  io.ReadCloser
-	stopChan      chan struct{}
-	wg            sync.WaitGroup
-	processor     *AudioProcessor
-	webhookURL    string
+
+	clientMu sync.Mutex
+	client   RTSPClient
+
+	lastPTS      sync.Map // payload type (uint8) -> time.Duration
+	lastCodec    sync.Map // payload type (uint8) -> string
+	lastKeyframe sync.Map // payload type (uint8) -> bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	webhookURL string
+
+	hls *hlsSegmenter
+	flv *flvStream
+
+	// outputHLS is the Output.kind "hls" sink: nil unless configured.
+	outputHLS *hlsOutput
+
+	// syncer batches audio+video events together when Mode is "both" and
+	// SyncToleranceMS > 0; nil otherwise, in which case events are
+	// submitted directly as they're produced.
+	syncer *frameSyncer
 }
 
 func newTrigger(logger logger.Logger,
@@ -47,31 +69,27 @@ func newTrigger(logger logger.Logger,
 		workerAllocator,
 		&configuration.Configuration,
 		"async",
-		"rtsp_webhook",
+		"rtsp",
 		configuration.Name,
 		restartTriggerChan)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to create abstract trigger")
 	}
 
-	t := &rtsp{
+	t := &rtspTrigger{
 		AbstractTrigger: abstractTrigger,
 		configuration:   configuration,
-		stopChan:        make(chan struct{}),
 	}
 	t.Trigger = t
-	t.allocateEvents(1)
 
 	return t, nil
 }
 
-func (r *rtsp) Start(checkpoint functionconfig.Checkpoint) error {
-	r.Logger.InfoWith("Starting RTSP listener trigger",
+func (r *rtspTrigger) Start(checkpoint functionconfig.Checkpoint) error {
+	r.Logger.InfoWith("Starting RTSP trigger",
 		"url", r.configuration.RTSPURL,
-		"bufferSize", r.configuration.BufferSize,
-		"sampleRate", r.configuration.SampleRate)
+		"transport", r.configuration.Transport)
 
-	fmt.Println(r.configuration.Output["kind"].(string))
 	if r.configuration.Output != nil {
 		kind, _ := r.configuration.Output["kind"].(string)
 		config, _ := r.configuration.Output["config"].(map[string]interface{})
@@ -79,187 +97,506 @@ func (r *rtsp) Start(checkpoint functionconfig.Checkpoint) error {
 			r.webhookURL, _ = config["url"].(string)
 			r.Logger.InfoWith("Webhook output configured", "url", r.webhookURL)
 		}
-	}
 
-	r.ffmpegCmd = exec.Command("ffmpeg",
-		"-rtsp_transport", "tcp",
-		"-i", r.configuration.RTSPURL,
-		"-f", "s16le",
-		"-acodec", "pcm_s16le",
-		"-ac", "1",
-		"-ar", fmt.Sprintf("%d", r.configuration.SampleRate),
-		"pipe:1",
-	)
-	r.ffmpegCmd.Stderr = os.Stderr
+		if kind == "hls" && config != nil {
+			root, _ := config["root"].(string)
+			if root == "" {
+				return errors.New("output kind 'hls' requires a 'root' directory")
+			}
+			addr, _ := config["addr"].(string)
+			if addr == "" {
+				addr = DefaultOutputHLSAddr
+			}
+			segmentSeconds := outputConfigInt(config["segmentSeconds"], DefaultOutputHLSSegmentSeconds)
+			listSize := outputConfigInt(config["listSize"], DefaultOutputHLSListSize)
 
-	var err error
-	r.ffmpegStdout, err = r.ffmpegCmd.StdoutPipe()
-	if err != nil {
-		return errors.Wrap(err, "Failed to get FFmpeg stdout pipe")
+			r.outputHLS = newHLSOutput(r.Logger, root, addr, segmentSeconds, listSize)
+			if err := r.outputHLS.Start(); err != nil {
+				return errors.Wrap(err, "Failed to start HLS output")
+			}
+			r.Logger.InfoWith("HLS output configured", "root", root, "addr", addr)
+		}
 	}
 
-	if err := r.ffmpegCmd.Start(); err != nil {
-		return errors.Wrap(err, "Failed to start FFmpeg process")
+	if r.configuration.HLSEnabled {
+		r.hls = newHLSSegmenter(
+			time.Duration(r.configuration.HLSSegmentSeconds)*time.Second,
+			r.configuration.HLSPlaylistSize)
+		r.hls.Start(r.configuration.HLSAddr)
+		r.Logger.InfoWith("HLS output configured", "addr", r.configuration.HLSAddr)
 	}
-	r.Logger.InfoWith("✓ FFmpeg started", "url", r.configuration.RTSPURL)
 
-	r.processor = NewAudioProcessor(
-		r.configuration.SampleRate,
-		r.configuration.ChunkDurationSeconds,
-		r.configuration.MaxBufferSeconds,
-		r.configuration.TrimSeconds,
-	)
+	if r.configuration.FLVEnabled {
+		r.flv = newFLVStream(r.configuration.FLVStreamKey)
+		r.flv.Start(r.configuration.FLVAddr)
+		r.Logger.InfoWith("HTTP-FLV output configured",
+			"addr", r.configuration.FLVAddr, "streamKey", r.configuration.FLVStreamKey)
+	}
+
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	if r.configuration.Mode == "both" && r.configuration.SyncToleranceMS > 0 {
+		r.syncer = newFrameSyncer(r.configuration.syncTolerance(), r.submitEvent)
+		r.wg.Add(1)
+		go r.flushSyncer()
+		r.Logger.InfoWith("Audio/video sync batching configured", "syncToleranceMs", r.configuration.SyncToleranceMS)
+	}
 
 	r.wg.Add(1)
-	go r.readAudioPackets()
+	go r.streamLoop()
 
 	return nil
 }
 
-func (r *rtsp) readAudioPackets() {
+// flushSyncer periodically emits any audio/video sample r.syncer has been
+// holding too long without finding a partner within tolerance.
+func (r *rtspTrigger) flushSyncer() {
 	defer r.wg.Done()
 
-	buf := make([]byte, r.configuration.BufferSize)
-	retryCount := 0
-	maxRetries := 5
+	maxAge := r.configuration.syncTolerance() * 2
+	ticker := time.NewTicker(r.configuration.syncTolerance())
+	defer ticker.Stop()
 
 	for {
 		select {
-		case <-r.stopChan:
-			r.Logger.InfoWith("✓ Audio packet reader stopped")
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.syncer.FlushStale(maxAge)
+		}
+	}
+}
+
+// streamLoop keeps (re)connecting to the RTSP source, mirroring the MJPEG
+// trigger's reconnect-with-fixed-delay behavior.
+func (r *rtspTrigger) streamLoop() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			r.Logger.Info("RTSP trigger stopped")
 			return
 		default:
 		}
 
-		n, err := r.ffmpegStdout.Read(buf)
-		if err != nil && err != io.EOF {
-			retryCount++
-			if retryCount > maxRetries {
-				r.Logger.ErrorWith("✗ Max retries exceeded, stopping reader", "error", err)
-				return
-			}
-			r.Logger.WarnWith("⚠ Read error, retrying", "error", err, "retry", retryCount)
-			time.Sleep(time.Second * time.Duration(retryCount))
+		if err := r.connectAndPlay(); err != nil {
+			r.Logger.WarnWith("RTSP stream error, retrying in 5 seconds", "error", err)
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// connectAndPlay performs DESCRIBE/SETUP/PLAY against the configured RTSP
+// URL via the configured RTSPClient backend, demuxes every advertised
+// track through a MediaPipeline, and blocks until the connection drops or
+// the trigger is stopped.
+func (r *rtspTrigger) connectAndPlay() error {
+	if r.hls != nil {
+		r.hls.Reset()
+	}
+	if r.flv != nil {
+		r.flv.Reset()
+	}
+
+	client := newRTSPClient(r.configuration)
+	defer client.Close()
+
+	r.clientMu.Lock()
+	r.client = client
+	r.clientMu.Unlock()
+
+	if err := client.Connect(r.configuration.RTSPURL); err != nil {
+		return errors.Wrap(err, "Failed to connect to RTSP source")
+	}
+
+	tracks, err := client.Describe()
+	if err != nil {
+		return errors.Wrap(err, "Failed to DESCRIBE RTSP stream")
+	}
+
+	if err := client.Setup(tracks); err != nil {
+		return errors.Wrap(err, "Failed to SETUP RTSP tracks")
+	}
+
+	pipeline, err := NewMediaPipeline(mediasOf(tracks))
+	if err != nil {
+		return errors.Wrap(err, "Failed to build media pipeline")
+	}
+
+	processors := make(map[uint8]*DataProcessorStream)
+	connDone := make(chan struct{})
+	defer close(connDone)
+
+	for _, track := range tracks {
+		// Mode gates the per-frame/per-chunk Output events only; HLS/FLV
+		// live view (handled separately in handlePacket) always consumes
+		// the video track regardless of Mode.
+		if r.configuration.Mode != "both" && track.Kind != r.configuration.Mode {
 			continue
 		}
-		retryCount = 0
 
-		if n > 0 {
-			chunks := r.processor.AddPCM(buf[:n])
+		processor := r.newProcessorForKind(track.Kind)
+		processor.Start(r.processingInterval(track.Kind))
+		processors[track.PT] = processor
 
-			if len(chunks) > 0 {
-				r.processor.lock.Lock()
-				rollingBuffer := make([]byte, len(r.processor.buffer))
-				copy(rollingBuffer, r.processor.buffer)
-				r.processor.lock.Unlock()
+		r.wg.Add(1)
+		go r.consumeProcessor(track.Kind, track.PT, processor, connDone)
+	}
 
-				workerInstance, err := r.WorkerAllocator.Allocate(time.Second * 5)
-				if err != nil {
-					r.Logger.WarnWith("⚠ Failed to allocate worker", "error", err)
-					continue
-				}
+	r.Logger.InfoWith("✓ Connected to RTSP stream",
+		"url", r.configuration.RTSPURL,
+		"backend", r.configuration.Backend)
 
-				event := &Event{
-					body:      rollingBuffer,
-					timestamp: time.Now(),
-					attributes: map[string]interface{}{
-						"buffer-size": len(rollingBuffer),
-						"chunks":      len(chunks),
-					},
-				}
+	err = client.Play(func(track RTSPTrack, pkt *rtp.Packet, pts time.Duration) {
+		r.handlePacket(pipeline, track, pkt, pts, processors[track.PT])
+	})
 
-				response, processErr := r.SubmitEventToWorker(r.Logger, workerInstance, event)
-				r.WorkerAllocator.Release(workerInstance)
+	for _, processor := range processors {
+		processor.Stop()
+	}
 
-				if processErr != nil {
-					r.Logger.WarnWith("⚠ Failed to process event", "error", processErr)
-					continue
-				}
+	return err
+}
 
-				typedResponse, ok := response.(nuclio.Response)
-				if !ok {
-					r.Logger.Warn("⚠ Received non-nuclio response")
-					continue
+// mediasOf collects the distinct *description.Media a set of tracks came
+// from, skipping synthetic tracks (Media nil) that an RTSPClient backend
+// produced without going through gortsplib's SDP parsing.
+func mediasOf(tracks []RTSPTrack) []*description.Media {
+	var medias []*description.Media
+	seen := make(map[*description.Media]bool)
+
+	for _, track := range tracks {
+		if track.Media == nil || seen[track.Media] {
+			continue
+		}
+		seen[track.Media] = true
+		medias = append(medias, track.Media)
+	}
+
+	return medias
+}
+
+func trackKind(media *description.Media) string {
+	if media.Type == description.MediaTypeVideo {
+		return "video"
+	}
+	return "audio"
+}
+
+func (r *rtspTrigger) newProcessorForKind(kind string) *DataProcessorStream {
+	if kind == "video" {
+		return NewDataProcessorStream(0, 0, 0, true)
+	}
+
+	return NewDataProcessorStream(
+		r.configuration.audioChunkBytes(),
+		r.configuration.audioMaxBytes(),
+		r.configuration.audioTrimBytes(),
+		false,
+	)
+}
+
+func (r *rtspTrigger) processingInterval(kind string) time.Duration {
+	if kind == "video" {
+		return videoProcessingInterval
+	}
+	return time.Duration(r.configuration.ChunkDurationSeconds) * time.Second
+}
+
+// handlePacket depacketizes a single unit of media via the MediaPipeline
+// (or, for a track with no Format, takes its payload as an already-decoded
+// frame straight from the RTSPClient backend), remembers its presentation
+// timestamp, and pushes the resulting bytes into the track's
+// DataProcessorStream.
+func (r *rtspTrigger) handlePacket(
+	pipeline *MediaPipeline,
+	track RTSPTrack,
+	pkt *rtp.Packet,
+	pts time.Duration,
+	processor *DataProcessorStream,
+) {
+	// HLS/FLV output consumes the H264 video track's raw access units
+	// directly, bypassing the YUV-decode/JPEG-encode step ProcessRTP
+	// performs below for the per-frame webhook path, so live-view mode
+	// doesn't pay that decode/encode cost on every frame. Only available
+	// against a track whose Format is *format.H264 (the gortsplib backend);
+	// the ffmpeg backend's already-decoded MJPEG track falls through to the
+	// normal path instead. The same access unit is handed to both
+	// segmenters so it's only depacketized once.
+	if (r.hls != nil || r.flv != nil) && track.Kind == "video" {
+		if h264, ok := track.Format.(*format.H264); ok {
+			frame, err := pipeline.ProcessRTPRaw(pkt, h264)
+			if err != nil {
+				r.Logger.WarnWith("Failed to process RTP packet for live view", "error", err)
+			} else if frame.RawNALUs != nil {
+				if r.hls != nil {
+					r.hls.Push(frame.RawNALUs, frame.Keyframe, pts)
+				}
+				if r.flv != nil {
+					r.flv.Push(frame.RawNALUs, frame.Keyframe, pts)
 				}
+			}
+			return
+		}
+	}
 
-				if typedResponse.StatusCode != 200 {
-					r.Logger.WarnWith("⚠ Handler returned non-200 status", "statusCode", typedResponse.StatusCode)
+	if processor == nil {
+		return
+	}
+
+	var frame Frame
+	if track.Format != nil {
+		var err error
+		frame, err = pipeline.ProcessRTP(pkt, track.Format)
+		if err != nil {
+			r.Logger.WarnWith("Failed to process RTP packet", "error", err)
+			return
+		}
+	} else {
+		frame = Frame{Data: pkt.Payload, Codec: track.Codec, Keyframe: true}
+	}
+
+	if len(frame.Data) == 0 {
+		return
+	}
+
+	if r.configuration.KeyframesOnly && track.Kind == "video" && !frame.Keyframe {
+		return
+	}
+
+	if track.Kind == "video" && frame.Codec == "mjpeg" &&
+		(r.configuration.MaxWidth > 0 || r.configuration.MaxHeight > 0) {
+		resized, err := resizeJPEG(frame.Data, r.configuration.MaxWidth, r.configuration.MaxHeight)
+		if err != nil {
+			r.Logger.WarnWith("Failed to downscale video frame, emitting at source resolution", "error", err)
+		} else {
+			frame.Data = resized
+		}
+	}
+
+	r.lastPTS.Store(track.PT, pts)
+	r.lastCodec.Store(track.PT, frame.Codec)
+	r.lastKeyframe.Store(track.PT, frame.Keyframe)
+
+	processor.Push(frame.Data)
+}
+
+// consumeProcessor reads decoded snapshots for one track off its
+// DataProcessorStream, applies the configured per-track processing factor,
+// tags each event with track kind and PTS, and submits it to a worker the
+// same way mjpeg.processFrame does.
+func (r *rtspTrigger) consumeProcessor(kind string, pt uint8, processor *DataProcessorStream, connDone <-chan struct{}) {
+	defer r.wg.Done()
+
+	factor := r.configuration.ProcessingFactor[kind]
+	if factor < 1 {
+		factor = DefaultProcessingFactor
+	}
+
+	// When FrameRate is set for the video track, it overrides
+	// ProcessingFactor's fixed every-Nth-frame count with a PTS-based
+	// minimum interval between emitted frames.
+	useFrameRate := kind == "video" && r.configuration.FrameRate > 0
+	var minInterval time.Duration
+	if useFrameRate {
+		minInterval = time.Duration(float64(time.Second) / r.configuration.FrameRate)
+	}
+
+	var frameCount int64
+	var lastEmittedPTS time.Duration
+	var haveEmitted bool
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-connDone:
+			return
+		case event, ok := <-processor.Output():
+			if !ok {
+				return
+			}
+
+			frameCount++
+
+			var pts time.Duration
+			if raw, ok := r.lastPTS.Load(pt); ok {
+				pts, _ = raw.(time.Duration)
+			}
+
+			if useFrameRate {
+				if haveEmitted && pts-lastEmittedPTS < minInterval {
 					continue
 				}
+				lastEmittedPTS = pts
+				haveEmitted = true
+			} else if frameCount%int64(factor) != 0 {
+				continue
+			}
 
-				if r.webhookURL != "" {
-					r.postHandlerOutputToWebhook(typedResponse.Body)
-				}
+			var codec string
+			if raw, ok := r.lastCodec.Load(pt); ok {
+				codec, _ = raw.(string)
+			}
+
+			var keyframe bool
+			if raw, ok := r.lastKeyframe.Load(pt); ok {
+				keyframe, _ = raw.(bool)
+			}
 
+			event.frameNum = frameCount
+			event.attributes = map[string]interface{}{
+				"kind":     kind,
+				"pts":      pts,
+				"url":      r.configuration.RTSPURL,
+				"codec":    codec,
+				"keyframe": keyframe,
+			}
+
+			if r.syncer != nil {
+				r.syncer.Offer(kind, event, pts)
+			} else {
+				r.submitEvent(event)
 			}
 		}
+	}
+}
+
+func (r *rtspTrigger) submitEvent(event *Event) {
+	response, submitError, processError := r.AllocateWorkerAndSubmitEvent(
+		event,
+		r.Logger,
+		10*time.Second)
 
-		if err == io.EOF {
-			r.Logger.InfoWith("ℹ FFmpeg stream ended")
+	if submitError != nil {
+		r.Logger.WarnWith("Failed to submit RTSP event", "error", submitError)
+		return
+	}
+
+	if processError != nil {
+		r.Logger.WarnWith("Failed to process RTSP event", "error", processError)
+		return
+	}
+
+	typedResponse, ok := response.(nuclio.Response)
+	if !ok {
+		return
+	}
+
+	if r.webhookURL != "" {
+		r.postToWebhookWithData("", typedResponse.Body)
+	}
+
+	if r.outputHLS != nil {
+		r.outputHLS.Write(typedResponse.Body)
+	}
+}
+
+// postToWebhookWithData sends data to the configured webhook as
+// multipart/form-data; text and data are both optional.
+func (r *rtspTrigger) postToWebhookWithData(text string, data []byte) {
+	if r.webhookURL == "" {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	if text != "" {
+		fw, err := writer.CreateFormField("text")
+		if err != nil {
+			r.Logger.WarnWith("Failed to create text form field", "error", err)
+			return
+		}
+		if _, err := fw.Write([]byte(text)); err != nil {
+			r.Logger.WarnWith("Failed to write text field", "error", err)
+			return
+		}
+	}
+
+	if len(data) > 0 {
+		fw, err := writer.CreateFormFile("data", "frame.bin")
+		if err != nil {
+			r.Logger.WarnWith("Failed to create data form file", "error", err)
+			return
+		}
+		if _, err := fw.Write(data); err != nil {
+			r.Logger.WarnWith("Failed to write data file", "error", err)
 			return
 		}
 	}
-}
 
-func (r *rtsp) postHandlerOutputToWebhook(body []byte) {
-	payload := map[string]interface{}{
-		"handler_output": string(body), // wrap the string in a JSON object
+	if err := writer.Close(); err != nil {
+		r.Logger.WarnWith("Failed to close multipart writer", "error", err)
+		return
 	}
-	jsonPayload, _ := json.Marshal(payload)
 
-	req, err := http.NewRequest("POST", r.webhookURL, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequest("POST", r.webhookURL, buf)
 	if err != nil {
-		r.Logger.WarnWith("⚠ Failed to create webhook POST request", "error", err)
+		r.Logger.WarnWith("Failed to create webhook request", "error", err)
 		return
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		r.Logger.WarnWith("⚠ Failed to POST handler output to webhook", "error", err)
+		r.Logger.WarnWith("Webhook POST failed", "error", err)
 		return
 	}
 	defer resp.Body.Close()
 
-	r.Logger.DebugWith("✓ Forwarded handler output to webhook", "statusCode", resp.StatusCode)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		r.Logger.WarnWith("Webhook returned non-success status", "status", resp.StatusCode)
+		return
+	}
+
+	r.Logger.DebugWith("Webhook POST succeeded", "status", resp.StatusCode, "text_len", len(text), "data_len", len(data))
 }
 
 // Stop the trigger
-func (r *rtsp) Stop(force bool) (functionconfig.Checkpoint, error) {
-	r.Logger.DebugWith("Stopping RTSP listener trigger")
+func (r *rtspTrigger) Stop(force bool) (functionconfig.Checkpoint, error) {
+	r.Logger.DebugWith("Stopping RTSP trigger")
+
+	if r.hls != nil {
+		r.hls.Stop()
+	}
+	if r.flv != nil {
+		r.flv.Stop()
+	}
+	if r.outputHLS != nil {
+		r.outputHLS.Stop()
+	}
+
+	r.cancel()
 
-	close(r.stopChan)
+	r.clientMu.Lock()
+	if r.client != nil {
+		r.client.Close()
+	}
+	r.clientMu.Unlock()
 
 	done := make(chan struct{})
 	go func() {
 		r.wg.Wait()
 		close(done)
 	}()
+
 	select {
 	case <-done:
-		r.Logger.DebugWith("✓ Readers stopped gracefully")
-	case <-time.After(5 * time.Second):
-		r.Logger.WarnWith("⚠ Reader stop timeout, forcing termination")
+		r.Logger.DebugWith("✓ RTSP trigger stopped gracefully")
+	case <-time.After(10 * time.Second):
+		r.Logger.WarnWith("⚠ Stop timeout, forcing termination")
 	}
 
-	if r.ffmpegCmd != nil && r.ffmpegCmd.ProcessState == nil {
-		if err := r.ffmpegCmd.Process.Kill(); err != nil {
-			r.Logger.WarnWith("⚠ Failed to kill FFmpeg process", "error", err)
-		}
-		r.ffmpegCmd.Wait()
-	}
-
-	r.Logger.InfoWith("✓ RTSP trigger stopped")
 	return nil, nil
 }
 
-func (r *rtsp) GetConfig() map[string]interface{} {
+func (r *rtspTrigger) GetConfig() map[string]interface{} {
 	return common.StructureToMap(r.configuration)
 }
-
-func (r *rtsp) allocateEvents(size int) {
-	r.events = make([]Event, size)
-	for i := 0; i < size; i++ {
-		r.events[i] = Event{}
-	}
-}
@@ -1,6 +1,8 @@
 package rtsp
 
 import (
+	"time"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/nuclio/errors"
 	"github.com/nuclio/nuclio/pkg/functionconfig"
@@ -14,18 +16,96 @@ const (
 	DefaultChunkDurationSeconds = 5
 	DefaultMaxBufferSeconds     = 45
 	DefaultTrimSeconds          = 30
+	DefaultTransport            = "tcp"
+	DefaultProcessingFactor     = 1
+	DefaultBackend              = "gortsplib"
+	DefaultHLSAddr              = ":8889"
+	DefaultHLSSegmentSeconds    = 4
+	DefaultHLSPlaylistSize      = 6
+	DefaultFLVAddr              = ":8890"
+	DefaultFLVStreamKey         = "stream"
+	DefaultMode                 = "both"
+	DefaultCodec                = "auto"
+	DefaultPixelFormat          = "yuv420p"
+
+	// Defaults for the Output.kind "hls" sink (hls_output.go): distinct
+	// from HLSAddr/HLSSegmentSeconds/HLSPlaylistSize above, which govern
+	// the raw-track live-view segmenter instead.
+	DefaultOutputHLSAddr           = ":8891"
+	DefaultOutputHLSSegmentSeconds = 4
+	DefaultOutputHLSListSize       = 6
 )
 
+// bytesPerAudioSample is the frame size of the little-endian 16-bit mono PCM
+// produced by MediaPipeline for LPCM/MPEG4Audio/MPEG1Audio tracks.
+const bytesPerAudioSample = 2
+
 type Configuration struct {
 	trigger.Configuration
 
 	RTSPURL              string                 `mapstructure:"rtspUrl"`
+	Backend              string                 `mapstructure:"backend"`   // RTSPClient implementation: "gortsplib" or "ffmpeg"
+	Transport            string                 `mapstructure:"transport"` // "tcp" or "udp"
+	Username             string                 `mapstructure:"username"`
+	Password             string                 `mapstructure:"password"`
+	InsecureSkipVerify   bool                   `mapstructure:"insecureSkipVerify"` // for rtsps:// with self-signed certs
 	BufferSize           int                    `mapstructure:"bufferSize"`
 	SampleRate           int                    `mapstructure:"sampleRate"`
 	ChunkDurationSeconds int                    `mapstructure:"chunkDurationSeconds"`
 	MaxBufferSeconds     int                    `mapstructure:"maxBufferSeconds"`
 	TrimSeconds          int                    `mapstructure:"trimSeconds"`
+	ProcessingFactor     map[string]int         `mapstructure:"processingFactor"` // per track kind ("video"/"audio"): process every Nth frame
+	KeyframesOnly        bool                   `mapstructure:"keyframesOnly"`    // video track: drop inter frames, emit only I-frames
 	Output               map[string]interface{} `mapstructure:"output"`
+
+	// HLS live-view output: an fMP4 HLS rendition of the H264 video track,
+	// served alongside (not instead of) the per-frame Output above.
+	HLSEnabled        bool   `mapstructure:"hlsEnabled"`
+	HLSAddr           string `mapstructure:"hlsAddr"` // HTTP listen address, e.g. ":8889"
+	HLSSegmentSeconds int    `mapstructure:"hlsSegmentSeconds"`
+	HLSPlaylistSize   int    `mapstructure:"hlsPlaylistSize"` // number of segments kept in the live playlist
+
+	// HTTP-FLV live-view output: the same H264 video track muxed into FLV
+	// tags instead of fMP4, served alongside HLSEnabled/Output rather than
+	// instead of them.
+	FLVEnabled   bool   `mapstructure:"flvEnabled"`
+	FLVAddr      string `mapstructure:"flvAddr"`      // HTTP listen address, e.g. ":8890"
+	FLVStreamKey string `mapstructure:"flvStreamKey"` // served at /live/<flvStreamKey>.flv
+
+	// Mode selects which track kinds are demuxed into per-frame/per-chunk
+	// Output events: "audio", "video", or "both" (the default). It doesn't
+	// affect HLSEnabled/FLVEnabled, which only ever consume the video track.
+	Mode string `mapstructure:"mode"`
+
+	// FrameRate throttles video events to a target rate via PTS gating
+	// (dropping frames whose PTS falls inside the previous emission's
+	// interval) instead of ProcessingFactor's fixed every-Nth-frame count.
+	// 0 (the default) leaves ProcessingFactor in charge.
+	FrameRate float64 `mapstructure:"frameRate"`
+
+	// MaxWidth/MaxHeight downscale video frames that exceed them (0 on
+	// either axis means unlimited on that axis), preserving aspect ratio.
+	// Only applies to frames that end up JPEG-encoded (the H264 decode
+	// path, and MJPEG passthrough); encoded-passthrough codecs like VP8/VP9
+	// aren't decoded at all today so can't be resized.
+	MaxWidth  int `mapstructure:"maxWidth"`
+	MaxHeight int `mapstructure:"maxHeight"`
+
+	// Codec and PixelFormat name the desired output encoding for video
+	// frames. Only "auto" (whatever ProcessRTP/ProcessRTPRaw already
+	// produce) and "yuv420p" are implemented today - picking another
+	// encoder or chroma subsampling would need per-codec encoders this
+	// trigger doesn't have, so anything else is rejected at configuration
+	// time rather than silently ignored.
+	Codec       string `mapstructure:"codec"`
+	PixelFormat string `mapstructure:"pixelFormat"`
+
+	// SyncToleranceMS batches an audio chunk and a video frame whose PTS
+	// fall within this many milliseconds of each other into a single
+	// combined event, instead of emitting them as two independent events.
+	// Only meaningful (and only accepted) when Mode is "both"; 0 (the
+	// default) disables batching.
+	SyncToleranceMS int `mapstructure:"syncToleranceMs"`
 }
 
 func NewConfiguration(id string,
@@ -35,7 +115,9 @@ func NewConfiguration(id string,
 	// Defaults
 	newConfiguration := Configuration{
 		RTSPURL:              "",
-		BufferSize:           DefaultMaxBufferSeconds,
+		Backend:              DefaultBackend,
+		Transport:            DefaultTransport,
+		BufferSize:           DefaultBufferSize,
 		SampleRate:           DefaultSampleRate,
 		ChunkDurationSeconds: DefaultChunkDurationSeconds,
 		MaxBufferSeconds:     DefaultMaxBufferSeconds,
@@ -59,6 +141,17 @@ func NewConfiguration(id string,
 		return nil, errors.New("rtspUrl is required")
 	}
 
+	if newConfiguration.Transport != "tcp" && newConfiguration.Transport != "udp" {
+		return nil, errors.New("transport must be 'tcp' or 'udp'")
+	}
+
+	if newConfiguration.Backend == "" {
+		newConfiguration.Backend = DefaultBackend
+	}
+	if newConfiguration.Backend != "gortsplib" && newConfiguration.Backend != "ffmpeg" {
+		return nil, errors.New("backend must be 'gortsplib' or 'ffmpeg'")
+	}
+
 	if newConfiguration.BufferSize <= 0 {
 		newConfiguration.BufferSize = DefaultBufferSize
 	}
@@ -67,5 +160,103 @@ func NewConfiguration(id string,
 		newConfiguration.SampleRate = DefaultSampleRate
 	}
 
+	if newConfiguration.ChunkDurationSeconds <= 0 {
+		newConfiguration.ChunkDurationSeconds = DefaultChunkDurationSeconds
+	}
+
+	if newConfiguration.MaxBufferSeconds <= 0 {
+		newConfiguration.MaxBufferSeconds = DefaultMaxBufferSeconds
+	}
+
+	if newConfiguration.TrimSeconds <= 0 || newConfiguration.TrimSeconds >= newConfiguration.MaxBufferSeconds {
+		newConfiguration.TrimSeconds = DefaultTrimSeconds
+	}
+
+	if newConfiguration.HLSAddr == "" {
+		newConfiguration.HLSAddr = DefaultHLSAddr
+	}
+	if newConfiguration.HLSSegmentSeconds <= 0 {
+		newConfiguration.HLSSegmentSeconds = DefaultHLSSegmentSeconds
+	}
+	if newConfiguration.HLSPlaylistSize <= 0 {
+		newConfiguration.HLSPlaylistSize = DefaultHLSPlaylistSize
+	}
+
+	if newConfiguration.FLVAddr == "" {
+		newConfiguration.FLVAddr = DefaultFLVAddr
+	}
+	if newConfiguration.FLVStreamKey == "" {
+		newConfiguration.FLVStreamKey = DefaultFLVStreamKey
+	}
+
+	if newConfiguration.ProcessingFactor == nil {
+		newConfiguration.ProcessingFactor = map[string]int{}
+	}
+	for _, kind := range []string{"video", "audio"} {
+		if newConfiguration.ProcessingFactor[kind] < 1 {
+			newConfiguration.ProcessingFactor[kind] = DefaultProcessingFactor
+		}
+	}
+
+	if newConfiguration.Mode == "" {
+		newConfiguration.Mode = DefaultMode
+	}
+	if newConfiguration.Mode != "audio" && newConfiguration.Mode != "video" && newConfiguration.Mode != "both" {
+		return nil, errors.New("mode must be 'audio', 'video', or 'both'")
+	}
+
+	if newConfiguration.FrameRate < 0 {
+		return nil, errors.New("frameRate must not be negative")
+	}
+
+	if newConfiguration.MaxWidth < 0 || newConfiguration.MaxHeight < 0 {
+		return nil, errors.New("maxWidth/maxHeight must not be negative")
+	}
+
+	if newConfiguration.Codec == "" {
+		newConfiguration.Codec = DefaultCodec
+	}
+	if newConfiguration.Codec != DefaultCodec {
+		return nil, errors.New("codec: only 'auto' is currently supported")
+	}
+
+	if newConfiguration.PixelFormat == "" {
+		newConfiguration.PixelFormat = DefaultPixelFormat
+	}
+	if newConfiguration.PixelFormat != DefaultPixelFormat {
+		return nil, errors.New("pixelFormat: only 'yuv420p' is currently supported")
+	}
+
+	if newConfiguration.SyncToleranceMS < 0 {
+		return nil, errors.New("syncToleranceMs must not be negative")
+	}
+	if newConfiguration.SyncToleranceMS > 0 && newConfiguration.Mode != "both" {
+		return nil, errors.New("syncToleranceMs only applies when mode is 'both'")
+	}
+
 	return &newConfiguration, nil
 }
+
+// syncTolerance returns SyncToleranceMS as a time.Duration.
+func (c *Configuration) syncTolerance() time.Duration {
+	return time.Duration(c.SyncToleranceMS) * time.Millisecond
+}
+
+// audioChunkBytes returns the chunkBytes argument for the audio track's
+// DataProcessorStream: the number of PCM bytes spanning ChunkDurationSeconds.
+func (c *Configuration) audioChunkBytes() int {
+	return c.SampleRate * bytesPerAudioSample * c.ChunkDurationSeconds
+}
+
+// audioMaxBytes returns the maxBytes argument for the audio track's
+// DataProcessorStream: the rolling buffer cap spanning MaxBufferSeconds.
+func (c *Configuration) audioMaxBytes() int {
+	return c.SampleRate * bytesPerAudioSample * c.MaxBufferSeconds
+}
+
+// audioTrimBytes returns the trimBytes argument for the audio track's
+// DataProcessorStream: the amount trimmed off the head once the rolling
+// buffer exceeds audioMaxBytes, spanning TrimSeconds.
+func (c *Configuration) audioTrimBytes() int {
+	return c.SampleRate * bytesPerAudioSample * c.TrimSeconds
+}
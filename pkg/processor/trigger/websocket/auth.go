@@ -0,0 +1,237 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nuclio/errors"
+)
+
+// Supported values for Configuration.AuthMode.
+const (
+	AuthModeNone      = "none"
+	AuthModeBearerJWT = "bearer_jwt"
+	AuthModeAPIKey    = "api_key"
+)
+
+// DefaultAuthMode disables authentication, matching the trigger's
+// historical behavior.
+const DefaultAuthMode = AuthModeNone
+
+// AuthStatistics counts rejected handshakes.
+type AuthStatistics struct {
+	RejectedTotal uint64
+}
+
+// KeepaliveStatistics counts connections reaped after a pong_timeout
+// expiry, standing in for the websocket_keepalive_timeouts_total metric
+// (labeled by trigger name by whatever scrapes GetKeepaliveStatistics)
+// until a real metrics registry is wired into this trigger.
+type KeepaliveStatistics struct {
+	TimeoutsTotal uint64
+}
+
+// ConnectionStatistics exposes the number of currently open WebSocket
+// connections, surfaced through the trigger's GetConnectionStatistics().
+type ConnectionStatistics struct {
+	ActiveConnections int
+}
+
+// Authenticator validates an incoming handshake request and, on success,
+// returns claims to be surfaced as immutable Event.attributes entries
+// (e.g. "auth.sub", "auth.scope").
+type Authenticator interface {
+	// Authenticate returns the claims for r, or an error. unauthorized
+	// reports whether the failure should be reported as 401 (vs 403).
+	Authenticate(r *http.Request) (claims map[string]interface{}, unauthorized bool, err error)
+}
+
+// NewAuthenticator builds the Authenticator configured by c.
+func NewAuthenticator(c *Configuration) (Authenticator, error) {
+	switch c.AuthMode {
+	case AuthModeNone, "":
+		return &noneAuthenticator{}, nil
+	case AuthModeAPIKey:
+		if len(c.APIKeys) == 0 {
+			return nil, errors.New("api_key auth requires at least one configured key")
+		}
+		return &apiKeyAuthenticator{
+			header: c.APIKeyHeader,
+			query:  c.APIKeyQuery,
+			keys:   c.APIKeys,
+		}, nil
+	case AuthModeBearerJWT:
+		if c.JWKSURL == "" {
+			return nil, errors.New("bearer_jwt auth requires jwks_url")
+		}
+		a := &bearerJWTAuthenticator{
+			jwksURL:  c.JWKSURL,
+			issuer:   c.JWTIssuer,
+			audience: c.JWTAudience,
+		}
+		if err := a.refreshJWKS(); err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch JWKS")
+		}
+		return a, nil
+	default:
+		return nil, errors.Errorf("unsupported auth mode: %s", c.AuthMode)
+	}
+}
+
+// noneAuthenticator accepts every handshake without claims.
+type noneAuthenticator struct{}
+
+func (*noneAuthenticator) Authenticate(*http.Request) (map[string]interface{}, bool, error) {
+	return nil, false, nil
+}
+
+// apiKeyAuthenticator requires a valid key in a header or query parameter.
+type apiKeyAuthenticator struct {
+	header string
+	query  string
+	keys   []string
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (map[string]interface{}, bool, error) {
+	key := r.Header.Get(a.header)
+	if key == "" {
+		key = r.URL.Query().Get(a.query)
+	}
+	if key == "" {
+		return nil, true, errors.New("missing API key")
+	}
+
+	for _, valid := range a.keys {
+		if key == valid {
+			return map[string]interface{}{"auth.sub": "api-key"}, false, nil
+		}
+	}
+
+	return nil, false, errors.New("invalid API key")
+}
+
+// bearerJWTAuthenticator validates a "Bearer" JWT against a JWKS endpoint,
+// checking issuer and audience.
+type bearerJWTAuthenticator struct {
+	jwksURL  string
+	issuer   string
+	audience string
+
+	jwks atomic.Pointer[keyfunc]
+}
+
+// keyfunc resolves a JWT's signing key from the configured JWKS endpoint.
+// It is fetched lazily and cached on the authenticator.
+type keyfunc func(*jwt.Token) (interface{}, error)
+
+// jwk is a single entry of a JSON Web Key Set, restricted to the RSA
+// fields this trigger needs to validate a signature.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// refreshJWKS fetches the JWKS endpoint and rebuilds the cached keyfunc.
+// Called once at authenticator construction; operators who rotate keys
+// are expected to restart the trigger, matching the coarse-grained
+// config-reload model used elsewhere in this package.
+func (a *bearerJWTAuthenticator) refreshJWKS() error {
+	resp, err := http.Get(a.jwksURL) //nolint:gosec // jwksURL is operator-configured
+	if err != nil {
+		return errors.Wrap(err, "Failed to reach JWKS endpoint")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	var jwks struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return errors.Wrap(err, "Failed to decode JWKS response")
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := parseRSAPublicKey(k.N, k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	kf := keyfunc(func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		pub, ok := keys[kid]
+		if !ok {
+			return nil, errors.Errorf("unknown JWKS key id: %s", kid)
+		}
+		return pub, nil
+	})
+	a.jwks.Store(&kf)
+
+	return nil
+}
+
+func parseRSAPublicKey(nEncoded, eEncoded string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(nEncoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode RSA modulus")
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(eEncoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode RSA exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func (a *bearerJWTAuthenticator) Authenticate(r *http.Request) (map[string]interface{}, bool, error) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return nil, true, errors.New("missing bearer token")
+	}
+	raw := strings.TrimPrefix(header, prefix)
+
+	kf := a.jwks.Load()
+	if kf == nil {
+		return nil, true, errors.New("JWKS not yet available")
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(raw, claims, *kf,
+		jwt.WithIssuer(a.issuer),
+		jwt.WithAudience(a.audience))
+	if err != nil || !token.Valid {
+		return nil, true, errors.Wrap(err, "invalid JWT")
+	}
+
+	attributes := map[string]interface{}{}
+	if sub, ok := claims["sub"]; ok {
+		attributes["auth.sub"] = sub
+	}
+	if scope, ok := claims["scope"]; ok {
+		attributes["auth.scope"] = scope
+	}
+
+	return attributes, false, nil
+}
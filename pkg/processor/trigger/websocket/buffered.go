@@ -0,0 +1,132 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// BufferedStatistics exposes Prometheus-style counters for the ring-buffer
+// backpressure mode, surfaced through the trigger's GetStatistics().
+type BufferedStatistics struct {
+	DroppedBytesTotal    uint64
+	DroppedMessagesTotal uint64
+	BufferHighWatermark  int
+}
+
+// DataProcessorBuffered keeps the N most-recent chunks in a bounded ring
+// buffer. When the producer outpaces the consumer, the oldest chunk is
+// dropped and the drop counters are incremented instead of blocking or
+// silently overwriting data like DataProcessorDiscrete and
+// DataProcessorStream do.
+type DataProcessorBuffered struct {
+	lock sync.Mutex
+
+	capacity int
+	chunks   [][]byte
+
+	droppedBytes    uint64
+	droppedMessages uint64
+	highWatermark   int
+
+	output chan *Event
+	stop   chan struct{}
+}
+
+// NewDataProcessorBuffered creates a ring buffer holding at most capacity
+// chunks. Once full, Push drops the oldest chunk to make room for the new
+// one and records the drop in the exported statistics.
+func NewDataProcessorBuffered(capacity int) *DataProcessorBuffered {
+	return &DataProcessorBuffered{
+		capacity: capacity,
+		chunks:   make([][]byte, 0, capacity),
+		output:   make(chan *Event, 8),
+		stop:     make(chan struct{}),
+	}
+}
+
+func (dp *DataProcessorBuffered) Start() {
+	go dp.loop()
+}
+
+func (dp *DataProcessorBuffered) Stop() {
+	close(dp.stop)
+}
+
+// Push appends a chunk to the ring buffer, dropping the oldest chunk when
+// the buffer is at capacity.
+func (dp *DataProcessorBuffered) Push(data []byte) {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	buf := make([]byte, len(data))
+	copy(buf, data)
+
+	if len(dp.chunks) >= dp.capacity {
+		oldest := dp.chunks[0]
+		dp.chunks = dp.chunks[1:]
+		dp.droppedBytes += uint64(len(oldest))
+		dp.droppedMessages++
+	}
+
+	dp.chunks = append(dp.chunks, buf)
+
+	if len(dp.chunks) > dp.highWatermark {
+		dp.highWatermark = len(dp.chunks)
+	}
+}
+
+func (dp *DataProcessorBuffered) loop() {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-dp.stop:
+			return
+		case <-ticker.C:
+			if ev := dp.tryEmit(); ev != nil {
+				dp.output <- ev
+			}
+		}
+	}
+}
+
+// tryEmit pops the oldest buffered chunk, if any, and emits it as an Event.
+func (dp *DataProcessorBuffered) tryEmit() *Event {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	if len(dp.chunks) == 0 {
+		return nil
+	}
+
+	chunk := dp.chunks[0]
+	dp.chunks = dp.chunks[1:]
+
+	return &Event{
+		body:      chunk,
+		timestamp: time.Now(),
+	}
+}
+
+func (dp *DataProcessorBuffered) Output() <-chan *Event {
+	return dp.output
+}
+
+// GetStatistics returns a snapshot of the drop/watermark counters.
+func (dp *DataProcessorBuffered) GetStatistics() BufferedStatistics {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	return BufferedStatistics{
+		DroppedBytesTotal:    dp.droppedBytes,
+		DroppedMessagesTotal: dp.droppedMessages,
+		BufferHighWatermark:  dp.highWatermark,
+	}
+}
@@ -0,0 +1,32 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import "testing"
+
+func BenchmarkDataProcessorBuffered_Push(b *testing.B) {
+	dp := NewDataProcessorBuffered(1024)
+	chunk := make([]byte, 4096)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dp.Push(chunk)
+	}
+}
+
+func BenchmarkFilterRegistry_Route(b *testing.B) {
+	fr := NewFilterRegistry()
+	for i := 0; i < 1000; i++ {
+		fr.NewFilter(nil, "topic", map[string]interface{}{"kind": "alert"})
+	}
+	event := &Event{attributes: map[string]interface{}{"kind": "alert"}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fr.Route(event)
+	}
+}
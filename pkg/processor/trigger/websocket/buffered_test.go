@@ -0,0 +1,62 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDataProcessorBuffered_EmitsPushedChunks(t *testing.T) {
+	dp := NewDataProcessorBuffered(4)
+	dp.Start()
+	defer dp.Stop()
+
+	dp.Push([]byte("hello"))
+
+	select {
+	case ev := <-dp.Output():
+		if string(ev.body) != "hello" {
+			t.Fatalf("expected 'hello', got '%s'", string(ev.body))
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func TestDataProcessorBuffered_DropsOldestWhenFull(t *testing.T) {
+	dp := NewDataProcessorBuffered(2)
+
+	dp.Push([]byte("a"))
+	dp.Push([]byte("b"))
+	dp.Push([]byte("c")) // drops "a"
+
+	stats := dp.GetStatistics()
+	if stats.DroppedMessagesTotal != 1 {
+		t.Fatalf("expected 1 dropped message, got %d", stats.DroppedMessagesTotal)
+	}
+	if stats.DroppedBytesTotal != 1 {
+		t.Fatalf("expected 1 dropped byte, got %d", stats.DroppedBytesTotal)
+	}
+	if stats.BufferHighWatermark != 2 {
+		t.Fatalf("expected high watermark 2, got %d", stats.BufferHighWatermark)
+	}
+
+	dp.Start()
+	defer dp.Stop()
+
+	for _, want := range []string{"b", "c"} {
+		select {
+		case ev := <-dp.Output():
+			if string(ev.body) != want {
+				t.Fatalf("expected %q, got %q", want, ev.body)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timeout waiting for event")
+		}
+	}
+}
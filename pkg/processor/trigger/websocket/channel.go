@@ -0,0 +1,129 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/nuclio/errors"
+)
+
+// Supported values for ChannelConfig.ContentType.
+const (
+	ChannelContentTypeJSON   = "json"
+	ChannelContentTypeBinary = "binary"
+)
+
+// DefaultChannelContentType is assumed for a channel that doesn't set
+// content_type explicitly.
+const DefaultChannelContentType = ChannelContentTypeJSON
+
+// ChannelSubprotocol is the Sec-WebSocket-Protocol value a client offers to
+// opt into the channel-multiplexing layer; include it in
+// Configuration.Subprotocols to negotiate it during the handshake.
+const ChannelSubprotocol = "nuclio.channels.v1"
+
+// ChannelConfig declares one named channel multiplexed onto a single
+// connection. Name is the value frames are tagged with; ContentType
+// selects whether its payloads are framed as JSON channelMessage control
+// frames ("json", the default) or binary frames carrying a one-byte
+// channel-index prefix ("binary").
+type ChannelConfig struct {
+	Name        string `mapstructure:"name"`
+	ContentType string `mapstructure:"content_type"`
+}
+
+// channelMessage is the JSON control-frame shape that multiplexes a named
+// channel's data and subscription control onto one connection, e.g.:
+//
+//	{"type":"data","channel":"control.commands","payload":{"cmd":"start"}}
+//	{"type":"subscribe","channel":"metrics.out"}
+type channelMessage struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// channelMux decodes and encodes the channel-multiplexing subprotocol for
+// one trigger: each inbound frame is either a binary payload prefixed with
+// its channel's index (for channels declared "binary"), or a JSON
+// channelMessage (for "data"/"subscribe"/"unsubscribe", and for any "json"
+// channel's payload).
+type channelMux struct {
+	channels []ChannelConfig
+	byName   map[string]int
+}
+
+// newChannelMux builds a channelMux from the configured channel list.
+func newChannelMux(channels []ChannelConfig) *channelMux {
+	byName := make(map[string]int, len(channels))
+	for i, c := range channels {
+		byName[c.Name] = i
+	}
+	return &channelMux{channels: channels, byName: byName}
+}
+
+// contentType returns channel's configured ContentType, defaulting to
+// DefaultChannelContentType if unset.
+func (m *channelMux) contentType(channel string) string {
+	if idx, ok := m.byName[channel]; ok && m.channels[idx].ContentType != "" {
+		return m.channels[idx].ContentType
+	}
+	return DefaultChannelContentType
+}
+
+// MIMEType returns the content type to stamp on an Event decoded from
+// channel, mirroring the Codec implementations' own contentType choices.
+func (m *channelMux) MIMEType(channel string) string {
+	if m.contentType(channel) == ChannelContentTypeBinary {
+		return "application/octet-stream"
+	}
+	return "application/json"
+}
+
+// Decode extracts the channelMessage carried by one inbound frame: either
+// a binary frame whose first byte selects a "binary" channel by index, or
+// a JSON channelMessage. It returns an error if the frame names a channel
+// this mux wasn't configured with.
+func (m *channelMux) Decode(raw []byte) (*channelMessage, error) {
+	if len(raw) > 0 && int(raw[0]) < len(m.channels) && m.channels[raw[0]].ContentType == ChannelContentTypeBinary {
+		return &channelMessage{Type: "data", Channel: m.channels[raw[0]].Name, Payload: raw[1:]}, nil
+	}
+
+	var msg channelMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode channel frame")
+	}
+
+	if _, ok := m.byName[msg.Channel]; !ok {
+		return nil, errors.Errorf("unknown channel: %s", msg.Channel)
+	}
+
+	return &msg, nil
+}
+
+// Encode frames payload for delivery on channel, matching whichever wire
+// format that channel was declared with.
+func (m *channelMux) Encode(channel string, payload []byte) ([]byte, error) {
+	idx, ok := m.byName[channel]
+	if !ok {
+		return nil, errors.Errorf("unknown channel: %s", channel)
+	}
+
+	if m.channels[idx].ContentType == ChannelContentTypeBinary {
+		framed := make([]byte, 0, len(payload)+1)
+		framed = append(framed, byte(idx))
+		framed = append(framed, payload...)
+		return framed, nil
+	}
+
+	encoded, err := json.Marshal(channelMessage{Type: "data", Channel: channel, Payload: payload})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode channel frame")
+	}
+	return encoded, nil
+}
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"testing"
+)
+
+func TestChannelMuxJSONRoundtrip(t *testing.T) {
+	mux := newChannelMux([]ChannelConfig{{Name: "control.commands"}})
+
+	raw := []byte(`{"type":"data","channel":"control.commands","payload":{"cmd":"start"}}`)
+	msg, err := mux.Decode(raw)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if msg.Channel != "control.commands" {
+		t.Fatalf("expected channel %q, got %q", "control.commands", msg.Channel)
+	}
+
+	encoded, err := mux.Encode("control.commands", []byte(`{"status":"started"}`))
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+
+	redecoded, err := mux.Decode(encoded)
+	if err != nil {
+		t.Fatalf("unexpected re-decode error: %v", err)
+	}
+	if redecoded.Channel != "control.commands" {
+		t.Fatalf("expected re-decoded channel %q, got %q", "control.commands", redecoded.Channel)
+	}
+	if string(redecoded.Payload) != `{"status":"started"}` {
+		t.Fatalf("expected payload %q, got %q", `{"status":"started"}`, redecoded.Payload)
+	}
+}
+
+func TestChannelMuxBinaryPrefix(t *testing.T) {
+	mux := newChannelMux([]ChannelConfig{
+		{Name: "control.commands"},
+		{Name: "audio.pcm", ContentType: ChannelContentTypeBinary},
+	})
+
+	raw := append([]byte{1}, []byte{0x01, 0x02, 0x03}...)
+	msg, err := mux.Decode(raw)
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if msg.Channel != "audio.pcm" {
+		t.Fatalf("expected channel %q, got %q", "audio.pcm", msg.Channel)
+	}
+	if len(msg.Payload) != 3 {
+		t.Fatalf("expected 3-byte payload, got %d", len(msg.Payload))
+	}
+
+	encoded, err := mux.Encode("audio.pcm", []byte{0x04, 0x05})
+	if err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	if encoded[0] != 1 {
+		t.Fatalf("expected channel-index prefix 1, got %d", encoded[0])
+	}
+}
+
+func TestChannelMuxUnknownChannel(t *testing.T) {
+	mux := newChannelMux([]ChannelConfig{{Name: "control.commands"}})
+
+	if _, err := mux.Decode([]byte(`{"type":"data","channel":"unknown","payload":1}`)); err == nil {
+		t.Fatal("expected error decoding frame for an unconfigured channel")
+	}
+
+	if _, err := mux.Encode("unknown", []byte("x")); err == nil {
+		t.Fatal("expected error encoding a frame for an unconfigured channel")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nuclio/errors"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Supported values for Configuration.MessageFormat.
+const (
+	MessageFormatRaw         = "raw"
+	MessageFormatJSON        = "json"
+	MessageFormatMsgPack     = "msgpack"
+	MessageFormatCloudEvents = "cloudevents-v1"
+)
+
+// DefaultMessageFormat preserves the historical behavior of treating
+// inbound frames as opaque bytes.
+const DefaultMessageFormat = MessageFormatRaw
+
+// decodedMessage is the structured result of decoding an inbound frame.
+type decodedMessage struct {
+	body        []byte
+	attributes  map[string]interface{}
+	contentType string
+}
+
+// Codec decodes an inbound WebSocket frame into a structured message and
+// symmetrically encodes an outbound one, so the trigger can interoperate
+// with a given wire format without per-function glue code.
+type Codec interface {
+	Decode(raw []byte) (*decodedMessage, error)
+	Encode(body []byte, attributes map[string]interface{}) ([]byte, error)
+}
+
+// NewCodec returns the Codec registered for messageFormat.
+func NewCodec(messageFormat string) (Codec, error) {
+	switch messageFormat {
+	case MessageFormatRaw, "":
+		return &rawCodec{}, nil
+	case MessageFormatJSON:
+		return &jsonCodec{}, nil
+	case MessageFormatMsgPack:
+		return &msgpackCodec{}, nil
+	case MessageFormatCloudEvents:
+		return &cloudEventsCodec{}, nil
+	default:
+		return nil, errors.Errorf("unsupported message_format: %s", messageFormat)
+	}
+}
+
+// rawCodec treats frames as opaque bytes, matching the trigger's historical
+// behavior.
+type rawCodec struct{}
+
+func (*rawCodec) Decode(raw []byte) (*decodedMessage, error) {
+	return &decodedMessage{
+		body:        raw,
+		contentType: "application/octet-stream",
+	}, nil
+}
+
+func (*rawCodec) Encode(body []byte, _ map[string]interface{}) ([]byte, error) {
+	return body, nil
+}
+
+// jsonCodec maps top-level JSON keys into attributes and keeps the raw
+// frame as the body.
+type jsonCodec struct{}
+
+func (*jsonCodec) Decode(raw []byte) (*decodedMessage, error) {
+	attributes := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &attributes); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode JSON message")
+	}
+
+	return &decodedMessage{
+		body:        raw,
+		attributes:  attributes,
+		contentType: "application/json",
+	}, nil
+}
+
+func (*jsonCodec) Encode(body []byte, attributes map[string]interface{}) ([]byte, error) {
+	if len(attributes) == 0 {
+		return body, nil
+	}
+
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode JSON message")
+	}
+	return encoded, nil
+}
+
+// msgpackCodec maps top-level MessagePack map keys into attributes.
+type msgpackCodec struct{}
+
+func (*msgpackCodec) Decode(raw []byte) (*decodedMessage, error) {
+	attributes := map[string]interface{}{}
+	if err := msgpack.Unmarshal(raw, &attributes); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode MsgPack message")
+	}
+
+	return &decodedMessage{
+		body:        raw,
+		attributes:  attributes,
+		contentType: "application/msgpack",
+	}, nil
+}
+
+func (*msgpackCodec) Encode(body []byte, attributes map[string]interface{}) ([]byte, error) {
+	if len(attributes) == 0 {
+		return body, nil
+	}
+
+	encoded, err := msgpack.Marshal(attributes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode MsgPack message")
+	}
+	return encoded, nil
+}
+
+// cloudEventsCodec decodes structured-mode CloudEvents JSON, populating
+// attributes from the "ce-*" fields and deriving the content type from
+// "datacontenttype".
+type cloudEventsCodec struct{}
+
+func (*cloudEventsCodec) Decode(raw []byte) (*decodedMessage, error) {
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode CloudEvents message")
+	}
+
+	attributes := map[string]interface{}{}
+	contentType := "application/json"
+	var body interface{} = raw
+
+	for key, value := range envelope {
+		switch key {
+		case "data":
+			body = value
+		case "datacontenttype":
+			if strVal, ok := value.(string); ok {
+				contentType = strVal
+			}
+		default:
+			attributes["ce-"+key] = value
+		}
+	}
+
+	bodyBytes, ok := body.([]byte)
+	if !ok {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to encode CloudEvents data field")
+		}
+		bodyBytes = encoded
+	}
+
+	return &decodedMessage{
+		body:        bodyBytes,
+		attributes:  attributes,
+		contentType: contentType,
+	}, nil
+}
+
+func (*cloudEventsCodec) Encode(body []byte, attributes map[string]interface{}) ([]byte, error) {
+	envelope := map[string]interface{}{
+		"data": json.RawMessage(body),
+	}
+	for key, value := range attributes {
+		envelope[strings.TrimPrefix(key, "ce-")] = value
+	}
+
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode CloudEvents message")
+	}
+	return encoded, nil
+}
@@ -0,0 +1,191 @@
+package websocket
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nuclio/logger"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/processor"
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/nuclio/nuclio/pkg/processor/worker"
+	nucliozap "github.com/nuclio/zap"
+	"github.com/stretchr/testify/suite"
+)
+
+// WebsocketConformanceTestSuite exercises the trigger's upgrader with a
+// handful of client-role protocol cases in the spirit of the Autobahn
+// WebSocket test suite: fragmented messages, invalid UTF-8 in a text frame,
+// an unexpected close code, and pings interleaved with data. It does not
+// replay the full Autobahn case set, but covers the gaps the happy-path
+// integration tests (trigger_integration_test.go) don't.
+type WebsocketConformanceTestSuite struct {
+	suite.Suite
+	logger                logger.Logger
+	namedWorkerAllocators *worker.AllocatorSyncMap
+	serverAddr            string
+}
+
+func (suite *WebsocketConformanceTestSuite) SetupTest() {
+	var err error
+	suite.logger, err = nucliozap.NewNuclioZapTest("conformance-test")
+	suite.Require().NoError(err)
+
+	suite.namedWorkerAllocators = worker.NewAllocatorSyncMap()
+	allocator, _ := newMockWorkerAllocator()
+	suite.namedWorkerAllocators.Store("mock-allocator", allocator)
+
+	// Distinct fixed port from trigger_integration_test.go to run in parallel.
+	suite.serverAddr = ":18081"
+}
+
+func (suite *WebsocketConformanceTestSuite) startTrigger(name string, extraAttributes map[string]interface{}) *websocket_trigger {
+	attributes := map[string]interface{}{
+		"websocket_addr": suite.serverAddr,
+	}
+	for k, v := range extraAttributes {
+		attributes[k] = v
+	}
+
+	triggerConfig := &functionconfig.Trigger{
+		Kind:                "websocket",
+		Name:                name,
+		WorkerAllocatorName: "mock-allocator",
+		Attributes:          attributes,
+	}
+
+	runtimeConfig := &runtime.Configuration{
+		Configuration: &processor.Configuration{
+			Config: functionconfig.Config{
+				Spec: functionconfig.Spec{
+					Runtime: "python",
+					Handler: "test_handler:handler",
+				},
+			},
+		},
+	}
+
+	f := &factory{}
+	triggerInstance, err := f.Create(suite.logger, name, triggerConfig, runtimeConfig, suite.namedWorkerAllocators, nil)
+	suite.Require().NoError(err)
+
+	wsTrigger := triggerInstance.(*websocket_trigger)
+	suite.Require().NoError(wsTrigger.Start(nil))
+
+	time.Sleep(200 * time.Millisecond)
+	return wsTrigger
+}
+
+func (suite *WebsocketConformanceTestSuite) dial() *websocket.Conn {
+	u := url.URL{Scheme: "ws", Host: "localhost" + suite.serverAddr, Path: "/ws"}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	suite.Require().NoError(err)
+	return conn
+}
+
+// A message fragmented across multiple frames (NextWriter + several Write
+// calls) must be reassembled by the upgrader's default framing before
+// reaching the trigger's codec, rather than being delivered frame-by-frame.
+func (suite *WebsocketConformanceTestSuite) TestFragmentedMessageIsReassembled() {
+	wsTrigger := suite.startTrigger("test-websocket-conformance-fragmented", nil)
+	defer wsTrigger.Stop(false)
+
+	conn := suite.dial()
+	defer conn.Close()
+
+	w, err := conn.NextWriter(websocket.BinaryMessage)
+	suite.Require().NoError(err)
+	_, err = w.Write([]byte("hello "))
+	suite.Require().NoError(err)
+	_, err = w.Write([]byte("fragmented "))
+	suite.Require().NoError(err)
+	_, err = w.Write([]byte("world"))
+	suite.Require().NoError(err)
+	suite.Require().NoError(w.Close())
+
+	// The connection staying open and accepting further writes is evidence
+	// the upgrader consumed the fragmented message as a single frame rather
+	// than erroring out on it.
+	time.Sleep(100 * time.Millisecond)
+	suite.NoError(conn.WriteMessage(websocket.BinaryMessage, []byte("still alive")))
+}
+
+// Gorilla does not itself validate text-frame payloads as UTF-8 (RFC 6455
+// leaves that to the application); the trigger should still accept the
+// frame rather than panicking or dropping the connection.
+func (suite *WebsocketConformanceTestSuite) TestInvalidUTF8TextFrameDoesNotBreakConnection() {
+	wsTrigger := suite.startTrigger("test-websocket-conformance-utf8", nil)
+	defer wsTrigger.Stop(false)
+
+	conn := suite.dial()
+	defer conn.Close()
+
+	invalidUTF8 := []byte{0xff, 0xfe, 0xfd}
+	suite.NoError(conn.WriteMessage(websocket.TextMessage, invalidUTF8))
+
+	time.Sleep(100 * time.Millisecond)
+	suite.NoError(conn.WriteMessage(websocket.TextMessage, []byte("still alive")))
+}
+
+// A close frame carrying a code other than 1000 (normal closure) should
+// still be handled as a clean disconnect by the upgrader's ReadMessage loop
+// (handleWS returns), not treated as an unrecovered error.
+func (suite *WebsocketConformanceTestSuite) TestUnexpectedCloseCodeIsHandled() {
+	wsTrigger := suite.startTrigger("test-websocket-conformance-close", nil)
+	defer wsTrigger.Stop(false)
+
+	conn := suite.dial()
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "going away unexpectedly")
+	suite.NoError(conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second)))
+	conn.Close()
+
+	// Reconnecting on the same address proves the trigger's server is still
+	// serving after the abnormal close, rather than having wedged.
+	time.Sleep(100 * time.Millisecond)
+	reconn := suite.dial()
+	defer reconn.Close()
+	suite.NoError(reconn.WriteMessage(websocket.BinaryMessage, []byte("reconnected")))
+}
+
+// Server-initiated pings (from the trigger's pingLoop) interleaved with
+// ordinary data frames must not corrupt the data stream; gorilla's default
+// ping handler answers automatically without the caller reading a separate
+// "ping event".
+func (suite *WebsocketConformanceTestSuite) TestPingInterleavedWithData() {
+	wsTrigger := suite.startTrigger("test-websocket-conformance-ping", map[string]interface{}{"ping_interval_seconds": 1})
+	defer wsTrigger.Stop(false)
+
+	conn := suite.dial()
+	defer conn.Close()
+
+	pongReceived := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		pongReceived <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, nil, time.Now().Add(time.Second))
+	})
+
+	suite.NoError(conn.WriteMessage(websocket.BinaryMessage, []byte("before ping")))
+
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pongReceived:
+	case <-time.After(2 * time.Second):
+		suite.Fail("Timeout waiting for trigger ping")
+	}
+
+	suite.NoError(conn.WriteMessage(websocket.BinaryMessage, []byte("after ping")))
+}
+
+func TestWebsocketConformanceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebsocketConformanceTestSuite))
+}
@@ -0,0 +1,45 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn wraps one active connection's *websocket.Conn with a write mutex:
+// gorilla/websocket requires at most one concurrent writer, but this
+// trigger now writes to a connection from more than one place - process()'s
+// reply-per-message path, FilterRegistry-routed broadcasts, and a handler
+// pushing a server-initiated frame asynchronously via Event.Conn - so every
+// write goes through Send instead of calling the underlying conn directly.
+type Conn struct {
+	mu           sync.Mutex
+	conn         *websocket.Conn
+	writeTimeout time.Duration // Configuration.WriteTimeoutSeconds; 0 disables
+}
+
+func newConn(c *websocket.Conn, writeTimeout time.Duration) *Conn {
+	return &Conn{conn: c, writeTimeout: writeTimeout}
+}
+
+// Send writes messageType/data to the underlying connection, serialized
+// against any other Send call for this Conn. When writeTimeout is set, a
+// write that can't complete within it (e.g. a stalled peer not draining its
+// TCP receive buffer) fails instead of blocking Send forever.
+func (c *Conn) Send(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writeTimeout > 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return err
+		}
+	}
+	return c.conn.WriteMessage(messageType, data)
+}
@@ -0,0 +1,78 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConnSendConcurrent exercises Send from many goroutines at once: gorilla
+// requires at most one concurrent writer per connection, so this only passes
+// if Conn's mutex is actually serializing the underlying WriteMessage calls.
+func TestConnSendConcurrent(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverConn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer serverConn.Close() // nolint: errcheck
+
+		conn := newConn(serverConn, 0)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(n int) {
+				defer wg.Done()
+				_ = conn.Send(websocket.TextMessage, []byte{byte(n)})
+			}(i)
+		}
+		wg.Wait()
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[len("http"):]
+	clientConn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer clientConn.Close() // nolint: errcheck
+
+	for i := 0; i < 20; i++ {
+		_, _, err := clientConn.ReadMessage()
+		require.NoError(t, err)
+	}
+}
+
+// TestConnSendWriteTimeout exercises the writeTimeout path directly: with no
+// peer reading, a write deadline in the past must fail WriteMessage rather
+// than block.
+func TestConnSendWriteTimeout(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	done := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverConn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer serverConn.Close() // nolint: errcheck
+
+		conn := newConn(serverConn, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		done <- conn.Send(websocket.TextMessage, []byte("hello"))
+	}))
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[len("http"):]
+	clientConn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer clientConn.Close() // nolint: errcheck
+
+	require.Error(t, <-done)
+}
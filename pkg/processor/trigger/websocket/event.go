@@ -16,14 +16,50 @@ import (
 // Event wraps a single message received over WebSocket
 type Event struct {
 	nuclio.AbstractEvent
-	body       []byte
-	attributes map[string]interface{}
-	timestamp  time.Time
+	body        []byte
+	attributes  map[string]interface{}
+	timestamp   time.Time
+	contentType string
+
+	// ackFn, if set, is called by Ack to tell the producing DataProcessor
+	// this event's bytes have been consumed and can be released from
+	// whatever inflight budget it tracks. Events produced by processors
+	// that don't track one (e.g. DataProcessorDiscrete) leave it nil, and
+	// Ack is then a no-op.
+	ackFn func()
+
+	// conn is the connection whose own per-session processor produced this
+	// event. A handler that type-asserts its nuclio.Event to *Event can
+	// call Conn().Send to push a server-initiated frame asynchronously,
+	// instead of only being able to reply once HandleEvent returns. nil if
+	// no connection was active when the event was produced (e.g. in tests).
+	conn *Conn
+}
+
+// Conn returns the connection whose session produced this event, or nil if
+// none was active when it was produced.
+func (e *Event) Conn() *Conn {
+	return e.conn
+}
+
+// Ack tells the event's producer that its body has been consumed, releasing
+// its bytes from whatever inflight budget the producer tracks (currently
+// only DataProcessorStream does). Safe to call on events that don't track
+// one, and safe to call more than once.
+func (e *Event) Ack() {
+	if e.ackFn != nil {
+		e.ackFn()
+	}
 }
 
-// GetContentType returns the content type of the WebSocket message
+// GetContentType returns the content type of the WebSocket message, as
+// determined by the Codec that decoded it. Defaults to
+// "application/octet-stream" for events built before a content type was set.
 func (e *Event) GetContentType() string {
-	return "application/octet-stream"
+	if e.contentType == "" {
+		return "application/octet-stream"
+	}
+	return e.contentType
 }
 
 // GetBody returns the WebSocket message data
@@ -0,0 +1,189 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// controlMessage is the envelope for client-sent control frames
+// multiplexed on the same socket as data frames, e.g.:
+//
+//	{"op":"subscribe","topic":"foo","filter":{"key":"value"}}
+//	{"op":"unsubscribe","id":"..."}
+type controlMessage struct {
+	Op     string                 `json:"op"`
+	ID     string                 `json:"id,omitempty"`
+	Topic  string                 `json:"topic,omitempty"`
+	Filter map[string]interface{} `json:"filter,omitempty"`
+}
+
+// controlMessageResponse is the server's reply to a {"op":"getLogs",...}
+// control frame.
+type controlMessageResponse struct {
+	Op   string     `json:"op"`
+	ID   string     `json:"id,omitempty"`
+	Logs []logEntry `json:"logs"`
+}
+
+// logEntry is one retained event in a controlMessageResponse.
+type logEntry struct {
+	Body       []byte                 `json:"body"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// filter matches events for a single subscription installed by a
+// connection: an event matches when every key/value pair in match is
+// present and equal in the event's attributes.
+type filter struct {
+	id    string
+	conn  *websocket.Conn
+	topic string
+	match map[string]interface{}
+	logs  []*Event
+}
+
+// matches reports whether attributes satisfies this filter.
+func (f *filter) matches(attributes map[string]interface{}) bool {
+	for key, want := range f.match {
+		got, ok := attributes[key]
+		if !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterRegistry tracks per-connection filters, modeled on the JSON-RPC
+// filter subsystem (newBlockFilter / newFilter): each connection installs
+// zero or more filters, and process() consults the registry before
+// broadcasting so a connection only receives events matching one of its
+// filters' topic/attributes.
+type FilterRegistry struct {
+	lock    sync.Mutex
+	filters map[string]*filter
+	byConn  map[*websocket.Conn]map[string]struct{}
+}
+
+// NewFilterRegistry creates an empty filter registry.
+func NewFilterRegistry() *FilterRegistry {
+	return &FilterRegistry{
+		filters: map[string]*filter{},
+		byConn:  map[*websocket.Conn]map[string]struct{}{},
+	}
+}
+
+// NewFilter installs a filter for conn and returns its id.
+func (fr *FilterRegistry) NewFilter(conn *websocket.Conn, topic string, match map[string]interface{}) string {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	id := newFilterID()
+	fr.filters[id] = &filter{id: id, conn: conn, topic: topic, match: match}
+
+	if fr.byConn[conn] == nil {
+		fr.byConn[conn] = map[string]struct{}{}
+	}
+	fr.byConn[conn][id] = struct{}{}
+
+	return id
+}
+
+// UninstallFilter removes a previously installed filter. It reports
+// whether a filter with that id existed.
+func (fr *FilterRegistry) UninstallFilter(id string) bool {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	f, ok := fr.filters[id]
+	if !ok {
+		return false
+	}
+	delete(fr.filters, id)
+	delete(fr.byConn[f.conn], id)
+
+	return true
+}
+
+// UninstallByTopic removes every filter conn installed under topic. Used by
+// the channel subprotocol's "unsubscribe" control frame, which names the
+// channel it wants to stop receiving rather than a filter id.
+func (fr *FilterRegistry) UninstallByTopic(conn *websocket.Conn, topic string) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	for id := range fr.byConn[conn] {
+		if f, ok := fr.filters[id]; ok && f.topic == topic {
+			delete(fr.filters, id)
+			delete(fr.byConn[conn], id)
+		}
+	}
+}
+
+// UninstallConn removes every filter owned by conn, e.g. on disconnect.
+func (fr *FilterRegistry) UninstallConn(conn *websocket.Conn) {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	for id := range fr.byConn[conn] {
+		delete(fr.filters, id)
+	}
+	delete(fr.byConn, conn)
+}
+
+// GetLogs returns events matched and retained by a filter since the last
+// GetLogs call (or since the filter was installed, for the first call),
+// clearing the retained set - the same drain-and-clear contract as
+// eth_getFilterChanges, so a connection that polls GetLogs periodically
+// bounds the filter's retained events instead of letting them accumulate
+// forever.
+func (fr *FilterRegistry) GetLogs(id string) []*Event {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	f, ok := fr.filters[id]
+	if !ok {
+		return nil
+	}
+	logs := f.logs
+	f.logs = nil
+	return logs
+}
+
+// Route returns, for a given produced event, the set of connections whose
+// installed filters match it, retaining the event on each matching filter
+// for a subsequent GetLogs call.
+func (fr *FilterRegistry) Route(event *Event) []*websocket.Conn {
+	fr.lock.Lock()
+	defer fr.lock.Unlock()
+
+	seen := map[*websocket.Conn]struct{}{}
+	var conns []*websocket.Conn
+
+	for _, f := range fr.filters {
+		if !f.matches(event.attributes) {
+			continue
+		}
+		f.logs = append(f.logs, event)
+		if _, ok := seen[f.conn]; !ok {
+			seen[f.conn] = struct{}{}
+			conns = append(conns, f.conn)
+		}
+	}
+
+	return conns
+}
+
+func newFilterID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,52 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"testing"
+)
+
+func TestFilterRegistry_RouteMatchesOnAttributes(t *testing.T) {
+	fr := NewFilterRegistry()
+
+	id := fr.NewFilter(nil, "foo", map[string]interface{}{"kind": "alert"})
+
+	matching := &Event{attributes: map[string]interface{}{"kind": "alert"}}
+	nonMatching := &Event{attributes: map[string]interface{}{"kind": "info"}}
+
+	if conns := fr.Route(matching); len(conns) != 1 {
+		t.Fatalf("expected 1 matching connection, got %d", len(conns))
+	}
+	if conns := fr.Route(nonMatching); len(conns) != 0 {
+		t.Fatalf("expected 0 matching connections, got %d", len(conns))
+	}
+
+	logs := fr.GetLogs(id)
+	if len(logs) != 1 || logs[0] != matching {
+		t.Fatalf("expected matching event retained in logs, got %v", logs)
+	}
+
+	if logs := fr.GetLogs(id); len(logs) != 0 {
+		t.Fatalf("expected GetLogs to drain the filter, got %v", logs)
+	}
+}
+
+func TestFilterRegistry_UninstallFilter(t *testing.T) {
+	fr := NewFilterRegistry()
+
+	id := fr.NewFilter(nil, "foo", nil)
+	if !fr.UninstallFilter(id) {
+		t.Fatal("expected UninstallFilter to succeed")
+	}
+	if fr.UninstallFilter(id) {
+		t.Fatal("expected second UninstallFilter to fail")
+	}
+
+	if conns := fr.Route(&Event{}); len(conns) != 0 {
+		t.Fatalf("expected no connections after uninstall, got %d", len(conns))
+	}
+}
@@ -0,0 +1,149 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+	pb "github.com/scc-digitalhub/digitalhub-serverless/pkg/proto/inference/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// inferenceStreamForwarder forwards Events emitted by this trigger's active
+// DataProcessor onto an OpenInference trigger's ModelStreamInfer RPC,
+// instead of (or alongside) this trigger's own worker dispatch - wired up
+// by Start when Configuration.InferenceStreamEndpoint is set. Each chunk is
+// sent as a single BYTES input tensor named "input"; responses are logged
+// rather than routed back to the originating connection, since that would
+// require threading per-connection routing through to a cross-trigger
+// response, which is out of scope here.
+type inferenceStreamForwarder struct {
+	logger logger.Logger
+
+	conn   *grpc.ClientConn
+	stream pb.GRPCInferenceService_ModelStreamInferClient
+
+	backpressureMode string
+	pending          chan *pb.ModelInferRequest
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newInferenceStreamForwarder dials endpoint and opens the ModelStreamInfer
+// stream. The connection is plaintext: this is meant for forwarding between
+// triggers on a trusted internal network, matching the scope of the
+// Configuration field that enables it.
+func newInferenceStreamForwarder(
+	logger logger.Logger, endpoint, backpressureMode string, queueSize int) (*inferenceStreamForwarder, error) {
+
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to dial inference_stream_endpoint")
+	}
+
+	stream, err := pb.NewGRPCInferenceServiceClient(conn).ModelStreamInfer(context.Background())
+	if err != nil {
+		_ = conn.Close()
+		return nil, errors.Wrap(err, "Failed to open ModelStreamInfer stream")
+	}
+
+	f := &inferenceStreamForwarder{
+		logger:           logger,
+		conn:             conn,
+		stream:           stream,
+		backpressureMode: backpressureMode,
+		pending:          make(chan *pb.ModelInferRequest, queueSize),
+		stop:             make(chan struct{}),
+	}
+
+	f.wg.Add(2)
+	go f.sendLoop()
+	go f.recvLoop()
+
+	return f, nil
+}
+
+// Forward enqueues event as a ModelStreamInfer request, applying
+// backpressureMode when the queue is full: "block" waits for room,
+// "drop-oldest" discards the oldest queued chunk to make room for event.
+func (f *inferenceStreamForwarder) Forward(event *Event) {
+	req := &pb.ModelInferRequest{
+		Id: newFilterID(),
+		Inputs: []*pb.ModelInferRequest_InferInputTensor{
+			{
+				Name:     "input",
+				Datatype: "BYTES",
+				Shape:    []int64{int64(len(event.body))},
+				Contents: &pb.InferTensorContents{BytesContents: [][]byte{event.body}},
+			},
+		},
+	}
+
+	if f.backpressureMode == InferenceStreamBackpressureBlock {
+		select {
+		case f.pending <- req:
+		case <-f.stop:
+		}
+		return
+	}
+
+	for {
+		select {
+		case f.pending <- req:
+			return
+		default:
+		}
+
+		select {
+		case <-f.pending:
+		case <-f.stop:
+			return
+		default:
+		}
+	}
+}
+
+func (f *inferenceStreamForwarder) sendLoop() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case <-f.stop:
+			return
+		case req := <-f.pending:
+			if err := f.stream.Send(req); err != nil {
+				f.logger.WarnWith("Failed to forward chunk to inference stream", "error", err)
+				return
+			}
+		}
+	}
+}
+
+func (f *inferenceStreamForwarder) recvLoop() {
+	defer f.wg.Done()
+
+	for {
+		resp, err := f.stream.Recv()
+		if err != nil {
+			return
+		}
+		f.logger.DebugWith("Received inference stream response", "requestID", resp.Id, "modelName", resp.ModelName)
+	}
+}
+
+// Stop tears down the forwarder's stream and connection.
+func (f *inferenceStreamForwarder) Stop() {
+	close(f.stop)
+	_ = f.stream.CloseSend()
+	_ = f.conn.Close()
+	f.wg.Wait()
+}
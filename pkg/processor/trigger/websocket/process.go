@@ -0,0 +1,226 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+)
+
+// Supported values for Configuration.Mode.
+const (
+	ModeHandler = "handler"
+	ModeProcess = "process"
+)
+
+// DefaultMode preserves the trigger's historical behavior of forwarding
+// frames to the function handler.
+const DefaultMode = ModeHandler
+
+// processShutdownGrace bounds how long stop waits for a SIGTERM'd
+// process-mode child to exit before escalating to SIGKILL.
+const processShutdownGrace = 5 * time.Second
+
+// processSession wires one accepted connection's inbound/outbound frames
+// to a spawned child process's stdin/stdout, websocketd-style: each
+// connection gets its own process, torn down on disconnect or trigger
+// Stop.
+type processSession struct {
+	logger logger.Logger
+	conn   *Conn
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	binary bool
+}
+
+// write feeds one inbound WebSocket frame to the child's stdin. In
+// line-delimited (non-binary) mode a newline is appended, so a child
+// reading with a line-buffered scanner sees one line per frame.
+func (s *processSession) write(data []byte) {
+	if s.stdin == nil {
+		return
+	}
+	if _, err := s.stdin.Write(data); err != nil {
+		s.logger.WarnWith("Failed to write to process-mode child stdin", "error", err)
+		return
+	}
+	if !s.binary {
+		_, _ = s.stdin.Write([]byte("\n"))
+	}
+}
+
+// pumpStdout streams the child's stdout back as outbound WebSocket frames
+// until it closes or a send fails, then releases launcher's fork slot.
+func (s *processSession) pumpStdout(stdout io.ReadCloser, launcher *processLauncher) {
+	defer atomic.AddInt64(&launcher.forks, -1)
+
+	if s.binary {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				chunk := append([]byte(nil), buf[:n]...)
+				if sendErr := s.conn.Send(websocket.BinaryMessage, chunk); sendErr != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := s.conn.Send(websocket.TextMessage, scanner.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+// stop closes the child's stdin, then SIGTERMs it and escalates to SIGKILL
+// if it hasn't exited within processShutdownGrace.
+func (s *processSession) stop() {
+	if s.stdin != nil {
+		_ = s.stdin.Close()
+	}
+	if s.cmd == nil || s.cmd.Process == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = s.cmd.Wait()
+		close(done)
+	}()
+
+	_ = s.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-done:
+	case <-time.After(processShutdownGrace):
+		_ = s.cmd.Process.Kill()
+		<-done
+	}
+}
+
+// processLauncher spawns Configuration.ProcessCommand for each connection
+// accepted while Mode is "process", enforcing MaxForks across all of them.
+type processLauncher struct {
+	command string
+	args    []string
+	env     map[string]string
+	binary  bool
+
+	maxForks int64
+	forks    int64 // atomic count of currently running children
+}
+
+func newProcessLauncher(c *Configuration) *processLauncher {
+	return &processLauncher{
+		command:  c.ProcessCommand,
+		args:     c.ProcessArgs,
+		env:      c.ProcessEnv,
+		binary:   c.ProcessBinary,
+		maxForks: int64(c.MaxForks),
+	}
+}
+
+// start spawns the configured command for one connection, wiring stdout to
+// conn via a new goroutine, and returns the session tracking it. It fails
+// if MaxForks is already at its cap.
+//
+// The fork slot is reserved with a single atomic increment up front, before
+// checking the cap, so two concurrent callers can't both pass a
+// read-then-increment check and together exceed MaxForks; a reservation
+// that turns out to push past the cap, or that a later setup step fails
+// after, is released with a matching decrement.
+func (l *processLauncher) start(parentLogger logger.Logger, conn *Conn, r *http.Request) (*processSession, error) {
+	forks := atomic.AddInt64(&l.forks, 1)
+	if l.maxForks > 0 && forks > l.maxForks {
+		atomic.AddInt64(&l.forks, -1)
+		return nil, errors.New("max_forks reached")
+	}
+
+	cmd := exec.Command(l.command, l.args...) //nolint:gosec // process_command is operator-configured
+	cmd.Env = append(os.Environ(), requestEnv(r)...)
+	for key, value := range l.env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		atomic.AddInt64(&l.forks, -1)
+		return nil, errors.Wrap(err, "Failed to open process-mode child stdin pipe")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		atomic.AddInt64(&l.forks, -1)
+		return nil, errors.Wrap(err, "Failed to open process-mode child stdout pipe")
+	}
+
+	if err := cmd.Start(); err != nil {
+		atomic.AddInt64(&l.forks, -1)
+		return nil, errors.Wrap(err, "Failed to start process-mode child")
+	}
+
+	session := &processSession{logger: parentLogger, conn: conn, cmd: cmd, stdin: stdin, binary: l.binary}
+	go session.pumpStdout(stdout, l)
+
+	return session, nil
+}
+
+// requestEnv builds the WEBSOCKET_* environment injected into a process-mode
+// child, surfacing handshake metadata the same way websocketd does, so a
+// plain script can read its caller's context without any WebSocket-aware
+// code of its own.
+func requestEnv(r *http.Request) []string {
+	env := []string{
+		"WEBSOCKET_REMOTE_ADDR=" + r.RemoteAddr,
+		"WEBSOCKET_REQUEST_URI=" + r.RequestURI,
+	}
+
+	for key, values := range r.URL.Query() {
+		if len(values) > 0 {
+			env = append(env, fmt.Sprintf("WEBSOCKET_QUERY_%s=%s", envKey(key), values[0]))
+		}
+	}
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			env = append(env, fmt.Sprintf("WEBSOCKET_HEADER_%s=%s", envKey(key), values[0]))
+		}
+	}
+
+	return env
+}
+
+// envKey upper-cases key and replaces characters that aren't valid in a
+// POSIX environment variable name with underscores.
+func envKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - ('a' - 'A')
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
@@ -0,0 +1,124 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	nucliozap "github.com/nuclio/zap"
+)
+
+func TestEnvKey(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"X-Api-Key", "X_API_KEY"},
+		{"room", "ROOM"},
+		{"a.b-c", "A_B_C"},
+	}
+
+	for _, tc := range testCases {
+		if got := envKey(tc.in); got != tc.want {
+			t.Errorf("envKey(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRequestEnv(t *testing.T) {
+	r := &http.Request{
+		RemoteAddr: "127.0.0.1:5555",
+		RequestURI: "/ws?room=lobby",
+		URL:        &url.URL{RawQuery: "room=lobby"},
+		Header:     http.Header{"X-Api-Key": []string{"secret"}},
+	}
+
+	env := requestEnv(r)
+
+	want := map[string]bool{
+		"WEBSOCKET_REMOTE_ADDR=127.0.0.1:5555": false,
+		"WEBSOCKET_REQUEST_URI=/ws?room=lobby": false,
+		"WEBSOCKET_QUERY_ROOM=lobby":           false,
+		"WEBSOCKET_HEADER_X_API_KEY=secret":    false,
+	}
+	for _, entry := range env {
+		if _, ok := want[entry]; ok {
+			want[entry] = true
+		}
+	}
+	for entry, found := range want {
+		if !found {
+			t.Errorf("expected requestEnv to include %q, got %v", entry, env)
+		}
+	}
+}
+
+func TestProcessLauncherMaxForksReached(t *testing.T) {
+	zapLogger, err := nucliozap.NewNuclioZapTest("process-test")
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	launcher := &processLauncher{command: "/bin/cat", maxForks: 1, forks: 1}
+
+	r := &http.Request{RemoteAddr: "127.0.0.1:1", RequestURI: "/ws", URL: &url.URL{}}
+	if _, err := launcher.start(zapLogger, nil, r); err == nil {
+		t.Fatal("expected start to fail once max_forks is reached")
+	}
+}
+
+// TestProcessLauncherMaxForksEnforcedConcurrently guards against the fork
+// slot being read-then-incremented as two separate atomic operations: with
+// that bug, concurrent callers can all pass the read before any of them
+// increments, letting more than maxForks children start at once.
+func TestProcessLauncherMaxForksEnforcedConcurrently(t *testing.T) {
+	zapLogger, err := nucliozap.NewNuclioZapTest("process-test")
+	if err != nil {
+		t.Fatalf("unexpected error creating logger: %v", err)
+	}
+
+	const maxForks = 3
+	// Children sleep long enough to still be running once every goroutine
+	// below has returned from start(), so a bug that momentarily lets the
+	// count exceed maxForks would be caught instead of having already
+	// exited and freed its slot.
+	launcher := &processLauncher{command: "/bin/sleep", args: []string{"1"}, maxForks: maxForks}
+
+	r := &http.Request{RemoteAddr: "127.0.0.1:1", RequestURI: "/ws", URL: &url.URL{}}
+
+	var started int64
+	var sessions []*processSession
+	var sessionsLock sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < maxForks*4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			session, err := launcher.start(zapLogger, nil, r)
+			if err != nil {
+				return
+			}
+			atomic.AddInt64(&started, 1)
+			sessionsLock.Lock()
+			sessions = append(sessions, session)
+			sessionsLock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for _, session := range sessions {
+		session.stop()
+	}
+
+	if started > maxForks {
+		t.Fatalf("expected at most %d concurrent forks, started %d", maxForks, started)
+	}
+}
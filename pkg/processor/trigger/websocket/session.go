@@ -0,0 +1,108 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package websocket
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// clientSession holds everything specific to one connected client: its
+// write-serializing Conn, its own DataProcessor/DataProcessorBuffered (so
+// one client's inbound chunking/ring buffer never mixes with another's),
+// the auth claims from its handshake, and the attributes/content-type most
+// recently decoded from its own inbound frames.
+type clientSession struct {
+	rawConn *websocket.Conn
+	conn    *Conn
+
+	processor         *DataProcessor
+	bufferedProcessor *DataProcessorBuffered
+
+	authClaims map[string]interface{}
+
+	lastDecodedLock sync.Mutex
+	lastAttributes  map[string]interface{}
+	lastContentType string
+
+	// done is closed when this connection's handleWS goroutine returns, so
+	// its dispatcher goroutine stops even if the trigger as a whole keeps
+	// running.
+	done chan struct{}
+}
+
+// output returns the Output() channel of whichever processor this session
+// is using.
+func (s *clientSession) output() <-chan *Event {
+	if s.bufferedProcessor != nil {
+		return s.bufferedProcessor.Output()
+	}
+	return s.processor.Output()
+}
+
+// push feeds one decoded inbound frame body into this session's processor.
+func (s *clientSession) push(data []byte) {
+	if s.bufferedProcessor != nil {
+		s.bufferedProcessor.Push(data)
+	} else {
+		s.processor.manageBuffer(data)
+	}
+}
+
+// start launches this session's processor's background loop.
+func (s *clientSession) start() {
+	if s.bufferedProcessor != nil {
+		s.bufferedProcessor.Start()
+	} else {
+		s.processor.Start()
+	}
+}
+
+// stop halts this session's processor's background loop.
+func (s *clientSession) stop() {
+	if s.bufferedProcessor != nil {
+		s.bufferedProcessor.Stop()
+	} else {
+		s.processor.Stop()
+	}
+}
+
+// statistics returns this session's ring-buffer drop/watermark counters, or
+// the zero value if it isn't running in ring backpressure mode.
+func (s *clientSession) statistics() BufferedStatistics {
+	if s.bufferedProcessor == nil {
+		return BufferedStatistics{}
+	}
+	return s.bufferedProcessor.GetStatistics()
+}
+
+// newSession builds a clientSession with a fresh per-connection processor,
+// matching configuration's backpressure mode the same way Start() used to
+// build the single, trigger-wide processor.
+func (ws_t *websocket_trigger) newSession(rawConn *websocket.Conn, conn *Conn, authClaims map[string]interface{}) *clientSession {
+	session := &clientSession{
+		rawConn:    rawConn,
+		conn:       conn,
+		authClaims: authClaims,
+		done:       make(chan struct{}),
+	}
+
+	if ws_t.configuration.BackpressureMode == BackpressureModeRing {
+		session.bufferedProcessor = NewDataProcessorBuffered(ws_t.configuration.RingBufferSize)
+	} else {
+		session.processor = NewDataProcessor(
+			ws_t.configuration.ChunkBytes,
+			ws_t.configuration.MaxBytes,
+			ws_t.configuration.TrimBytes,
+			ws_t.configuration.SleepTime,
+			ws_t.configuration.IsStream,
+		)
+	}
+
+	return session
+}
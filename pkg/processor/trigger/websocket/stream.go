@@ -12,32 +12,107 @@ import (
 )
 
 // DataProcessorStream aggregates incoming byte stream into fixed-size chunks,
-// keeps a rolling buffer, and periodically emits snapshots as Events.
+// keeps a rolling buffer per channel, and periodically emits snapshots as
+// Events. Consecutive snapshots overlap by chunkBytes-stepBytes bytes
+// (Hann-window style) when stepBytes < chunkBytes; pass stepBytes ==
+// chunkBytes for the original non-overlapping, tumbling-window behavior. When
+// channels > 1, the incoming stream is treated as sampleBytes-wide samples
+// interleaved round-robin across channels and is de-interleaved into one
+// rolling buffer - and one emitted Event, carrying its channel index in
+// metadata - per channel.
+//
+// Output backpressure doesn't block Push/tryEmit's caller (loop): a full
+// output channel drops its oldest queued event, ring-buffer style, rather
+// than stalling, and loop's emit granularity adapts to how far the consumer
+// has fallen behind (see adaptiveChunkBytes) instead of staying fixed at
+// chunkBytes.
 type DataProcessorStream struct {
-	lock       sync.Mutex
-	chunkBytes int
-	maxBytes   int
-	trimBytes  int
-	chunkBuf   []byte
-	buffer     []byte
-	newBytes   int
-	output     chan *Event
-	stop       chan struct{}
+	lock sync.Mutex
+
+	chunkBytes  int
+	stepBytes   int
+	maxBytes    int
+	trimBytes   int
+	channels    int
+	sampleBytes int
+
+	frameBuf           []byte
+	channelBuffers     [][]byte
+	bytesSinceLastEmit int
+
+	// adaptiveChunkBytes is the emit-gating threshold tryEmit actually uses
+	// in place of the static chunkBytes: it grows (clamped to maxBytes) when
+	// avgLagBytes shows the consumer falling behind, coalescing more data
+	// into fewer, larger emissions, and shrinks back toward chunkBytes under
+	// low load. inflightBytes tracks bytes handed to output that haven't
+	// been Ack'd yet; avgLagBytes is its EWMA across ticks.
+	adaptiveChunkBytes int
+	inflightBytes      int
+	avgLagBytes        float64
+
+	droppedBytes    uint64
+	droppedMessages uint64
+
+	output chan *Event
+	stop   chan struct{}
 }
 
+// lagEWMAAlpha weights the most recent tick's inflightBytes sample against
+// avgLagBytes' running history; higher reacts faster to a stalled consumer.
+const lagEWMAAlpha = 0.3
+
+// NewDataProcessorStream creates a sliding-window stream processor. channels
+// and sampleBytes are optional: pass 1 and 0 respectively to disable channel
+// de-interleaving and treat the incoming stream as a single channel.
 func NewDataProcessorStream(
 	chunkBytes,
+	stepBytes,
 	maxBytes,
-	trimBytes int,
+	trimBytes,
+	channels,
+	sampleBytes int,
 ) *DataProcessorStream {
+	if channels < 1 {
+		channels = 1
+	}
+
 	return &DataProcessorStream{
-		chunkBytes: chunkBytes,
-		maxBytes:   maxBytes,
-		trimBytes:  trimBytes,
-		chunkBuf:   []byte{},
-		buffer:     []byte{},
-		output:     make(chan *Event, 8),
-		stop:       make(chan struct{}),
+		chunkBytes:         chunkBytes,
+		stepBytes:          stepBytes,
+		maxBytes:           maxBytes,
+		trimBytes:          trimBytes,
+		channels:           channels,
+		sampleBytes:        sampleBytes,
+		frameBuf:           []byte{},
+		channelBuffers:     make([][]byte, channels),
+		adaptiveChunkBytes: chunkBytes,
+		output:             make(chan *Event, 8),
+		stop:               make(chan struct{}),
+	}
+}
+
+// StreamStatistics reports DataProcessorStream's current backpressure and
+// adaptive-chunking state, mirroring BufferedStatistics' role for
+// DataProcessorBuffered but on the output, consumer-facing side instead of
+// the input side.
+type StreamStatistics struct {
+	DroppedBytesTotal    uint64
+	DroppedMessagesTotal uint64
+	InflightBytes        int
+	AdaptiveChunkBytes   int
+}
+
+// GetStatistics returns a snapshot of dp's current backpressure and
+// adaptive-chunking counters.
+func (dp *DataProcessorStream) GetStatistics() StreamStatistics {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	return StreamStatistics{
+		DroppedBytesTotal:    dp.droppedBytes,
+		DroppedMessagesTotal: dp.droppedMessages,
+		InflightBytes:        dp.inflightBytes,
+		AdaptiveChunkBytes:   dp.adaptiveChunkBytes,
 	}
 }
 
@@ -49,24 +124,44 @@ func (dp *DataProcessorStream) Stop() {
 	close(dp.stop)
 }
 
-// append raw incoming data and convert it into fixed-size chunks.
-// Chunks are appended to rolling buffer.
+// Push appends raw incoming data to the rolling buffer(s) and tracks how many
+// new bytes per channel have arrived since the last emission. When channel
+// de-interleaving is configured, data is first split into
+// channels*sampleBytes frames and each sampleBytes-wide sample routed to its
+// channel's buffer; otherwise data is appended directly, byte for byte, so
+// overlapping windows can step by less than a full chunkBytes at a time.
 func (dp *DataProcessorStream) Push(data []byte) {
 	dp.lock.Lock()
 	defer dp.lock.Unlock()
 
-	dp.chunkBuf = append(dp.chunkBuf, data...)
+	if dp.channels > 1 && dp.sampleBytes > 0 {
+		dp.frameBuf = append(dp.frameBuf, data...)
 
-	for len(dp.chunkBuf) >= dp.chunkBytes {
-		chunk := make([]byte, dp.chunkBytes)
-		copy(chunk, dp.chunkBuf[:dp.chunkBytes])
-		dp.chunkBuf = dp.chunkBuf[dp.chunkBytes:]
-		dp.buffer = append(dp.buffer, chunk...)
-		if len(dp.buffer) > dp.maxBytes {
-			dp.buffer = dp.buffer[dp.trimBytes:]
+		frameSize := dp.channels * dp.sampleBytes
+		for len(dp.frameBuf) >= frameSize {
+			frame := dp.frameBuf[:frameSize]
+			dp.frameBuf = dp.frameBuf[frameSize:]
+
+			for ch := 0; ch < dp.channels; ch++ {
+				dp.appendToChannel(ch, frame[ch*dp.sampleBytes:(ch+1)*dp.sampleBytes])
+			}
+			dp.bytesSinceLastEmit += dp.sampleBytes
 		}
-		dp.newBytes += len(chunk)
+		return
+	}
+
+	dp.appendToChannel(0, data)
+	dp.bytesSinceLastEmit += len(data)
+}
+
+// appendToChannel appends data to channel ch's rolling buffer, trimming
+// trimBytes from the head for as long as the buffer exceeds maxBytes.
+func (dp *DataProcessorStream) appendToChannel(ch int, data []byte) {
+	buf := append(dp.channelBuffers[ch], data...)
+	for len(buf) > dp.maxBytes {
+		buf = buf[dp.trimBytes:]
 	}
+	dp.channelBuffers[ch] = buf
 }
 
 func (dp *DataProcessorStream) loop(processingInterval time.Duration) {
@@ -78,31 +173,122 @@ func (dp *DataProcessorStream) loop(processingInterval time.Duration) {
 		case <-dp.stop:
 			return
 		case <-ticker.C:
-			if ev := dp.tryEmit(); ev != nil {
-				dp.output <- ev
+			dp.updateAdaptiveChunk()
+			for _, event := range dp.tryEmit() {
+				dp.emitNonBlocking(event)
 			}
 		}
 	}
 }
 
-// emit an event only if enough new data has arrived
-// since last emission (at least one full chunk)
-func (dp *DataProcessorStream) tryEmit() *Event {
+// tryEmit emits one snapshot per channel once at least stepBytes of new data
+// has arrived since the last emission. bytesSinceLastEmit is decremented by
+// stepBytes rather than reset to zero, so a burst of Push calls that outpaces
+// the ticker still produces exactly one emission per stepBytes of new data
+// over subsequent ticks, instead of one emission per tick regardless of how
+// much arrived. The gate uses adaptiveChunkBytes rather than the static
+// chunkBytes, so emission granularity widens automatically while the
+// consumer is falling behind (see updateAdaptiveChunk).
+func (dp *DataProcessorStream) tryEmit() []*Event {
 	dp.lock.Lock()
 	defer dp.lock.Unlock()
 
-	if dp.newBytes < dp.chunkBytes {
+	if dp.bytesSinceLastEmit < dp.stepBytes || len(dp.channelBuffers[0]) < dp.adaptiveChunkBytes {
 		return nil
 	}
 
-	snapshot := make([]byte, len(dp.buffer))
-	copy(snapshot, dp.buffer)
+	now := time.Now()
+	events := make([]*Event, 0, dp.channels)
+	for ch := 0; ch < dp.channels; ch++ {
+		snapshot := make([]byte, len(dp.channelBuffers[ch]))
+		copy(snapshot, dp.channelBuffers[ch])
 
-	dp.newBytes = 0
+		event := &Event{
+			body:      snapshot,
+			timestamp: now,
+		}
+		if dp.channels > 1 {
+			event.attributes = map[string]interface{}{"channel": ch}
+		}
+		event.ackFn = dp.onAck(len(snapshot))
+		events = append(events, event)
+	}
 
-	return &Event{
-		body:      snapshot,
-		timestamp: time.Now(),
+	dp.bytesSinceLastEmit -= dp.stepBytes
+	for _, event := range events {
+		dp.inflightBytes += len(event.body)
+	}
+
+	return events
+}
+
+// onAck returns an ack callback that decrements inflightBytes by n once,
+// bound to dp and captured by tryEmit's emitted Event so Event.Ack doesn't
+// need to know anything about DataProcessorStream's internals.
+func (dp *DataProcessorStream) onAck(n int) func() {
+	var acked sync.Once
+	return func() {
+		acked.Do(func() {
+			dp.lock.Lock()
+			dp.inflightBytes -= n
+			if dp.inflightBytes < 0 {
+				dp.inflightBytes = 0
+			}
+			dp.lock.Unlock()
+		})
+	}
+}
+
+// emitNonBlocking hands event to output without blocking the emitting
+// goroutine (loop): if output is full, its oldest queued event is dropped
+// to make room, ring-buffer style, rather than stalling emission of fresh
+// data behind a slow consumer. The dropped event's ack is never observed,
+// so its bytes are also backed out of inflightBytes.
+func (dp *DataProcessorStream) emitNonBlocking(event *Event) {
+	for {
+		select {
+		case dp.output <- event:
+			return
+		default:
+		}
+
+		select {
+		case dropped := <-dp.output:
+			dp.lock.Lock()
+			dp.droppedBytes += uint64(len(dropped.body))
+			dp.droppedMessages++
+			dp.lock.Unlock()
+			dropped.Ack()
+		default:
+			// Another goroutine drained output between our full send attempt
+			// and this drop attempt; just retry the send.
+		}
+	}
+}
+
+// updateAdaptiveChunk folds the current inflightBytes sample into avgLagBytes
+// (an EWMA) and grows or shrinks adaptiveChunkBytes accordingly: a
+// consistently high lag means the consumer isn't keeping up, so coalescing
+// more data into fewer, larger emissions reduces send frequency; once lag
+// subsides, the threshold relaxes back toward the configured chunkBytes
+// floor. Bounds are clamped to [chunkBytes, maxBytes].
+func (dp *DataProcessorStream) updateAdaptiveChunk() {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+
+	dp.avgLagBytes = lagEWMAAlpha*float64(dp.inflightBytes) + (1-lagEWMAAlpha)*dp.avgLagBytes
+
+	switch {
+	case dp.avgLagBytes > float64(dp.adaptiveChunkBytes):
+		dp.adaptiveChunkBytes *= 2
+		if dp.adaptiveChunkBytes > dp.maxBytes {
+			dp.adaptiveChunkBytes = dp.maxBytes
+		}
+	case dp.avgLagBytes < float64(dp.chunkBytes)/2:
+		dp.adaptiveChunkBytes /= 2
+		if dp.adaptiveChunkBytes < dp.chunkBytes {
+			dp.adaptiveChunkBytes = dp.chunkBytes
+		}
 	}
 }
 
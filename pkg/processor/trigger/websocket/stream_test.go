@@ -8,8 +8,11 @@ import (
 func TestDataProcessorStream_EmitsAfterChunk(t *testing.T) {
 	dp := NewDataProcessorStream(
 		4,  // chunkBytes
+		4,  // stepBytes (== chunkBytes: tumbling window)
 		32, // maxBytes
 		4,  // trimBytes
+		1,  // channels
+		0,  // sampleBytes
 	)
 
 	dp.Start(10 * time.Millisecond)
@@ -28,7 +31,7 @@ func TestDataProcessorStream_EmitsAfterChunk(t *testing.T) {
 }
 
 func TestDataProcessorStream_DoesNotEmitBeforeChunk(t *testing.T) {
-	dp := NewDataProcessorStream(4, 32, 4)
+	dp := NewDataProcessorStream(4, 4, 32, 4, 1, 0)
 	dp.Start(10 * time.Millisecond)
 	defer dp.Stop()
 
@@ -45,8 +48,11 @@ func TestDataProcessorStream_DoesNotEmitBeforeChunk(t *testing.T) {
 func TestDataProcessorStream_RollingBuffer(t *testing.T) {
 	dp := NewDataProcessorStream(
 		4, // chunkBytes
+		4, // stepBytes
 		8, // maxBytes
 		4, // trimBytes
+		1, // channels
+		0, // sampleBytes
 	)
 
 	dp.Start(10 * time.Millisecond)
@@ -70,7 +76,7 @@ func TestDataProcessorStream_RollingBuffer(t *testing.T) {
 }
 
 func TestDataProcessorStream_MultipleChunksFromSinglePush(t *testing.T) {
-	dp := NewDataProcessorStream(4, 32, 4)
+	dp := NewDataProcessorStream(4, 4, 32, 4, 1, 0)
 	dp.Start(10 * time.Millisecond)
 	defer dp.Stop()
 
@@ -85,3 +91,156 @@ func TestDataProcessorStream_MultipleChunksFromSinglePush(t *testing.T) {
 		t.Fatal("timeout waiting for event")
 	}
 }
+
+func TestDataProcessorStream_OverlappingWindowsStepSmallerThanChunk(t *testing.T) {
+	dp := NewDataProcessorStream(
+		8, // chunkBytes (window size)
+		4, // stepBytes (hop: 4 bytes of overlap with the previous window)
+		8, // maxBytes
+		4, // trimBytes (== stepBytes for true overlap semantics)
+		1, // channels
+		0, // sampleBytes
+	)
+
+	dp.Start(10 * time.Millisecond)
+	defer dp.Stop()
+
+	dp.Push([]byte("abcd"))
+	dp.Push([]byte("efgh")) // first full window: abcdefgh
+
+	select {
+	case ev := <-dp.Output():
+		if string(ev.body) != "abcdefgh" {
+			t.Fatalf("expected first window 'abcdefgh', got '%s'", string(ev.body))
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timeout waiting for first window")
+	}
+
+	dp.Push([]byte("ijkl")) // trims by stepBytes(4): buffer becomes efghijkl
+
+	select {
+	case ev := <-dp.Output():
+		if string(ev.body) != "efghijkl" {
+			t.Fatalf("expected overlapping window 'efghijkl', got '%s'", string(ev.body))
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timeout waiting for overlapping window")
+	}
+}
+
+func TestDataProcessorStream_MultiChannelDeinterleaving(t *testing.T) {
+	dp := NewDataProcessorStream(
+		2,  // chunkBytes
+		2,  // stepBytes
+		32, // maxBytes
+		2,  // trimBytes
+		2,  // channels
+		1,  // sampleBytes
+	)
+
+	dp.Start(10 * time.Millisecond)
+	defer dp.Stop()
+
+	// Interleaved as: ch0 ch1 ch0 ch1 -> channel 0 gets "ac", channel 1 gets "bd"
+	dp.Push([]byte("abcd"))
+
+	received := map[int]string{}
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-dp.Output():
+			ch, ok := ev.attributes["channel"].(int)
+			if !ok {
+				t.Fatalf("expected channel attribute on event, got %v", ev.attributes)
+			}
+			received[ch] = string(ev.body)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timeout waiting for channel event")
+		}
+	}
+
+	if received[0] != "ac" {
+		t.Fatalf("expected channel 0 'ac', got '%s'", received[0])
+	}
+	if received[1] != "bd" {
+		t.Fatalf("expected channel 1 'bd', got '%s'", received[1])
+	}
+}
+
+func TestDataProcessorStream_AckReleasesInflightBytes(t *testing.T) {
+	dp := NewDataProcessorStream(4, 4, 32, 4, 1, 0)
+	dp.Start(10 * time.Millisecond)
+	defer dp.Stop()
+
+	dp.Push([]byte("abcd"))
+
+	var ev *Event
+	select {
+	case ev = <-dp.Output():
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timeout waiting for event")
+	}
+
+	if stats := dp.GetStatistics(); stats.InflightBytes != len(ev.body) {
+		t.Fatalf("expected InflightBytes %d, got %d", len(ev.body), stats.InflightBytes)
+	}
+
+	ev.Ack()
+
+	if stats := dp.GetStatistics(); stats.InflightBytes != 0 {
+		t.Fatalf("expected InflightBytes 0 after Ack, got %d", stats.InflightBytes)
+	}
+
+	// A second Ack must not double-release.
+	ev.Ack()
+	if stats := dp.GetStatistics(); stats.InflightBytes != 0 {
+		t.Fatalf("expected InflightBytes to stay 0 after repeat Ack, got %d", stats.InflightBytes)
+	}
+}
+
+func TestDataProcessorStream_EmitNonBlockingDropsOldestWhenOutputFull(t *testing.T) {
+	dp := NewDataProcessorStream(4, 4, 32, 4, 1, 0)
+
+	// Fill output to capacity without a consumer draining it.
+	for i := 0; i < cap(dp.output); i++ {
+		dp.emitNonBlocking(&Event{body: []byte("x")})
+	}
+
+	overflow := &Event{body: []byte("overflow")}
+	dp.emitNonBlocking(overflow)
+
+	stats := dp.GetStatistics()
+	if stats.DroppedMessagesTotal != 1 {
+		t.Fatalf("expected 1 dropped message, got %d", stats.DroppedMessagesTotal)
+	}
+	if stats.DroppedBytesTotal != 1 {
+		t.Fatalf("expected 1 dropped byte, got %d", stats.DroppedBytesTotal)
+	}
+
+	// The newest event must still have made it in, displacing the oldest.
+	var last *Event
+	for i := 0; i < cap(dp.output); i++ {
+		last = <-dp.output
+	}
+	if last != overflow {
+		t.Fatalf("expected the overflow event to be the most recently queued one")
+	}
+}
+
+func TestDataProcessorStream_AdaptiveChunkGrowsAndShrinks(t *testing.T) {
+	dp := NewDataProcessorStream(4, 4, 1<<20, 4, 1, 0)
+
+	dp.inflightBytes = 1000
+	dp.updateAdaptiveChunk()
+	if dp.adaptiveChunkBytes <= dp.chunkBytes {
+		t.Fatalf("expected adaptiveChunkBytes to grow above chunkBytes under sustained lag, got %d", dp.adaptiveChunkBytes)
+	}
+
+	dp.inflightBytes = 0
+	for i := 0; i < 40; i++ {
+		dp.updateAdaptiveChunk()
+	}
+	if dp.adaptiveChunkBytes != dp.chunkBytes {
+		t.Fatalf("expected adaptiveChunkBytes to shrink back to chunkBytes floor %d, got %d", dp.chunkBytes, dp.adaptiveChunkBytes)
+	}
+}
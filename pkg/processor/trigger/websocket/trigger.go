@@ -8,9 +8,12 @@ package websocket
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,11 +30,44 @@ type websocket_trigger struct {
 	trigger.AbstractTrigger
 	configuration *Configuration
 
-	processor *DataProcessor
+	codec          Codec
+	filters        *FilterRegistry
+	channels       *channelMux
+	authenticator  Authenticator
+	authStats      AuthStatistics
+	keepaliveStats KeepaliveStatistics
+
+	// inferenceForwarder forwards emitted chunks to an OpenInference
+	// trigger's ModelStreamInfer RPC instead of this trigger's own worker
+	// pool, when Configuration.InferenceStreamEndpoint is set.
+	inferenceForwarder *inferenceStreamForwarder
+
+	// processLauncher is set instead of codec/filters when Configuration.
+	// Mode is "process": every accepted connection spawns its own child
+	// process rather than reaching this trigger's handler/worker pool.
+	processLauncher *processLauncher
 
 	wsServer *http.Server
-	wsConn   *websocket.Conn
-	wsLock   sync.Mutex
+
+	// sessions maps every currently-open connection's raw *websocket.Conn
+	// (the key FilterRegistry.Route returns) to its clientSession: its own
+	// write-serializing Conn, its own DataProcessor so one client's input
+	// never cross-contaminates another's chunking/ring buffer, and the
+	// attributes/content-type most recently decoded from its own frames.
+	sessionsLock sync.Mutex
+	sessions     map[*websocket.Conn]*clientSession
+
+	// processSessions tracks every currently-running Configuration.Mode
+	// "process" child, so Stop can SIGTERM/SIGKILL them all instead of
+	// relying on their connection's read loop to notice the socket closed.
+	processSessionsLock sync.Mutex
+	processSessions     map[*websocket.Conn]*processSession
+
+	// closedStats accumulates the ring-buffer counters of sessions that
+	// have since disconnected, so GetStatistics' totals survive a client
+	// reconnecting rather than resetting to zero each time.
+	statsLock   sync.Mutex
+	closedStats BufferedStatistics
 
 	stop chan struct{}
 	wg   sync.WaitGroup
@@ -60,6 +96,8 @@ func newTrigger(
 	ws_t := &websocket_trigger{
 		AbstractTrigger: abstract,
 		configuration:   configuration,
+		sessions:        make(map[*websocket.Conn]*clientSession),
+		processSessions: make(map[*websocket.Conn]*processSession),
 		stop:            make(chan struct{}),
 	}
 	ws_t.Trigger = ws_t
@@ -67,18 +105,39 @@ func newTrigger(
 }
 
 func (ws_t *websocket_trigger) Start(_ functionconfig.Checkpoint) error {
-	ws_t.processor = NewDataProcessor(
-		ws_t.configuration.ChunkBytes,
-		ws_t.configuration.MaxBytes,
-		ws_t.configuration.TrimBytes,
-		ws_t.configuration.SleepTime,
-		ws_t.configuration.IsStream,
-	)
+	authenticator, err := NewAuthenticator(ws_t.configuration)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create authenticator")
+	}
+	ws_t.authenticator = authenticator
 
-	ws_t.processor.Start()
+	if ws_t.configuration.Mode == ModeProcess {
+		ws_t.processLauncher = newProcessLauncher(ws_t.configuration)
+	} else {
+		codec, err := NewCodec(ws_t.configuration.MessageFormat)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create codec")
+		}
+		ws_t.codec = codec
+		ws_t.filters = NewFilterRegistry()
 
-	ws_t.wg.Add(1)
-	go ws_t.eventDispatcher()
+		if len(ws_t.configuration.Channels) > 0 {
+			ws_t.channels = newChannelMux(ws_t.configuration.Channels)
+		}
+
+		if ws_t.configuration.InferenceStreamEndpoint != "" {
+			forwarder, err := newInferenceStreamForwarder(
+				ws_t.Logger,
+				ws_t.configuration.InferenceStreamEndpoint,
+				ws_t.configuration.InferenceStreamBackpressure,
+				ws_t.configuration.InferenceStreamQueueSize,
+			)
+			if err != nil {
+				return errors.Wrap(err, "Failed to start inference stream forwarder")
+			}
+			ws_t.inferenceForwarder = forwarder
+		}
+	}
 
 	ws_t.wg.Add(1)
 	go ws_t.startServer()
@@ -86,6 +145,23 @@ func (ws_t *websocket_trigger) Start(_ functionconfig.Checkpoint) error {
 	return nil
 }
 
+// checkOrigin enforces Configuration.AllowedOrigins. An empty allowlist
+// accepts any origin, matching the trigger's historical behavior.
+func (ws_t *websocket_trigger) checkOrigin(r *http.Request) bool {
+	if len(ws_t.configuration.AllowedOrigins) == 0 {
+		return true
+	}
+
+	origin := r.Header.Get("Origin")
+	for _, allowed := range ws_t.configuration.AllowedOrigins {
+		if origin == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (ws_t *websocket_trigger) startServer() {
 	defer ws_t.wg.Done()
 
@@ -97,46 +173,344 @@ func (ws_t *websocket_trigger) startServer() {
 		Handler: mux,
 	}
 
+	if ws_t.configuration.TLSCertFile != "" {
+		_ = ws_t.wsServer.ListenAndServeTLS(ws_t.configuration.TLSCertFile, ws_t.configuration.TLSKeyFile)
+		return
+	}
+
 	_ = ws_t.wsServer.ListenAndServe()
 }
 
 func (ws_t *websocket_trigger) handleWS(w http.ResponseWriter, r *http.Request) {
-	up := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+	claims, unauthorized, err := ws_t.authenticator.Authenticate(r)
+	if err != nil {
+		atomic.AddUint64(&ws_t.authStats.RejectedTotal, 1)
+		w.Header().Set("WWW-Authenticate", `Bearer realm="websocket"`)
+		if unauthorized {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+		return
+	}
+
+	up := websocket.Upgrader{
+		CheckOrigin:       ws_t.checkOrigin,
+		Subprotocols:      ws_t.configuration.Subprotocols,
+		EnableCompression: ws_t.configuration.EnableCompression,
+	}
 	conn, err := up.Upgrade(w, r, nil)
 	if err != nil {
 		return
 	}
 
-	ws_t.wsLock.Lock()
-	ws_t.wsConn = conn
-	ws_t.wsLock.Unlock()
+	authClaims := claims
+	if subprotocol := conn.Subprotocol(); subprotocol != "" {
+		if authClaims == nil {
+			authClaims = map[string]interface{}{}
+		}
+		authClaims["subprotocol"] = subprotocol
+	}
+
+	if ws_t.configuration.EnableCompression {
+		conn.EnableWriteCompression(true)
+		if ws_t.configuration.CompressionLevel != 0 {
+			_ = conn.SetCompressionLevel(ws_t.configuration.CompressionLevel)
+		}
+	}
+
+	if ws_t.configuration.MaxMessageBytes > 0 {
+		conn.SetReadLimit(ws_t.configuration.MaxMessageBytes)
+	}
+
+	writeTimeout := time.Duration(ws_t.configuration.WriteTimeoutSeconds) * time.Second
+	wrapped := newConn(conn, writeTimeout)
+
+	if ws_t.configuration.Mode == ModeProcess {
+		ws_t.handleProcessConnection(conn, r, wrapped)
+		return
+	}
+
+	session := ws_t.newSession(conn, wrapped, authClaims)
+	session.start()
+
+	ws_t.sessionsLock.Lock()
+	ws_t.sessions[conn] = session
+	ws_t.sessionsLock.Unlock()
+	defer func() {
+		ws_t.sessionsLock.Lock()
+		delete(ws_t.sessions, conn)
+		ws_t.sessionsLock.Unlock()
+
+		close(session.done)
+		session.stop()
+		ws_t.recordClosedStatistics(session.statistics())
+	}()
+
+	defer ws_t.filters.UninstallConn(conn)
+
+	ws_t.wg.Add(1)
+	go ws_t.sessionDispatcher(session)
+
+	// readTimeout is the effective read deadline: ReadTimeoutSeconds, when
+	// set, is an independent idle-read bound on top of the pong-driven
+	// deadline, so whichever is stricter wins.
+	readTimeout := time.Duration(ws_t.configuration.PongTimeoutSeconds) * time.Second
+	if ws_t.configuration.ReadTimeoutSeconds > 0 {
+		if configured := time.Duration(ws_t.configuration.ReadTimeoutSeconds) * time.Second; configured < readTimeout {
+			readTimeout = configured
+		}
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+
+	pingStop := make(chan struct{})
+	defer close(pingStop)
+	go ws_t.pingLoop(wrapped, pingStop)
 
 	for {
 		_, data, err := conn.ReadMessage()
 		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				ws_t.closeOnPongTimeout(conn)
+			}
 			if err == io.EOF {
 				return
 			}
 			return
 		}
-		ws_t.processor.manageBuffer(data)
+
+		if ws_t.channels != nil {
+			ws_t.handleChannelFrame(session, conn, authClaims, data)
+			continue
+		}
+
+		if ws_t.handleControlMessage(conn, data) {
+			continue
+		}
+
+		decoded, err := ws_t.codec.Decode(data)
+		if err != nil {
+			ws_t.Logger.WarnWith("Failed to decode WebSocket frame", "error", err)
+			continue
+		}
+
+		// auth claims are immutable per-connection and layered underneath
+		// whatever the codec decoded from the frame itself.
+		attributes := map[string]interface{}{}
+		for k, v := range authClaims {
+			attributes[k] = v
+		}
+		for k, v := range decoded.attributes {
+			attributes[k] = v
+		}
+
+		session.lastDecodedLock.Lock()
+		session.lastAttributes = attributes
+		session.lastContentType = decoded.contentType
+		session.lastDecodedLock.Unlock()
+
+		session.push(decoded.body)
+	}
+}
+
+// pingLoop sends periodic pings so a dead peer's connection is reaped once
+// its read deadline (refreshed only by a pong reply) elapses.
+func (ws_t *websocket_trigger) pingLoop(conn *Conn, stop chan struct{}) {
+	interval := time.Duration(ws_t.configuration.PingIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ws_t.stop:
+			return
+		case <-ticker.C:
+			if err := conn.Send(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
 	}
 }
 
-func (ws_t *websocket_trigger) eventDispatcher() {
+// closeOnPongTimeout records a websocket_keepalive_timeouts_total hit and,
+// if Configuration.CloseOnPongTimeout is set, sends an RFC 6455 code-1011
+// close frame before the caller's failed ReadMessage unwinds the
+// connection. It fires for any read deadline expiry, whether driven by a
+// missed pong or by Configuration.ReadTimeoutSeconds. The close frame is
+// best-effort: the read already failed, so there is nothing further to
+// retry on a write error.
+func (ws_t *websocket_trigger) closeOnPongTimeout(conn *websocket.Conn) {
+	atomic.AddUint64(&ws_t.keepaliveStats.TimeoutsTotal, 1)
+	if !ws_t.configuration.CloseOnPongTimeout {
+		return
+	}
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "pong_timeout")
+	_ = conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+	_ = conn.Close()
+}
+
+// handleProcessConnection implements Configuration.Mode "process": it
+// spawns the configured child process for this connection, wires inbound
+// frames to its stdin until the connection or the child goes away, and
+// tears the child down on either.
+func (ws_t *websocket_trigger) handleProcessConnection(conn *websocket.Conn, r *http.Request, wrapped *Conn) {
+	session, err := ws_t.processLauncher.start(ws_t.Logger, wrapped, r)
+	if err != nil {
+		ws_t.Logger.WarnWith("Failed to start process-mode child", "error", err, "remoteAddr", r.RemoteAddr)
+		_ = conn.Close()
+		return
+	}
+
+	ws_t.Logger.InfoWith("Process-mode connection started", "remoteAddr", r.RemoteAddr)
+
+	ws_t.processSessionsLock.Lock()
+	ws_t.processSessions[conn] = session
+	ws_t.processSessionsLock.Unlock()
+
+	defer func() {
+		ws_t.processSessionsLock.Lock()
+		delete(ws_t.processSessions, conn)
+		ws_t.processSessionsLock.Unlock()
+
+		session.stop()
+		ws_t.Logger.InfoWith("Process-mode connection stopped", "remoteAddr", r.RemoteAddr)
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		session.write(data)
+	}
+}
+
+// handleChannelFrame decodes one inbound frame under the channel
+// multiplexing subprotocol (Configuration.Channels): "subscribe"/
+// "unsubscribe" apply against the filter registry keyed by channel name as
+// topic, matching how handleControlMessage's generic {"op":...} frames
+// work; anything else is pushed into session as a data frame, stamped with
+// its producing channel's name and content type.
+func (ws_t *websocket_trigger) handleChannelFrame(session *clientSession, conn *websocket.Conn, authClaims map[string]interface{}, data []byte) {
+	msg, err := ws_t.channels.Decode(data)
+	if err != nil {
+		ws_t.Logger.WarnWith("Failed to decode channel frame", "error", err)
+		return
+	}
+
+	switch msg.Type {
+	case "subscribe":
+		ws_t.filters.NewFilter(conn, msg.Channel, map[string]interface{}{"channel": msg.Channel})
+		return
+	case "unsubscribe":
+		ws_t.filters.UninstallByTopic(conn, msg.Channel)
+		return
+	}
+
+	attributes := map[string]interface{}{}
+	for k, v := range authClaims {
+		attributes[k] = v
+	}
+	attributes["channel"] = msg.Channel
+
+	session.lastDecodedLock.Lock()
+	session.lastAttributes = attributes
+	session.lastContentType = ws_t.channels.MIMEType(msg.Channel)
+	session.lastDecodedLock.Unlock()
+
+	session.push(msg.Payload)
+}
+
+// handleControlMessage recognizes a
+// {"op":"subscribe"|"unsubscribe"|"getLogs",...} control frame, applies it
+// against the filter registry, and reports whether data was a control
+// frame (and so should not be treated as a data message).
+func (ws_t *websocket_trigger) handleControlMessage(conn *websocket.Conn, data []byte) bool {
+	var cm controlMessage
+	if err := json.Unmarshal(data, &cm); err != nil || cm.Op == "" {
+		return false
+	}
+
+	switch cm.Op {
+	case "subscribe":
+		ws_t.filters.NewFilter(conn, cm.Topic, cm.Filter)
+	case "unsubscribe":
+		ws_t.filters.UninstallFilter(cm.ID)
+	case "getLogs":
+		ws_t.handleGetLogs(conn, cm.ID)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// handleGetLogs replies on conn with the events a filter has matched and
+// retained since the last getLogs call - draining the filter the same way
+// GetLogs does, so a connection that polls this op periodically bounds
+// retained events instead of letting them grow unbounded.
+func (ws_t *websocket_trigger) handleGetLogs(conn *websocket.Conn, id string) {
+	writer := ws_t.lookupConnWriter(conn)
+	if writer == nil {
+		return
+	}
+
+	logs := ws_t.filters.GetLogs(id)
+	entries := make([]logEntry, len(logs))
+	for i, event := range logs {
+		entries[i] = logEntry{Body: event.GetBody(), Attributes: event.GetHeaders()}
+	}
+
+	encoded, err := json.Marshal(controlMessageResponse{Op: "getLogs", ID: id, Logs: entries})
+	if err != nil {
+		ws_t.Logger.WarnWith("Failed to encode getLogs response", "error", err)
+		return
+	}
+
+	_ = writer.Send(websocket.TextMessage, encoded)
+}
+
+// sessionDispatcher reads session's own processor's Output() and dispatches
+// each Event, one goroutine per connection - so one client's handler
+// response can never be stamped with another client's attributes, and
+// (unless Configuration.Broadcast is set) always replies to the client that
+// produced it rather than whichever connection happened to be accepted most
+// recently.
+func (ws_t *websocket_trigger) sessionDispatcher(session *clientSession) {
 	defer ws_t.wg.Done()
 
+	output := session.output()
+
 	for {
 		select {
 		case <-ws_t.stop:
 			return
-		case event := <-ws_t.processor.Output():
-			ws_t.process(event)
+		case <-session.done:
+			return
+		case event := <-output:
+			session.lastDecodedLock.Lock()
+			event.attributes = session.lastAttributes
+			event.contentType = session.lastContentType
+			session.lastDecodedLock.Unlock()
+
+			event.conn = session.conn
+
+			if ws_t.inferenceForwarder != nil {
+				ws_t.inferenceForwarder.Forward(event)
+				continue
+			}
+			ws_t.process(event, session)
 		}
 	}
 }
 
-func (ws_t *websocket_trigger) process(event *Event) {
+func (ws_t *websocket_trigger) process(event *Event, session *clientSession) {
 	w, err := ws_t.WorkerAllocator.Allocate(5 * time.Second)
 	if err != nil {
 		return
@@ -148,18 +522,116 @@ func (ws_t *websocket_trigger) process(event *Event) {
 		return
 	}
 
-	if r, ok := resp.(nuclio.Response); ok {
-		ws_t.wsLock.Lock()
-		if ws_t.wsConn != nil {
-			_ = ws_t.wsConn.WriteMessage(websocket.TextMessage, r.Body)
+	r, ok := resp.(nuclio.Response)
+	if !ok {
+		return
+	}
+
+	var encoded []byte
+	if ws_t.channels != nil {
+		channel, _ := event.attributes["channel"].(string)
+		encoded, err = ws_t.channels.Encode(channel, r.Body)
+	} else {
+		encoded, err = ws_t.codec.Encode(r.Body, event.attributes)
+	}
+	if err != nil {
+		ws_t.Logger.WarnWith("Failed to encode WebSocket response", "error", err)
+		return
+	}
+
+	if conns := ws_t.filters.Route(event); len(conns) > 0 {
+		for _, conn := range conns {
+			if writer := ws_t.lookupConnWriter(conn); writer != nil {
+				_ = writer.Send(websocket.TextMessage, encoded)
+			}
 		}
-		ws_t.wsLock.Unlock()
+		return
+	}
+
+	if ws_t.configuration.Broadcast {
+		ws_t.Broadcast(encoded)
+		return
+	}
+
+	if session.conn != nil {
+		_ = session.conn.Send(websocket.TextMessage, encoded)
+	}
+}
+
+// lookupConnWriter returns the write-serializing Conn wrapper registered for
+// conn, or nil if conn is no longer open.
+func (ws_t *websocket_trigger) lookupConnWriter(conn *websocket.Conn) *Conn {
+	ws_t.sessionsLock.Lock()
+	defer ws_t.sessionsLock.Unlock()
+	if session, ok := ws_t.sessions[conn]; ok {
+		return session.conn
+	}
+	return nil
+}
+
+// Broadcast pushes data to every currently open connection as a single
+// text message, for server-initiated pushes that aren't a reply to any one
+// inbound message (e.g. a handler reacting to an external event on its own
+// goroutine), and for process()'s own reply path when Configuration.
+// Broadcast is set. It returns the number of connections the message was
+// sent to.
+func (ws_t *websocket_trigger) Broadcast(data []byte) int {
+	ws_t.sessionsLock.Lock()
+	writers := make([]*Conn, 0, len(ws_t.sessions))
+	for _, session := range ws_t.sessions {
+		writers = append(writers, session.conn)
+	}
+	ws_t.sessionsLock.Unlock()
+
+	sent := 0
+	for _, writer := range writers {
+		if err := writer.Send(websocket.TextMessage, data); err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// ConnectionCount returns the number of currently open WebSocket connections.
+func (ws_t *websocket_trigger) ConnectionCount() int {
+	ws_t.sessionsLock.Lock()
+	defer ws_t.sessionsLock.Unlock()
+	return len(ws_t.sessions)
+}
+
+// recordClosedStatistics folds a disconnected session's final ring-buffer
+// counters into closedStats, so GetStatistics' totals survive a client
+// reconnecting instead of resetting to zero each time.
+func (ws_t *websocket_trigger) recordClosedStatistics(stats BufferedStatistics) {
+	ws_t.statsLock.Lock()
+	defer ws_t.statsLock.Unlock()
+
+	ws_t.closedStats.DroppedBytesTotal += stats.DroppedBytesTotal
+	ws_t.closedStats.DroppedMessagesTotal += stats.DroppedMessagesTotal
+	if stats.BufferHighWatermark > ws_t.closedStats.BufferHighWatermark {
+		ws_t.closedStats.BufferHighWatermark = stats.BufferHighWatermark
 	}
 }
 
 func (ws_t *websocket_trigger) Stop(bool) (functionconfig.Checkpoint, error) {
 	close(ws_t.stop)
-	ws_t.processor.Stop()
+
+	ws_t.sessionsLock.Lock()
+	for _, session := range ws_t.sessions {
+		_ = session.rawConn.Close()
+	}
+	ws_t.sessionsLock.Unlock()
+
+	ws_t.processSessionsLock.Lock()
+	for conn, session := range ws_t.processSessions {
+		_ = conn.Close()
+		session.stop()
+	}
+	ws_t.processSessionsLock.Unlock()
+
+	if ws_t.inferenceForwarder != nil {
+		ws_t.inferenceForwarder.Stop()
+	}
 	if ws_t.wsServer != nil {
 		_ = ws_t.wsServer.Shutdown(context.TODO())
 	}
@@ -167,6 +639,53 @@ func (ws_t *websocket_trigger) Stop(bool) (functionconfig.Checkpoint, error) {
 	return nil, nil
 }
 
+// GetConfig returns the trigger's configuration plus the current live
+// connection count.
 func (ws_t *websocket_trigger) GetConfig() map[string]any {
-	return common.StructureToMap(ws_t.configuration)
+	config := common.StructureToMap(ws_t.configuration)
+	config["activeConnections"] = ws_t.ConnectionCount()
+	return config
+}
+
+// GetStatistics returns the ring-buffer drop/watermark counters, summed
+// across every session (open and already-disconnected). It returns the
+// zero value when the trigger is not running in "ring" backpressure mode.
+func (ws_t *websocket_trigger) GetStatistics() BufferedStatistics {
+	ws_t.statsLock.Lock()
+	total := ws_t.closedStats
+	ws_t.statsLock.Unlock()
+
+	ws_t.sessionsLock.Lock()
+	sessions := make([]*clientSession, 0, len(ws_t.sessions))
+	for _, session := range ws_t.sessions {
+		sessions = append(sessions, session)
+	}
+	ws_t.sessionsLock.Unlock()
+
+	for _, session := range sessions {
+		live := session.statistics()
+		total.DroppedBytesTotal += live.DroppedBytesTotal
+		total.DroppedMessagesTotal += live.DroppedMessagesTotal
+		if live.BufferHighWatermark > total.BufferHighWatermark {
+			total.BufferHighWatermark = live.BufferHighWatermark
+		}
+	}
+
+	return total
+}
+
+// GetAuthStatistics returns a snapshot of the rejected-handshake counter.
+func (ws_t *websocket_trigger) GetAuthStatistics() AuthStatistics {
+	return AuthStatistics{RejectedTotal: atomic.LoadUint64(&ws_t.authStats.RejectedTotal)}
+}
+
+// GetKeepaliveStatistics returns a snapshot of
+// websocket_keepalive_timeouts_total for this trigger.
+func (ws_t *websocket_trigger) GetKeepaliveStatistics() KeepaliveStatistics {
+	return KeepaliveStatistics{TimeoutsTotal: atomic.LoadUint64(&ws_t.keepaliveStats.TimeoutsTotal)}
+}
+
+// GetConnectionStatistics returns a snapshot of the current connection count.
+func (ws_t *websocket_trigger) GetConnectionStatistics() ConnectionStatistics {
+	return ConnectionStatistics{ActiveConnections: ws_t.ConnectionCount()}
 }
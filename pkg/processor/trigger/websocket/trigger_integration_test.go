@@ -255,15 +255,18 @@ func (suite *WebsocketIntegrationTestSuite) TestConcurrentConnections() {
 	time.Sleep(100 * time.Millisecond)
 
 	// Verify all connections were handled
-	suite.Equal(0, len(wsTrigger.conns)) // All should be cleaned up
+	suite.Equal(0, wsTrigger.ConnectionCount()) // All should be cleaned up
 
 	// Stop the trigger
 	_, err = wsTrigger.Stop(false)
 	suite.NoError(err)
 }
 
-func (suite *WebsocketIntegrationTestSuite) TestConnectionLimit() {
-	// Create trigger with limited connections (1 worker = 1 max client)
+func (suite *WebsocketIntegrationTestSuite) TestMultipleConcurrentClients() {
+	// A single worker allocator must no longer cap how many clients can be
+	// connected at once - only how many of their messages can be in flight
+	// to a handler simultaneously. Two clients dialing in against a
+	// 1-worker allocator should both be accepted as their own session.
 	triggerConfig := &functionconfig.Trigger{
 		Kind:                "websocket",
 		Name:                "test-websocket-limit",
@@ -300,26 +303,23 @@ func (suite *WebsocketIntegrationTestSuite) TestConnectionLimit() {
 	// Give server time to start
 	time.Sleep(100 * time.Millisecond)
 
-	// First connection should succeed
 	u := url.URL{Scheme: "ws", Host: "localhost" + suite.serverAddr, Path: "/ws"}
+
 	conn1, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 	suite.NoError(err)
 	defer conn1.Close()
 
-	// Give time for connection to be established
+	conn2, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	suite.NoError(err)
+	defer conn2.Close()
+
+	// Give time for both handshakes to register their session.
 	time.Sleep(50 * time.Millisecond)
 
-	// Second connection should be rejected (only 1 worker/client allowed)
-	conn2, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
-	if err == nil {
-		conn2.Close()
-		suite.Fail("Expected second connection to be rejected")
-	} else {
-		suite.T().Logf("Second connection correctly rejected: %v", err)
-	}
+	suite.Equal(2, wsTrigger.ConnectionCount())
 
-	// Close first connection
 	conn1.Close()
+	conn2.Close()
 
 	// Stop the trigger
 	_, err = wsTrigger.Stop(false)
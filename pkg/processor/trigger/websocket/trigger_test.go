@@ -347,26 +347,25 @@ func (suite *WebsocketTriggerTestSuite) TestConnectionManagement() {
 	wsTrigger := triggerInstance.(*websocket_trigger)
 
 	// Test initial state
-	suite.Equal(0, len(wsTrigger.conns))
-	suite.Equal(1, wsTrigger.maxClients) // numWorkers = 1 by default
+	suite.Equal(0, wsTrigger.ConnectionCount())
 
-	// Simulate adding connections
+	// Simulate adding sessions, as handleWS does on a successful handshake
 	mockConn1 := &websocket.Conn{}
 	mockConn2 := &websocket.Conn{}
 
-	wsTrigger.connLock.Lock()
-	wsTrigger.conns[mockConn1] = struct{}{}
-	wsTrigger.conns[mockConn2] = struct{}{}
-	wsTrigger.connLock.Unlock()
+	wsTrigger.sessionsLock.Lock()
+	wsTrigger.sessions[mockConn1] = &clientSession{rawConn: mockConn1}
+	wsTrigger.sessions[mockConn2] = &clientSession{rawConn: mockConn2}
+	wsTrigger.sessionsLock.Unlock()
 
-	suite.Equal(2, len(wsTrigger.conns))
+	suite.Equal(2, wsTrigger.ConnectionCount())
 
-	// Test removing connections
-	wsTrigger.connLock.Lock()
-	delete(wsTrigger.conns, mockConn1)
-	wsTrigger.connLock.Unlock()
+	// Test removing a session, as handleWS's deferred cleanup does
+	wsTrigger.sessionsLock.Lock()
+	delete(wsTrigger.sessions, mockConn1)
+	wsTrigger.sessionsLock.Unlock()
 
-	suite.Equal(1, len(wsTrigger.conns))
+	suite.Equal(1, wsTrigger.ConnectionCount())
 }
 
 func (suite *WebsocketTriggerTestSuite) TestEventProcessing() {
@@ -1,6 +1,8 @@
 package websocket
 
 import (
+	"compress/flate"
+
 	"github.com/nuclio/nuclio/pkg/functionconfig"
 	"github.com/nuclio/nuclio/pkg/processor/runtime"
 	"github.com/nuclio/nuclio/pkg/processor/trigger"
@@ -16,6 +18,44 @@ const (
 	DefaultMaxBufferSeconds     = 45
 	DefaultTrimSeconds          = 30
 	DefaultAccumulateBuffer     = true
+
+	// DefaultBackpressureMode preserves the historical DataProcessor
+	// behavior of replacing/trimming in place.
+	DefaultBackpressureMode = "replace"
+	DefaultRingBufferSize   = 64
+
+	DefaultAPIKeyHeader = "X-API-Key"
+	DefaultAPIKeyQuery  = "api_key"
+
+	DefaultPingIntervalSeconds = 30
+	DefaultPongTimeoutSeconds  = 60
+
+	// DefaultCloseOnPongTimeout preserves the historical behavior of
+	// reaping a connection outright once its pong_timeout expires.
+	DefaultCloseOnPongTimeout = true
+
+	// DefaultInferenceStreamBackpressure drops the oldest unsent chunk
+	// rather than blocking the event dispatcher when the forwarder's
+	// gRPC stream can't keep up.
+	DefaultInferenceStreamBackpressure = InferenceStreamBackpressureDropOldest
+
+	// DefaultInferenceStreamQueueSize bounds the forwarder's in-flight
+	// chunk queue.
+	DefaultInferenceStreamQueueSize = 16
+)
+
+// Supported values for Configuration.InferenceStreamBackpressure.
+const (
+	InferenceStreamBackpressureDropOldest = "drop-oldest"
+	InferenceStreamBackpressureBlock      = "block"
+)
+
+// Supported values for Configuration.BackpressureMode.
+const (
+	BackpressureModeReplace = "replace"
+	BackpressureModeTrim    = "trim"
+	BackpressureModeRing    = "ring"
+	BackpressureModeBlock   = "block"
 )
 
 type Configuration struct {
@@ -28,6 +68,154 @@ type Configuration struct {
 	MaxBufferSeconds     int    `mapstructure:"max_buffer_seconds"`
 	TrimSeconds          int    `mapstructure:"trim_seconds"`
 	AccumulateBuffer     bool   `mapstructure:"accumulate_buffer"`
+
+	// BackpressureMode selects how the trigger copes with a producer that
+	// outpaces the consumer: "replace" and "trim" preserve the historical
+	// DataProcessor semantics, "ring" switches to DataProcessorBuffered
+	// (bounded ring buffer with drop metrics), "block" is reserved for a
+	// future blocking-producer mode.
+	BackpressureMode string `mapstructure:"backpressure_mode"`
+	RingBufferSize   int    `mapstructure:"ring_buffer_size"`
+
+	// MessageFormat selects the Codec used to decode inbound frames and
+	// encode outbound ones. Defaults to "raw" (opaque bytes) for backward
+	// compatibility.
+	MessageFormat string `mapstructure:"message_format"`
+
+	// TLSCertFile/TLSKeyFile, when both set, make the listener serve
+	// wss:// instead of plain ws://.
+	TLSCertFile string `mapstructure:"tls_cert_file"`
+	TLSKeyFile  string `mapstructure:"tls_key_file"`
+
+	// AllowedOrigins enforces websocket.Upgrader.CheckOrigin against an
+	// allowlist. Empty means any origin is accepted (historical behavior).
+	AllowedOrigins []string `mapstructure:"allowed_origins"`
+
+	// AuthMode selects the Authenticator applied to the handshake request:
+	// "none" (default), "bearer_jwt", or "api_key".
+	AuthMode string `mapstructure:"auth"`
+
+	JWKSURL     string `mapstructure:"jwks_url"`
+	JWTIssuer   string `mapstructure:"jwt_issuer"`
+	JWTAudience string `mapstructure:"jwt_audience"`
+
+	APIKeyHeader string   `mapstructure:"api_key_header"`
+	APIKeyQuery  string   `mapstructure:"api_key_query"`
+	APIKeys      []string `mapstructure:"api_keys"`
+
+	// PingIntervalSeconds controls how often the server pings each
+	// connection to detect dead peers; PongTimeoutSeconds is how long it
+	// waits for the corresponding pong before reaping the connection.
+	PingIntervalSeconds int `mapstructure:"ping_interval_seconds"`
+	PongTimeoutSeconds  int `mapstructure:"pong_timeout_seconds"`
+
+	// CloseOnPongTimeout, when true (the default), proactively closes a
+	// connection with RFC 6455 code 1011 once PongTimeoutSeconds expires
+	// without a pong reply, instead of leaving it for the next failed read
+	// to discover.
+	CloseOnPongTimeout bool `mapstructure:"close_on_timeout"`
+
+	// ReadTimeoutSeconds, if set, bounds how long the server waits for any
+	// message (not just a pong) before treating the connection as dead,
+	// independent of PongTimeoutSeconds - the stricter of the two applies.
+	// 0 (the default) leaves PongTimeoutSeconds as the only read deadline.
+	ReadTimeoutSeconds int `mapstructure:"read_timeout_seconds"`
+
+	// WriteTimeoutSeconds, if set, bounds every Conn.Send call: a write
+	// that can't complete within it (e.g. a stalled peer not draining its
+	// TCP receive buffer) fails instead of blocking indefinitely. 0 (the
+	// default) disables write deadlines entirely.
+	WriteTimeoutSeconds int `mapstructure:"write_timeout_seconds"`
+
+	// Broadcast, when true, delivers every handler response to all
+	// currently connected clients instead of just the one whose message
+	// produced it - for a handler that reacts to one client's input but
+	// whose result every viewer should see (e.g. several browsers watching
+	// transcribed audio from one RTSP feed). It only affects replies with
+	// no FilterRegistry subscription match; a topic-filtered reply still
+	// only goes to its subscribers. False (the default) preserves the
+	// historical per-connection reply behavior.
+	Broadcast bool `mapstructure:"broadcast"`
+
+	// Subprotocols is the ordered list offered to clients during the
+	// WebSocket handshake's Sec-WebSocket-Protocol negotiation. The first
+	// entry also present in the client's offer is selected and exposed to
+	// the handler as the Event attribute "subprotocol"; an empty list
+	// (the default) disables negotiation entirely.
+	Subprotocols []string `mapstructure:"subprotocols"`
+
+	// InferenceStreamEndpoint, when set, is the address of an
+	// OpenInference trigger's gRPC server; every Event this trigger's
+	// active DataProcessor emits is forwarded there via ModelStreamInfer
+	// instead of being dispatched to this trigger's own worker pool. An
+	// empty value (the default) disables forwarding entirely.
+	InferenceStreamEndpoint string `mapstructure:"inference_stream_endpoint"`
+
+	// InferenceStreamBackpressure selects how the forwarder copes with a
+	// ModelStreamInfer stream that can't keep up: "drop-oldest" (default)
+	// discards the oldest unsent chunk, "block" waits for room instead.
+	InferenceStreamBackpressure string `mapstructure:"inference_stream_backpressure"`
+
+	// InferenceStreamQueueSize bounds the forwarder's in-flight chunk
+	// queue before InferenceStreamBackpressure kicks in.
+	InferenceStreamQueueSize int `mapstructure:"inference_stream_queue_size"`
+
+	// MaxMessageBytes caps how large a single inbound frame may be before
+	// the connection is closed with RFC 6455 code 1009 (message too big);
+	// <= 0 (the default) leaves gorilla/websocket's own unlimited default
+	// in place.
+	MaxMessageBytes int64 `mapstructure:"max_message_bytes"`
+
+	// EnableCompression negotiates the permessage-deflate extension (RFC
+	// 7692) during the handshake. CompressionLevel, if non-zero, is passed
+	// to the per-connection flate writer (see compress/flate's level
+	// constants); left at 0 (flate's default level) otherwise.
+	//
+	// gorilla/websocket's permessage-deflate support does not implement
+	// the context-takeover negotiation parameters RFC 7692 defines
+	// (server_no_context_takeover/client_no_context_takeover): every
+	// message is compressed with a fresh context regardless, which is to
+	// say gorilla always behaves as if both were set. There is deliberately
+	// no configuration field for them here, since exposing one would imply
+	// a choice this trigger cannot actually honor.
+	EnableCompression bool `mapstructure:"enable_compression"`
+	CompressionLevel  int  `mapstructure:"compression_level"`
+
+	// Channels, when non-empty, enables the channel-multiplexing
+	// subprotocol (see ChannelSubprotocol): every inbound/outbound frame is
+	// tagged with one of these declared channel names instead of being
+	// decoded by MessageFormat, letting a single connection carry several
+	// independent named streams (e.g. "audio.pcm", "control.commands",
+	// "metrics.out") at once. An empty list (the default) disables it
+	// entirely and leaves MessageFormat in charge of framing, as before.
+	Channels []ChannelConfig `mapstructure:"channels"`
+
+	// Mode selects how an accepted connection is driven: "handler" (the
+	// default) forwards frames to this trigger's own function handler, as
+	// before; "process" instead spawns ProcessCommand once per connection,
+	// websocketd-style, piping inbound frames to its stdin and streaming
+	// its stdout back as outbound frames.
+	Mode string `mapstructure:"mode"`
+
+	// ProcessCommand/ProcessArgs/ProcessEnv configure the child spawned per
+	// connection when Mode is "process". ProcessEnv entries are layered on
+	// top of this processor's own environment and the WEBSOCKET_* request
+	// metadata variables injected automatically (remote address, query
+	// parameters, headers).
+	ProcessCommand string            `mapstructure:"process_command"`
+	ProcessArgs    []string          `mapstructure:"process_args"`
+	ProcessEnv     map[string]string `mapstructure:"process_env"`
+
+	// ProcessBinary selects how a process-mode child's stdout is framed
+	// back to the client: false (the default) scans it line-delimited and
+	// sends each line as a text message; true instead streams raw chunks
+	// as binary messages, for children emitting non-line-oriented data.
+	ProcessBinary bool `mapstructure:"process_binary"`
+
+	// MaxForks caps how many process-mode children may run concurrently;
+	// connections beyond the cap are rejected at handshake. <= 0 (the
+	// default) leaves it uncapped.
+	MaxForks int `mapstructure:"max_forks"`
 }
 
 func NewConfiguration(id string,
@@ -35,14 +223,26 @@ func NewConfiguration(id string,
 	runtimeConfiguration *runtime.Configuration) (*Configuration, error) {
 
 	newConfiguration := Configuration{
-		WebSocketAddr:        "",
-		DataType:             "",
-		BufferSize:           DefaultBufferSize,
-		SampleRate:           DefaultSampleRate,
-		ChunkDurationSeconds: DefaultChunkDurationSeconds,
-		MaxBufferSeconds:     DefaultMaxBufferSeconds,
-		TrimSeconds:          DefaultTrimSeconds,
-		AccumulateBuffer:     DefaultAccumulateBuffer,
+		WebSocketAddr:               "",
+		DataType:                    "",
+		BufferSize:                  DefaultBufferSize,
+		SampleRate:                  DefaultSampleRate,
+		ChunkDurationSeconds:        DefaultChunkDurationSeconds,
+		MaxBufferSeconds:            DefaultMaxBufferSeconds,
+		TrimSeconds:                 DefaultTrimSeconds,
+		AccumulateBuffer:            DefaultAccumulateBuffer,
+		BackpressureMode:            DefaultBackpressureMode,
+		RingBufferSize:              DefaultRingBufferSize,
+		MessageFormat:               DefaultMessageFormat,
+		AuthMode:                    DefaultAuthMode,
+		APIKeyHeader:                DefaultAPIKeyHeader,
+		APIKeyQuery:                 DefaultAPIKeyQuery,
+		PingIntervalSeconds:         DefaultPingIntervalSeconds,
+		PongTimeoutSeconds:          DefaultPongTimeoutSeconds,
+		CloseOnPongTimeout:          DefaultCloseOnPongTimeout,
+		InferenceStreamBackpressure: DefaultInferenceStreamBackpressure,
+		InferenceStreamQueueSize:    DefaultInferenceStreamQueueSize,
+		Mode:                        DefaultMode,
 	}
 
 	baseConfiguration, err := trigger.NewConfiguration(id, triggerConfiguration, runtimeConfiguration)
@@ -63,5 +263,90 @@ func NewConfiguration(id string,
 		return nil, errors.New("data_type is required")
 	}
 
+	switch newConfiguration.BackpressureMode {
+	case BackpressureModeReplace, BackpressureModeTrim, BackpressureModeRing, BackpressureModeBlock:
+	default:
+		return nil, errors.Errorf("unsupported backpressure_mode: %s", newConfiguration.BackpressureMode)
+	}
+
+	if _, err := NewCodec(newConfiguration.MessageFormat); err != nil {
+		return nil, errors.Wrap(err, "Failed to validate message_format")
+	}
+
+	switch newConfiguration.AuthMode {
+	case AuthModeNone, AuthModeBearerJWT, AuthModeAPIKey, "":
+	default:
+		return nil, errors.Errorf("unsupported auth mode: %s", newConfiguration.AuthMode)
+	}
+
+	if (newConfiguration.TLSCertFile == "") != (newConfiguration.TLSKeyFile == "") {
+		return nil, errors.New("tls_cert_file and tls_key_file must be set together")
+	}
+
+	if newConfiguration.PingIntervalSeconds <= 0 {
+		return nil, errors.New("ping_interval_seconds must be positive")
+	}
+
+	if newConfiguration.PongTimeoutSeconds <= 0 {
+		return nil, errors.New("pong_timeout_seconds must be positive")
+	}
+
+	if newConfiguration.ReadTimeoutSeconds < 0 {
+		return nil, errors.New("read_timeout_seconds must not be negative")
+	}
+
+	if newConfiguration.WriteTimeoutSeconds < 0 {
+		return nil, errors.New("write_timeout_seconds must not be negative")
+	}
+
+	for _, subprotocol := range newConfiguration.Subprotocols {
+		if subprotocol == "" {
+			return nil, errors.New("subprotocols must not contain an empty entry")
+		}
+	}
+
+	switch newConfiguration.InferenceStreamBackpressure {
+	case InferenceStreamBackpressureDropOldest, InferenceStreamBackpressureBlock:
+	default:
+		return nil, errors.Errorf("unsupported inference_stream_backpressure: %s", newConfiguration.InferenceStreamBackpressure)
+	}
+
+	if newConfiguration.InferenceStreamQueueSize <= 0 {
+		return nil, errors.New("inference_stream_queue_size must be positive")
+	}
+
+	if newConfiguration.CompressionLevel != 0 {
+		if newConfiguration.CompressionLevel < flate.HuffmanOnly || newConfiguration.CompressionLevel > flate.BestCompression {
+			return nil, errors.Errorf("compression_level must be 0 (default) or between %d and %d", flate.HuffmanOnly, flate.BestCompression)
+		}
+	}
+
+	seenChannels := map[string]bool{}
+	for _, channel := range newConfiguration.Channels {
+		if channel.Name == "" {
+			return nil, errors.New("channels entries must set name")
+		}
+		if seenChannels[channel.Name] {
+			return nil, errors.Errorf("duplicate channel name: %s", channel.Name)
+		}
+		seenChannels[channel.Name] = true
+
+		switch channel.ContentType {
+		case ChannelContentTypeJSON, ChannelContentTypeBinary, "":
+		default:
+			return nil, errors.Errorf("unsupported channel content_type: %s", channel.ContentType)
+		}
+	}
+
+	switch newConfiguration.Mode {
+	case ModeHandler, "":
+	case ModeProcess:
+		if newConfiguration.ProcessCommand == "" {
+			return nil, errors.New(`process_command is required when mode is "process"`)
+		}
+	default:
+		return nil, errors.Errorf("unsupported mode: %s", newConfiguration.Mode)
+	}
+
 	return &newConfiguration, nil
 }
